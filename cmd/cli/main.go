@@ -11,39 +11,71 @@ import (
 	"github.com/Eastore-project/ddo-client/internal/commands/admin"
 	"github.com/Eastore-project/ddo-client/internal/commands/allocations"
 	"github.com/Eastore-project/ddo-client/internal/commands/payments"
+	"github.com/Eastore-project/ddo-client/internal/commands/piece"
 	"github.com/Eastore-project/ddo-client/internal/commands/sp"
+	"github.com/Eastore-project/ddo-client/internal/commands/tx"
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 )
 
 func main() {
-	// Load configuration from environment variables
-	config.LoadFromEnv()
-
 	app := &cli.App{
 		Name:  "ddo-client",
 		Usage: "A CLI application for interacting with DDO smart contracts",
 		Before: func(c *cli.Context) error {
+			if err := output.Setup(c); err != nil {
+				return err
+			}
+
+			// Load the profile, then environment variables on top of it. Commands
+			// apply their own flags to this base configuration.
+			cfg, err := config.Load(c.String("config"), c.String("profile"), os.Getenv)
+			if err != nil {
+				return err
+			}
+			config.SetBase(c.App, cfg)
+
 			// Print configuration info
 			if c.Bool("verbose") {
-				fmt.Printf("RPC Endpoint: %s\n", config.RPCEndpoint)
-				if config.ContractAddress != "" {
-					fmt.Printf("Contract Address: %s\n", config.ContractAddress)
+				w := output.Progress(c)
+				if cfg.Profile != "" {
+					fmt.Fprintf(w, "Profile: %s\n", cfg.Profile)
+				}
+				fmt.Fprintf(w, "RPC Endpoint: %s\n", cfg.RPCEndpoint)
+				if cfg.ContractAddress != "" {
+					fmt.Fprintf(w, "Contract Address: %s\n", cfg.ContractAddress)
 				}
 			}
 			return nil
 		},
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.BoolFlag{
 				Name:    "verbose",
 				Aliases: []string{"v"},
 				Usage:   "Show verbose output",
 			},
-		},
+			&cli.StringFlag{
+				Name:    "profile",
+				Usage:   "Network profile: mainnet, calibration, devnet or one from the config file",
+				EnvVars: []string{"DDO_PROFILE"},
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				Usage:   "Config file path (default: ~/.ddo-client/config.toml)",
+				EnvVars: []string{"DDO_CONFIG"},
+			},
+			output.Flag(),
+		}, append(config.TxFlags(), config.RPCFlags()...)...),
 		Commands: []*cli.Command{
 			allocations.AllocationsCommand(),
 			payments.PaymentsCommand(),
 			sp.SPCommand(),
 			admin.AdminCommand(),
+			piece.PieceCommand(),
+			tx.TxCommand(),
+			commands.ExporterCommand(),
+			commands.ServeCommand(),
+			commands.NotifyCommand(),
 			commands.ApproveTokenCommand(),
 		},
 	}
@@ -9,8 +9,9 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
-	"github.com/Eastore-project/ddo-client/pkg/utils"
+	"github.com/Eastore-project/ddo-client/pkg/types"
 )
 
 func AdminCommand() *cli.Command {
@@ -35,21 +36,9 @@ func setPaymentsContractCommand() *cli.Command {
 		Name:  "set-payments-contract",
 		Usage: "Set the payments contract address",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.StringFlag{
 				Name:     "address",
 				Aliases:  []string{"a"},
@@ -57,37 +46,35 @@ func setPaymentsContractCommand() *cli.Command {
 				Required: true,
 			},
 		},
-		Action: func(c *cli.Context) error {
-			applyConfigOverrides(c)
-			if missing := config.GetMissingConfig(); len(missing) > 0 {
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			w := output.Progress(c)
+
+			if missing := cfg.MissingConfig(); len(missing) > 0 {
 				return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 			}
 
 			addr := common.HexToAddress(c.String("address"))
 
-			ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+			ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 			if err != nil {
 				return fmt.Errorf("failed to create DDO contract client: %v", err)
 			}
 
-			fmt.Printf("Setting payments contract to %s...\n", addr.Hex())
+			fmt.Fprintf(w, "Setting payments contract to %s...\n", addr.Hex())
 
 			txHash, err := ddoClient.SetPaymentsContract(addr)
 			if err != nil {
 				return fmt.Errorf("failed to set payments contract: %v", err)
 			}
 
-			fmt.Printf("Transaction Hash: %s\n", txHash)
-
-			fmt.Printf("Waiting for transaction to be mined...\n")
-			if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-				fmt.Printf("Warning: transaction may not have been mined: %v\n", err)
-				return nil
-			}
+			fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
-			fmt.Printf("Payments contract updated successfully!\n")
-			return nil
-		},
+			fmt.Fprintf(w, "Waiting for transaction to be mined...\n")
+			result := output.WaitTx(ddoClient.TxManager(), txHash)
+			return output.RenderTx(c, result, func() {
+				fmt.Fprintf(w, "Payments contract updated successfully!\n")
+			})
+		}),
 	}
 }
 
@@ -96,58 +83,44 @@ func setCommissionRateCommand() *cli.Command {
 		Name:  "set-commission-rate",
 		Usage: "Set the commission rate in basis points (max 100 = 1%)",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.Uint64Flag{
 				Name:     "bps",
 				Usage:    "Commission rate in basis points (e.g. 50 = 0.5%)",
 				Required: true,
 			},
 		},
-		Action: func(c *cli.Context) error {
-			applyConfigOverrides(c)
-			if missing := config.GetMissingConfig(); len(missing) > 0 {
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			w := output.Progress(c)
+
+			if missing := cfg.MissingConfig(); len(missing) > 0 {
 				return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 			}
 
 			bps := new(big.Int).SetUint64(c.Uint64("bps"))
 
-			ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+			ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 			if err != nil {
 				return fmt.Errorf("failed to create DDO contract client: %v", err)
 			}
 
-			fmt.Printf("Setting commission rate to %s bps (%.2f%%)...\n", bps.String(), float64(bps.Uint64())/100.0)
+			fmt.Fprintf(w, "Setting commission rate to %s bps (%.2f%%)...\n", bps.String(), float64(bps.Uint64())/100.0)
 
 			txHash, err := ddoClient.SetCommissionRate(bps)
 			if err != nil {
 				return fmt.Errorf("failed to set commission rate: %v", err)
 			}
 
-			fmt.Printf("Transaction Hash: %s\n", txHash)
-
-			fmt.Printf("Waiting for transaction to be mined...\n")
-			if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-				fmt.Printf("Warning: transaction may not have been mined: %v\n", err)
-				return nil
-			}
+			fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
-			fmt.Printf("Commission rate updated successfully!\n")
-			return nil
-		},
+			fmt.Fprintf(w, "Waiting for transaction to be mined...\n")
+			result := output.WaitTx(ddoClient.TxManager(), txHash)
+			return output.RenderTx(c, result, func() {
+				fmt.Fprintf(w, "Commission rate updated successfully!\n")
+			})
+		}),
 	}
 }
 
@@ -156,30 +129,19 @@ func setLockupAmountCommand() *cli.Command {
 		Name:  "set-lockup-amount",
 		Usage: "Set the allocation lockup amount (in token base units)",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.StringFlag{
 				Name:     "amount",
 				Usage:    "Lockup amount in base units (e.g. 1000000000000000000 for 1 token with 18 decimals)",
 				Required: true,
 			},
 		},
-		Action: func(c *cli.Context) error {
-			applyConfigOverrides(c)
-			if missing := config.GetMissingConfig(); len(missing) > 0 {
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			w := output.Progress(c)
+
+			if missing := cfg.MissingConfig(); len(missing) > 0 {
 				return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 			}
 
@@ -188,29 +150,26 @@ func setLockupAmountCommand() *cli.Command {
 				return fmt.Errorf("invalid amount: %s", c.String("amount"))
 			}
 
-			ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+			ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 			if err != nil {
 				return fmt.Errorf("failed to create DDO contract client: %v", err)
 			}
 
-			fmt.Printf("Setting allocation lockup amount to %s...\n", amount.String())
+			fmt.Fprintf(w, "Setting allocation lockup amount to %s...\n", amount.String())
 
 			txHash, err := ddoClient.SetAllocationLockupAmount(amount)
 			if err != nil {
 				return fmt.Errorf("failed to set lockup amount: %v", err)
 			}
 
-			fmt.Printf("Transaction Hash: %s\n", txHash)
+			fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
-			fmt.Printf("Waiting for transaction to be mined...\n")
-			if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-				fmt.Printf("Warning: transaction may not have been mined: %v\n", err)
-				return nil
-			}
-
-			fmt.Printf("Allocation lockup amount updated successfully!\n")
-			return nil
-		},
+			fmt.Fprintf(w, "Waiting for transaction to be mined...\n")
+			result := output.WaitTx(ddoClient.TxManager(), txHash)
+			return output.RenderTx(c, result, func() {
+				fmt.Fprintf(w, "Allocation lockup amount updated successfully!\n")
+			})
+		}),
 	}
 }
 
@@ -219,51 +178,37 @@ func pauseCommand() *cli.Command {
 		Name:  "pause",
 		Usage: "Pause the contract (owner-only)",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 		},
-		Action: func(c *cli.Context) error {
-			applyConfigOverrides(c)
-			if missing := config.GetMissingConfig(); len(missing) > 0 {
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			w := output.Progress(c)
+
+			if missing := cfg.MissingConfig(); len(missing) > 0 {
 				return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 			}
 
-			ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+			ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 			if err != nil {
 				return fmt.Errorf("failed to create DDO contract client: %v", err)
 			}
 
-			fmt.Printf("Pausing contract...\n")
+			fmt.Fprintf(w, "Pausing contract...\n")
 
 			txHash, err := ddoClient.Pause()
 			if err != nil {
 				return fmt.Errorf("failed to pause contract: %v", err)
 			}
 
-			fmt.Printf("Transaction Hash: %s\n", txHash)
+			fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
-			fmt.Printf("Waiting for transaction to be mined...\n")
-			if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-				fmt.Printf("Warning: transaction may not have been mined: %v\n", err)
-				return nil
-			}
-
-			fmt.Printf("Contract paused successfully!\n")
-			return nil
-		},
+			fmt.Fprintf(w, "Waiting for transaction to be mined...\n")
+			result := output.WaitTx(ddoClient.TxManager(), txHash)
+			return output.RenderTx(c, result, func() {
+				fmt.Fprintf(w, "Contract paused successfully!\n")
+			})
+		}),
 	}
 }
 
@@ -272,51 +217,37 @@ func unpauseCommand() *cli.Command {
 		Name:  "unpause",
 		Usage: "Unpause the contract (owner-only)",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 		},
-		Action: func(c *cli.Context) error {
-			applyConfigOverrides(c)
-			if missing := config.GetMissingConfig(); len(missing) > 0 {
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			w := output.Progress(c)
+
+			if missing := cfg.MissingConfig(); len(missing) > 0 {
 				return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 			}
 
-			ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+			ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 			if err != nil {
 				return fmt.Errorf("failed to create DDO contract client: %v", err)
 			}
 
-			fmt.Printf("Unpausing contract...\n")
+			fmt.Fprintf(w, "Unpausing contract...\n")
 
 			txHash, err := ddoClient.Unpause()
 			if err != nil {
 				return fmt.Errorf("failed to unpause contract: %v", err)
 			}
 
-			fmt.Printf("Transaction Hash: %s\n", txHash)
+			fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
-			fmt.Printf("Waiting for transaction to be mined...\n")
-			if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-				fmt.Printf("Warning: transaction may not have been mined: %v\n", err)
-				return nil
-			}
-
-			fmt.Printf("Contract unpaused successfully!\n")
-			return nil
-		},
+			fmt.Fprintf(w, "Waiting for transaction to be mined...\n")
+			result := output.WaitTx(ddoClient.TxManager(), txHash)
+			return output.RenderTx(c, result, func() {
+				fmt.Fprintf(w, "Contract unpaused successfully!\n")
+			})
+		}),
 	}
 }
 
@@ -325,26 +256,13 @@ func pausedCommand() *cli.Command {
 		Name:  "is-paused",
 		Usage: "Check if the contract is paused",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
 		},
-		Action: func(c *cli.Context) error {
-			if contract := c.String("contract"); contract != "" {
-				config.ContractAddress = contract
-			}
-			if rpc := c.String("rpc"); rpc != "" {
-				config.RPCEndpoint = rpc
-			}
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			w := output.Progress(c)
 
-			ddoClient, err := ddo.NewReadOnlyClientWithParams(config.RPCEndpoint, config.ContractAddress)
+			ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
 			if err != nil {
 				return fmt.Errorf("failed to create DDO contract client: %v", err)
 			}
@@ -354,14 +272,14 @@ func pausedCommand() *cli.Command {
 				return fmt.Errorf("failed to get paused status: %v", err)
 			}
 
-			if paused {
-				fmt.Printf("Contract is PAUSED\n")
-			} else {
-				fmt.Printf("Contract is ACTIVE (not paused)\n")
-			}
-
-			return nil
-		},
+			return output.Render(c, types.ContractStatus{Paused: paused}, func() {
+				if paused {
+					fmt.Fprintf(w, "Contract is PAUSED\n")
+				} else {
+					fmt.Fprintf(w, "Contract is ACTIVE (not paused)\n")
+				}
+			})
+		}),
 	}
 }
 
@@ -370,21 +288,9 @@ func blacklistSectorCommand() *cli.Command {
 		Name:  "blacklist-sector",
 		Usage: "Blacklist or unblacklist a sector for a provider (owner-only)",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.Uint64Flag{
 				Name:     "provider",
 				Aliases:  []string{"p"},
@@ -402,9 +308,10 @@ func blacklistSectorCommand() *cli.Command {
 				Usage: "Remove from blacklist instead of adding",
 			},
 		},
-		Action: func(c *cli.Context) error {
-			applyConfigOverrides(c)
-			if missing := config.GetMissingConfig(); len(missing) > 0 {
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			w := output.Progress(c)
+
+			if missing := cfg.MissingConfig(); len(missing) > 0 {
 				return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 			}
 
@@ -412,7 +319,7 @@ func blacklistSectorCommand() *cli.Command {
 			sectorNumber := c.Uint64("sector")
 			blacklisted := !c.Bool("remove")
 
-			ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+			ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 			if err != nil {
 				return fmt.Errorf("failed to create DDO contract client: %v", err)
 			}
@@ -421,28 +328,25 @@ func blacklistSectorCommand() *cli.Command {
 			if !blacklisted {
 				action = "Removing blacklist for"
 			}
-			fmt.Printf("%s sector %d for provider %d...\n", action, sectorNumber, providerId)
+			fmt.Fprintf(w, "%s sector %d for provider %d...\n", action, sectorNumber, providerId)
 
 			txHash, err := ddoClient.BlacklistSector(providerId, sectorNumber, blacklisted)
 			if err != nil {
 				return fmt.Errorf("failed to blacklist sector: %v", err)
 			}
 
-			fmt.Printf("Transaction Hash: %s\n", txHash)
+			fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
-			fmt.Printf("Waiting for transaction to be mined...\n")
-			if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-				fmt.Printf("Warning: transaction may not have been mined: %v\n", err)
-				return nil
-			}
-
-			if blacklisted {
-				fmt.Printf("Sector %d for provider %d blacklisted successfully!\n", sectorNumber, providerId)
-			} else {
-				fmt.Printf("Sector %d for provider %d removed from blacklist successfully!\n", sectorNumber, providerId)
-			}
-			return nil
-		},
+			fmt.Fprintf(w, "Waiting for transaction to be mined...\n")
+			result := output.WaitTx(ddoClient.TxManager(), txHash)
+			return output.RenderTx(c, result, func() {
+				if blacklisted {
+					fmt.Fprintf(w, "Sector %d for provider %d blacklisted successfully!\n", sectorNumber, providerId)
+				} else {
+					fmt.Fprintf(w, "Sector %d for provider %d removed from blacklist successfully!\n", sectorNumber, providerId)
+				}
+			})
+		}),
 	}
 }
 
@@ -451,16 +355,8 @@ func isSectorBlacklistedCommand() *cli.Command {
 		Name:  "is-sector-blacklisted",
 		Usage: "Check if a sector is blacklisted for a provider",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
 			&cli.Uint64Flag{
 				Name:     "provider",
 				Aliases:  []string{"p"},
@@ -474,15 +370,10 @@ func isSectorBlacklistedCommand() *cli.Command {
 				Required: true,
 			},
 		},
-		Action: func(c *cli.Context) error {
-			if contract := c.String("contract"); contract != "" {
-				config.ContractAddress = contract
-			}
-			if rpc := c.String("rpc"); rpc != "" {
-				config.RPCEndpoint = rpc
-			}
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			w := output.Progress(c)
 
-			ddoClient, err := ddo.NewReadOnlyClientWithParams(config.RPCEndpoint, config.ContractAddress)
+			ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
 			if err != nil {
 				return fmt.Errorf("failed to create DDO contract client: %v", err)
 			}
@@ -495,25 +386,14 @@ func isSectorBlacklistedCommand() *cli.Command {
 				return fmt.Errorf("failed to check sector blacklist: %v", err)
 			}
 
-			if blacklisted {
-				fmt.Printf("Sector %d for provider %d is BLACKLISTED\n", sectorNumber, providerId)
-			} else {
-				fmt.Printf("Sector %d for provider %d is NOT blacklisted\n", sectorNumber, providerId)
-			}
-
-			return nil
-		},
-	}
-}
-
-func applyConfigOverrides(c *cli.Context) {
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
-	if pk := c.String("private-key"); pk != "" {
-		config.PrivateKey = pk
+			status := types.SectorBlacklistStatus{Provider: providerId, Sector: sectorNumber, Blacklisted: blacklisted}
+			return output.Render(c, status, func() {
+				if blacklisted {
+					fmt.Fprintf(w, "Sector %d for provider %d is BLACKLISTED\n", sectorNumber, providerId)
+				} else {
+					fmt.Fprintf(w, "Sector %d for provider %d is NOT blacklisted\n", sectorNumber, providerId)
+				}
+			})
+		}),
 	}
 }
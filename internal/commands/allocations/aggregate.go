@@ -0,0 +1,125 @@
+package allocations
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eastore-project/fildeal/src/buffer"
+	dealutils "github.com/eastore-project/fildeal/src/deal/utils"
+	commcid "github.com/filecoin-project/go-fil-commcid"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// prepareAggregatePiece packs every entry of inputDir into a single aggregate piece. Files
+// ending in .car are used as they are; other files and folders are converted to CARs first.
+// The piece is padded up to minPieceSize and must not exceed maxPieceSize (when non-zero).
+func prepareAggregatePiece(w io.Writer, inputDir, outDir string, bufferConfig *buffer.Config, minPieceSize, maxPieceSize uint64) (*types.PreparedPiece, error) {
+	entries, err := os.ReadDir(inputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input folder: %w", err)
+	}
+
+	var subs []types.SubPiece
+	var generated []string
+	defer func() {
+		// Generated CARs are contained in the aggregate file
+		for _, path := range generated {
+			os.Remove(path)
+		}
+	}()
+
+	for _, entry := range entries {
+		source := filepath.Join(inputDir, entry.Name())
+
+		if entry.Type().IsRegular() && strings.EqualFold(filepath.Ext(entry.Name()), ".car") {
+			pieceCid, pieceSize, carSize, err := utils.CarPieceCommitment(source)
+			if err != nil {
+				return nil, err
+			}
+			subs = append(subs, types.SubPiece{
+				Source:    source,
+				PieceCid:  pieceCid,
+				PieceSize: pieceSize,
+				CarSize:   carSize,
+				CarPath:   source,
+			})
+			continue
+		}
+		if !entry.Type().IsRegular() && !entry.IsDir() {
+			continue
+		}
+
+		result, err := dealutils.ConvertToCar(source, outDir, source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to car: %w", source, err)
+		}
+		carPath := filepath.Join(outDir, result.PieceCid+".car")
+		generated = append(generated, carPath)
+		subs = append(subs, types.SubPiece{
+			Source:     source,
+			PieceCid:   result.PieceCid,
+			PieceSize:  result.PieceSize,
+			PayloadCid: result.DataCid,
+			CarSize:    result.CarSize,
+			CarPath:    carPath,
+		})
+	}
+	if len(subs) == 0 {
+		return nil, fmt.Errorf("no files to aggregate in %s", inputDir)
+	}
+
+	ordered, layoutSize := utils.LayoutSubPieces(subs)
+	if maxPieceSize != 0 && layoutSize > maxPieceSize {
+		return nil, fmt.Errorf("aggregate of %d inputs needs a %d byte piece, above the maximum of %d", len(subs), layoutSize, maxPieceSize)
+	}
+	fmt.Fprintf(w, "Aggregating %d inputs into one piece\n", len(ordered))
+
+	aggFile, err := os.CreateTemp(outDir, "aggregate-*.bin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aggregate file: %w", err)
+	}
+	rawCommP, pieceSize, written, err := utils.WriteAggregate(aggFile, ordered, minPieceSize)
+	if closeErr := aggFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(aggFile.Name())
+		return nil, err
+	}
+
+	pieceCid, err := commcid.DataCommitmentV1ToCID(rawCommP)
+	if err != nil {
+		os.Remove(aggFile.Name())
+		return nil, fmt.Errorf("failed to build piece CID: %w", err)
+	}
+	aggPath := filepath.Join(outDir, pieceCid.String()+".bin")
+	if err := os.Rename(aggFile.Name(), aggPath); err != nil {
+		return nil, fmt.Errorf("failed to rename aggregate file: %w", err)
+	}
+
+	bufferResp, err := newBuffer(bufferConfig).Store(aggPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store aggregate in buffer: %w", err)
+	}
+
+	// Only user-supplied CARs remain on disk after aggregation
+	for i := range ordered {
+		if ordered[i].CarPath != ordered[i].Source {
+			ordered[i].CarPath = ""
+		}
+	}
+
+	return &types.PreparedPiece{
+		PieceCid:    pieceCid.String(),
+		PieceSize:   pieceSize,
+		CarSize:     written,
+		CarPath:     aggPath,
+		DownloadURL: bufferResp.URL,
+		SubPieces:   ordered,
+	}, nil
+}
@@ -4,29 +4,25 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"fmt"
-	"math/big"
-	"math/rand"
 	"os"
+	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/eastore-project/fildeal/src/buffer"
 	dealutils "github.com/eastore-project/fildeal/src/deal/utils"
-	eabi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ipfs/go-cid"
-	"github.com/oklog/ulid/v2"
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
 	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
 	"github.com/Eastore-project/ddo-client/pkg/curio"
-	"github.com/Eastore-project/ddo-client/pkg/curio/cidconv"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
 	"github.com/Eastore-project/ddo-client/pkg/types"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
@@ -37,32 +33,32 @@ func CreateFromFileCommand() *cli.Command {
 		Aliases: []string{"cff"},
 		Usage:   "Create allocation requests from files/folders using data preparation with payment setup",
 		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.PaymentsContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
+			// File input
 			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
+				Name:    "input",
+				Aliases: []string{"i"},
+				Usage:   "Input file or folder path",
 			},
+			// Pre-built pieces
 			&cli.StringFlag{
-				Name:    "payments-contract",
-				Aliases: []string{"pc"},
-				Usage:   "Payments contract address (overrides PAYMENTS_CONTRACT_ADDRESS env var)",
+				Name:  "car",
+				Usage: "Existing CAR file to allocate as-is, skipping data preparation (alternative to --input)",
 			},
 			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
+				Name:  "piece-cid",
+				Usage: "Piece CID (v1 or v2) of data prepared elsewhere; with --download-url instead of --input, or to check the unpadded piece CID of --car",
 			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
+			&cli.Uint64Flag{
+				Name:  "piece-size",
+				Usage: "Padded piece size for --piece-cid (implied by a v2 piece CID); with --car, pads the piece up to this size",
 			},
-			// File input
-			&cli.StringFlag{
-				Name:     "input",
-				Aliases:  []string{"i"},
-				Usage:    "Input file or folder path",
-				Required: true,
+			&cli.Uint64Flag{
+				Name:  "car-size",
+				Usage: "CAR size in bytes for --piece-cid (implied by a v2 piece CID; needed for --curio-upload)",
 			},
 			&cli.StringFlag{
 				Name:  "outdir",
@@ -85,10 +81,13 @@ func CreateFromFileCommand() *cli.Command {
 				EnvVars: []string{"BUFFER_URL"},
 			},
 			// Deal parameters
-			&cli.Uint64Flag{
-				Name:     "provider",
-				Usage:    "Provider/Miner ID",
-				Required: true,
+			&cli.Uint64SliceFlag{
+				Name:  "provider",
+				Usage: "Provider/Miner ID; repeat or comma-separate to store one replica per provider",
+			},
+			&cli.IntFlag{
+				Name:  "replicas",
+				Usage: "Store replicas with the N cheapest registered providers accepting the token, piece size and term (alternative to --provider)",
 			},
 			&cli.Int64Flag{
 				Name:  "term-min",
@@ -105,14 +104,25 @@ func CreateFromFileCommand() *cli.Command {
 				Usage: "Expiration offset from current block",
 				Value: 172800,
 			},
+			&cli.Uint64Flag{
+				Name:  "max-piece-size",
+				Usage: "Split the input into pieces of at most this size (defaults to the providers' maximum piece size)",
+			},
+			&cli.BoolFlag{
+				Name:  "aggregate",
+				Usage: "Pack every file, folder or CAR in the input folder into a single aggregate piece",
+			},
+			&cli.StringFlag{
+				Name:  "manifest",
+				Usage: "Where to write the piece manifest when the input is split or aggregated (default: <outdir>/manifest.json)",
+			},
 			&cli.StringFlag{
 				Name:  "download-url",
 				Usage: "Download URL for the piece (optional, will use buffer URL if not provided)",
 			},
 			&cli.StringFlag{
-				Name:     "payment-token",
-				Usage:    "Payment token address (required)",
-				Required: true,
+				Name:  "payment-token",
+				Usage: "Payment token address (defaults to the profile's payment token)",
 			},
 			&cli.BoolFlag{
 				Name:  "dry-run",
@@ -143,78 +153,90 @@ func CreateFromFileCommand() *cli.Command {
 				Usage: "Filecoin address of the provider (e.g., t03123279). If not provided, derives f0<provider_id>",
 			},
 		},
-		Action: executeCreateFromFile,
+		Action: config.Action(executeCreateFromFile),
 	}
 }
 
-func executeCreateFromFile(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
+func executeCreateFromFile(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	// Command line flags take precedence over the profile
+	curioAPI := c.String("curio-api")
+	if curioAPI == "" {
+		curioAPI = cfg.CurioAPI
+	}
+	curioUpload := c.Bool("curio-upload")
+	if !c.IsSet("curio-upload") {
+		curioUpload = cfg.CurioUpload
+	}
+
+	providers := c.Uint64Slice("provider")
+	replicas := c.Int("replicas")
+	if len(providers) == 0 && replicas <= 0 {
+		return fmt.Errorf("either --provider or --replicas is required")
 	}
-	if paymentsContract := c.String("payments-contract"); paymentsContract != "" {
-		config.PaymentsContractAddress = paymentsContract
+	if len(providers) > 0 && replicas > 0 {
+		return fmt.Errorf("use either --provider or --replicas, not both")
 	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
+	multiProvider := len(providers) > 1 || replicas > 1
+	if multiProvider && curioAPI != "" {
+		return fmt.Errorf("--curio-api applies to a single provider; with several providers each Curio API is discovered from chain")
 	}
-	if pk := c.String("private-key"); pk != "" {
-		config.PrivateKey = pk
+	if multiProvider && c.String("provider-fil-addr") != "" {
+		return fmt.Errorf("--provider-fil-addr applies to a single provider")
 	}
-	curioAPI := c.String("curio-api")
-	curioUpload := c.Bool("curio-upload")
 
-	// Auto-discover Curio API URL from on-chain miner info if not provided
-	if curioUpload && curioAPI == "" && c.IsSet("provider") {
-		fmt.Printf("No --curio-api provided, discovering SP URL from chain...\n")
-		discovered, err := curio.DiscoverSPURL(config.RPCEndpoint, c.Uint64("provider"))
-		if err != nil {
-			fmt.Printf("Warning: could not auto-discover SP URL: %v\n", err)
-			fmt.Printf("Use --curio-api to provide manually\n")
-		} else {
-			curioAPI = discovered
-			fmt.Printf("Discovered Curio API: %s\n", discovered)
-		}
+	// The data comes from --input, an existing --car, or a --piece-cid with its --download-url
+	inputPath := c.String("input")
+	carPath := c.String("car")
+	pieceCid := c.String("piece-cid")
+	external := carPath == "" && pieceCid != ""
+	switch {
+	case inputPath == "" && carPath == "" && pieceCid == "":
+		return fmt.Errorf("one of --input, --car or --piece-cid is required")
+	case inputPath != "" && (carPath != "" || pieceCid != ""):
+		return fmt.Errorf("--input cannot be combined with --car or --piece-cid")
+	case external && c.String("download-url") == "":
+		return fmt.Errorf("--piece-cid without --car requires --download-url")
+	}
+	if inputPath == "" && (c.Bool("aggregate") || c.IsSet("max-piece-size") || c.IsSet("manifest")) {
+		return fmt.Errorf("--aggregate, --max-piece-size and --manifest only apply to --input")
 	}
 
 	// Validate required configuration
-	if missing := config.GetMissingConfig(); len(missing) > 0 {
+	if missing := cfg.MissingConfig(); len(missing) > 0 {
 		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
-	if config.PaymentsContractAddress == "" {
+	if cfg.PaymentsContractAddress == "" {
 		return fmt.Errorf("payments contract address required (use --payments-contract flag or PAYMENTS_CONTRACT_ADDRESS env var)")
 	}
 
-	inputPath := c.String("input")
 	outDir := c.String("outdir")
-	paymentToken := c.String("payment-token")
-
-	// Determine if we need to keep CAR files for Curio upload
-	curioEnabled := curioUpload && curioAPI != ""
+	paymentToken, err := cfg.TokenOrDefault("payment-token", c.String("payment-token"))
+	if err != nil {
+		return err
+	}
 
-	// Handle temporary directory
+	// Handle temporary directory. CAR files are kept until every Curio upload is done.
+	// Pre-built pieces are not written anywhere.
 	useTempDir := outDir == ""
-	var err error
 
-	if useTempDir {
-		outDir, err = os.MkdirTemp("", "ddo-client-*")
-		if err != nil {
-			return fmt.Errorf("failed to create temporary directory: %w", err)
-		}
-		// Only clean up temp dir after Curio upload is done (deferred at end)
-		if !curioEnabled {
+	if inputPath != "" {
+		if useTempDir {
+			outDir, err = os.MkdirTemp("", "ddo-client-*")
+			if err != nil {
+				return fmt.Errorf("failed to create temporary directory: %w", err)
+			}
 			defer os.RemoveAll(outDir)
-		}
-		fmt.Printf("Using temporary directory: %s\n", outDir)
-	} else {
-		if err := os.MkdirAll(outDir, 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+			fmt.Fprintf(w, "Using temporary directory: %s\n", outDir)
+		} else {
+			if err := os.MkdirAll(outDir, 0755); err != nil {
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
 		}
 	}
 
-	fmt.Printf("Preparing data from: %s\n", inputPath)
-
 	// Create data prep config
 	bufferConfig := &buffer.Config{
 		Type:    c.String("buffer-type"),
@@ -222,81 +244,214 @@ func executeCreateFromFile(c *cli.Context) error {
 		BaseURL: c.String("buffer-url"),
 	}
 
-	// Prepare data using fildeal's PrepareData utility
-	prepResult, err := dealutils.PrepareData(inputPath, outDir, bufferConfig)
-	if err != nil {
-		return fmt.Errorf("failed to prepare data: %w", err)
+	// With --replicas the providers are picked first, so the data is prepared for them
+	if replicas > 0 {
+		providers, err = selectReplicaProviders(w, cfg, replicas, common.HexToAddress(paymentToken), c.Int64("term-min"), c.Uint64("piece-size"))
+		if err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("Data prepared successfully!\n")
-	fmt.Printf("   Piece CID: %s\n", prepResult.PieceCid)
-	fmt.Printf("   Piece Size: %d bytes\n", prepResult.PieceSize)
-	fmt.Printf("   Payload CID: %s\n", prepResult.PayloadCid)
-	fmt.Printf("   CAR Size: %d bytes\n", prepResult.CarSize)
-	fmt.Printf("   CAR Path: %s\n", prepResult.LocalPath)
-	if prepResult.BufferInfo.URL != "" {
-		fmt.Printf("   Buffer URL: %s\n", prepResult.BufferInfo.URL)
+	// Check the providers' piece size limits before preparing data
+	minPieceSize, maxPieceSize, err := providerPieceSizeLimits(cfg, providers)
+	if err != nil {
+		return err
+	}
+	if m := c.Uint64("max-piece-size"); m != 0 && (maxPieceSize == 0 || m < maxPieceSize) {
+		maxPieceSize = m
 	}
 
-	// Convert CID string to bytes
-	cidObj, err := cid.Decode(prepResult.PieceCid)
-	if err != nil {
-		return fmt.Errorf("failed to decode piece CID: %w", err)
+	var split bool
+	aggregate := c.Bool("aggregate")
+	if inputPath != "" {
+		dataSize, err := utils.DataSize(inputPath)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		split = !aggregate && maxPieceSize != 0 && dataSize > uint64(utils.MaxChunkPayload(maxPieceSize))
+		if split && c.String("download-url") != "" {
+			return fmt.Errorf("--download-url cannot be used when the input is split into several pieces")
+		}
+
+		fmt.Fprintf(w, "Preparing data from: %s\n", inputPath)
 	}
 
-	// Determine download URL
-	downloadURL := c.String("download-url")
-	if downloadURL == "" && prepResult.BufferInfo.URL != "" {
-		downloadURL = prepResult.BufferInfo.URL
+	// The prepared pieces are shared by all replicas
+	var pieces []types.PreparedPiece
+	if carPath != "" {
+		piece, err := prepareCarPiece(w, carPath, bufferConfig, c.String("download-url"), pieceCid, c.Uint64("piece-size"), minPieceSize, maxPieceSize)
+		if err != nil {
+			return fmt.Errorf("invalid CAR file: %w", err)
+		}
+		pieces = []types.PreparedPiece{*piece}
+
+		fmt.Fprintf(w, "CAR file validated, skipping data preparation\n")
+		fmt.Fprintf(w, "   Piece CID: %s\n", piece.PieceCid)
+		fmt.Fprintf(w, "   Piece Size: %d bytes\n", piece.PieceSize)
+		if piece.PayloadCid != "" {
+			fmt.Fprintf(w, "   Payload CID: %s\n", piece.PayloadCid)
+		}
+		fmt.Fprintf(w, "   CAR Size: %d bytes\n", piece.CarSize)
+		fmt.Fprintf(w, "   CAR Path: %s\n", piece.CarPath)
+	} else if external {
+		piece, err := prepareExternalPiece(pieceCid, c.Uint64("piece-size"), c.Uint64("car-size"), c.String("download-url"), minPieceSize, maxPieceSize)
+		if err != nil {
+			return fmt.Errorf("invalid piece: %w", err)
+		}
+		if curioUpload && piece.CarSize == 0 {
+			return fmt.Errorf("--curio-upload needs the CAR size of a v1 piece CID (use --car-size or a v2 piece CID)")
+		}
+		pieces = []types.PreparedPiece{*piece}
+
+		fmt.Fprintf(w, "Using pre-built piece, skipping data preparation\n")
+		fmt.Fprintf(w, "   Piece CID: %s\n", piece.PieceCid)
+		fmt.Fprintf(w, "   Piece Size: %d bytes\n", piece.PieceSize)
+		if piece.CarSize != 0 {
+			fmt.Fprintf(w, "   CAR Size: %d bytes\n", piece.CarSize)
+		}
+	} else if aggregate {
+		piece, err := prepareAggregatePiece(w, inputPath, outDir, bufferConfig, minPieceSize, maxPieceSize)
+		if err != nil {
+			return fmt.Errorf("failed to prepare data: %w", err)
+		}
+		if downloadURL := c.String("download-url"); downloadURL != "" {
+			piece.DownloadURL = downloadURL
+		}
+		pieces = []types.PreparedPiece{*piece}
+
+		fmt.Fprintf(w, "Data prepared successfully! (%d inputs aggregated)\n", len(piece.SubPieces))
+		fmt.Fprintf(w, "   Piece CID: %s\n", piece.PieceCid)
+		fmt.Fprintf(w, "   Piece Size: %d bytes\n", piece.PieceSize)
+		fmt.Fprintf(w, "   Aggregate Size: %d bytes\n", piece.CarSize)
+		fmt.Fprintf(w, "   Aggregate Path: %s\n", piece.CarPath)
+		for _, sub := range piece.SubPieces {
+			fmt.Fprintf(w, "   + %s: %s (%d bytes at offset %d)\n", sub.Source, sub.PieceCid, sub.PieceSize, sub.Offset)
+		}
+	} else if split {
+		pieces, err = prepareSplitPieces(w, inputPath, outDir, bufferConfig, utils.MaxChunkPayload(maxPieceSize), minPieceSize)
+		if err != nil {
+			return fmt.Errorf("failed to prepare data: %w", err)
+		}
+
+		fmt.Fprintf(w, "Data prepared successfully! (%d pieces)\n", len(pieces))
+		for i, piece := range pieces {
+			fmt.Fprintf(w, "   Piece %d: %s (%d bytes, CAR %d bytes)\n", i+1, piece.PieceCid, piece.PieceSize, piece.CarSize)
+		}
+
+	} else {
+		// Prepare data using fildeal's PrepareData utility
+		prepResult, err := dealutils.PrepareData(inputPath, outDir, bufferConfig)
+		if err != nil {
+			return fmt.Errorf("failed to prepare data: %w", err)
+		}
+
+		fmt.Fprintf(w, "Data prepared successfully!\n")
+		fmt.Fprintf(w, "   Piece CID: %s\n", prepResult.PieceCid)
+		fmt.Fprintf(w, "   Piece Size: %d bytes\n", prepResult.PieceSize)
+		fmt.Fprintf(w, "   Payload CID: %s\n", prepResult.PayloadCid)
+		fmt.Fprintf(w, "   CAR Size: %d bytes\n", prepResult.CarSize)
+		fmt.Fprintf(w, "   CAR Path: %s\n", prepResult.LocalPath)
+		if prepResult.BufferInfo.URL != "" {
+			fmt.Fprintf(w, "   Buffer URL: %s\n", prepResult.BufferInfo.URL)
+		}
+
+		// Determine download URL
+		downloadURL := c.String("download-url")
+		if downloadURL == "" && prepResult.BufferInfo.URL != "" {
+			downloadURL = prepResult.BufferInfo.URL
+		}
+
+		pieces = []types.PreparedPiece{{
+			PieceCid:    prepResult.PieceCid,
+			PieceSize:   prepResult.PieceSize,
+			PayloadCid:  prepResult.PayloadCid,
+			CarSize:     prepResult.CarSize,
+			CarPath:     prepResult.LocalPath,
+			DownloadURL: downloadURL,
+		}}
+	}
+
+	// Save the file-to-piece manifest, or the aggregate's sub-piece index
+	if split || aggregate {
+		manifestPath := c.String("manifest")
+		if manifestPath == "" {
+			manifestPath = "manifest.json"
+			if !useTempDir {
+				manifestPath = filepath.Join(outDir, manifestPath)
+			}
+		}
+		if err := utils.WritePieceManifest(manifestPath, &types.PieceManifest{Input: inputPath, Pieces: pieces}); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "   Manifest: %s\n", manifestPath)
 	}
 
-	// Create PieceInfo from prepared data
-	pieceInfo := types.PieceInfo{
-		PieceCid:            cidObj.Bytes(),
-		Size:                prepResult.PieceSize,
-		Provider:            c.Uint64("provider"),
-		TermMin:             c.Int64("term-min"),
-		TermMax:             c.Int64("term-max"),
-		ExpirationOffset:    c.Int64("expiration-offset"),
-		DownloadURL:         downloadURL,
-		PaymentTokenAddress: common.HexToAddress(paymentToken),
+	// Create PieceInfos from prepared data; the provider is filled in per replica
+	pieceInfos := make([]types.PieceInfo, 0, len(pieces))
+	for _, piece := range pieces {
+		// Convert CID string to bytes
+		cidObj, err := cid.Decode(piece.PieceCid)
+		if err != nil {
+			return fmt.Errorf("failed to decode piece CID: %w", err)
+		}
+		pieceInfos = append(pieceInfos, types.PieceInfo{
+			PieceCid:            cidObj.Bytes(),
+			Size:                piece.PieceSize,
+			TermMin:             c.Int64("term-min"),
+			TermMax:             c.Int64("term-max"),
+			ExpirationOffset:    c.Int64("expiration-offset"),
+			DownloadURL:         piece.DownloadURL,
+			PaymentTokenAddress: common.HexToAddress(paymentToken),
+		})
 	}
 
 	// Get user address from private key
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey, "0x"))
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
 	if err != nil {
 		return fmt.Errorf("failed to parse private key: %v", err)
 	}
 	userAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
 
 	// Display allocation information
-	fmt.Printf("\nAllocation Creation Summary:\n")
-	fmt.Printf("   Client Address: %s\n", userAddress.Hex())
-	fmt.Printf("   DDO Contract: %s\n", config.ContractAddress)
-	fmt.Printf("   Payments Contract: %s\n", config.PaymentsContractAddress)
-	fmt.Printf("   RPC: %s\n", config.RPCEndpoint)
-	if curioEnabled {
-		fmt.Printf("   Curio API: %s\n", curioAPI)
+	fmt.Fprintf(w, "\nAllocation Creation Summary:\n")
+	fmt.Fprintf(w, "   Client Address: %s\n", userAddress.Hex())
+	fmt.Fprintf(w, "   DDO Contract: %s\n", cfg.ContractAddress)
+	fmt.Fprintf(w, "   Payments Contract: %s\n", cfg.PaymentsContractAddress)
+	fmt.Fprintf(w, "   RPC: %s\n", cfg.RPCEndpoint)
+	if curioAPI != "" {
+		fmt.Fprintf(w, "   Curio API: %s\n", curioAPI)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// Display piece information
-	fmt.Printf("Prepared Piece:\n")
-	fmt.Printf("   Provider: %d\n", pieceInfo.Provider)
-	fmt.Printf("   Size: %d bytes\n", pieceInfo.Size)
-	fmt.Printf("   Payment Token: %s\n", pieceInfo.PaymentTokenAddress.Hex())
-	if pieceInfo.DownloadURL != "" {
-		fmt.Printf("   Download URL: %s\n", pieceInfo.DownloadURL)
+	if len(pieceInfos) == 1 {
+		fmt.Fprintf(w, "Prepared Piece:\n")
+	} else {
+		fmt.Fprintf(w, "Prepared Pieces: %d\n", len(pieceInfos))
+	}
+	if len(providers) == 1 {
+		fmt.Fprintf(w, "   Provider: %d\n", providers[0])
+	} else {
+		fmt.Fprintf(w, "   Providers: %v (%d replicas)\n", providers, len(providers))
+	}
+	var totalSize uint64
+	for _, p := range pieceInfos {
+		totalSize += p.Size
 	}
-	fmt.Println()
+	fmt.Fprintf(w, "   Size: %d bytes\n", totalSize)
+	fmt.Fprintf(w, "   Payment Token: %s\n", common.HexToAddress(paymentToken).Hex())
+	if len(pieceInfos) == 1 && pieceInfos[0].DownloadURL != "" {
+		fmt.Fprintf(w, "   Download URL: %s\n", pieceInfos[0].DownloadURL)
+	}
+	fmt.Fprintln(w)
 
 	if c.Bool("dry-run") {
-		fmt.Printf("Dry run completed - no transactions sent\n")
+		fmt.Fprintf(w, "Dry run completed - no transactions sent\n")
 		return nil
 	}
 
 	// Create eth client for monitoring
-	ethClient, err := ethclient.Dial(config.RPCEndpoint)
+	ethClient, err := rpcclient.Dial(cfg.RPCEndpoint, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create eth client: %v", err)
 	}
@@ -312,282 +467,119 @@ func executeCreateFromFile(c *cli.Context) error {
 		return fmt.Errorf("failed to create transactor: %v", err)
 	}
 
+	// Both clients send with one manager, so that their nonces do not collide
+	txm := txmgr.New(ethClient, cfg.Tx)
+
 	// Create DDO contract client
-	ddoClient, err := ddo.NewClientWithTransactor(ethClient, config.ContractAddress, auth)
+	ddoClient, err := ddo.NewClientWithTransactor(ethClient, cfg.ContractAddress, auth, txm)
 	if err != nil {
 		return fmt.Errorf("failed to create DDO contract client: %v", err)
 	}
 
-	pieceInfos := []types.PieceInfo{pieceInfo}
-
-	// Calculate storage costs
-	fmt.Printf("Calculating storage costs...\n")
-	costResult, err := utils.CalculateStorageCosts(ddoClient, pieceInfos)
-	if err != nil {
-		return fmt.Errorf("failed to calculate storage costs: %v", err)
-	}
-
-	fmt.Printf("Cost Analysis:\n")
-	fmt.Printf("   Total Storage Cost: %s\n", costResult.TotalCost.String())
-	fmt.Printf("   Price: %s\n", utils.FormatPriceBothFormats(costResult.PricePerBytePerEpoch))
-	fmt.Printf("   Total Bytes: %d\n", costResult.TotalBytes)
-	fmt.Printf("   Total Epochs: %d\n", costResult.TotalEpochs)
-	fmt.Printf("   User Address: %s\n", userAddress.Hex())
-	fmt.Println()
-
-	// If dry run, just show costs and requirements
-	if c.Bool("dry-run") {
-		// Calculate total DataCap needed
-		totalDataCap := utils.CalculateTotalDataCap(pieceInfos)
-
-		// Calculate one month allowance for operator approval
-		oneMonthCost := new(big.Int).Mul(
-			new(big.Int).SetUint64(costResult.TotalBytes),
-			costResult.PricePerBytePerEpoch,
-		)
-		oneMonthCost.Mul(oneMonthCost, big.NewInt(utils.EPOCHS_PER_MONTH))
-
-		fmt.Printf("Dry Run Results:\n\n")
-
-		fmt.Printf("DataCap Requirements:\n")
-		fmt.Printf("   Total DataCap Needed: %s (%s)\n", totalDataCap.String(), utils.FormatBytes(totalDataCap))
-		fmt.Printf("   Number of Pieces: %d\n", len(pieceInfos))
-		fmt.Println()
-
-		fmt.Printf("Payment Requirements:\n")
-		fmt.Printf("   Token: %s\n", pieceInfo.PaymentTokenAddress.Hex())
-		fmt.Printf("   Total Storage Cost: %s\n", costResult.TotalCost.String())
-		fmt.Printf("   Price: %s\n", utils.FormatPriceBothFormats(costResult.PricePerBytePerEpoch))
-		fmt.Printf("   Required Operator Allowance: %s\n", oneMonthCost.String())
-		fmt.Printf("   Total Term Length: %d epochs\n", costResult.TotalEpochs)
-		fmt.Println()
-
-		fmt.Printf("Account Information:\n")
-		fmt.Printf("   User Address: %s\n", userAddress.Hex())
-		fmt.Printf("   DDO Contract: %s\n", config.ContractAddress)
-		fmt.Printf("   Payments Contract: %s\n", config.PaymentsContractAddress)
-		fmt.Println()
-
-		fmt.Printf("Next Steps:\n")
-		fmt.Printf("1. Ensure you have enough tokens in your wallet\n")
-		fmt.Printf("2. Approve the payments contract to spend your tokens (if ERC20)\n")
-		fmt.Printf("3. Run without --dry-run to execute\n")
-		return nil
-	}
-
 	// Create payments client
-	paymentsClient, err := payments.NewClientWithTransactor(ethClient, config.PaymentsContractAddress, auth)
+	paymentsClient, err := payments.NewClientWithTransactor(ethClient, cfg.PaymentsContractAddress, auth, txm)
 	if err != nil {
 		return fmt.Errorf("failed to create payments contract client: %v", err)
 	}
 
-	// Setup payments if not skipped
-	if !c.Bool("skip-payment-setup") {
-		fmt.Printf("Setting up payments...\n")
-
-		contractAddress := common.HexToAddress(config.ContractAddress)
-		err := utils.CheckAndSetupPayments(
-			ethClient,
-			ddoClient,
-			paymentsClient,
-			pieceInfos,
-			userAddress,
-			contractAddress,
-			auth,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to setup payments: %v", err)
-		}
-
-		fmt.Printf("Payment setup completed!\n\n")
-	} else {
-		fmt.Printf("Skipping payment setup - ensure payments are configured manually\n")
-	}
-
-	// Execute the transaction
-	fmt.Printf("Creating allocation request...\n")
-	fmt.Printf("DDO Contract: %s\n", config.ContractAddress)
-	fmt.Printf("Payments Contract: %s\n", config.PaymentsContractAddress)
-	fmt.Printf("RPC: %s\n", config.RPCEndpoint)
-
-	txHash, err := ddoClient.CreateAllocationRequests(pieceInfos)
-	if err != nil {
-		return fmt.Errorf("failed to create allocation requests: %v", err)
+	job := &replicationJob{
+		cfg:            cfg,
+		ddoClient:      ddoClient,
+		paymentsClient: paymentsClient,
+		auth:           auth,
+		privateKey:     privateKey,
+		userAddress:    userAddress,
+		pieceInfos:     pieceInfos,
+		pieces:         pieces,
+		curioUpload:    curioUpload,
+		curioAPI:       curioAPI,
 	}
 
-	fmt.Printf("Transaction successful!\n")
-	fmt.Printf("Transaction Hash: %s\n", txHash)
-
-	// Wait for transaction to be mined and get receipt
-	fmt.Printf("Waiting for allocation creation transaction to be mined...\n")
-	receipt, err := utils.WaitForTransactionWithReceipt(ethClient, txHash)
-	if err != nil {
-		fmt.Printf("Warning: could not get transaction receipt: %v\n", err)
-	} else {
-		fmt.Printf("Allocation creation transaction mined successfully!\n")
+	if len(providers) == 1 {
+		result, err := createReplica(c, job, providers[0])
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if renderErr := output.Render(c, []types.ReplicaResult{result}, nil); renderErr != nil {
+			return renderErr
+		}
+		return err
 	}
 
-	// Submit deal to Curio MK20 if enabled
-	if curioEnabled && receipt != nil {
-		fmt.Printf("\nSubmitting deal to Curio MK20...\n")
-		if err := submitToCurio(c, privateKey, userAddress, cidObj, prepResult.CarSize, prepResult.LocalPath, receipt, curioAPI); err != nil {
-			return fmt.Errorf("failed to submit deal to Curio: %v", err)
+	results := make([]types.ReplicaResult, 0, len(providers))
+	for i, providerID := range providers {
+		fmt.Fprintf(w, "\n📦 Replica %d/%d: provider %d\n", i+1, len(providers), providerID)
+		result, err := createReplica(c, job, providerID)
+		if err != nil {
+			fmt.Fprintf(w, "❌ Replica for provider %d failed: %v\n", providerID, err)
+			result.Error = err.Error()
 		}
-	} else if curioEnabled && receipt == nil {
-		fmt.Printf("Warning: skipping Curio submission - no transaction receipt available\n")
+		results = append(results, result)
 	}
 
-	// Clean up temp dir after Curio upload if needed
-	if useTempDir && curioEnabled {
-		os.RemoveAll(outDir)
+	if err := output.Render(c, results, nil); err != nil {
+		return err
 	}
-
-	return nil
+	return printReplicaSummary(w, results)
 }
 
 // submitToCurio handles the Curio MK20 deal submission and CAR file upload.
+// It returns the IDs of the deals submitted, in allocation order.
 func submitToCurio(
 	c *cli.Context,
+	cfg config.Config,
 	privateKey *ecdsa.PrivateKey,
 	userAddress common.Address,
-	pieceCidV1 cid.Cid,
-	carSize uint64,
-	carFilePath string,
-	receipt *ethtypes.Receipt,
+	pieces []types.PreparedPiece,
+	providerID uint64,
+	allocationIDs []uint64,
 	curioAPI string,
-) error {
-	ctx := context.Background()
-
-	// Parse AllocationCreated events from receipt
-	allocationIDs, err := ddo.ParseAllocationCreatedEvents(receipt)
-	if err != nil {
-		return fmt.Errorf("failed to parse allocation events: %w", err)
-	}
-	fmt.Printf("   Found %d allocation(s): %v\n", len(allocationIDs), allocationIDs)
-
-	// Convert piece CID V1 -> V2
-	pieceCidV2, err := cidconv.PieceCidV2FromV1(pieceCidV1, carSize)
-	if err != nil {
-		return fmt.Errorf("failed to convert piece CID to V2: %w", err)
-	}
-	fmt.Printf("   Piece CID V2: %s\n", pieceCidV2.String())
-
-	// Derive Filecoin addresses
-	userFilAddr, err := curio.EthToFilecoinDelegated(userAddress)
-	if err != nil {
-		return fmt.Errorf("failed to derive user Filecoin address: %w", err)
-	}
-	fmt.Printf("   User Filecoin Address: %s\n", userFilAddr.String())
-
-	ddoContractAddr := common.HexToAddress(config.ContractAddress)
-	ddoFilAddr, err := curio.EthToFilecoinDelegated(ddoContractAddr)
-	if err != nil {
-		return fmt.Errorf("failed to derive DDO contract Filecoin address: %w", err)
-	}
-	fmt.Printf("   DDO Contract Filecoin Address: %s\n", ddoFilAddr.String())
+) ([]string, error) {
+	w := output.Progress(c)
 
 	// Determine provider Filecoin address
 	providerFilAddr := c.String("provider-fil-addr")
 	if providerFilAddr == "" {
-		providerID := c.Uint64("provider")
 		addr, err := curio.ProviderIDToFilecoinAddr(providerID)
 		if err != nil {
-			return fmt.Errorf("failed to derive provider Filecoin address: %w", err)
+			return nil, fmt.Errorf("failed to derive provider Filecoin address: %w", err)
 		}
 		providerFilAddr = addr.String()
 	}
-	fmt.Printf("   Provider Filecoin Address: %s\n", providerFilAddr)
+	fmt.Fprintf(w, "   Provider Filecoin Address: %s\n", providerFilAddr)
 
 	// Determine contract address for verification
-	contractVerifyAddr := config.ContractAddress
+	contractVerifyAddr := cfg.ContractAddress
 	if c.Bool("skip-contract-verify") {
 		contractVerifyAddr = "0xtest"
 	}
 
-	// Create Curio client
-	curioClient := curio.NewClient(curioAPI, privateKey)
-
-	fmt.Printf("   CAR file: %s\n", carFilePath)
-
-	// ABI-encode the verify method params type for reuse
-	uint64Ty, _ := eabi.NewType("uint64", "", nil)
-	verifyArgs := eabi.Arguments{{Type: uint64Ty}}
-
-	// Submit a deal for each allocation
-	for _, allocID := range allocationIDs {
-		fmt.Printf("\n   Submitting deal for allocation %d...\n", allocID)
-
-		// Generate ULID
-		entropy := rand.New(rand.NewSource(time.Now().UnixNano()))
-		dealID := ulid.MustNew(ulid.Timestamp(time.Now()), entropy)
-
-		// Build notification payload (CBOR-encoded allocation ID)
-		notifPayload := curio.CborEncodeUint64(allocID)
-
-		// ABI-encode allocation ID as (uint64) for contract verification
-		verifyParams, err := verifyArgs.Pack(allocID)
-		if err != nil {
-			return fmt.Errorf("failed to ABI-encode verify params for allocation %d: %w", allocID, err)
-		}
-
-		// Build deal — client is the DDO Diamond contract (on-chain allocation owner)
-		deal := &curio.Deal{
-			Identifier: dealID,
-			Client:     ddoFilAddr.String(),
-			Data: &curio.DataSource{
-				PieceCID: pieceCidV2,
-				Format: curio.PieceDataFormat{
-					Car: &curio.FormatCar{},
-				},
-				SourceHttpPut: &curio.DataSourcePut{},
-			},
-			Products: curio.Products{
-				DDOV1: &curio.DDOV1{
-					Provider:                   providerFilAddr,
-					PieceManager:               userFilAddr.String(),
-					Duration:                   518400,
-					AllocationId:               &allocID,
-					ContractAddress:            contractVerifyAddr,
-					ContractVerifyMethod:       "getDealId",
-					ContractVerifyMethodParams: verifyParams,
-					NotificationAddress:        ddoFilAddr.String(),
-					NotificationPayload:        notifPayload,
-				},
-				RetrievalV1: &curio.RetrievalV1{
-					Indexing: true,
-				},
-			},
-		}
-
-		// POST /store
-		fmt.Printf("   Storing deal %s...\n", dealID.String())
-		if err := curioClient.Store(ctx, deal); err != nil {
-			return fmt.Errorf("failed to store deal %s: %w", dealID.String(), err)
-		}
-		fmt.Printf("   Deal stored successfully!\n")
-
-		// Upload CAR file
-		fmt.Printf("   Uploading CAR file...\n")
-		carFile, err := os.Open(carFilePath)
-		if err != nil {
-			return fmt.Errorf("failed to open CAR file: %w", err)
-		}
-
-		if err := curioClient.UploadSerial(ctx, dealID, carFile); err != nil {
-			carFile.Close()
-			return fmt.Errorf("failed to upload CAR file: %w", err)
-		}
-		carFile.Close()
-		fmt.Printf("   CAR file uploaded successfully!\n")
-
-		// Finalize upload
-		fmt.Printf("   Finalizing upload...\n")
-		if err := curioClient.UploadSerialFinalize(ctx, dealID); err != nil {
-			return fmt.Errorf("failed to finalize upload: %w", err)
-		}
-		fmt.Printf("   Upload finalized! Deal ID: %s\n", dealID.String())
+	// Client is the DDO Diamond contract (on-chain allocation owner)
+	params := curio.DDODealParams{
+		Client:         userAddress,
+		DDOContract:    common.HexToAddress(cfg.ContractAddress),
+		VerifyContract: contractVerifyAddr,
+		Provider:       providerFilAddr,
+	}
+	ids, err := curio.NewClient(curioAPI, privateKey).SubmitDDODeals(context.Background(), params, pieces, allocationIDs,
+		func(piece types.PreparedPiece, deal *curio.Deal) {
+			fmt.Fprintf(w, "\n   Submitting deal for allocation %d...\n", *deal.Products.DDOV1.AllocationId)
+			// Pieces prepared elsewhere have no local CAR; Curio fetches them from their URL
+			if piece.CarPath != "" {
+				fmt.Fprintf(w, "   CAR file: %s\n", piece.CarPath)
+			} else {
+				fmt.Fprintf(w, "   Download URL: %s\n", piece.DownloadURL)
+			}
+			fmt.Fprintf(w, "   Storing deal %s...\n", deal.Identifier.String())
+		})
+	dealIDs := make([]string, len(ids))
+	for i, id := range ids {
+		dealIDs[i] = id.String()
+	}
+	if err != nil {
+		return dealIDs, err
 	}
 
-	fmt.Printf("\nCurio MK20 deal submission completed!\n")
-	return nil
+	fmt.Fprintf(w, "\nCurio MK20 deal submission completed! Deal IDs: %v\n", dealIDs)
+	return dealIDs, nil
 }
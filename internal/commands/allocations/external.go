@@ -0,0 +1,92 @@
+package allocations
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/eastore-project/fildeal/src/buffer"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// prepareCarPiece validates an existing CAR file and computes its piece CID, padded up to
+// the larger of pieceSize and minPieceSize. When expectedPieceCid is set the result must
+// match it. Without a downloadURL the CAR is stored in the buffer.
+func prepareCarPiece(w io.Writer, carPath string, bufferConfig *buffer.Config, downloadURL, expectedPieceCid string, pieceSize, minPieceSize, maxPieceSize uint64) (*types.PreparedPiece, error) {
+	roots, err := utils.ReadCarRoots(carPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(w, "Computing piece CID of %s...\n", carPath)
+	pieceCid, naturalSize, carSize, err := utils.CarPieceCommitment(carPath)
+	if err != nil {
+		return nil, err
+	}
+
+	piece := &types.PreparedPiece{
+		PieceCid:  pieceCid,
+		PieceSize: naturalSize,
+		CarSize:   carSize,
+		CarPath:   carPath,
+	}
+	if len(roots) > 0 {
+		piece.PayloadCid = roots[0].String()
+	}
+
+	// The expected piece CID is the CAR's own, before any padding
+	if expectedPieceCid != "" {
+		expected, payloadSize, err := utils.ParsePieceCid(expectedPieceCid)
+		if err != nil {
+			return nil, err
+		}
+		if expected.String() != pieceCid {
+			return nil, fmt.Errorf("piece CID of %s is %s, not %s", carPath, pieceCid, expectedPieceCid)
+		}
+		if payloadSize != 0 && payloadSize != carSize {
+			return nil, fmt.Errorf("%s is %d bytes, but piece CID %s is for %d bytes", carPath, carSize, expectedPieceCid, payloadSize)
+		}
+	}
+
+	if pieceSize != 0 && pieceSize < naturalSize {
+		return nil, fmt.Errorf("--piece-size %d is smaller than the CAR's %d byte piece", pieceSize, naturalSize)
+	}
+	target := max(pieceSize, minPieceSize)
+	if target > naturalSize {
+		piece.PieceCid, err = utils.PadPieceCid(pieceCid, naturalSize, target)
+		if err != nil {
+			return nil, err
+		}
+		piece.PieceSize = target
+	}
+	if maxPieceSize != 0 && piece.PieceSize > maxPieceSize {
+		return nil, fmt.Errorf("CAR needs a %d byte piece, above the maximum of %d; existing CARs cannot be split", piece.PieceSize, maxPieceSize)
+	}
+
+	piece.DownloadURL = downloadURL
+	if downloadURL == "" {
+		bufferResp, err := newBuffer(bufferConfig).Store(carPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store CAR in buffer: %w", err)
+		}
+		piece.DownloadURL = bufferResp.URL
+	}
+	return piece, nil
+}
+
+// prepareExternalPiece validates a piece CID, size and URL prepared elsewhere against the
+// providers' piece size limits
+func prepareExternalPiece(pieceCid string, pieceSize, carSize uint64, downloadURL string, minPieceSize, maxPieceSize uint64) (*types.PreparedPiece, error) {
+	piece, err := utils.ExternalPiece(pieceCid, pieceSize, carSize, downloadURL)
+	if err != nil {
+		return nil, err
+	}
+	if piece.PieceSize < minPieceSize {
+		return nil, fmt.Errorf("piece size %d is below the providers' minimum of %d", piece.PieceSize, minPieceSize)
+	}
+	if maxPieceSize != 0 && piece.PieceSize > maxPieceSize {
+		return nil, fmt.Errorf("piece size %d is above the maximum of %d", piece.PieceSize, maxPieceSize)
+	}
+	return piece, nil
+}
@@ -11,6 +11,7 @@ func AllocationsCommand() *cli.Command {
 		Usage:   "Allocation management commands",
 		Subcommands: []*cli.Command{
 			QueryCommand(),
+			QuoteCommand(),
 			CreateFromFileCommand(),
 			QueryClaimInfoCommand(),
 		},
@@ -2,13 +2,17 @@ package allocations
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
 func QueryCommand() *cli.Command {
@@ -17,16 +21,8 @@ func QueryCommand() *cli.Command {
 		Aliases: []string{"q"},
 		Usage:   "Query allocation IDs for a client address or provider ID",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
 			&cli.StringFlag{
 				Name:    "client-address",
 				Aliases: []string{"a"},
@@ -46,26 +42,24 @@ func QueryCommand() *cli.Command {
 				Name:  "count-only",
 				Usage: "Only show the count of allocations, not the full list",
 			},
+			&cli.BoolFlag{
+				Name:  "details",
+				Usage: "Also fetch the state and payment rail of each listed allocation (batched)",
+			},
 		},
-		Action: executeQuery,
+		Action: config.Action(executeQuery),
 	}
 }
 
-func executeQuery(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
+func executeQuery(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration (only contract and RPC needed for read operations)
 	missing := []string{}
-	if config.ContractAddress == "" {
+	if cfg.ContractAddress == "" {
 		missing = append(missing, "DDO_CONTRACT_ADDRESS")
 	}
-	if config.RPCEndpoint == "" {
+	if cfg.RPCEndpoint == "" {
 		missing = append(missing, "RPC_URL")
 	}
 
@@ -77,6 +71,7 @@ func executeQuery(c *cli.Context) error {
 	providerId := c.Uint64("provider-id")
 	allocationId := c.Uint64("allocation-id")
 	countOnly := c.Bool("count-only")
+	withDetails := c.Bool("details") && !countOnly
 
 	// Validate input: need either client address, provider ID, or allocation ID
 	if clientAddress == "" && providerId == 0 && allocationId == 0 {
@@ -89,19 +84,19 @@ func executeQuery(c *cli.Context) error {
 		return fmt.Errorf("can only specify one of --client-address, --provider-id, or --allocation-id")
 	}
 
-	fmt.Printf("Contract: %s\n", config.ContractAddress)
-	fmt.Printf("RPC: %s\n", config.RPCEndpoint)
-	fmt.Println()
+	fmt.Fprintf(w, "Contract: %s\n", cfg.ContractAddress)
+	fmt.Fprintf(w, "RPC: %s\n", cfg.RPCEndpoint)
+	fmt.Fprintln(w)
 
 	// Create contract client (read-only, no private key needed)
-	client, err := ddo.NewReadOnlyClientWithParams(config.RPCEndpoint, config.ContractAddress)
+	client, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create contract client: %v", err)
 	}
 
 	if allocationId != 0 {
 		// Query specific allocation details
-		fmt.Printf("Querying allocation details for ID: %d\n\n", allocationId)
+		fmt.Fprintf(w, "Querying allocation details for ID: %d\n\n", allocationId)
 
 		// Get allocation info (sectorNumber, activated, etc.)
 		allocInfo, err := client.GetAllocationInfo(allocationId)
@@ -109,95 +104,150 @@ func executeQuery(c *cli.Context) error {
 			return fmt.Errorf("failed to get allocation info: %v", err)
 		}
 
+		details := types.AllocationDetails{AllocationId: allocationId}
 		if allocInfo.Client == (common.Address{}) {
-			fmt.Printf("Allocation %d not found\n", allocationId)
-			return nil
+			return output.Render(c, details, func() {
+				fmt.Fprintf(w, "Allocation %d not found\n", allocationId)
+			})
 		}
 
-		fmt.Printf("Allocation Info:\n")
-		fmt.Printf("   Client: %s\n", allocInfo.Client.Hex())
-		fmt.Printf("   Provider: %d\n", allocInfo.Provider)
-		fmt.Printf("   Activated: %v\n", allocInfo.Activated)
-		fmt.Printf("   Payment Token: %s\n", allocInfo.PaymentToken.Hex())
-		fmt.Printf("   Piece Size: %d bytes\n", allocInfo.PieceSize)
-		fmt.Printf("   Price Per Byte Per Epoch: %s\n", allocInfo.PricePerBytePerEpoch.String())
-		if allocInfo.Activated {
-			fmt.Printf("   Sector Number: %d\n", allocInfo.SectorNumber)
-		} else {
-			fmt.Printf("   Sector Number: pending (not yet activated)\n")
-		}
-		fmt.Printf("   Rail ID: %s\n", allocInfo.RailId.String())
-		fmt.Println()
-
 		// Get rail info
-		railId, providerId, railView, err := client.GetAllocationRailInfo(allocationId)
+		_, _, railView, err := client.GetAllocationRailInfo(allocationId)
 		if err != nil {
 			return fmt.Errorf("failed to get allocation rail info: %v", err)
 		}
+		details = utils.NewAllocationDetails(allocationId, allocInfo, railView)
 
-		_ = providerId // already shown from allocInfo
-		_ = railId     // already shown from allocInfo
-
-		if railView != nil {
-			fmt.Printf("Rail Information:\n")
-			fmt.Printf("   Token: %s\n", railView.Token.Hex())
-			fmt.Printf("   From (Payer): %s\n", railView.From.Hex())
-			fmt.Printf("   To (Payee): %s\n", railView.To.Hex())
-			fmt.Printf("   Operator: %s\n", railView.Operator.Hex())
-			fmt.Printf("   Validator: %s\n", railView.Validator.Hex())
-			fmt.Printf("   Payment Rate: %s per epoch\n", railView.PaymentRate.String())
-			fmt.Printf("   Lockup Period: %s epochs\n", railView.LockupPeriod.String())
-			fmt.Printf("   Lockup Fixed: %s\n", railView.LockupFixed.String())
-			fmt.Printf("   Settled Up To: epoch %s\n", railView.SettledUpTo.String())
-			fmt.Printf("   End Epoch: %s\n", railView.EndEpoch.String())
-			fmt.Printf("   Commission Rate BPS: %s\n", railView.CommissionRateBps.String())
-			fmt.Printf("   Service Fee Recipient: %s\n", railView.ServiceFeeRecipient.Hex())
-		}
+		return output.Render(c, details, func() { printAllocationDetails(w, details) })
+	}
 
-	} else if clientAddress != "" {
+	var list types.AllocationList
+	if clientAddress != "" {
 		// Query allocations for client
-		fmt.Printf("🔍 Querying allocations for client: %s\n", clientAddress)
+		fmt.Fprintf(w, "🔍 Querying allocations for client: %s\n", clientAddress)
 
 		allocationIds, err := client.GetAllocationIdsForClient(clientAddress)
 		if err != nil {
 			return fmt.Errorf("failed to get allocation IDs: %v", err)
 		}
 
-		fmt.Printf("Total allocations: %d\n", len(allocationIds))
-
-		if len(allocationIds) == 0 {
-			fmt.Printf("No allocations found for this client.\n")
-		} else if !countOnly {
-			fmt.Printf("\nAllocation IDs:\n")
-			for i, id := range allocationIds {
-				fmt.Printf("  %d: %d\n", i+1, id)
+		addr := common.HexToAddress(clientAddress)
+		list = types.AllocationList{Client: &addr, Count: len(allocationIds)}
+		if !countOnly {
+			list.AllocationIds = allocationIds
+		}
+		if withDetails {
+			if list.Allocations, err = getAllocationDetails(w, client, allocationIds); err != nil {
+				return err
 			}
 		}
-	} else if providerId != 0 {
-		// Query allocations for provider
-		fmt.Printf("🔍 Querying allocations for provider: %d\n", providerId)
 
-		allocationIds, err := client.GetAllocationIdsForProvider(providerId)
-		if err != nil {
-			return fmt.Errorf("failed to get allocation IDs for provider: %v", err)
+		return output.Render(c, list, func() {
+			fmt.Fprintf(w, "Total allocations: %d\n", len(allocationIds))
+
+			if len(allocationIds) == 0 {
+				fmt.Fprintf(w, "No allocations found for this client.\n")
+			} else if withDetails {
+				printAllocationTable(w, list.Allocations)
+			} else if !countOnly {
+				fmt.Fprintf(w, "\nAllocation IDs:\n")
+				for i, id := range allocationIds {
+					fmt.Fprintf(w, "  %d: %d\n", i+1, id)
+				}
+			}
+		})
+	}
+
+	// Query allocations for provider
+	fmt.Fprintf(w, "🔍 Querying allocations for provider: %d\n", providerId)
+
+	allocationIds, err := client.GetAllocationIdsForProvider(providerId)
+	if err != nil {
+		return fmt.Errorf("failed to get allocation IDs for provider: %v", err)
+	}
+
+	list = types.AllocationList{Provider: providerId, Count: len(allocationIds)}
+	if !countOnly {
+		list.AllocationIds = allocationIds
+	}
+	if withDetails {
+		if list.Allocations, err = getAllocationDetails(w, client, allocationIds); err != nil {
+			return err
 		}
+	}
 
-		fmt.Printf("📊 Results:\n")
-		fmt.Printf("Total allocations: %d\n", len(allocationIds))
+	return output.Render(c, list, func() {
+		fmt.Fprintf(w, "📊 Results:\n")
+		fmt.Fprintf(w, "Total allocations: %d\n", len(allocationIds))
 
 		if len(allocationIds) == 0 {
-			fmt.Printf("No allocations found for this provider.\n")
+			fmt.Fprintf(w, "No allocations found for this provider.\n")
 		} else {
 			if countOnly {
-				fmt.Printf("Count: %d\n", len(allocationIds))
+				fmt.Fprintf(w, "Count: %d\n", len(allocationIds))
+			} else if withDetails {
+				printAllocationTable(w, list.Allocations)
 			} else {
-				fmt.Printf("\nAllocation IDs:\n")
+				fmt.Fprintf(w, "\nAllocation IDs:\n")
 				for i, id := range allocationIds {
-					fmt.Printf("  %d: %d\n", i+1, id)
+					fmt.Fprintf(w, "  %d: %d\n", i+1, id)
 				}
 			}
 		}
+	})
+}
+
+// getAllocationDetails fetches the state and rail of many allocations with batched calls
+func getAllocationDetails(w io.Writer, client *ddo.Client, allocationIds []uint64) ([]types.AllocationDetails, error) {
+	fmt.Fprintf(w, "📦 Fetching details of %d allocations...\n", len(allocationIds))
+	return utils.GetAllocationDetails(client, allocationIds)
+}
+
+func printAllocationTable(w io.Writer, allocations []types.AllocationDetails) {
+	fmt.Fprintf(w, "\n%-12s %-10s %-10s %-12s %-10s %-10s %-14s\n", "Allocation", "Provider", "Activated", "Piece Size", "Sector", "Rail ID", "Settled Up To")
+	fmt.Fprintf(w, "%-12s %-10s %-10s %-12s %-10s %-10s %-14s\n", "----------", "--------", "---------", "----------", "------", "-------", "-------------")
+	for _, a := range allocations {
+		sector, settledUpTo := "-", "-"
+		if a.Activated {
+			sector = fmt.Sprintf("%d", a.SectorNumber)
+		}
+		if a.Rail != nil && a.RailId.Sign() > 0 {
+			settledUpTo = a.Rail.SettledUpTo.String()
+		}
+		fmt.Fprintf(w, "%-12d %-10d %-10v %-12d %-10s %-10s %-14s\n",
+			a.AllocationId, a.Provider, a.Activated, a.PieceSize, sector, a.RailId.String(), settledUpTo)
 	}
+}
 
-	return nil
+func printAllocationDetails(w io.Writer, details types.AllocationDetails) {
+	fmt.Fprintf(w, "Allocation Info:\n")
+	fmt.Fprintf(w, "   Client: %s\n", details.Client.Hex())
+	fmt.Fprintf(w, "   Provider: %d\n", details.Provider)
+	fmt.Fprintf(w, "   Activated: %v\n", details.Activated)
+	fmt.Fprintf(w, "   Payment Token: %s\n", details.PaymentToken.Hex())
+	fmt.Fprintf(w, "   Piece Size: %d bytes\n", details.PieceSize)
+	fmt.Fprintf(w, "   Price Per Byte Per Epoch: %s\n", details.PricePerBytePerEpoch.String())
+	if details.Activated {
+		fmt.Fprintf(w, "   Sector Number: %d\n", details.SectorNumber)
+	} else {
+		fmt.Fprintf(w, "   Sector Number: pending (not yet activated)\n")
+	}
+	fmt.Fprintf(w, "   Rail ID: %s\n", details.RailId.String())
+	fmt.Fprintln(w)
+
+	if railView := details.Rail; railView != nil {
+		fmt.Fprintf(w, "Rail Information:\n")
+		fmt.Fprintf(w, "   Token: %s\n", railView.Token.Hex())
+		fmt.Fprintf(w, "   From (Payer): %s\n", railView.From.Hex())
+		fmt.Fprintf(w, "   To (Payee): %s\n", railView.To.Hex())
+		fmt.Fprintf(w, "   Operator: %s\n", railView.Operator.Hex())
+		fmt.Fprintf(w, "   Validator: %s\n", railView.Validator.Hex())
+		fmt.Fprintf(w, "   Payment Rate: %s per epoch\n", railView.PaymentRate.String())
+		fmt.Fprintf(w, "   Lockup Period: %s epochs\n", railView.LockupPeriod.String())
+		fmt.Fprintf(w, "   Lockup Fixed: %s\n", railView.LockupFixed.String())
+		fmt.Fprintf(w, "   Settled Up To: epoch %s\n", railView.SettledUpTo.String())
+		fmt.Fprintf(w, "   End Epoch: %s\n", railView.EndEpoch.String())
+		fmt.Fprintf(w, "   Commission Rate BPS: %s\n", railView.CommissionRateBps.String())
+		fmt.Fprintf(w, "   Service Fee Recipient: %s\n", railView.ServiceFeeRecipient.Hex())
+	}
 }
@@ -10,7 +10,9 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
 )
 
 func QueryClaimInfoCommand() *cli.Command {
@@ -19,16 +21,8 @@ func QueryClaimInfoCommand() *cli.Command {
 		Aliases: []string{"qci"},
 		Usage:   "Query claim information for a specific client address and claim ID",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
 			&cli.StringFlag{
 				Name:     "client-address",
 				Aliases:  []string{"a"},
@@ -43,28 +37,22 @@ func QueryClaimInfoCommand() *cli.Command {
 			},
 			&cli.BoolFlag{
 				Name:  "json",
-				Usage: "Output in JSON format",
+				Usage: "Output in JSON format (same as --output json)",
 			},
 		},
-		Action: executeQueryClaimInfo,
+		Action: config.Action(executeQueryClaimInfo),
 	}
 }
 
-func executeQueryClaimInfo(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
+func executeQueryClaimInfo(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration
 	missing := []string{}
-	if config.ContractAddress == "" {
+	if cfg.ContractAddress == "" {
 		missing = append(missing, "DDO_CONTRACT_ADDRESS")
 	}
-	if config.RPCEndpoint == "" {
+	if cfg.RPCEndpoint == "" {
 		missing = append(missing, "RPC_URL")
 	}
 
@@ -74,7 +62,6 @@ func executeQueryClaimInfo(c *cli.Context) error {
 
 	clientAddress := c.String("client-address")
 	claimIdStr := c.String("claim-id")
-	jsonOutput := c.Bool("json")
 
 	// Parse claim ID
 	claimId, err := strconv.ParseUint(claimIdStr, 10, 64)
@@ -82,17 +69,15 @@ func executeQueryClaimInfo(c *cli.Context) error {
 		return fmt.Errorf("invalid claim ID '%s': %v", claimIdStr, err)
 	}
 
-	if !jsonOutput {
-		fmt.Printf("🔍 Querying claim info for:\n")
-		fmt.Printf("Client: %s\n", clientAddress)
-		fmt.Printf("Claim ID: %d\n", claimId)
-		fmt.Printf("Contract: %s\n", config.ContractAddress)
-		fmt.Printf("RPC: %s\n", config.RPCEndpoint)
-		fmt.Println()
-	}
+	fmt.Fprintf(w, "🔍 Querying claim info for:\n")
+	fmt.Fprintf(w, "Client: %s\n", clientAddress)
+	fmt.Fprintf(w, "Claim ID: %d\n", claimId)
+	fmt.Fprintf(w, "Contract: %s\n", cfg.ContractAddress)
+	fmt.Fprintf(w, "RPC: %s\n", cfg.RPCEndpoint)
+	fmt.Fprintln(w)
 
 	// Create contract client (read-only, no private key needed)
-	client, err := ddo.NewReadOnlyClientWithParams(config.RPCEndpoint, config.ContractAddress)
+	client, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create contract client: %v", err)
 	}
@@ -103,74 +88,51 @@ func executeQueryClaimInfo(c *cli.Context) error {
 		return fmt.Errorf("failed to get claim info: %v", err)
 	}
 
-	if jsonOutput {
-		// Output in JSON format
-		for i, claim := range claims {
-			// Decode CID from Data bytes (skip first byte which contains extra info)
-			var cidStr string
-			if len(claim.Data) > 1 {
-				actualCidData := claim.Data[1:]
-				if cidObj, err := cid.Parse(actualCidData); err == nil {
-					cidStr = cidObj.String()
-				}
-			}
-
-			fmt.Printf("{\n")
-			fmt.Printf("  \"index\": %d,\n", i)
-			fmt.Printf("  \"provider\": %d,\n", claim.Provider)
-			fmt.Printf("  \"client\": %d,\n", claim.Client)
-			fmt.Printf("  \"data\": \"%s\",\n", hex.EncodeToString(claim.Data))
-			if cidStr != "" {
-				fmt.Printf("  \"pieceCid\": \"%s\",\n", cidStr)
-			}
-			fmt.Printf("  \"size\": %d,\n", claim.Size)
-			fmt.Printf("  \"termMin\": %d,\n", claim.TermMin)
-			fmt.Printf("  \"termMax\": %d,\n", claim.TermMax)
-			fmt.Printf("  \"termStart\": %d,\n", claim.TermStart)
-			fmt.Printf("  \"sector\": %d\n", claim.Sector)
-			fmt.Printf("}")
-			if i < len(claims)-1 {
-				fmt.Printf(",")
+	infos := make([]types.ClaimInfo, len(claims))
+	for i, claim := range claims {
+		infos[i] = types.ClaimInfo{
+			Index:     i,
+			Provider:  claim.Provider,
+			Client:    claim.Client,
+			Data:      hex.EncodeToString(claim.Data),
+			Size:      claim.Size,
+			TermMin:   claim.TermMin,
+			TermMax:   claim.TermMax,
+			TermStart: claim.TermStart,
+			Sector:    claim.Sector,
+		}
+		// Decode CID from Data bytes (skip first byte which is extra info)
+		if len(claim.Data) > 1 {
+			if cidObj, err := cid.Parse(claim.Data[1:]); err == nil {
+				infos[i].PieceCid = cidObj.String()
 			}
-			fmt.Printf("\n")
 		}
-	} else {
-		// Human-readable output
-		fmt.Printf("📊 Results:\n")
-		fmt.Printf("Found %d claim(s)\n\n", len(claims))
+	}
 
-		if len(claims) == 0 {
-			fmt.Printf("No claims found for this client and claim ID.\n")
-		} else {
-			for i, claim := range claims {
-				// Decode CID from Data bytes (skip first byte which is extra info)
-				var cidStr string
-				if len(claim.Data) > 1 {
-					// Skip the first byte (extra info) and parse the rest as CID
-					actualCidData := claim.Data[1:]
-					if cidObj, err := cid.Parse(actualCidData); err == nil {
-						cidStr = cidObj.String()
-					}
-				}
+	return output.Render(c, infos, func() {
+		fmt.Fprintf(w, "📊 Results:\n")
+		fmt.Fprintf(w, "Found %d claim(s)\n\n", len(infos))
 
-				fmt.Printf("Claim #%d:\n", i+1)
-				fmt.Printf("  Provider ID: %d\n", claim.Provider)
-				fmt.Printf("  Client ID: %d\n", claim.Client)
-				fmt.Printf("  Data (hex): %s\n", hex.EncodeToString(claim.Data))
-				if cidStr != "" {
-					fmt.Printf("  Piece CID: %s\n", cidStr)
-				}
-				fmt.Printf("  Size: %d bytes\n", claim.Size)
-				fmt.Printf("  Term Min: %d\n", claim.TermMin)
-				fmt.Printf("  Term Max: %d\n", claim.TermMax)
-				fmt.Printf("  Term Start: %d\n", claim.TermStart)
-				fmt.Printf("  Sector ID: %d\n", claim.Sector)
-				if i < len(claims)-1 {
-					fmt.Printf("\n")
-				}
+		if len(infos) == 0 {
+			fmt.Fprintf(w, "No claims found for this client and claim ID.\n")
+			return
+		}
+		for i, claim := range infos {
+			fmt.Fprintf(w, "Claim #%d:\n", i+1)
+			fmt.Fprintf(w, "  Provider ID: %d\n", claim.Provider)
+			fmt.Fprintf(w, "  Client ID: %d\n", claim.Client)
+			fmt.Fprintf(w, "  Data (hex): %s\n", claim.Data)
+			if claim.PieceCid != "" {
+				fmt.Fprintf(w, "  Piece CID: %s\n", claim.PieceCid)
+			}
+			fmt.Fprintf(w, "  Size: %d bytes\n", claim.Size)
+			fmt.Fprintf(w, "  Term Min: %d\n", claim.TermMin)
+			fmt.Fprintf(w, "  Term Max: %d\n", claim.TermMax)
+			fmt.Fprintf(w, "  Term Start: %d\n", claim.TermStart)
+			fmt.Fprintf(w, "  Sector ID: %d\n", claim.Sector)
+			if i < len(infos)-1 {
+				fmt.Fprintf(w, "\n")
 			}
 		}
-	}
-
-	return nil
+	})
 }
@@ -0,0 +1,168 @@
+package allocations
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+func QuoteCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "quote",
+		Usage: "Estimate the full cost of an allocation without preparing data or signing",
+		Description: `Computes the padded piece size of a dataset locally and prices it with the
+provider's on-chain rate. The quote includes the storage cost over the term,
+the contract's fixed allocation lockup, the deposit and operator allowances
+that create-from-file would set up, and the gas of createAllocationRequests.
+
+Gas can only be estimated for a sender whose payments are already set up;
+pass that address with --from.`,
+		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.RPCFlag(),
+			&cli.StringFlag{
+				Name:     "size",
+				Aliases:  []string{"s"},
+				Usage:    "Data size in bytes, or a file or folder path to measure",
+				Required: true,
+			},
+			&cli.Uint64Flag{
+				Name:     "provider",
+				Aliases:  []string{"p"},
+				Usage:    "Provider/Miner ID",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Aliases: []string{"t", "payment-token"},
+				Usage:   "Payment token address (defaults to the profile's payment token)",
+			},
+			&cli.Int64Flag{
+				Name:  "term",
+				Usage: "Term length in epochs (the allocation's minimum term)",
+				Value: 518400, // create-from-file --term-min default
+			},
+			&cli.Int64Flag{
+				Name:  "days",
+				Usage: "Term length in days (alternative to --term)",
+			},
+			&cli.Int64Flag{
+				Name:  "term-max",
+				Usage: "Maximum term, used for the gas estimate",
+				Value: 5256000, // create-from-file default
+			},
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "Client address to estimate gas for (no private key needed)",
+			},
+		},
+		Action: config.Action(executeQuote),
+	}
+}
+
+func executeQuote(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	// Validate required configuration (only need contract and RPC for queries)
+	if err := cfg.RequireContract(); err != nil {
+		return err
+	}
+
+	if c.IsSet("term") && c.IsSet("days") {
+		return fmt.Errorf("use either --term or --days, not both")
+	}
+	tokenAddr, err := cfg.TokenOrDefault("token", c.String("token"))
+	if err != nil {
+		return err
+	}
+	if !common.IsHexAddress(tokenAddr) {
+		return fmt.Errorf("invalid token address: %s", tokenAddr)
+	}
+	var from common.Address
+	if f := c.String("from"); f != "" {
+		if !common.IsHexAddress(f) {
+			return fmt.Errorf("invalid from address: %s", f)
+		}
+		from = common.HexToAddress(f)
+	}
+
+	termLength := c.Int64("term")
+	if days := c.Int64("days"); days != 0 {
+		termLength = days * utils.EPOCHS_PER_DAY
+	}
+	if termLength <= 0 {
+		return fmt.Errorf("term length must be positive")
+	}
+
+	dataSize, err := strconv.ParseUint(c.String("size"), 10, 64)
+	if err != nil {
+		dataSize, err = utils.DataSize(c.String("size"))
+		if err != nil {
+			return fmt.Errorf("--size is neither a byte count nor a readable path: %v", err)
+		}
+	}
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	quote, err := utils.QuoteAllocation(ddoClient, c.Uint64("provider"), common.HexToAddress(tokenAddr), dataSize, termLength, c.Int64("term-max"), from)
+	if err != nil {
+		return err
+	}
+
+	return output.Render(c, quote, func() { printQuote(w, cfg, quote, from) })
+}
+
+func printQuote(w io.Writer, cfg config.Config, q *types.AllocationQuote, from common.Address) {
+	meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, q.Token)
+	fil := &token.NativeTokenMetadata
+
+	fmt.Fprintf(w, "💰 Allocation Quote:\n")
+	fmt.Fprintf(w, "   Provider: %d\n", q.Provider)
+	fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, q.Token.Hex())
+	fmt.Fprintf(w, "   Data Size: %d bytes (%s)\n", q.DataSize, utils.FormatBytes(new(big.Int).SetUint64(q.DataSize)))
+	fmt.Fprintf(w, "   Piece Size: %d bytes (%s, padded)\n", q.PieceSize, utils.FormatBytes(new(big.Int).SetUint64(q.PieceSize)))
+	fmt.Fprintf(w, "   Term: %d epochs (~%.1f days)\n", q.TermLength, float64(q.TermLength)/utils.EPOCHS_PER_DAY)
+	fmt.Fprintf(w, "   Price: %s\n", utils.FormatPriceBothFormats(q.PricePerBytePerEpoch, meta))
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "Storage Cost:\n")
+	fmt.Fprintf(w, "   Monthly: %s\n", utils.FormatTokenAmount(q.MonthlyCost, meta))
+	fmt.Fprintf(w, "   Full Term: %s\n", utils.FormatTokenAmount(q.StorageCost, meta))
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "Upfront Requirements:\n")
+	fmt.Fprintf(w, "   Allocation Lockup (per rail): %s\n", utils.FormatTokenAmount(q.AllocationLockupAmount, meta))
+	fmt.Fprintf(w, "   Payments Deposit: %s\n", utils.FormatTokenAmount(q.RequiredDeposit, meta))
+	fmt.Fprintf(w, "   Operator Rate Allowance: %s per epoch\n", utils.FormatTokenAmount(q.RateAllowance, meta))
+	fmt.Fprintf(w, "   Operator Lockup Allowance: %s\n", utils.FormatTokenAmount(q.LockupAllowance, meta))
+	if q.GasCost != nil {
+		fmt.Fprintf(w, "   createAllocationRequests Gas: %d @ %s = %s\n",
+			q.GasLimit, utils.FormatTokenAmount(q.GasPrice, fil), utils.FormatTokenAmount(q.GasCost, fil))
+	} else {
+		fmt.Fprintf(w, "   createAllocationRequests Gas: unavailable\n")
+	}
+	fmt.Fprintln(w)
+
+	if q.GasError != "" {
+		fmt.Fprintf(w, "⚠️  Could not estimate gas: %s\n", q.GasError)
+		if from == (common.Address{}) {
+			fmt.Fprintf(w, "   Use --from with a client address whose payments are set up\n")
+		}
+	}
+	fmt.Fprintf(w, "ℹ️  Deposit and lockup allowance include the same 2x buffer create-from-file applies\n")
+}
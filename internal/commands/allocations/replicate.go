@@ -0,0 +1,193 @@
+package allocations
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/curio"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// replicationJob holds everything the replicas of one prepared dataset share
+type replicationJob struct {
+	cfg            config.Config
+	ddoClient      *ddo.Client
+	paymentsClient *payments.Client
+	auth           *bind.TransactOpts
+	privateKey     *ecdsa.PrivateKey
+	userAddress    common.Address
+	pieceInfos     []types.PieceInfo // Provider is set per replica
+	pieces         []types.PreparedPiece
+	curioUpload    bool
+	curioAPI       string // discovered per provider when empty
+}
+
+// selectReplicaProviders picks the n cheapest registered providers that accept the token
+// and minimum term, and share a piece size range. It runs before data preparation, so the
+// pieces are sized for the providers picked; pieceSize is zero unless already known.
+func selectReplicaProviders(w io.Writer, cfg config.Config, n int, paymentToken common.Address, termMin int64, pieceSize uint64) ([]uint64, error) {
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	// Without a piece size, offers are ranked by price, which orders them as the cost of
+	// any one size and term would
+	filter := utils.SPSearchFilter{
+		Tokens:     []common.Address{paymentToken},
+		PieceSize:  pieceSize,
+		TermLength: termMin,
+	}
+	decimals := func(tokenAddr common.Address) (uint8, error) {
+		meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddr)
+		if err != nil {
+			return 0, err
+		}
+		return meta.Decimals, nil
+	}
+
+	offers, _, err := utils.SearchSPOffers(ddoClient, filter, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search storage providers: %v", err)
+	}
+
+	providers := utils.TopProviders(offers, n)
+	if len(providers) == 0 {
+		if pieceSize != 0 {
+			return nil, fmt.Errorf("no registered provider accepts token %s for a %d byte piece and a %d epoch term",
+				paymentToken.Hex(), pieceSize, termMin)
+		}
+		return nil, fmt.Errorf("no registered provider accepts token %s for a %d epoch term", paymentToken.Hex(), termMin)
+	}
+	if len(providers) < n {
+		fmt.Fprintf(w, "⚠️  Only %d provider(s) match, creating %d of %d requested replicas\n", len(providers), len(providers), n)
+	}
+
+	fmt.Fprintf(w, "Selected providers from the SP registry: %v\n", providers)
+	return providers, nil
+}
+
+// createReplica sets up payments, creates the allocation and optionally submits the
+// deal to Curio for one provider. The result records how far the replica got.
+func createReplica(c *cli.Context, job *replicationJob, providerID uint64) (types.ReplicaResult, error) {
+	w := output.Progress(c)
+
+	cfg := job.cfg
+	result := types.ReplicaResult{Provider: providerID}
+
+	pieceInfos := make([]types.PieceInfo, len(job.pieceInfos))
+	for i, p := range job.pieceInfos {
+		p.Provider = providerID
+		pieceInfos[i] = p
+	}
+
+	// Calculate storage costs
+	fmt.Fprintf(w, "Calculating storage costs...\n")
+	costResult, err := utils.CalculateStorageCosts(job.ddoClient, pieceInfos)
+	if err != nil {
+		return result, fmt.Errorf("failed to calculate storage costs: %v", err)
+	}
+
+	tokenMeta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, pieceInfos[0].PaymentTokenAddress)
+
+	fmt.Fprintf(w, "Cost Analysis:\n")
+	fmt.Fprintf(w, "   Total Storage Cost: %s\n", utils.FormatTokenAmount(costResult.TotalCost, tokenMeta))
+	fmt.Fprintf(w, "   Price: %s\n", utils.FormatPriceBothFormats(costResult.PricePerBytePerEpoch, tokenMeta))
+	fmt.Fprintf(w, "   Total Bytes: %d\n", costResult.TotalBytes)
+	fmt.Fprintf(w, "   Total Epochs: %d\n", costResult.TotalEpochs)
+	fmt.Fprintf(w, "   User Address: %s\n", job.userAddress.Hex())
+	fmt.Fprintln(w)
+
+	if c.Bool("skip-payment-setup") {
+		fmt.Fprintf(w, "Skipping payment setup - ensure payments are configured manually\n")
+	}
+	fmt.Fprintf(w, "DDO Contract: %s\n", cfg.ContractAddress)
+	fmt.Fprintf(w, "Payments Contract: %s\n", cfg.PaymentsContractAddress)
+	fmt.Fprintf(w, "RPC: %s\n", cfg.RPCEndpoint)
+
+	result.AllocationIds, result.TxHash, err = utils.CreateAllocations(
+		job.ddoClient,
+		job.paymentsClient,
+		pieceInfos,
+		job.userAddress,
+		common.HexToAddress(cfg.ContractAddress),
+		job.auth,
+		c.Bool("skip-payment-setup"),
+		func(step string) { fmt.Fprintf(w, "%s...\n", step) },
+	)
+	if err != nil {
+		return result, err
+	}
+	fmt.Fprintf(w, "Allocation creation transaction mined successfully!\n")
+	fmt.Fprintf(w, "Transaction Hash: %s\n", result.TxHash)
+	fmt.Fprintf(w, "   Found %d allocation(s): %v\n", len(result.AllocationIds), result.AllocationIds)
+
+	if !job.curioUpload {
+		return result, nil
+	}
+
+	// Auto-discover Curio API URL from on-chain miner info if not provided
+	curioAPI := job.curioAPI
+	if curioAPI == "" {
+		fmt.Fprintf(w, "No --curio-api provided, discovering SP URL from chain...\n")
+		discovered, err := curio.DiscoverSPURL(cfg.RPCEndpoint, cfg.RPC, providerID)
+		if err != nil {
+			fmt.Fprintf(w, "Warning: could not auto-discover SP URL: %v\n", err)
+			fmt.Fprintf(w, "Use --curio-api to provide manually\n")
+			return result, nil
+		}
+		curioAPI = discovered
+		fmt.Fprintf(w, "Discovered Curio API: %s\n", discovered)
+	}
+
+	// Submit deal to Curio MK20
+	fmt.Fprintf(w, "\nSubmitting deal to Curio MK20...\n")
+	dealIDs, err := submitToCurio(c, cfg, job.privateKey, job.userAddress, job.pieces, providerID, result.AllocationIds, curioAPI)
+	result.CurioDealIds = dealIDs
+	if err != nil {
+		return result, fmt.Errorf("failed to submit deal to Curio: %v", err)
+	}
+	result.CurioSubmitted = true
+
+	return result, nil
+}
+
+// printReplicaSummary reports the outcome of every replica and fails if any replica failed
+func printReplicaSummary(w io.Writer, results []types.ReplicaResult) error {
+	var failed int
+	fmt.Fprintf(w, "\n📋 Replication Summary:\n")
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			fmt.Fprintf(w, "   ❌ Provider %d: %s\n", r.Provider, r.Error)
+			if r.TxHash != "" {
+				fmt.Fprintf(w, "      Transaction Hash: %s\n", r.TxHash)
+			}
+			continue
+		}
+
+		fmt.Fprintf(w, "   ✅ Provider %d: allocation(s) %v\n", r.Provider, r.AllocationIds)
+		fmt.Fprintf(w, "      Transaction Hash: %s\n", r.TxHash)
+		if r.CurioSubmitted {
+			fmt.Fprintf(w, "      Curio MK20 deal submitted\n")
+		}
+	}
+	fmt.Fprintf(w, "\n%d of %d replicas created\n", len(results)-failed, len(results))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d replicas failed", failed, len(results))
+	}
+	return nil
+}
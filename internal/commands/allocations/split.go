@@ -0,0 +1,146 @@
+package allocations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/eastore-project/fildeal/src/buffer"
+	dealutils "github.com/eastore-project/fildeal/src/deal/utils"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// carOverheadAllowance bounds the CAR header and directory nodes of a chunk
+const carOverheadAllowance = 64 << 10
+
+// prepareSplitPieces packs the input into one CAR per chunk of at most maxPayload bytes and
+// stores each CAR in the buffer. Pieces smaller than minPieceSize are padded up to it.
+func prepareSplitPieces(w io.Writer, inputPath, outDir string, bufferConfig *buffer.Config, maxPayload int64, minPieceSize uint64) ([]types.PreparedPiece, error) {
+	files, total, err := utils.ListFileRanges(inputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// File paths in the CARs are relative to the input folder
+	parent := inputPath
+	if info, err := os.Stat(inputPath); err == nil && !info.IsDir() {
+		parent = filepath.Dir(inputPath)
+	}
+
+	chunks := utils.SplitFileRanges(files, maxPayload)
+	fmt.Fprintf(w, "Splitting %d bytes into %d pieces\n", total, len(chunks))
+
+	buf := newBuffer(bufferConfig)
+
+	pieces := make([]types.PreparedPiece, 0, len(chunks))
+	for i, chunk := range chunks {
+		fmt.Fprintf(w, "   Preparing piece %d/%d (%d file range(s))...\n", i+1, len(chunks), len(chunk))
+
+		piece, err := prepareChunk(chunk, parent, outDir, minPieceSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare piece %d: %w", i+1, err)
+		}
+
+		bufferResp, err := buf.Store(piece.CarPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store piece %d in buffer: %w", i+1, err)
+		}
+		piece.DownloadURL = bufferResp.URL
+
+		pieces = append(pieces, *piece)
+	}
+	return pieces, nil
+}
+
+// prepareChunk writes the CAR file of one chunk using fildeal's file list input
+func prepareChunk(chunk []types.FileRange, parent, outDir string, minPieceSize uint64) (*types.PreparedPiece, error) {
+	var payload int64
+	finfos := make([]dealutils.Finfo, len(chunk))
+	for i, f := range chunk {
+		finfos[i] = dealutils.Finfo{Path: f.Path, Size: f.Size, Start: f.Start, End: f.End}
+		payload += f.End - f.Start
+	}
+
+	listFile, err := os.CreateTemp(outDir, "chunk-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file list: %w", err)
+	}
+	defer os.Remove(listFile.Name())
+	if err := json.NewEncoder(listFile).Encode(finfos); err != nil {
+		listFile.Close()
+		return nil, fmt.Errorf("failed to write file list: %w", err)
+	}
+	if err := listFile.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write file list: %w", err)
+	}
+
+	params := &dealutils.CarParams{
+		Input:  listFile.Name(),
+		OutDir: outDir,
+		Parent: parent,
+	}
+	// Pad small pieces up to the minimum when the CAR is sure to fit in it
+	if utils.PaddedPieceSize(uint64(payload+payload/64)+carOverheadAllowance) <= minPieceSize {
+		params.PieceSize = minPieceSize
+	}
+
+	result, err := params.GenerateCarUtil()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate car file: %w", err)
+	}
+
+	carPath := filepath.Join(outDir, result.PieceCid+".car")
+	info, err := os.Stat(carPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get car file size: %w", err)
+	}
+
+	return &types.PreparedPiece{
+		PieceCid:   result.PieceCid,
+		PieceSize:  result.PieceSize,
+		PayloadCid: result.DataCid,
+		CarSize:    uint64(info.Size()),
+		CarPath:    carPath,
+		Files:      chunk,
+	}, nil
+}
+
+// newBuffer returns the buffer selected by the config, as dealutils.PrepareData does
+func newBuffer(bufferConfig *buffer.Config) buffer.Buffer {
+	switch bufferConfig.Type {
+	case "lighthouse":
+		return buffer.NewLighthouseBuffer(bufferConfig.ApiKey, bufferConfig.BaseURL)
+	default:
+		return buffer.NewLocalBuffer()
+	}
+}
+
+// providerPieceSizeLimits returns the piece size range accepted by all providers. Both
+// limits are zero when no providers are given.
+func providerPieceSizeLimits(cfg config.Config, providers []uint64) (uint64, uint64, error) {
+	if len(providers) == 0 {
+		return 0, 0, nil
+	}
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	configs := make(map[uint64]*types.SPConfig, len(providers))
+	for _, id := range providers {
+		cfg, err := ddoClient.GetSPConfig(id)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get SP config for provider %d: %v", id, err)
+		}
+		configs[id] = cfg
+	}
+	return utils.PieceSizeLimits(configs)
+}
@@ -5,12 +5,15 @@ import (
 	"math/big"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
 	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/types"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
@@ -20,31 +23,18 @@ func ApproveTokenCommand() *cli.Command {
 		Aliases: []string{"at"},
 		Usage:   "Check and approve ERC20 token allowance for the payments contract",
 		Flags: []cli.Flag{
+			config.PaymentsContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.StringFlag{
-				Name:    "payments-contract",
-				Aliases: []string{"pc"},
-				Usage:   "Payments contract address (overrides PAYMENTS_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
-			&cli.StringFlag{
-				Name:     "token",
-				Aliases:  []string{"t"},
-				Usage:    "ERC20 token contract address",
-				Required: true,
+				Name:    "token",
+				Aliases: []string{"t"},
+				Usage:   "ERC20 token contract address (defaults to the profile's payment token)",
 			},
 			&cli.StringFlag{
 				Name:    "amount",
 				Aliases: []string{"a"},
-				Usage:   "Amount to approve (if not provided, will approve unlimited)",
+				Usage:   "Amount to approve in token units (\"100\", \"100 USDFC\") or base units (\"100000000 wei\") (defaults to 2x your balance)",
 			},
 			&cli.BoolFlag{
 				Name:  "check-only",
@@ -55,45 +45,39 @@ func ApproveTokenCommand() *cli.Command {
 				Usage: "Approve unlimited amount (max uint256)",
 			},
 		},
-		Action: executeApproveToken,
+		Action: config.Action(executeApproveToken),
 	}
 }
 
-func executeApproveToken(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if paymentsContract := c.String("payments-contract"); paymentsContract != "" {
-		config.PaymentsContractAddress = paymentsContract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
-	if pk := c.String("private-key"); pk != "" {
-		config.PrivateKey = pk
-	}
+func executeApproveToken(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration
-	if missing := config.GetMissingConfig(); len(missing) > 0 {
+	if missing := cfg.MissingConfig(); len(missing) > 0 {
 		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
-	if config.PaymentsContractAddress == "" {
+	if cfg.PaymentsContractAddress == "" {
 		return fmt.Errorf("payments contract address required (use --payments-contract flag or PAYMENTS_CONTRACT_ADDRESS env var)")
 	}
 
-	tokenAddress := c.String("token")
+	tokenAddress, err := cfg.TokenOrDefault("token", c.String("token"))
+	if err != nil {
+		return err
+	}
 	checkOnly := c.Bool("check-only")
 	unlimited := c.Bool("unlimited")
 	amountStr := c.String("amount")
 
 	// Get user address from private key
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey, "0x"))
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
 	if err != nil {
 		return fmt.Errorf("failed to parse private key: %v", err)
 	}
 	userAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
 
 	// Create payments client to get contract address
-	paymentsClient, err := payments.NewReadOnlyClientWithParams(config.RPCEndpoint, config.PaymentsContractAddress)
+	paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create payments client: %v", err)
 	}
@@ -101,14 +85,14 @@ func executeApproveToken(c *cli.Context) error {
 
 	spenderAddress := paymentsClient.GetContractAddress()
 
-	fmt.Printf("🔍 Token Allowance Check:\n")
-	fmt.Printf("   User Address: %s\n", userAddress.Hex())
-	fmt.Printf("   Token Address: %s\n", tokenAddress)
-	fmt.Printf("   Spender (Payments Contract): %s\n", spenderAddress.Hex())
-	fmt.Println()
+	fmt.Fprintf(w, "🔍 Token Allowance Check:\n")
+	fmt.Fprintf(w, "   User Address: %s\n", userAddress.Hex())
+	fmt.Fprintf(w, "   Token Address: %s\n", tokenAddress)
+	fmt.Fprintf(w, "   Spender (Payments Contract): %s\n", spenderAddress.Hex())
+	fmt.Fprintln(w)
 
 	// Create ERC20 client for read-only operations first
-	erc20ReadClient, err := token.NewERC20ReadOnlyClient(config.RPCEndpoint, tokenAddress)
+	erc20ReadClient, err := token.NewERC20ReadOnlyClient(cfg.RPCEndpoint, tokenAddress, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create ERC20 read client: %v", err)
 	}
@@ -125,15 +109,30 @@ func executeApproveToken(c *cli.Context) error {
 		return fmt.Errorf("failed to get current allowance: %v", err)
 	}
 
-	fmt.Printf("📊 Current Status:\n")
-	fmt.Printf("   Token Balance: %s\n", balance.String())
-	fmt.Printf("   Current Allowance: %s\n", allowance.String())
-	fmt.Println()
+	meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, common.HexToAddress(tokenAddress))
+	if err != nil {
+		return fmt.Errorf("failed to get token metadata: %v", err)
+	}
+
+	fmt.Fprintf(w, "📊 Current Status:\n")
+	fmt.Fprintf(w, "   Token: %s (%d decimals)\n", meta.Symbol, meta.Decimals)
+	fmt.Fprintf(w, "   Token Balance: %s\n", utils.FormatTokenAmount(balance, meta))
+	fmt.Fprintf(w, "   Current Allowance: %s\n", utils.FormatTokenAmount(allowance, meta))
+	fmt.Fprintln(w)
+
+	approval := types.TokenApproval{
+		Owner:     userAddress,
+		Token:     common.HexToAddress(tokenAddress),
+		Spender:   spenderAddress,
+		Balance:   balance,
+		Allowance: allowance,
+	}
 
 	// If check-only, just display the information
 	if checkOnly {
-		fmt.Printf("✅ Allowance check completed\n")
-		return nil
+		return output.Render(c, approval, func() {
+			fmt.Fprintf(w, "✅ Allowance check completed\n")
+		})
 	}
 
 	// Determine the amount to approve
@@ -143,59 +142,70 @@ func executeApproveToken(c *cli.Context) error {
 		// Use max uint256 for unlimited approval
 		approveAmount = new(big.Int)
 		approveAmount.SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
-		fmt.Printf("🔓 Setting unlimited allowance...\n")
+		fmt.Fprintf(w, "🔓 Setting unlimited allowance...\n")
 	} else if amountStr != "" {
 		// Parse the provided amount
-		approveAmount = new(big.Int)
-		approveAmount, ok := approveAmount.SetString(amountStr, 10)
-		if !ok {
-			return fmt.Errorf("invalid amount format: %s", amountStr)
+		approveAmount, err = utils.ParseTokenAmount(amountStr, meta)
+		if err != nil {
+			return err
 		}
-		fmt.Printf("💰 Setting allowance to: %s\n", approveAmount.String())
+		fmt.Fprintf(w, "💰 Setting allowance to: %s\n", utils.FormatTokenAmount(approveAmount, meta))
 	} else {
 		// Default: approve 2x the user's current balance for convenience
 		approveAmount = new(big.Int).Mul(balance, big.NewInt(2))
-		fmt.Printf("💰 Setting allowance to 2x balance: %s\n", approveAmount.String())
+		fmt.Fprintf(w, "💰 Setting allowance to 2x balance: %s\n", utils.FormatTokenAmount(approveAmount, meta))
 	}
 
 	// Check if approval is needed
 	if allowance.Cmp(approveAmount) >= 0 {
-		fmt.Printf("✅ Current allowance is already sufficient\n")
-		return nil
+		return output.Render(c, approval, func() {
+			fmt.Fprintf(w, "✅ Current allowance is already sufficient\n")
+		})
 	}
 
 	// Create ERC20 client for transactions
-	erc20Client, err := token.NewERC20ClientWithParams(config.RPCEndpoint, tokenAddress, config.PrivateKey)
+	erc20Client, err := token.NewERC20ClientWithParams(cfg.RPCEndpoint, tokenAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 	if err != nil {
 		return fmt.Errorf("failed to create ERC20 client: %v", err)
 	}
 	defer erc20Client.Close()
 
 	// Send approval transaction
-	fmt.Printf("📝 Sending approval transaction...\n")
+	fmt.Fprintf(w, "📝 Sending approval transaction...\n")
 	txHash, err := erc20Client.Approve(spenderAddress, approveAmount)
 	if err != nil {
 		return fmt.Errorf("failed to approve tokens: %v", err)
 	}
 
-	fmt.Printf("✅ Approval transaction sent: %s\n", txHash)
+	fmt.Fprintf(w, "✅ Approval transaction sent: %s\n", txHash)
 
 	// Wait for transaction to be mined using the ERC20 client's ethclient
-	fmt.Printf("⏳ Waiting for transaction to be mined...\n")
-	if err := utils.WaitForTransaction(erc20Client.GetEthClient(), txHash); err != nil {
-		fmt.Printf("⚠️  Warning: transaction may not have been mined: %v\n", err)
-		return nil
-	}
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	result := output.WaitTx(erc20Client.TxManager(), txHash)
+	approval.Tx = &result
 
 	// Verify the new allowance
-	newAllowance, err := erc20ReadClient.GetAllowance(userAddress, spenderAddress)
-	if err != nil {
-		fmt.Printf("⚠️  Warning: failed to verify new allowance: %v\n", err)
-		return nil
+	verified := false
+	if result.Status == types.TxStatusSuccess {
+		newAllowance, err := erc20ReadClient.GetAllowance(userAddress, spenderAddress)
+		if err != nil {
+			fmt.Fprintf(w, "⚠️  Warning: failed to verify new allowance: %v\n", err)
+		} else {
+			approval.Allowance = newAllowance
+			verified = true
+		}
 	}
 
-	fmt.Printf("🎉 Approval successful!\n")
-	fmt.Printf("   New Allowance: %s\n", newAllowance.String())
-
-	return nil
+	err = output.Render(c, approval, func() {
+		output.PrintTxOutcome(w, result, func() {
+			fmt.Fprintf(w, "🎉 Approval successful!\n")
+			if verified {
+				fmt.Fprintf(w, "   New Allowance: %s\n", utils.FormatTokenAmount(approval.Allowance, meta))
+			}
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return output.TxError(result)
 }
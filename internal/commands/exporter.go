@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/exporter"
+)
+
+func ExporterCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "exporter",
+		Usage: "Serve Prometheus metrics on payments accounts, rails, allocations and storage providers",
+		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.PaymentsContractFlag(),
+			config.RPCFlag(),
+			&cli.StringFlag{
+				Name:  "listen",
+				Usage: "Address to serve /metrics on (use :9464 to listen on all interfaces)",
+				Value: "127.0.0.1:9464",
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "Time between two reads of the contracts",
+				Value: exporter.DefaultInterval,
+			},
+			&cli.StringSliceFlag{
+				Name:  "client",
+				Usage: "Client (payer) address to report on (repeatable)",
+			},
+			&cli.StringSliceFlag{
+				Name:    "token",
+				Aliases: []string{"t"},
+				Usage:   "Payment token of the clients (repeatable, defaults to the profile's payment token)",
+			},
+			&cli.StringFlag{
+				Name:  "operator",
+				Usage: "Operator whose approvals by the clients are reported (defaults to the DDO contract)",
+			},
+			&cli.Uint64SliceFlag{
+				Name:    "provider",
+				Aliases: []string{"p"},
+				Usage:   "Storage provider actor ID to report on (repeatable)",
+			},
+		},
+		Action: config.Action(executeExporter),
+	}
+}
+
+func executeExporter(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	if err := cfg.RequireContract(); err != nil {
+		return err
+	}
+	if err := cfg.RequirePayments(); err != nil {
+		return err
+	}
+
+	expCfg := exporter.Config{
+		Interval:  c.Duration("interval"),
+		Operator:  common.HexToAddress(cfg.ContractAddress),
+		Providers: c.Uint64Slice("provider"),
+	}
+	for _, addr := range c.StringSlice("client") {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid client address: %s", addr)
+		}
+		expCfg.Clients = append(expCfg.Clients, common.HexToAddress(addr))
+	}
+	tokens := c.StringSlice("token")
+	if len(tokens) == 0 && cfg.PaymentToken != "" {
+		tokens = []string{cfg.PaymentToken}
+	}
+	for _, addr := range tokens {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid token address: %s", addr)
+		}
+		expCfg.Tokens = append(expCfg.Tokens, common.HexToAddress(addr))
+	}
+	if len(expCfg.Clients) > 0 && len(expCfg.Tokens) == 0 {
+		return fmt.Errorf("missing token address for the clients (use --token flag or set payment_token in the profile)")
+	}
+	if operator := c.String("operator"); operator != "" {
+		if !common.IsHexAddress(operator) {
+			return fmt.Errorf("invalid operator address: %s", operator)
+		}
+		expCfg.Operator = common.HexToAddress(operator)
+	}
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create payments client: %v", err)
+	}
+	defer paymentsClient.Close()
+
+	exp := exporter.New(ddoClient, paymentsClient, ddoClient.GetEthClient(), expCfg)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp.Handler())
+	server := &http.Server{
+		Addr:              c.String("listen"),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go exp.Run(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	fmt.Fprintf(w, "📈 Serving metrics on http://%s/metrics\n", server.Addr)
+	fmt.Fprintf(w, "   Clients: %d, Tokens: %d, Providers: %d, Refresh: every %s\n",
+		len(expCfg.Clients), len(expCfg.Tokens), len(expCfg.Providers), c.Duration("interval"))
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("metrics server failed: %v", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	fmt.Fprintf(w, "🛑 Stopping exporter\n")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
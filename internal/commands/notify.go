@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/notify"
+)
+
+func NotifyCommand() *cli.Command {
+	fileFlag := &cli.StringFlag{
+		Name:     "file",
+		Aliases:  []string{"f"},
+		Usage:    "Notifier config file (YAML, or JSON with a .json extension)",
+		Required: true,
+	}
+
+	return &cli.Command{
+		Name:  "notify",
+		Usage: "Deliver allocation, payment and Curio deal events to webhooks",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "run",
+				Usage: "Watch the contracts, payer runways and Curio deals and deliver events until interrupted",
+				Description: `Polls the DDO and Payments contracts for allocation.activated, rail.created and
+rail.terminated events, checks the runway of the configured payer accounts
+(funds.low) and the status of the configured Curio deals (curio.deal_failed).
+
+Events are POSTed as JSON to each webhook whose event list and filters match,
+signed with the webhook secret and retried with backoff. Deliveries that fail
+every attempt are appended to the dead-letter file.
+
+The last block read, watched deals, runway alerts and undelivered events are kept
+in the state file, so a restarted notifier resumes where it stopped. A private key is needed to
+query Curio deal status.`,
+				Flags: []cli.Flag{
+					config.ContractFlag(),
+					config.PaymentsContractFlag(),
+					config.RPCFlag(),
+					config.PrivateKeyFlag(),
+					fileFlag,
+				},
+				Action: config.Action(executeNotifyRun),
+			},
+			{
+				Name:   "test",
+				Usage:  "Send a signed test event to every configured webhook",
+				Flags:  []cli.Flag{fileFlag},
+				Action: executeNotifyTest,
+			},
+		},
+	}
+}
+
+// loadNotifyConfig reads the notifier config, placing the state and dead-letter
+// files next to it unless it sets them
+func loadNotifyConfig(path string, cfg config.Config) (notify.Config, error) {
+	notifyCfg, err := notify.LoadConfig(path)
+	if err != nil {
+		return notify.Config{}, err
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	if notifyCfg.StateFile == "" {
+		notifyCfg.StateFile = base + ".state.json"
+	}
+	if notifyCfg.DeadLetterFile == "" {
+		notifyCfg.DeadLetterFile = base + ".dead-letter.jsonl"
+	}
+	notifyCfg.Operator = common.HexToAddress(cfg.ContractAddress)
+	return notifyCfg, nil
+}
+
+// newNotifier creates a notifier over the given clients. Curio deals are only
+// watched with a private key, which authenticates the status requests.
+func newNotifier(cfg config.Config, notifyCfg notify.Config, ddoClient *ddo.Client, paymentsClient *payments.Client) (*notify.Notifier, error) {
+	var curioSource notify.Curio
+	if cfg.PrivateKey != "" {
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %v", err)
+		}
+		curioSource = notify.NewCurio(cfg.RPCEndpoint, cfg.RPC, privateKey)
+	} else if len(notifyCfg.Deals) > 0 {
+		return nil, fmt.Errorf("watching Curio deals requires a private key")
+	}
+
+	n, err := notify.New(ddoClient, paymentsClient, ddoClient.GetEthClient(), curioSource, notifyCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notifier: %v", err)
+	}
+	return n, nil
+}
+
+func executeNotifyRun(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	if err := cfg.RequireContract(); err != nil {
+		return err
+	}
+	if err := cfg.RequirePayments(); err != nil {
+		return err
+	}
+	notifyCfg, err := loadNotifyConfig(c.String("file"), cfg)
+	if err != nil {
+		return err
+	}
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create payments client: %v", err)
+	}
+	defer paymentsClient.Close()
+
+	n, err := newNotifier(cfg, notifyCfg, ddoClient, paymentsClient)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(w, "🔔 Delivering events to %d webhook(s)\n", len(notifyCfg.Webhooks))
+	fmt.Fprintf(w, "   Runway Accounts: %d, Curio Deals: %d, Confirmations: %d\n",
+		len(notifyCfg.Accounts), len(notifyCfg.Deals), notifyCfg.Confirmations)
+	fmt.Fprintf(w, "   State: %s\n", notifyCfg.StateFile)
+	fmt.Fprintf(w, "   Dead Letters: %s\n", notifyCfg.DeadLetterFile)
+
+	n.Run(ctx)
+	fmt.Fprintf(w, "🛑 Notifier stopped\n")
+	return nil
+}
+
+func executeNotifyTest(c *cli.Context) error {
+	w := output.Progress(c)
+
+	notifyCfg, err := notify.LoadConfig(c.String("file"))
+	if err != nil {
+		return err
+	}
+	n, err := notify.New(nil, nil, nil, nil, notifyCfg)
+	if err != nil {
+		return err
+	}
+
+	failed := n.Test(c.Context)
+	for _, hook := range notifyCfg.Webhooks {
+		if err, ok := failed[hook.Name]; ok {
+			fmt.Fprintf(w, "❌ %s: %v\n", hook.Name, err)
+		} else {
+			fmt.Fprintf(w, "✅ %s\n", hook.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d webhook(s) failed", len(failed), len(notifyCfg.Webhooks))
+	}
+	return nil
+}
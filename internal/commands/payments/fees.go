@@ -0,0 +1,251 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+func FeesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "fees",
+		Usage: "Inspect accumulated network fees and participate in the fee auction",
+		Subcommands: []*cli.Command{
+			ShowFeesCommand(),
+			BurnForFeesCommand(),
+		},
+	}
+}
+
+func ShowFeesCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "show",
+		Aliases: []string{"info"},
+		Usage:   "Show the network fee rate, accumulated fees and auction price per token",
+		Flags: append(paymentsFlags, []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     "token",
+				Aliases:  []string{"t"},
+				Usage:    "Token address (repeatable)",
+				Required: true,
+			},
+		}...),
+		Action: config.Action(executeShowFees),
+	}
+}
+
+func BurnForFeesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "burn",
+		Usage: "Buy accumulated fees of a token by burning native FIL at the current auction price",
+		Flags: append(paymentsFlags, []cli.Flag{
+			&cli.StringFlag{
+				Name:     "token",
+				Aliases:  []string{"t"},
+				Usage:    "Token address whose fees to buy",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "amount",
+				Aliases: []string{"a"},
+				Usage:   "Amount of fees to buy in token units (\"12.5\", \"12.5 USDFC\") or base units (\"12500000 wei\") (defaults to all accumulated fees)",
+			},
+			&cli.StringFlag{
+				Name:    "recipient",
+				Aliases: []string{"to"},
+				Usage:   "Address receiving the fee tokens (defaults to your own address)",
+			},
+			&cli.StringFlag{
+				Name:     "max-price",
+				Usage:    "Maximum auction price you are willing to burn, in FIL (\"0.5\", \"0.5 FIL\") or attoFIL (\"500000000000000000 wei\")",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Skip the confirmation prompt",
+			},
+		}...),
+		Action: config.Action(executeBurnForFees),
+	}
+}
+
+// latestBlockTime returns the timestamp of the latest block, which the fee auction uses as its clock
+func latestBlockTime(client *payments.Client) (time.Time, error) {
+	header, err := client.GetEthClient().HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest block header: %v", err)
+	}
+	return time.Unix(int64(header.Time), 0).UTC(), nil
+}
+
+func executeShowFees(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	client, err := createPaymentsClient(cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	feeNum, err := client.GetNetworkFeeNumerator()
+	if err != nil {
+		return fmt.Errorf("failed to get network fee numerator: %v", err)
+	}
+	feeDenom, err := client.GetNetworkFeeDenominator()
+	if err != nil {
+		return fmt.Errorf("failed to get network fee denominator: %v", err)
+	}
+
+	now, err := latestBlockTime(client)
+	if err != nil {
+		return err
+	}
+
+	var statuses []*types.FeeAuctionStatus
+	for _, tokenStr := range c.StringSlice("token") {
+		status, err := utils.GetFeeAuctionStatus(client, common.HexToAddress(tokenStr), now)
+		if err != nil {
+			return fmt.Errorf("failed to get fee status for token %s: %v", tokenStr, err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	fees := types.NetworkFees{
+		NetworkFeeNumerator:   feeNum,
+		NetworkFeeDenominator: feeDenom,
+		BlockTime:             now,
+		Tokens:                statuses,
+	}
+	return output.Render(c, fees, func() {
+		fmt.Fprintf(w, "💸 Network Fees:\n")
+		fmt.Fprintf(w, "   Contract: %s\n", client.GetContractAddress().Hex())
+		fmt.Fprintf(w, "   Fee Rate: %s/%s of each settlement\n", feeNum.String(), feeDenom.String())
+		fmt.Fprintf(w, "   Block Time: %s\n", now.Format(time.RFC3339))
+		fmt.Fprintln(w)
+
+		for _, s := range statuses {
+			meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, s.Token)
+			fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, s.Token.Hex())
+			fmt.Fprintf(w, "      Accumulated Fees: %s\n", utils.FormatTokenAmount(s.AccumulatedFees, meta))
+			fmt.Fprintf(w, "      Auction Start Price: %s attoFIL\n", s.StartPrice.String())
+			fmt.Fprintf(w, "      Auction Started: %s\n", s.StartTime.Format(time.RFC3339))
+			fmt.Fprintf(w, "      Estimated Current Price: %s attoFIL\n", s.CurrentPrice.String())
+			fmt.Fprintln(w)
+		}
+	})
+}
+
+func executeBurnForFees(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	// Validate private key configuration
+	if err := validatePrivateKeyConfig(cfg); err != nil {
+		return err
+	}
+
+	tokenAddr := common.HexToAddress(c.String("token"))
+
+	maxPrice, err := utils.ParseTokenAmount(c.String("max-price"), &token.NativeTokenMetadata)
+	if err != nil {
+		return fmt.Errorf("invalid --max-price: %v", err)
+	}
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+	if err != nil {
+		return fmt.Errorf("failed to parse private key: %v", err)
+	}
+	userAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	recipient := userAddress
+	if r := c.String("recipient"); r != "" {
+		recipient = common.HexToAddress(r)
+	}
+
+	client, err := payments.NewClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
+	if err != nil {
+		return fmt.Errorf("failed to create payments transaction client: %v", err)
+	}
+	defer client.Close()
+
+	now, err := latestBlockTime(client)
+	if err != nil {
+		return err
+	}
+
+	status, err := utils.GetFeeAuctionStatus(client, tokenAddr, now)
+	if err != nil {
+		return fmt.Errorf("failed to get fee status: %v", err)
+	}
+
+	meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to get token metadata: %v", err)
+	}
+
+	amount := status.AccumulatedFees
+	if amountStr := c.String("amount"); amountStr != "" {
+		amount, err = utils.ParseTokenAmount(amountStr, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(w, "🔥 Fee Auction:\n")
+	fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, tokenAddr.Hex())
+	fmt.Fprintf(w, "   Accumulated Fees: %s\n", utils.FormatTokenAmount(status.AccumulatedFees, meta))
+	fmt.Fprintf(w, "   Requested: %s\n", utils.FormatTokenAmount(amount, meta))
+	fmt.Fprintf(w, "   Recipient: %s\n", recipient.Hex())
+	fmt.Fprintf(w, "   Estimated Price: %s attoFIL\n", status.CurrentPrice.String())
+	fmt.Fprintf(w, "   Max Price: %s attoFIL\n", maxPrice.String())
+	fmt.Fprintln(w)
+
+	if amount.Sign() == 0 {
+		return fmt.Errorf("no fees to buy for token %s", tokenAddr.Hex())
+	}
+	if amount.Cmp(status.AccumulatedFees) > 0 {
+		return fmt.Errorf("requested %s exceeds accumulated fees %s", utils.FormatTokenAmount(amount, meta), utils.FormatTokenAmount(status.AccumulatedFees, meta))
+	}
+	if status.CurrentPrice.Cmp(maxPrice) > 0 {
+		return fmt.Errorf("auction price %s attoFIL is above --max-price %s", status.CurrentPrice.String(), maxPrice.String())
+	}
+
+	if !c.Bool("yes") {
+		ok, err := output.Confirm(w, fmt.Sprintf("Burn %s attoFIL for %s?", status.CurrentPrice.String(), utils.FormatTokenAmount(amount, meta)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintf(w, "Aborted - no transaction sent\n")
+			return nil
+		}
+	}
+
+	fmt.Fprintf(w, "📝 Sending burnForFees transaction...\n")
+	txHash, err := client.BurnForFees(tokenAddr, recipient, amount, status.CurrentPrice)
+	if err != nil {
+		return fmt.Errorf("failed to burn for fees: %v", err)
+	}
+	fmt.Fprintf(w, "✅ Transaction sent: %s\n", txHash)
+
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	result := output.WaitTx(client.TxManager(), txHash)
+	if result.Status == types.TxStatusPending {
+		return fmt.Errorf("burnForFees transaction failed: %s", result.Error)
+	}
+	return output.RenderTx(c, result, func() {
+		fmt.Fprintf(w, "✅ Bought %s of fees for %s attoFIL\n", utils.FormatTokenAmount(amount, meta), status.CurrentPrice.String())
+	})
+}
@@ -0,0 +1,125 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// healthExitCode is returned when an account's runway is below --min-runway-days
+const healthExitCode = 2
+
+func HealthCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "health",
+		Usage: "Report settled balance, burn rate and funding runway of a payer account",
+		Flags: append(paymentsFlags, []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     "token",
+				Aliases:  []string{"t"},
+				Usage:    "Token address (repeatable)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "address",
+				Aliases: []string{"a"},
+				Usage:   "Account address (defaults to the address of --private-key / PRIVATE_KEY)",
+			},
+			&cli.Float64Flag{
+				Name:  "min-runway-days",
+				Usage: fmt.Sprintf("Exit with code %d if any token has less runway than this many days (0 disables the check)", healthExitCode),
+			},
+		}...),
+		Action: config.Action(executeHealth),
+	}
+}
+
+func executeHealth(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	client, err := createPaymentsClient(cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var owner common.Address
+	if addr := c.String("address"); addr != "" {
+		owner = common.HexToAddress(addr)
+	} else if cfg.PrivateKey != "" {
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("failed to parse private key: %v", err)
+		}
+		owner = crypto.PubkeyToAddress(privateKey.PublicKey)
+	} else {
+		return fmt.Errorf("account address required (use --address or --private-key)")
+	}
+
+	header, err := client.GetEthClient().HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block header: %v", err)
+	}
+	currentEpoch := header.Number.Uint64()
+	currentTime := time.Unix(int64(header.Time), 0).UTC()
+
+	minRunwayDays := c.Float64("min-runway-days")
+	var reports []*types.AccountHealth
+	var lowRunway []string
+
+	for _, tokenStr := range c.StringSlice("token") {
+		token := common.HexToAddress(tokenStr)
+		health, err := utils.GetAccountHealth(client, token, owner, currentEpoch, currentTime)
+		if err != nil {
+			return fmt.Errorf("failed to get account health for token %s: %v", token.Hex(), err)
+		}
+		reports = append(reports, health)
+
+		if minRunwayDays > 0 && !health.Unlimited && health.RunwayDays < minRunwayDays {
+			lowRunway = append(lowRunway, fmt.Sprintf("%s (%.1f days)", token.Hex(), health.RunwayDays))
+		}
+	}
+
+	err = output.Render(c, reports, func() {
+		fmt.Fprintf(w, "🩺 Account Health:\n")
+		fmt.Fprintf(w, "   Account: %s\n", owner.Hex())
+		fmt.Fprintf(w, "   Current Epoch: %d (%s)\n", currentEpoch, currentTime.Format(time.RFC3339))
+		fmt.Fprintln(w)
+
+		for _, h := range reports {
+			meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, h.Token)
+			fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, h.Token.Hex())
+			fmt.Fprintf(w, "      Current Funds: %s\n", utils.FormatTokenAmount(h.CurrentFunds, meta))
+			fmt.Fprintf(w, "      Available (settled): %s\n", utils.FormatTokenAmount(h.AvailableFunds, meta))
+			fmt.Fprintf(w, "      Lockup Rate: %s per epoch\n", utils.FormatTokenAmount(h.LockupRate, meta))
+			fmt.Fprintf(w, "      Burn Rate: %s per epoch (%s per day) across %d active and %d terminated rail(s)\n",
+				utils.FormatTokenAmount(h.BurnRatePerEpoch, meta), utils.FormatTokenAmount(h.BurnRatePerDay, meta), h.ActiveRails, h.TerminatedRails)
+			if h.Unlimited {
+				fmt.Fprintf(w, "      Runway: unlimited (no lockup rate)\n")
+			} else {
+				fmt.Fprintf(w, "      Funded Until: epoch %s (~%s)\n", h.FundedUntilEpoch.String(), h.RunsOutAt.Format(time.RFC3339))
+				fmt.Fprintf(w, "      Runway: %s epochs (~%.1f days)\n", h.RunwayEpochs.String(), h.RunwayDays)
+			}
+			fmt.Fprintln(w)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(lowRunway) > 0 {
+		return cli.Exit(fmt.Sprintf("runway below %.1f days for: %s", minRunwayDays, strings.Join(lowRunway, ", ")), healthExitCode)
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package payments
 
 import (
 	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
 )
 
 func PaymentsCommand() *cli.Command {
@@ -15,6 +17,9 @@ func PaymentsCommand() *cli.Command {
 			QueryAccountCommand(),
 			QueryOperatorApprovalCommand(),
 			QueryRailCommand(),
+			HealthCommand(),
+			StatementCommand(),
+			FeesCommand(),
 			// Transaction commands
 			SetOperatorAllowanceCommand(),
 			WithdrawCommand(),
@@ -23,19 +28,7 @@ func PaymentsCommand() *cli.Command {
 }
 
 var paymentsFlags = []cli.Flag{
-	&cli.StringFlag{
-		Name:    "payments-contract",
-		Aliases: []string{"pc"},
-		Usage:   "Payments contract address (overrides PAYMENTS_CONTRACT_ADDRESS env var)",
-	},
-	&cli.StringFlag{
-		Name:    "rpc",
-		Aliases: []string{"r"},
-		Usage:   "RPC endpoint (overrides RPC_URL env var)",
-	},
-	&cli.StringFlag{
-		Name:    "private-key",
-		Aliases: []string{"pk"},
-		Usage:   "Private key (overrides PRIVATE_KEY env var) - required for transaction commands",
-	},
+	config.PaymentsContractFlag(),
+	config.RPCFlag(),
+	config.PrivateKeyFlag(),
 }
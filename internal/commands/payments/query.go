@@ -8,6 +8,9 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
 // Query subcommands
@@ -18,7 +21,7 @@ func QueryContractInfoCommand() *cli.Command {
 		Aliases: []string{"info"},
 		Usage:   "Show contract basic information",
 		Flags:   paymentsFlags,
-		Action:  executeQueryContractInfo,
+		Action:  config.Action(executeQueryContractInfo),
 	}
 }
 
@@ -29,10 +32,9 @@ func QueryAccountCommand() *cli.Command {
 		Usage:   "Query account information",
 		Flags: append(paymentsFlags, []cli.Flag{
 			&cli.StringFlag{
-				Name:     "token",
-				Aliases:  []string{"t"},
-				Usage:    "Token address",
-				Required: true,
+				Name:    "token",
+				Aliases: []string{"t"},
+				Usage:   "Token address (defaults to the profile's payment token)",
 			},
 			&cli.StringFlag{
 				Name:     "address",
@@ -41,7 +43,7 @@ func QueryAccountCommand() *cli.Command {
 				Required: true,
 			},
 		}...),
-		Action: executeQueryAccount,
+		Action: config.Action(executeQueryAccount),
 	}
 }
 
@@ -52,10 +54,9 @@ func QueryOperatorApprovalCommand() *cli.Command {
 		Usage:   "Query operator approval information",
 		Flags: append(paymentsFlags, []cli.Flag{
 			&cli.StringFlag{
-				Name:     "token",
-				Aliases:  []string{"t"},
-				Usage:    "Token address",
-				Required: true,
+				Name:    "token",
+				Aliases: []string{"t"},
+				Usage:   "Token address (defaults to the profile's payment token)",
 			},
 			&cli.StringFlag{
 				Name:     "account",
@@ -70,7 +71,7 @@ func QueryOperatorApprovalCommand() *cli.Command {
 				Required: true,
 			},
 		}...),
-		Action: executeQueryOperatorApproval,
+		Action: config.Action(executeQueryOperatorApproval),
 	}
 }
 
@@ -78,33 +79,34 @@ func QueryRailCommand() *cli.Command {
 	return &cli.Command{
 		Name:    "rail",
 		Aliases: []string{"r"},
-		Usage:   "Query rail information by rail ID",
+		Usage:   "Query rail information by rail ID, or manage a rail via subcommands",
 		Flags: append(paymentsFlags, []cli.Flag{
 			&cli.Uint64Flag{
-				Name:     "rail-id",
-				Aliases:  []string{"id"},
-				Usage:    "Rail ID",
-				Required: true,
+				Name:    "rail-id",
+				Aliases: []string{"id"},
+				Usage:   "Rail ID",
 			},
 		}...),
-		Action: executeQueryRail,
+		Action: config.Action(executeQueryRail),
+		Subcommands: []*cli.Command{
+			TerminateRailCommand(),
+			SettleRailCommand(),
+			FinalizeRailCommand(),
+		},
 	}
 }
 
 // Query command implementations
 
-func executeQueryContractInfo(c *cli.Context) error {
-	client, err := createPaymentsClient(c)
+func executeQueryContractInfo(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	client, err := createPaymentsClient(cfg)
 	if err != nil {
 		return err
 	}
 	defer client.Close()
 
-	fmt.Printf("📋 Contract Information:\n")
-	fmt.Printf("   Address: %s\n", config.PaymentsContractAddress)
-	fmt.Printf("   RPC: %s\n", config.RPCEndpoint)
-	fmt.Println()
-
 	commissionMax, err := client.GetCommissionMaxBPS()
 	if err != nil {
 		return fmt.Errorf("failed to get commission max: %v", err)
@@ -120,59 +122,79 @@ func executeQueryContractInfo(c *cli.Context) error {
 		return fmt.Errorf("failed to get network fee denominator: %v", err)
 	}
 
-	fmt.Printf("   Commission Max BPS: %s\n", commissionMax.String())
-	fmt.Printf("   Network Fee: %s/%s\n", feeNum.String(), feeDenom.String())
-	fmt.Println()
-
-	return nil
+	info := types.PaymentsContractInfo{
+		Address:               common.HexToAddress(cfg.PaymentsContractAddress),
+		CommissionMaxBps:      commissionMax,
+		NetworkFeeNumerator:   feeNum,
+		NetworkFeeDenominator: feeDenom,
+	}
+	return output.Render(c, info, func() {
+		fmt.Fprintf(w, "📋 Contract Information:\n")
+		fmt.Fprintf(w, "   Address: %s\n", cfg.PaymentsContractAddress)
+		fmt.Fprintf(w, "   RPC: %s\n", cfg.RPCEndpoint)
+		fmt.Fprintln(w)
+
+		fmt.Fprintf(w, "   Commission Max BPS: %s\n", commissionMax.String())
+		fmt.Fprintf(w, "   Network Fee: %s/%s\n", feeNum.String(), feeDenom.String())
+		fmt.Fprintln(w)
+	})
 }
 
-func executeQueryAccount(c *cli.Context) error {
-	client, err := createPaymentsClient(c)
+func executeQueryAccount(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	client, err := createPaymentsClient(cfg)
 	if err != nil {
 		return err
 	}
 	defer client.Close()
 
-	tokenAddr := common.HexToAddress(c.String("token"))
+	tokenStr, err := cfg.TokenOrDefault("token", c.String("token"))
+	if err != nil {
+		return err
+	}
+	tokenAddr := common.HexToAddress(tokenStr)
 	accountAddr := common.HexToAddress(c.String("address"))
 
-	fmt.Printf("💰 Account Information:\n")
-	fmt.Printf("   Token: %s\n", tokenAddr.Hex())
-	fmt.Printf("   Account: %s\n", accountAddr.Hex())
-	fmt.Println()
-
 	account, err := client.GetAccount(tokenAddr, accountAddr)
 	if err != nil {
 		return fmt.Errorf("failed to get account: %v", err)
 	}
 
-	fmt.Printf("   Funds: %s\n", account.Funds.String())
-	fmt.Printf("   Lockup Current: %s\n", account.LockupCurrent.String())
-	fmt.Printf("   Lockup Rate: %s\n", account.LockupRate.String())
-	fmt.Printf("   Lockup Last Settled At: %s\n", account.LockupLastSettledAt.String())
-	fmt.Println()
-
-	return nil
+	details := types.AccountDetails{Token: tokenAddr, Address: accountAddr, Account: *account}
+	return output.Render(c, details, func() {
+		meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddr)
+
+		fmt.Fprintf(w, "💰 Account Information:\n")
+		fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, tokenAddr.Hex())
+		fmt.Fprintf(w, "   Account: %s\n", accountAddr.Hex())
+		fmt.Fprintln(w)
+
+		fmt.Fprintf(w, "   Funds: %s\n", utils.FormatTokenAmount(account.Funds, meta))
+		fmt.Fprintf(w, "   Lockup Current: %s\n", utils.FormatTokenAmount(account.LockupCurrent, meta))
+		fmt.Fprintf(w, "   Lockup Rate: %s per epoch\n", utils.FormatTokenAmount(account.LockupRate, meta))
+		fmt.Fprintf(w, "   Lockup Last Settled At: %s\n", account.LockupLastSettledAt.String())
+		fmt.Fprintln(w)
+	})
 }
 
-func executeQueryOperatorApproval(c *cli.Context) error {
-	client, err := createPaymentsClient(c)
+func executeQueryOperatorApproval(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	client, err := createPaymentsClient(cfg)
 	if err != nil {
 		return err
 	}
 	defer client.Close()
 
-	tokenAddr := common.HexToAddress(c.String("token"))
+	tokenStr, err := cfg.TokenOrDefault("token", c.String("token"))
+	if err != nil {
+		return err
+	}
+	tokenAddr := common.HexToAddress(tokenStr)
 	accountAddr := common.HexToAddress(c.String("account"))
 	operatorAddr := common.HexToAddress(c.String("operator"))
 
-	fmt.Printf("🔐 Operator Approval:\n")
-	fmt.Printf("   Token: %s\n", tokenAddr.Hex())
-	fmt.Printf("   Account: %s\n", accountAddr.Hex())
-	fmt.Printf("   Operator: %s\n", operatorAddr.Hex())
-	fmt.Println()
-
 	approval, err := client.GetOperatorApproval(tokenAddr, accountAddr, operatorAddr)
 	if err != nil {
 		return fmt.Errorf("failed to get operator approval: %v", err)
@@ -181,50 +203,73 @@ func executeQueryOperatorApproval(c *cli.Context) error {
 	rateAvailable := new(big.Int).Sub(approval.RateAllowance, approval.RateUsage)
 	lockupAvailable := new(big.Int).Sub(approval.LockupAllowance, approval.LockupUsage)
 
-	fmt.Printf("   Is Approved: %t\n", approval.IsApproved)
-	fmt.Printf("   Rate Allowance: %s\n", approval.RateAllowance.String())
-	fmt.Printf("   Rate Usage: %s\n", approval.RateUsage.String())
-	fmt.Printf("   Rate Available: %s\n", rateAvailable.String())
-	fmt.Printf("   Lockup Allowance: %s\n", approval.LockupAllowance.String())
-	fmt.Printf("   Lockup Usage: %s\n", approval.LockupUsage.String())
-	fmt.Printf("   Lockup Available: %s\n", lockupAvailable.String())
-	fmt.Printf("   Max Lockup Period: %s epochs\n", approval.MaxLockupPeriod.String())
-	fmt.Println()
-
-	return nil
+	details := types.OperatorApprovalDetails{
+		Token:            tokenAddr,
+		Account:          accountAddr,
+		Operator:         operatorAddr,
+		OperatorApproval: *approval,
+		RateAvailable:    rateAvailable,
+		LockupAvailable:  lockupAvailable,
+	}
+	return output.Render(c, details, func() {
+		meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddr)
+
+		fmt.Fprintf(w, "🔐 Operator Approval:\n")
+		fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, tokenAddr.Hex())
+		fmt.Fprintf(w, "   Account: %s\n", accountAddr.Hex())
+		fmt.Fprintf(w, "   Operator: %s\n", operatorAddr.Hex())
+		fmt.Fprintln(w)
+
+		fmt.Fprintf(w, "   Is Approved: %t\n", approval.IsApproved)
+		fmt.Fprintf(w, "   Rate Allowance: %s\n", utils.FormatTokenAmount(approval.RateAllowance, meta))
+		fmt.Fprintf(w, "   Rate Usage: %s\n", utils.FormatTokenAmount(approval.RateUsage, meta))
+		fmt.Fprintf(w, "   Rate Available: %s\n", utils.FormatTokenAmount(rateAvailable, meta))
+		fmt.Fprintf(w, "   Lockup Allowance: %s\n", utils.FormatTokenAmount(approval.LockupAllowance, meta))
+		fmt.Fprintf(w, "   Lockup Usage: %s\n", utils.FormatTokenAmount(approval.LockupUsage, meta))
+		fmt.Fprintf(w, "   Lockup Available: %s\n", utils.FormatTokenAmount(lockupAvailable, meta))
+		fmt.Fprintf(w, "   Max Lockup Period: %s epochs\n", approval.MaxLockupPeriod.String())
+		fmt.Fprintln(w)
+	})
 }
 
-func executeQueryRail(c *cli.Context) error {
-	client, err := createPaymentsClient(c)
+func executeQueryRail(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	client, err := createPaymentsClient(cfg)
 	if err != nil {
 		return err
 	}
 	defer client.Close()
 
+	if !c.IsSet("rail-id") {
+		return fmt.Errorf("--rail-id is required")
+	}
 	railId := big.NewInt(int64(c.Uint64("rail-id")))
 
-	fmt.Printf("🚄 Rail Information:\n")
-	fmt.Printf("   Rail ID: %s\n", railId.String())
-	fmt.Println()
-
 	rail, err := client.GetRail(railId)
 	if err != nil {
 		return fmt.Errorf("failed to get rail: %v", err)
 	}
 
-	fmt.Printf("   Token: %s\n", rail.Token.Hex())
-	fmt.Printf("   From: %s\n", rail.From.Hex())
-	fmt.Printf("   To: %s\n", rail.To.Hex())
-	fmt.Printf("   Operator: %s\n", rail.Operator.Hex())
-	fmt.Printf("   Validator: %s\n", rail.Validator.Hex())
-	fmt.Printf("   Payment Rate: %s\n", rail.PaymentRate.String())
-	fmt.Printf("   Lockup Period: %s epochs\n", rail.LockupPeriod.String())
-	fmt.Printf("   Lockup Fixed: %s\n", rail.LockupFixed.String())
-	fmt.Printf("   Settled Up To: %s\n", rail.SettledUpTo.String())
-	fmt.Printf("   End Epoch: %s\n", rail.EndEpoch.String())
-	fmt.Printf("   Commission Rate BPS: %s\n", rail.CommissionRateBps.String())
-	fmt.Printf("   Service Fee Recipient: %s\n", rail.ServiceFeeRecipient.Hex())
-	fmt.Println()
-
-	return nil
+	return output.Render(c, types.RailDetails{RailId: railId, RailView: *rail}, func() {
+		meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, rail.Token)
+
+		fmt.Fprintf(w, "🚄 Rail Information:\n")
+		fmt.Fprintf(w, "   Rail ID: %s\n", railId.String())
+		fmt.Fprintln(w)
+
+		fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, rail.Token.Hex())
+		fmt.Fprintf(w, "   From: %s\n", rail.From.Hex())
+		fmt.Fprintf(w, "   To: %s\n", rail.To.Hex())
+		fmt.Fprintf(w, "   Operator: %s\n", rail.Operator.Hex())
+		fmt.Fprintf(w, "   Validator: %s\n", rail.Validator.Hex())
+		fmt.Fprintf(w, "   Payment Rate: %s per epoch\n", utils.FormatTokenAmount(rail.PaymentRate, meta))
+		fmt.Fprintf(w, "   Lockup Period: %s epochs\n", rail.LockupPeriod.String())
+		fmt.Fprintf(w, "   Lockup Fixed: %s\n", utils.FormatTokenAmount(rail.LockupFixed, meta))
+		fmt.Fprintf(w, "   Settled Up To: %s\n", rail.SettledUpTo.String())
+		fmt.Fprintf(w, "   End Epoch: %s\n", rail.EndEpoch.String())
+		fmt.Fprintf(w, "   Commission Rate BPS: %s\n", rail.CommissionRateBps.String())
+		fmt.Fprintf(w, "   Service Fee Recipient: %s\n", rail.ServiceFeeRecipient.Hex())
+		fmt.Fprintln(w)
+	})
 }
@@ -0,0 +1,362 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+var railActionFlags = append(paymentsFlags, []cli.Flag{
+	config.ContractFlag(),
+	&cli.Uint64Flag{
+		Name:    "rail-id",
+		Aliases: []string{"id"},
+		Usage:   "Rail ID (required if --allocation-id is not specified)",
+	},
+	&cli.Uint64Flag{
+		Name:    "allocation-id",
+		Aliases: []string{"a"},
+		Usage:   "Allocation ID whose rail should be used (required if --rail-id is not specified)",
+	},
+	&cli.BoolFlag{
+		Name:    "yes",
+		Aliases: []string{"y"},
+		Usage:   "Skip the confirmation prompt",
+	},
+}...)
+
+func TerminateRailCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "terminate",
+		Usage:  "Terminate a payment rail as its payer (client)",
+		Flags:  railActionFlags,
+		Action: config.Action(executeTerminateRail),
+	}
+}
+
+func SettleRailCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "settle",
+		Usage: "Settle a payment rail up to an epoch",
+		Flags: append(railActionFlags, []cli.Flag{
+			&cli.Uint64Flag{
+				Name:    "until-epoch",
+				Aliases: []string{"e"},
+				Usage:   "Epoch until which to settle (defaults to current block number)",
+			},
+		}...),
+		Action: config.Action(executeSettleRail),
+	}
+}
+
+func FinalizeRailCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "finalize",
+		Usage:  "Settle a terminated rail past its end epoch without validation and release the remaining lockup",
+		Flags:  railActionFlags,
+		Action: config.Action(executeFinalizeRail),
+	}
+}
+
+// railTarget holds everything a rail action needs after resolving flags
+type railTarget struct {
+	cfg          config.Config
+	client       *payments.Client
+	userAddress  common.Address
+	railId       *big.Int
+	allocationId uint64
+	rail         *types.RailView
+	currentEpoch *big.Int
+	feeNum       *big.Int
+	feeDenom     *big.Int
+}
+
+// prepareRailAction resolves the rail from --rail-id or --allocation-id and creates a
+// transacting payments client
+func prepareRailAction(c *cli.Context, cfg config.Config) (*railTarget, error) {
+	if err := validatePrivateKeyConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	railIdSet := c.IsSet("rail-id")
+	allocationId := c.Uint64("allocation-id")
+	if !railIdSet && allocationId == 0 {
+		return nil, fmt.Errorf("either --rail-id or --allocation-id must be specified")
+	}
+	if railIdSet && allocationId != 0 {
+		return nil, fmt.Errorf("only one of --rail-id or --allocation-id can be specified")
+	}
+
+	var railId *big.Int
+	if allocationId != 0 {
+		if cfg.ContractAddress == "" {
+			return nil, fmt.Errorf("DDO contract address required to look up the rail (use --contract flag or DDO_CONTRACT_ADDRESS env var)")
+		}
+
+		ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create DDO contract client: %v", err)
+		}
+		defer ddoClient.Close()
+
+		id, _, _, err := ddoClient.GetAllocationRailInfo(allocationId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rail for allocation %d: %v", allocationId, err)
+		}
+		if id == 0 {
+			return nil, fmt.Errorf("allocation %d has no payment rail", allocationId)
+		}
+		railId = new(big.Int).SetUint64(id)
+	}
+	if railId == nil {
+		railId = new(big.Int).SetUint64(c.Uint64("rail-id"))
+	}
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+
+	client, err := payments.NewClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payments transaction client: %v", err)
+	}
+
+	target := &railTarget{
+		cfg:          cfg,
+		client:       client,
+		userAddress:  crypto.PubkeyToAddress(privateKey.PublicKey),
+		railId:       railId,
+		allocationId: allocationId,
+	}
+
+	target.rail, err = client.GetRail(railId)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get rail %s: %v", railId.String(), err)
+	}
+
+	blockNumber, err := client.GetEthClient().BlockNumber(context.Background())
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get current block number: %v", err)
+	}
+	target.currentEpoch = new(big.Int).SetUint64(blockNumber)
+
+	target.feeNum, err = client.GetNetworkFeeNumerator()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get network fee numerator: %v", err)
+	}
+	target.feeDenom, err = client.GetNetworkFeeDenominator()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get network fee denominator: %v", err)
+	}
+
+	return target, nil
+}
+
+func printRailSummary(w io.Writer, t *railTarget) {
+	fmt.Fprintf(w, "🚄 Rail %s:\n", t.railId.String())
+	if t.allocationId != 0 {
+		fmt.Fprintf(w, "   Allocation ID: %d\n", t.allocationId)
+	}
+	meta := utils.LookupTokenMetadata(t.cfg.RPCEndpoint, t.cfg.RPC, t.rail.Token)
+	fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, t.rail.Token.Hex())
+	fmt.Fprintf(w, "   Payer: %s\n", t.rail.From.Hex())
+	fmt.Fprintf(w, "   Payee: %s\n", t.rail.To.Hex())
+	fmt.Fprintf(w, "   Operator: %s\n", t.rail.Operator.Hex())
+	fmt.Fprintf(w, "   Payment Rate: %s per epoch\n", utils.FormatTokenAmount(t.rail.PaymentRate, meta))
+	fmt.Fprintf(w, "   Lockup Period: %s epochs\n", t.rail.LockupPeriod.String())
+	fmt.Fprintf(w, "   Lockup Fixed: %s\n", utils.FormatTokenAmount(t.rail.LockupFixed, meta))
+	fmt.Fprintf(w, "   Settled Up To: %s\n", t.rail.SettledUpTo.String())
+	if utils.IsRailTerminated(t.rail) {
+		fmt.Fprintf(w, "   Status: terminated (end epoch %s)\n", t.rail.EndEpoch.String())
+	} else {
+		fmt.Fprintf(w, "   Status: active\n")
+	}
+	fmt.Fprintf(w, "   Current Epoch: %s\n", t.currentEpoch.String())
+	fmt.Fprintln(w)
+}
+
+func printRailProjection(w io.Writer, title string, p *types.RailProjection, meta *types.TokenMetadata) {
+	fmt.Fprintf(w, "📊 %s:\n", title)
+	fmt.Fprintf(w, "   Epochs: %s -> %s\n", p.FromEpoch.String(), p.UntilEpoch.String())
+	fmt.Fprintf(w, "   Gross Payout: %s\n", utils.FormatTokenAmount(p.GrossPayout, meta))
+	fmt.Fprintf(w, "   Network Fee: %s\n", utils.FormatTokenAmount(p.NetworkFee, meta))
+	fmt.Fprintf(w, "   Operator Commission: %s\n", utils.FormatTokenAmount(p.OperatorCommission, meta))
+	fmt.Fprintf(w, "   Net Payout to Payee: %s\n", utils.FormatTokenAmount(p.NetPayout, meta))
+	fmt.Fprintf(w, "   Lockup Refunded to Payer: %s\n", utils.FormatTokenAmount(p.LockupRefund, meta))
+	fmt.Fprintln(w)
+}
+
+// sendRailTransaction asks for confirmation, sends the transaction, waits for it and
+// prints the result
+func sendRailTransaction(c *cli.Context, t *railTarget, action string, projection *types.RailProjection, send func() (string, error)) error {
+	w := output.Progress(c)
+
+	result := types.RailActionResult{
+		Action:       strings.ToLower(action),
+		RailId:       t.railId,
+		AllocationId: t.allocationId,
+		Projection:   projection,
+	}
+
+	if !c.Bool("yes") {
+		ok, err := output.Confirm(w, fmt.Sprintf("%s rail %s?", action, t.railId.String()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return output.Render(c, result, func() {
+				fmt.Fprintf(w, "Aborted - no transaction sent\n")
+			})
+		}
+	}
+
+	fmt.Fprintf(w, "📝 Sending %s transaction...\n", strings.ToLower(action))
+	txHash, err := send()
+	if err != nil {
+		return fmt.Errorf("failed to %s rail: %v", strings.ToLower(action), err)
+	}
+	fmt.Fprintf(w, "✅ Transaction sent: %s\n", txHash)
+
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	tx := output.WaitTx(t.client.TxManager(), txHash)
+	if tx.Status == types.TxStatusPending {
+		return fmt.Errorf("%s transaction failed: %s", strings.ToLower(action), tx.Error)
+	}
+	result.Tx = &tx
+	if err := output.TxError(tx); err != nil {
+		return err
+	}
+
+	rail, err := t.client.GetRail(t.railId)
+	if err != nil {
+		fmt.Fprintf(w, "⚠️  Warning: could not read rail after transaction: %v\n", err)
+		return output.Render(c, result, nil)
+	}
+	result.SettledUpTo = rail.SettledUpTo
+	result.EndEpoch = rail.EndEpoch
+
+	return output.Render(c, result, func() {
+		fmt.Fprintf(w, "✅ %s completed\n", action)
+		fmt.Fprintf(w, "   Settled Up To: %s\n", rail.SettledUpTo.String())
+		fmt.Fprintf(w, "   End Epoch: %s\n", rail.EndEpoch.String())
+	})
+}
+
+func executeTerminateRail(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	t, err := prepareRailAction(c, cfg)
+	if err != nil {
+		return err
+	}
+	defer t.client.Close()
+
+	printRailSummary(w, t)
+
+	if utils.IsRailTerminated(t.rail) {
+		return fmt.Errorf("rail %s is already terminated (end epoch %s)", t.railId.String(), t.rail.EndEpoch.String())
+	}
+	if t.rail.From != t.userAddress {
+		return fmt.Errorf("only the rail payer %s can terminate this rail from the client side (you are %s)", t.rail.From.Hex(), t.userAddress.Hex())
+	}
+
+	// After termination the payee keeps being paid for one lockup period
+	endEpoch := new(big.Int).Add(t.currentEpoch, t.rail.LockupPeriod)
+	terminated := *t.rail
+	terminated.EndEpoch = endEpoch
+	projection := utils.ProjectRailSettlement(t.railId, &terminated, endEpoch, t.feeNum, t.feeDenom)
+	printRailProjection(w, "Projected Outcome After Termination", projection, utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, t.rail.Token))
+
+	return sendRailTransaction(c, t, "Terminate", projection, func() (string, error) {
+		return t.client.TerminateRail(t.railId)
+	})
+}
+
+func executeSettleRail(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	t, err := prepareRailAction(c, cfg)
+	if err != nil {
+		return err
+	}
+	defer t.client.Close()
+
+	printRailSummary(w, t)
+
+	if t.rail.From != t.userAddress && t.rail.To != t.userAddress {
+		return fmt.Errorf("only the rail payer or payee can settle this rail (you are %s)", t.userAddress.Hex())
+	}
+
+	untilEpoch := new(big.Int).Set(t.currentEpoch)
+	if c.IsSet("until-epoch") {
+		untilEpoch.SetUint64(c.Uint64("until-epoch"))
+		if untilEpoch.Cmp(t.currentEpoch) > 0 {
+			return fmt.Errorf("cannot settle future epochs: until-epoch %s is after current epoch %s", untilEpoch.String(), t.currentEpoch.String())
+		}
+	}
+
+	projection := utils.ProjectRailSettlement(t.railId, t.rail, untilEpoch, t.feeNum, t.feeDenom)
+	// Lockup is only released when a terminated rail is fully settled
+	if !utils.IsRailTerminated(t.rail) || projection.UntilEpoch.Cmp(t.rail.EndEpoch) < 0 {
+		projection.LockupRefund = big.NewInt(0)
+	}
+	printRailProjection(w, "Projected Settlement", projection, utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, t.rail.Token))
+
+	if projection.GrossPayout.Sign() == 0 && !utils.IsRailTerminated(t.rail) {
+		result := types.RailActionResult{Action: "settle", RailId: t.railId, AllocationId: t.allocationId, Projection: projection}
+		return output.Render(c, result, func() {
+			fmt.Fprintf(w, "Nothing to settle\n")
+		})
+	}
+
+	return sendRailTransaction(c, t, "Settle", projection, func() (string, error) {
+		return t.client.SettleRail(t.railId, projection.UntilEpoch)
+	})
+}
+
+func executeFinalizeRail(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	t, err := prepareRailAction(c, cfg)
+	if err != nil {
+		return err
+	}
+	defer t.client.Close()
+
+	printRailSummary(w, t)
+
+	if !utils.IsRailTerminated(t.rail) {
+		return fmt.Errorf("rail %s is not terminated - run 'payments rail terminate' first", t.railId.String())
+	}
+	if t.rail.From != t.userAddress {
+		return fmt.Errorf("only the rail payer %s can finalize this rail (you are %s)", t.rail.From.Hex(), t.userAddress.Hex())
+	}
+	if t.currentEpoch.Cmp(t.rail.EndEpoch) <= 0 {
+		return fmt.Errorf("rail %s cannot be finalized until after epoch %s (current epoch %s)", t.railId.String(), t.rail.EndEpoch.String(), t.currentEpoch.String())
+	}
+
+	projection := utils.ProjectRailSettlement(t.railId, t.rail, t.rail.EndEpoch, t.feeNum, t.feeDenom)
+	printRailProjection(w, "Projected Final Settlement", projection, utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, t.rail.Token))
+
+	return sendRailTransaction(c, t, "Finalize", projection, func() (string, error) {
+		return t.client.SettleTerminatedRailWithoutValidation(t.railId)
+	})
+}
@@ -10,7 +10,10 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/types"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
@@ -21,10 +24,9 @@ func SetOperatorAllowanceCommand() *cli.Command {
 		Usage:   "Set or update operator approval and allowances",
 		Flags: append(paymentsFlags, []cli.Flag{
 			&cli.StringFlag{
-				Name:     "token",
-				Aliases:  []string{"t"},
-				Usage:    "ERC20 token contract address",
-				Required: true,
+				Name:    "token",
+				Aliases: []string{"t"},
+				Usage:   "ERC20 token contract address (defaults to the profile's payment token)",
 			},
 			&cli.StringFlag{
 				Name:     "operator",
@@ -40,12 +42,12 @@ func SetOperatorAllowanceCommand() *cli.Command {
 			&cli.StringFlag{
 				Name:    "rate-allowance",
 				Aliases: []string{"ra"},
-				Usage:   "Maximum payment rate the operator can set (defaults to current value)",
+				Usage:   "Maximum payment rate per epoch the operator can set, in token units (\"0.01\", \"0.01 USDFC\") or base units (\"10000 wei\") (defaults to current value)",
 			},
 			&cli.StringFlag{
 				Name:    "lockup-allowance",
 				Aliases: []string{"la"},
-				Usage:   "Maximum amount of funds the operator can lock up (defaults to current value)",
+				Usage:   "Maximum amount of funds the operator can lock up, in token units (\"50\", \"50 USDFC\") or base units (\"50000000 wei\") (defaults to current value)",
 			},
 			&cli.StringFlag{
 				Name:    "max-lockup-period",
@@ -61,41 +63,47 @@ func SetOperatorAllowanceCommand() *cli.Command {
 				Usage: "Only check current operator approval without setting",
 			},
 		}...),
-		Action: executeSetOperatorAllowance,
+		Action: config.Action(executeSetOperatorAllowance),
 	}
 }
 
-func executeSetOperatorAllowance(c *cli.Context) error {
+func executeSetOperatorAllowance(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
 	// Validate private key configuration
-	if err := validatePrivateKeyConfig(c); err != nil {
+	if err := validatePrivateKeyConfig(cfg); err != nil {
 		return err
 	}
 
-	tokenAddress := common.HexToAddress(c.String("token"))
+	tokenStr, err := cfg.TokenOrDefault("token", c.String("token"))
+	if err != nil {
+		return err
+	}
+	tokenAddress := common.HexToAddress(tokenStr)
 	operatorAddress := common.HexToAddress(c.String("operator"))
 	approved := c.Bool("approved")
 	checkOnly := c.Bool("check-only")
 	unlimited := c.Bool("unlimited")
 
 	// Get user address from private key
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey, "0x"))
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
 	if err != nil {
 		return fmt.Errorf("failed to parse private key: %v", err)
 	}
 	userAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
 
 	// Create payments client
-	paymentsClient, err := payments.NewReadOnlyClientWithParams(config.RPCEndpoint, config.PaymentsContractAddress)
+	paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create payments client: %v", err)
 	}
 	defer paymentsClient.Close()
 
-	fmt.Printf("🔍 Operator Allowance Management:\n")
-	fmt.Printf("   Account: %s\n", userAddress.Hex())
-	fmt.Printf("   Token: %s\n", tokenAddress.Hex())
-	fmt.Printf("   Operator: %s\n", operatorAddress.Hex())
-	fmt.Println()
+	fmt.Fprintf(w, "🔍 Operator Allowance Management:\n")
+	fmt.Fprintf(w, "   Account: %s\n", userAddress.Hex())
+	fmt.Fprintf(w, "   Token: %s\n", tokenAddress.Hex())
+	fmt.Fprintf(w, "   Operator: %s\n", operatorAddress.Hex())
+	fmt.Fprintln(w)
 
 	// Check current operator approval
 	currentApproval, err := paymentsClient.GetOperatorApproval(tokenAddress, userAddress, operatorAddress)
@@ -103,24 +111,39 @@ func executeSetOperatorAllowance(c *cli.Context) error {
 		return fmt.Errorf("failed to get current operator approval: %v", err)
 	}
 
+	meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get token metadata: %v", err)
+	}
+
 	rateAvailable := new(big.Int).Sub(currentApproval.RateAllowance, currentApproval.RateUsage)
 	lockupAvailable := new(big.Int).Sub(currentApproval.LockupAllowance, currentApproval.LockupUsage)
 
-	fmt.Printf("📊 Current Status:\n")
-	fmt.Printf("   Is Approved: %t\n", currentApproval.IsApproved)
-	fmt.Printf("   Rate Allowance: %s\n", currentApproval.RateAllowance.String())
-	fmt.Printf("   Rate Usage: %s\n", currentApproval.RateUsage.String())
-	fmt.Printf("   Rate Available: %s\n", rateAvailable.String())
-	fmt.Printf("   Lockup Allowance: %s\n", currentApproval.LockupAllowance.String())
-	fmt.Printf("   Lockup Usage: %s\n", currentApproval.LockupUsage.String())
-	fmt.Printf("   Lockup Available: %s\n", lockupAvailable.String())
-	fmt.Printf("   Max Lockup Period: %s epochs\n", currentApproval.MaxLockupPeriod.String())
-	fmt.Println()
+	fmt.Fprintf(w, "📊 Current Status:\n")
+	fmt.Fprintf(w, "   Is Approved: %t\n", currentApproval.IsApproved)
+	fmt.Fprintf(w, "   Rate Allowance: %s\n", utils.FormatTokenAmount(currentApproval.RateAllowance, meta))
+	fmt.Fprintf(w, "   Rate Usage: %s\n", utils.FormatTokenAmount(currentApproval.RateUsage, meta))
+	fmt.Fprintf(w, "   Rate Available: %s\n", utils.FormatTokenAmount(rateAvailable, meta))
+	fmt.Fprintf(w, "   Lockup Allowance: %s\n", utils.FormatTokenAmount(currentApproval.LockupAllowance, meta))
+	fmt.Fprintf(w, "   Lockup Usage: %s\n", utils.FormatTokenAmount(currentApproval.LockupUsage, meta))
+	fmt.Fprintf(w, "   Lockup Available: %s\n", utils.FormatTokenAmount(lockupAvailable, meta))
+	fmt.Fprintf(w, "   Max Lockup Period: %s epochs\n", currentApproval.MaxLockupPeriod.String())
+	fmt.Fprintln(w)
+
+	details := types.OperatorApprovalDetails{
+		Token:            tokenAddress,
+		Account:          userAddress,
+		Operator:         operatorAddress,
+		OperatorApproval: *currentApproval,
+		RateAvailable:    rateAvailable,
+		LockupAvailable:  lockupAvailable,
+	}
 
 	// If check-only, just display the information
 	if checkOnly {
-		fmt.Printf("✅ Operator approval check completed\n")
-		return nil
+		return output.Render(c, details, func() {
+			fmt.Fprintf(w, "✅ Operator approval check completed\n")
+		})
 	}
 
 	// Determine the allowances to set
@@ -133,16 +156,14 @@ func executeSetOperatorAllowance(c *cli.Context) error {
 		rateAllowance = maxUint256
 		lockupAllowance = maxUint256
 		maxLockupPeriod = big.NewInt(525600) // 1 year in epochs (assuming ~1 minute per epoch)
-		fmt.Printf("🔓 Setting unlimited allowances...\n")
+		fmt.Fprintf(w, "🔓 Setting unlimited allowances...\n")
 	} else {
 		// Parse provided values or use current values as defaults
 		rateAllowanceStr := c.String("rate-allowance")
 		if rateAllowanceStr != "" {
-			rateAllowance = new(big.Int)
-			var ok bool
-			rateAllowance, ok = rateAllowance.SetString(rateAllowanceStr, 10)
-			if !ok {
-				return fmt.Errorf("invalid rate-allowance format: %s", rateAllowanceStr)
+			rateAllowance, err = utils.ParseTokenAmount(rateAllowanceStr, meta)
+			if err != nil {
+				return fmt.Errorf("invalid rate-allowance: %v", err)
 			}
 		} else {
 			// Default to current value if not specified
@@ -151,11 +172,9 @@ func executeSetOperatorAllowance(c *cli.Context) error {
 
 		lockupAllowanceStr := c.String("lockup-allowance")
 		if lockupAllowanceStr != "" {
-			lockupAllowance = new(big.Int)
-			var ok bool
-			lockupAllowance, ok = lockupAllowance.SetString(lockupAllowanceStr, 10)
-			if !ok {
-				return fmt.Errorf("invalid lockup-allowance format: %s", lockupAllowanceStr)
+			lockupAllowance, err = utils.ParseTokenAmount(lockupAllowanceStr, meta)
+			if err != nil {
+				return fmt.Errorf("invalid lockup-allowance: %v", err)
 			}
 		} else {
 			// Default to current value if not specified
@@ -175,33 +194,33 @@ func executeSetOperatorAllowance(c *cli.Context) error {
 			maxLockupPeriod = new(big.Int).Set(currentApproval.MaxLockupPeriod)
 		}
 
-		fmt.Printf("💰 Setting allowances:\n")
+		fmt.Fprintf(w, "💰 Setting allowances:\n")
 		if c.String("rate-allowance") != "" {
-			fmt.Printf("   Rate Allowance: %s (new value)\n", rateAllowance.String())
+			fmt.Fprintf(w, "   Rate Allowance: %s (new value)\n", utils.FormatTokenAmount(rateAllowance, meta))
 		} else {
-			fmt.Printf("   Rate Allowance: %s (keeping current)\n", rateAllowance.String())
+			fmt.Fprintf(w, "   Rate Allowance: %s (keeping current)\n", utils.FormatTokenAmount(rateAllowance, meta))
 		}
 		if c.String("lockup-allowance") != "" {
-			fmt.Printf("   Lockup Allowance: %s (new value)\n", lockupAllowance.String())
+			fmt.Fprintf(w, "   Lockup Allowance: %s (new value)\n", utils.FormatTokenAmount(lockupAllowance, meta))
 		} else {
-			fmt.Printf("   Lockup Allowance: %s (keeping current)\n", lockupAllowance.String())
+			fmt.Fprintf(w, "   Lockup Allowance: %s (keeping current)\n", utils.FormatTokenAmount(lockupAllowance, meta))
 		}
 		if c.String("max-lockup-period") != "" {
-			fmt.Printf("   Max Lockup Period: %s epochs (new value)\n", maxLockupPeriod.String())
+			fmt.Fprintf(w, "   Max Lockup Period: %s epochs (new value)\n", maxLockupPeriod.String())
 		} else {
-			fmt.Printf("   Max Lockup Period: %s epochs (keeping current)\n", maxLockupPeriod.String())
+			fmt.Fprintf(w, "   Max Lockup Period: %s epochs (keeping current)\n", maxLockupPeriod.String())
 		}
 	}
 
 	// Create payments client for transactions
-	paymentsTransactClient, err := payments.NewClientWithParams(config.RPCEndpoint, config.PaymentsContractAddress, config.PrivateKey)
+	paymentsTransactClient, err := payments.NewClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 	if err != nil {
 		return fmt.Errorf("failed to create payments transaction client: %v", err)
 	}
 	defer paymentsTransactClient.Close()
 
 	// Send the set operator approval transaction
-	fmt.Printf("📝 Sending operator approval transaction...\n")
+	fmt.Fprintf(w, "📝 Sending operator approval transaction...\n")
 	txHash, err := paymentsTransactClient.SetOperatorApproval(
 		tokenAddress,
 		operatorAddress,
@@ -214,33 +233,38 @@ func executeSetOperatorAllowance(c *cli.Context) error {
 		return fmt.Errorf("failed to set operator approval: %v", err)
 	}
 
-	fmt.Printf("✅ Operator approval transaction sent: %s\n", txHash)
+	fmt.Fprintf(w, "✅ Operator approval transaction sent: %s\n", txHash)
 
 	// Wait for transaction to be mined using the payments client's ethclient
-	fmt.Printf("⏳ Waiting for transaction to be mined...\n")
-	if err := utils.WaitForTransaction(paymentsTransactClient.GetEthClient(), txHash); err != nil {
-		fmt.Printf("⚠️  Warning: transaction may not have been mined: %v\n", err)
-		return nil
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	tx := output.WaitTx(paymentsTransactClient.TxManager(), txHash)
+	details.Tx = &tx
+	if tx.Status != types.TxStatusSuccess {
+		if err := output.Render(c, details, func() { output.PrintTxOutcome(w, tx, nil) }); err != nil {
+			return err
+		}
+		return output.TxError(tx)
 	}
 
 	// Verify the new operator approval
 	newApproval, err := paymentsClient.GetOperatorApproval(tokenAddress, userAddress, operatorAddress)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: could not verify new operator approval: %v\n", err)
-		return nil
+		fmt.Fprintf(w, "⚠️  Warning: could not verify new operator approval: %v\n", err)
+		return output.Render(c, details, nil)
 	}
 
-	newRateAvailable := new(big.Int).Sub(newApproval.RateAllowance, newApproval.RateUsage)
-	newLockupAvailable := new(big.Int).Sub(newApproval.LockupAllowance, newApproval.LockupUsage)
-
-	fmt.Printf("✅ Transaction mined successfully!\n")
-	fmt.Printf("📊 New Operator Approval Status:\n")
-	fmt.Printf("   Is Approved: %t\n", newApproval.IsApproved)
-	fmt.Printf("   Rate Allowance: %s\n", newApproval.RateAllowance.String())
-	fmt.Printf("   Rate Available: %s\n", newRateAvailable.String())
-	fmt.Printf("   Lockup Allowance: %s\n", newApproval.LockupAllowance.String())
-	fmt.Printf("   Lockup Available: %s\n", newLockupAvailable.String())
-	fmt.Printf("   Max Lockup Period: %s epochs\n", newApproval.MaxLockupPeriod.String())
+	details.OperatorApproval = *newApproval
+	details.RateAvailable = new(big.Int).Sub(newApproval.RateAllowance, newApproval.RateUsage)
+	details.LockupAvailable = new(big.Int).Sub(newApproval.LockupAllowance, newApproval.LockupUsage)
 
-	return nil
+	return output.Render(c, details, func() {
+		fmt.Fprintf(w, "✅ Transaction mined successfully!\n")
+		fmt.Fprintf(w, "📊 New Operator Approval Status:\n")
+		fmt.Fprintf(w, "   Is Approved: %t\n", newApproval.IsApproved)
+		fmt.Fprintf(w, "   Rate Allowance: %s\n", utils.FormatTokenAmount(newApproval.RateAllowance, meta))
+		fmt.Fprintf(w, "   Rate Available: %s\n", utils.FormatTokenAmount(details.RateAvailable, meta))
+		fmt.Fprintf(w, "   Lockup Allowance: %s\n", utils.FormatTokenAmount(newApproval.LockupAllowance, meta))
+		fmt.Fprintf(w, "   Lockup Available: %s\n", utils.FormatTokenAmount(details.LockupAvailable, meta))
+		fmt.Fprintf(w, "   Max Lockup Period: %s epochs\n", newApproval.MaxLockupPeriod.String())
+	})
 }
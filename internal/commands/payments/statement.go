@@ -0,0 +1,317 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// defaultStatementDays is the lookback used when --from is not given
+const defaultStatementDays = 30
+
+// statementLineOutput is a statement line with USD-formatted amounts for JSON output
+type statementLineOutput struct {
+	types.StatementLine
+	GrossUSD              string `json:"grossUsd"`
+	NetworkFeeUSD         string `json:"networkFeeUsd"`
+	OperatorCommissionUSD string `json:"operatorCommissionUsd"`
+	NetUSD                string `json:"netUsd"`
+}
+
+// clientStatement is the JSON form of a client spending statement
+type clientStatement struct {
+	Client       common.Address        `json:"client"`
+	FromEpoch    uint64                `json:"fromEpoch"`
+	ToEpoch      uint64                `json:"toEpoch"`
+	Totals       []statementLineOutput `json:"totals"`
+	ByAllocation []statementLineOutput `json:"byAllocation"`
+	ByProvider   []statementLineOutput `json:"byProvider"`
+	Payments     []types.PaymentRecord `json:"payments"`
+}
+
+func StatementCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "statement",
+		Usage: "Produce a spending statement for a client's allocations",
+		Flags: append(paymentsFlags, []cli.Flag{
+			config.ContractFlag(),
+			&cli.StringFlag{
+				Name:  "client",
+				Usage: "Client address (defaults to the address of --private-key / PRIVATE_KEY)",
+			},
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: fmt.Sprintf("Start of the statement as an epoch or date (YYYY-MM-DD) - defaults to %d days before --to", defaultStatementDays),
+			},
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "End of the statement as an epoch or date (YYYY-MM-DD, included in full) - defaults to current block number",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table, csv, json or yaml (overrides --output)",
+			},
+			&cli.StringFlag{
+				Name:  "group-by",
+				Usage: "Grouping used for csv output: allocation, provider or token",
+				Value: "allocation",
+			},
+			&cli.BoolFlag{
+				Name:  "raw",
+				Usage: "Write csv amounts in token base units instead of USD",
+			},
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Write csv/json output to this file instead of stdout",
+			},
+		}...),
+		Action: config.Action(executeStatement),
+	}
+}
+
+func executeStatement(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	if cfg.ContractAddress == "" {
+		return fmt.Errorf("missing DDO contract address (use --contract flag or DDO_CONTRACT_ADDRESS env var)")
+	}
+	if cfg.RPCEndpoint == "" {
+		return fmt.Errorf("RPC endpoint required (use --rpc flag or RPC_URL env var)")
+	}
+
+	format := output.Format(c)
+	if format != "table" && format != "csv" && format != "json" && format != "yaml" {
+		return fmt.Errorf("invalid format %q (expected table, csv, json or yaml)", format)
+	}
+	groupBy := c.String("group-by")
+	if groupBy != "allocation" && groupBy != "provider" && groupBy != "token" {
+		return fmt.Errorf("invalid group-by %q (expected allocation, provider or token)", groupBy)
+	}
+
+	var clientAddr common.Address
+	if addr := c.String("client"); addr != "" {
+		clientAddr = common.HexToAddress(addr)
+	} else if cfg.PrivateKey != "" {
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("failed to parse private key: %v", err)
+		}
+		clientAddr = crypto.PubkeyToAddress(privateKey.PublicKey)
+	} else {
+		return fmt.Errorf("client address required (use --client or --private-key)")
+	}
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	paymentsAddr := cfg.PaymentsContractAddress
+	if paymentsAddr == "" {
+		addr, err := ddoClient.GetPaymentsContract()
+		if err != nil {
+			return fmt.Errorf("failed to get payments contract address from DDO contract: %v", err)
+		}
+		paymentsAddr = addr.Hex()
+	}
+
+	paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, paymentsAddr, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create payments client: %v", err)
+	}
+	defer paymentsClient.Close()
+
+	header, err := ddoClient.GetEthClient().HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block header: %v", err)
+	}
+	currentEpoch := header.Number.Uint64()
+	currentTime := time.Unix(int64(header.Time), 0).UTC()
+
+	toEpoch := currentEpoch
+	if to := c.String("to"); to != "" {
+		toEpoch, err = utils.ParseEndEpochOrDate(to, currentEpoch, currentTime)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %v", err)
+		}
+		if toEpoch > currentEpoch {
+			toEpoch = currentEpoch
+		}
+	}
+	var fromEpoch uint64
+	if from := c.String("from"); from != "" {
+		fromEpoch, err = utils.ParseEpochOrDate(from, currentEpoch, currentTime)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %v", err)
+		}
+	} else if lookback := uint64(defaultStatementDays * utils.EPOCHS_PER_DAY); toEpoch > lookback {
+		fromEpoch = toEpoch - lookback
+	}
+	if fromEpoch > toEpoch {
+		return fmt.Errorf("--from (epoch %d) is after --to (epoch %d)", fromEpoch, toEpoch)
+	}
+
+	allocationIds, err := ddoClient.GetAllocationIdsForClient(clientAddr.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to get allocation IDs for client: %v", err)
+	}
+
+	rails, err := utils.GetAllocationRails(ddoClient, allocationIds)
+	if err != nil {
+		return err
+	}
+
+	records, err := utils.CollectPaymentRecords(paymentsClient, rails, fromEpoch, toEpoch, currentEpoch, currentTime)
+	if err != nil {
+		return fmt.Errorf("failed to collect payment records: %v", err)
+	}
+
+	byAllocation := utils.SummarizeByAllocation(records)
+	byProvider := utils.SummarizeByProvider(records)
+	totals := utils.SummarizeByToken(records)
+
+	if format == "table" {
+		printClientStatement(w, cfg, clientAddr, fromEpoch, toEpoch, len(allocationIds), len(records), totals, byAllocation, byProvider)
+		return nil
+	}
+
+	tokenUSD := usdFormatter(cfg)
+
+	out := output.Writer(c)
+	if path := c.String("file"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if format != "csv" {
+		statement := clientStatement{
+			Client:       clientAddr,
+			FromEpoch:    fromEpoch,
+			ToEpoch:      toEpoch,
+			Totals:       withUSD(totals, tokenUSD),
+			ByAllocation: withUSD(byAllocation, tokenUSD),
+			ByProvider:   withUSD(byProvider, tokenUSD),
+			Payments:     records,
+		}
+		if err := utils.WriteResult(out, format, statement); err != nil {
+			return fmt.Errorf("failed to write statement: %v", err)
+		}
+	} else {
+		lines := byAllocation
+		switch groupBy {
+		case "provider":
+			lines = byProvider
+		case "token":
+			lines = totals
+		}
+
+		formatAmount := tokenUSD
+		if c.Bool("raw") {
+			formatAmount = nil
+		}
+		if err := utils.WriteStatementCSV(out, lines, formatAmount); err != nil {
+			return fmt.Errorf("failed to write statement CSV: %v", err)
+		}
+	}
+
+	if path := c.String("file"); path != "" {
+		fmt.Fprintf(w, "✅ Statement written to %s\n", path)
+	}
+
+	return nil
+}
+
+// withUSD adds USD-formatted amounts to statement lines
+func withUSD(lines []types.StatementLine, tokenUSD func(common.Address, *big.Int) string) []statementLineOutput {
+	out := make([]statementLineOutput, 0, len(lines))
+	for _, l := range lines {
+		out = append(out, statementLineOutput{
+			StatementLine:         l,
+			GrossUSD:              tokenUSD(l.Token, l.Gross),
+			NetworkFeeUSD:         tokenUSD(l.Token, l.NetworkFee),
+			OperatorCommissionUSD: tokenUSD(l.Token, l.OperatorCommission),
+			NetUSD:                tokenUSD(l.Token, l.Net),
+		})
+	}
+	return out
+}
+
+func printClientStatement(w io.Writer,
+	cfg config.Config,
+	client common.Address,
+	fromEpoch, toEpoch uint64,
+	allocationCount, paymentCount int,
+	totals, byAllocation, byProvider []types.StatementLine,
+) {
+	fmt.Fprintf(w, "🧾 Spending Statement:\n")
+	fmt.Fprintf(w, "   Client: %s\n", client.Hex())
+	fmt.Fprintf(w, "   Epochs: %d - %d\n", fromEpoch, toEpoch)
+	fmt.Fprintf(w, "   Allocations: %d\n", allocationCount)
+	fmt.Fprintf(w, "   Payments: %d\n", paymentCount)
+	fmt.Fprintln(w)
+
+	if paymentCount == 0 {
+		fmt.Fprintf(w, "📭 No payments found in this range\n")
+		return
+	}
+
+	fmt.Fprintf(w, "📊 Total Spend by Token:\n")
+	for _, l := range totals {
+		fmt.Fprintf(w, "   %s (%s): %s\n", utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, l.Token).Symbol, l.Token.Hex(), formatSpend(cfg, l))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "🏢 By Provider:\n")
+	for _, l := range byProvider {
+		fmt.Fprintf(w, "   Provider %d (%s): %s\n", l.ProviderId, l.Token.Hex(), formatSpend(cfg, l))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "📦 By Allocation:\n")
+	for _, l := range byAllocation {
+		fmt.Fprintf(w, "   Allocation %d (provider %d, rail %s, token %s): %s\n",
+			l.AllocationId, l.ProviderId, l.RailId.String(), l.Token.Hex(), formatSpend(cfg, l))
+	}
+	fmt.Fprintln(w)
+}
+
+// usdFormatter returns a function rendering an amount of a USD-pegged token in USD using
+// the token's decimals as reported by the configured RPC endpoint
+func usdFormatter(cfg config.Config) func(common.Address, *big.Int) string {
+	return func(token common.Address, amount *big.Int) string {
+		return utils.ConvertTokenUnitsToUSD(amount, utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, token).Decimals)
+	}
+}
+
+// formatSpend renders a statement line's gross spend and its breakdown in USD
+func formatSpend(cfg config.Config, l types.StatementLine) string {
+	tokenUSD := usdFormatter(cfg)
+	meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, l.Token)
+	return fmt.Sprintf("$%s over %d payment(s) (provider $%s, commission $%s, network fee $%s; %s)",
+		tokenUSD(l.Token, l.Gross), l.Payments,
+		tokenUSD(l.Token, l.Net),
+		tokenUSD(l.Token, l.OperatorCommission),
+		tokenUSD(l.Token, l.NetworkFee),
+		utils.FormatTokenAmount(l.Gross, meta))
+}
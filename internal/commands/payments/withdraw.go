@@ -10,7 +10,10 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/types"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
@@ -21,15 +24,14 @@ func WithdrawCommand() *cli.Command {
 		Usage:   "Withdraw tokens from your account",
 		Flags: append(paymentsFlags, []cli.Flag{
 			&cli.StringFlag{
-				Name:     "token",
-				Aliases:  []string{"t"},
-				Usage:    "ERC20 token contract address",
-				Required: true,
+				Name:    "token",
+				Aliases: []string{"t"},
+				Usage:   "ERC20 token contract address (defaults to the profile's payment token)",
 			},
 			&cli.StringFlag{
 				Name:     "amount",
 				Aliases:  []string{"a"},
-				Usage:    "Amount to withdraw",
+				Usage:    "Amount to withdraw in token units (\"12.5\", \"12.5 USDFC\") or base units (\"12500000 wei\")",
 				Required: true,
 			},
 			&cli.StringFlag{
@@ -42,30 +44,40 @@ func WithdrawCommand() *cli.Command {
 				Usage: "Check account balance before withdrawing",
 			},
 		}...),
-		Action: executeWithdraw,
+		Action: config.Action(executeWithdraw),
 	}
 }
 
-func executeWithdraw(c *cli.Context) error {
+func executeWithdraw(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
 	// Validate private key configuration
-	if err := validatePrivateKeyConfig(c); err != nil {
+	if err := validatePrivateKeyConfig(cfg); err != nil {
 		return err
 	}
 
-	tokenAddress := common.HexToAddress(c.String("token"))
+	tokenStr, err := cfg.TokenOrDefault("token", c.String("token"))
+	if err != nil {
+		return err
+	}
+	tokenAddress := common.HexToAddress(tokenStr)
 	amountStr := c.String("amount")
 	toAddressStr := c.String("to")
 	checkBalance := c.Bool("check-balance")
 
+	meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get token metadata: %v", err)
+	}
+
 	// Parse withdrawal amount
-	amount := new(big.Int)
-	amount, ok := amount.SetString(amountStr, 10)
-	if !ok {
-		return fmt.Errorf("invalid amount format: %s", amountStr)
+	amount, err := utils.ParseTokenAmount(amountStr, meta)
+	if err != nil {
+		return err
 	}
 
 	// Get user address from private key
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey, "0x"))
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
 	if err != nil {
 		return fmt.Errorf("failed to parse private key: %v", err)
 	}
@@ -79,19 +91,19 @@ func executeWithdraw(c *cli.Context) error {
 		toAddress = userAddress // Default to user's own address
 	}
 
-	fmt.Printf("💰 Withdraw Information:\n")
-	fmt.Printf("   From Account: %s\n", userAddress.Hex())
-	fmt.Printf("   Token: %s\n", tokenAddress.Hex())
-	fmt.Printf("   Amount: %s\n", amount.String())
-	fmt.Printf("   To Address: %s\n", toAddress.Hex())
+	fmt.Fprintf(w, "💰 Withdraw Information:\n")
+	fmt.Fprintf(w, "   From Account: %s\n", userAddress.Hex())
+	fmt.Fprintf(w, "   Token: %s (%s)\n", meta.Symbol, tokenAddress.Hex())
+	fmt.Fprintf(w, "   Amount: %s\n", utils.FormatTokenAmount(amount, meta))
+	fmt.Fprintf(w, "   To Address: %s\n", toAddress.Hex())
 	if toAddress == userAddress {
-		fmt.Printf("   (Withdrawing to your own address)\n")
+		fmt.Fprintf(w, "   (Withdrawing to your own address)\n")
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// Check balance if requested
 	if checkBalance {
-		paymentsClient, err := payments.NewReadOnlyClientWithParams(config.RPCEndpoint, config.PaymentsContractAddress)
+		paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.RPC)
 		if err != nil {
 			return fmt.Errorf("failed to create payments client: %v", err)
 		}
@@ -102,30 +114,30 @@ func executeWithdraw(c *cli.Context) error {
 			return fmt.Errorf("failed to get account balance: %v", err)
 		}
 
-		fmt.Printf("📊 Current Account Status:\n")
-		fmt.Printf("   Available Funds: %s\n", account.Funds.String())
-		fmt.Printf("   Locked Funds: %s\n", account.LockupCurrent.String())
-		fmt.Printf("   Withdrawal Amount: %s\n", amount.String())
+		fmt.Fprintf(w, "📊 Current Account Status:\n")
+		fmt.Fprintf(w, "   Available Funds: %s\n", utils.FormatTokenAmount(account.Funds, meta))
+		fmt.Fprintf(w, "   Locked Funds: %s\n", utils.FormatTokenAmount(account.LockupCurrent, meta))
+		fmt.Fprintf(w, "   Withdrawal Amount: %s\n", utils.FormatTokenAmount(amount, meta))
 
 		// Check if withdrawal amount exceeds available funds
 		if account.Funds.Cmp(amount) < 0 {
-			return fmt.Errorf("insufficient funds: available %s, requested %s", account.Funds.String(), amount.String())
+			return fmt.Errorf("insufficient funds: available %s, requested %s", utils.FormatTokenAmount(account.Funds, meta), utils.FormatTokenAmount(amount, meta))
 		}
 
 		remaining := new(big.Int).Sub(account.Funds, amount)
-		fmt.Printf("   Remaining After Withdrawal: %s\n", remaining.String())
-		fmt.Println()
+		fmt.Fprintf(w, "   Remaining After Withdrawal: %s\n", utils.FormatTokenAmount(remaining, meta))
+		fmt.Fprintln(w)
 	}
 
 	// Create payments client for transactions
-	paymentsTransactClient, err := payments.NewClientWithParams(config.RPCEndpoint, config.PaymentsContractAddress, config.PrivateKey)
+	paymentsTransactClient, err := payments.NewClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 	if err != nil {
 		return fmt.Errorf("failed to create payments transaction client: %v", err)
 	}
 	defer paymentsTransactClient.Close()
 
 	// Send the withdrawal transaction
-	fmt.Printf("📝 Sending withdrawal transaction...\n")
+	fmt.Fprintf(w, "📝 Sending withdrawal transaction...\n")
 	var txHash string
 	if toAddressStr == "" {
 		// Use regular withdraw function if no destination specified
@@ -139,20 +151,28 @@ func executeWithdraw(c *cli.Context) error {
 		return fmt.Errorf("failed to withdraw: %v", err)
 	}
 
-	fmt.Printf("✅ Withdrawal transaction sent: %s\n", txHash)
+	fmt.Fprintf(w, "✅ Withdrawal transaction sent: %s\n", txHash)
 
 	// Wait for transaction to be mined using the payments client's ethclient
-	fmt.Printf("⏳ Waiting for transaction to be mined...\n")
-	if err := utils.WaitForTransaction(paymentsTransactClient.GetEthClient(), txHash); err != nil {
-		fmt.Printf("⚠️  Warning: transaction may not have been mined: %v\n", err)
-		return nil
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	result := types.WithdrawResult{
+		Token:  tokenAddress,
+		From:   userAddress,
+		To:     toAddress,
+		Amount: amount,
+		Tx:     output.WaitTx(paymentsTransactClient.TxManager(), txHash),
 	}
 
-	fmt.Printf("✅ Withdrawal completed successfully!\n")
-	fmt.Printf("   Transaction: %s\n", txHash)
-	fmt.Printf("   Amount: %s\n", amount.String())
-	fmt.Printf("   From: %s\n", userAddress.Hex())
-	fmt.Printf("   To: %s\n", toAddress.Hex())
-
-	return nil
+	if err := output.Render(c, result, func() {
+		output.PrintTxOutcome(w, result.Tx, func() {
+			fmt.Fprintf(w, "✅ Withdrawal completed successfully!\n")
+			fmt.Fprintf(w, "   Transaction: %s\n", txHash)
+			fmt.Fprintf(w, "   Amount: %s\n", utils.FormatTokenAmount(amount, meta))
+			fmt.Fprintf(w, "   From: %s\n", userAddress.Hex())
+			fmt.Fprintf(w, "   To: %s\n", toAddress.Hex())
+		})
+	}); err != nil {
+		return err
+	}
+	return output.TxError(result.Tx)
 }
@@ -0,0 +1,70 @@
+package piece
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+func CommpCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "commp",
+		Usage:     "Compute the piece CID (v1 and v2) of a file or URL",
+		ArgsUsage: "<file|url>",
+		Description: `Streams the data through the commP hasher, so multi-GiB files are
+processed with a fixed amount of memory. The data is hashed as-is; pass a
+CAR file to get the piece CID create-from-file would allocate.`,
+		Action: executeCommp,
+	}
+}
+
+func executeCommp(c *cli.Context) error {
+	w := output.Progress(c)
+
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one file or URL argument")
+	}
+
+	source := c.Args().First()
+	commitment, err := computeCommitment(c, source)
+	if err != nil {
+		return err
+	}
+
+	return output.Render(c, commitment, func() {
+		fmt.Fprintf(w, "🧩 Piece Commitment for %s:\n", source)
+		printCommitment(w, commitment)
+	})
+}
+
+// computeCommitment streams source through the commP hasher
+func computeCommitment(c *cli.Context, source string) (*types.PieceCommitment, error) {
+	r, err := utils.OpenPieceSource(c.Context, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open source: %v", err)
+	}
+	defer r.Close()
+
+	commitment, err := utils.ComputePieceCommitment(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute piece commitment: %v", err)
+	}
+	return commitment, nil
+}
+
+func printCommitment(w io.Writer, p *types.PieceCommitment) {
+	fmt.Fprintf(w, "   Piece CID (v1): %s\n", p.PieceCidV1)
+	if p.PieceCidV2 != "" {
+		fmt.Fprintf(w, "   Piece CID (v2): %s\n", p.PieceCidV2)
+	} else {
+		fmt.Fprintf(w, "   Piece CID (v2): n/a (payload below 127 bytes)\n")
+	}
+	fmt.Fprintf(w, "   Piece Size: %d bytes (%s, padded)\n", p.PieceSize, utils.FormatBytes(new(big.Int).SetUint64(p.PieceSize)))
+	fmt.Fprintf(w, "   Payload Size: %d bytes\n", p.PayloadSize)
+}
@@ -0,0 +1,16 @@
+package piece
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+func PieceCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "piece",
+		Usage: "Compute and verify piece commitments locally",
+		Subcommands: []*cli.Command{
+			CommpCommand(),
+			VerifyCommand(),
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package piece
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+func VerifyCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "verify",
+		Usage:     "Check that a file or URL matches an allocation's piece on-chain",
+		ArgsUsage: "<file|url>",
+		Description: `Computes the piece CID of the data locally and compares its hash and
+padded size with the allocation stored by the DDO contract. Data smaller than
+the allocation is padded to its size first, as pieces are when they are
+allocated. Once the allocation has been claimed, the claim's piece CID and size are checked too.
+Exits with an error if anything differs.`,
+		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.RPCFlag(),
+			&cli.Uint64Flag{
+				Name:     "allocation-id",
+				Aliases:  []string{"a"},
+				Usage:    "Allocation ID to verify against",
+				Required: true,
+			},
+		},
+		Action: config.Action(executeVerify),
+	}
+}
+
+func executeVerify(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	// Validate required configuration (only need contract and RPC for queries)
+	if err := cfg.RequireContract(); err != nil {
+		return err
+	}
+
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one file or URL argument")
+	}
+
+	allocationId := c.Uint64("allocation-id")
+	source := c.Args().First()
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	// Fail fast on a bad ID before reading what may be a large file
+	info, err := ddoClient.GetAllocationInfo(allocationId)
+	if err != nil {
+		return fmt.Errorf("failed to get allocation info: %v", err)
+	}
+	if info.Client == (common.Address{}) {
+		return fmt.Errorf("allocation %d not found", allocationId)
+	}
+
+	var claim *types.Claim
+	if info.Activated {
+		claim, err = ddoClient.GetClaimInfo(info.Provider, allocationId)
+		if err != nil {
+			fmt.Fprintf(w, "⚠️  Could not get claim %d: %v\n", allocationId, err)
+		}
+	}
+
+	commitment, err := computeCommitment(c, source)
+	if err != nil {
+		return err
+	}
+
+	result, err := utils.VerifyPiece(allocationId, commitment, info, claim)
+	if err != nil {
+		return fmt.Errorf("failed to verify piece: %v", err)
+	}
+	result.Source = source
+
+	if err := output.Render(c, result, func() { printVerification(w, result) }); err != nil {
+		return err
+	}
+
+	if !result.Match {
+		return fmt.Errorf("%s does not match allocation %d", source, allocationId)
+	}
+	return nil
+}
+
+func printVerification(w io.Writer, v *types.PieceVerification) {
+	mark := func(ok bool) string {
+		if ok {
+			return "✅"
+		}
+		return "❌"
+	}
+
+	fmt.Fprintf(w, "🔍 Piece Verification for allocation %d:\n", v.AllocationId)
+	fmt.Fprintf(w, "   Source: %s\n", v.Source)
+	printCommitment(w, &v.Commitment)
+	if v.PaddedPieceCid != "" {
+		fmt.Fprintf(w, "   Padded Piece CID: %s (%d bytes)\n", v.PaddedPieceCid, v.PieceSize)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "Allocation:\n")
+	fmt.Fprintf(w, "   %s Piece CID Hash: %s\n", mark(v.HashMatches), v.PieceCidHash)
+	if !v.HashMatches {
+		fmt.Fprintf(w, "      expected %s\n", v.ExpectedPieceCidHash)
+	}
+	fmt.Fprintf(w, "   %s Piece Size: %d\n", mark(v.SizeMatches), v.PieceSize)
+	if !v.SizeMatches {
+		fmt.Fprintf(w, "      expected %d\n", v.ExpectedPieceSize)
+	}
+
+	if v.ClaimChecked {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Claim:\n")
+		fmt.Fprintf(w, "   %s Piece CID: %s\n", mark(v.ClaimMatches), v.ClaimPieceCid)
+		fmt.Fprintf(w, "      Size: %d\n", v.ClaimSize)
+	}
+	fmt.Fprintln(w)
+
+	if v.Match {
+		fmt.Fprintf(w, "✅ Data matches allocation %d\n", v.AllocationId)
+	} else {
+		fmt.Fprintf(w, "❌ Data does not match allocation %d\n", v.AllocationId)
+	}
+}
@@ -0,0 +1,301 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/eastore-project/fildeal/src/buffer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/api"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/notify"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
+)
+
+func ServeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Serve the client over an authenticated local HTTP/JSON API",
+		Description: `Exposes quotes, storage provider queries, allocations, payments accounts,
+settlement and Curio deal status over HTTP. Allocation creation and settlement
+run as background jobs polled at /v1/jobs/{id}. The OpenAPI spec is served at
+/openapi.yaml.
+
+Requests carry the API token as "Authorization: Bearer <token>". Without
+--api-token a random token is generated and printed at startup. Without a
+private key the server is read-only. Allocation requests may only name inputs
+below --input-dir.
+
+With --notify-config the server also runs the webhook notifier of "ddo notify run".`,
+		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.PaymentsContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
+			&cli.StringFlag{
+				Name:  "listen",
+				Usage: "Address to serve the API on",
+				Value: "127.0.0.1:8090",
+			},
+			&cli.StringFlag{
+				Name:    "api-token",
+				Usage:   "Bearer token clients must send (generated when empty)",
+				EnvVars: []string{"DDO_API_TOKEN"},
+			},
+			&cli.IntFlag{
+				Name:  "max-jobs",
+				Usage: "Jobs running at the same time",
+				Value: api.DefaultMaxJobs,
+			},
+			&cli.StringFlag{
+				Name:  "input-dir",
+				Usage: "Directory whose files and folders allocation requests may use as input (server-side inputs are disabled when empty)",
+			},
+			&cli.StringFlag{
+				Name:  "data-dir",
+				Usage: "Directory where server-side inputs are prepared into CAR files (default: <tmp>/ddo-client-api)",
+			},
+			&cli.StringFlag{
+				Name:  "buffer-type",
+				Usage: "Buffer type (lighthouse or local)",
+				Value: "local",
+			},
+			&cli.StringFlag{
+				Name:    "buffer-api-key",
+				Usage:   "Buffer service API key",
+				EnvVars: []string{"BUFFER_API_KEY"},
+			},
+			&cli.StringFlag{
+				Name:    "buffer-url",
+				Usage:   "Buffer service base URL",
+				EnvVars: []string{"BUFFER_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "curio-api",
+				Usage:   "Default Curio MK20 API base URL (discovered per provider when empty)",
+				EnvVars: []string{"CURIO_API"},
+			},
+			&cli.StringFlag{
+				Name:  "notify-config",
+				Usage: "Run a webhook notifier with this config file, also watching the Curio deals the server submits",
+			},
+		},
+		Action: config.Action(executeServe),
+	}
+}
+
+func executeServe(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	if err := cfg.RequireContract(); err != nil {
+		return err
+	}
+	if err := cfg.RequirePayments(); err != nil {
+		return err
+	}
+	if c.Int("max-jobs") < 1 {
+		return fmt.Errorf("--max-jobs must be at least 1")
+	}
+
+	token := c.String("api-token")
+	generated := token == ""
+	if generated {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("failed to generate API token: %v", err)
+		}
+		token = hex.EncodeToString(b)
+	}
+
+	var inputDir string
+	if dir := c.String("input-dir"); dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("invalid --input-dir: %v", err)
+		}
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			return fmt.Errorf("--input-dir %s is not a directory", dir)
+		}
+		inputDir = abs
+	}
+
+	dataDir := c.String("data-dir")
+	if dataDir == "" {
+		dataDir = filepath.Join(os.TempDir(), "ddo-client-api")
+	}
+	curioAPI := c.String("curio-api")
+	if curioAPI == "" {
+		curioAPI = cfg.CurioAPI
+	}
+
+	apiCfg := api.Config{
+		Token:            token,
+		RPCEndpoint:      cfg.RPCEndpoint,
+		RPC:              cfg.RPC,
+		ContractAddress:  common.HexToAddress(cfg.ContractAddress),
+		PaymentsContract: common.HexToAddress(cfg.PaymentsContractAddress),
+		PaymentToken:     cfg.PaymentToken,
+		CurioAPI:         curioAPI,
+		InputDir:         inputDir,
+		DataDir:          dataDir,
+		Buffer: &buffer.Config{
+			Type:    c.String("buffer-type"),
+			ApiKey:  c.String("buffer-api-key"),
+			BaseURL: c.String("buffer-url"),
+		},
+		MaxJobs: c.Int("max-jobs"),
+	}
+
+	ddoClient, paymentsClient, closeClients, err := serveClients(cfg, &apiCfg)
+	if err != nil {
+		return err
+	}
+	defer closeClients()
+
+	var notifier *notify.Notifier
+	if path := c.String("notify-config"); path != "" {
+		notifyCfg, err := loadNotifyConfig(path, cfg)
+		if err != nil {
+			return err
+		}
+		if notifier, err = newNotifier(cfg, notifyCfg, ddoClient, paymentsClient); err != nil {
+			return err
+		}
+		apiCfg.Notifier = notifier
+	}
+
+	srv := api.New(apiCfg, ddoClient, paymentsClient)
+	defer srv.Close()
+
+	server := &http.Server{
+		Addr:              c.String("listen"),
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	notifierDone := make(chan struct{})
+	if notifier != nil {
+		go func() {
+			defer close(notifierDone)
+			notifier.Run(ctx)
+		}()
+	} else {
+		close(notifierDone)
+	}
+	defer func() {
+		stop()
+		<-notifierDone
+	}()
+
+	fmt.Fprintf(w, "🌐 Serving the API on http://%s (spec at /openapi.yaml)\n", server.Addr)
+	if apiCfg.PrivateKey != nil {
+		fmt.Fprintf(w, "   Client: %s\n", crypto.PubkeyToAddress(apiCfg.PrivateKey.PublicKey).Hex())
+	} else {
+		fmt.Fprintf(w, "   Read-only: no private key configured\n")
+	}
+	fmt.Fprintf(w, "   Data Dir: %s, Max Jobs: %d\n", dataDir, apiCfg.MaxJobs)
+	if inputDir != "" {
+		fmt.Fprintf(w, "   Input Dir: %s\n", inputDir)
+	}
+	if notifier != nil {
+		fmt.Fprintf(w, "   Notifier: %s\n", c.String("notify-config"))
+	}
+	if generated {
+		fmt.Fprintf(w, "🔑 API token: %s\n", token)
+	}
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("API server failed: %v", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	fmt.Fprintf(w, "🛑 Stopping API server, waiting for running jobs\n")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// serveClients creates the contract clients of the server. With a private key they
+// share one connection and the key's transactor, which is set in apiCfg.
+func serveClients(cfg config.Config, apiCfg *api.Config) (*ddo.Client, *payments.Client, func(), error) {
+	if cfg.PrivateKey == "" {
+		ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create DDO contract client: %v", err)
+		}
+		paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.RPC)
+		if err != nil {
+			ddoClient.Close()
+			return nil, nil, nil, fmt.Errorf("failed to create payments client: %v", err)
+		}
+		return ddoClient, paymentsClient, func() {
+			ddoClient.Close()
+			paymentsClient.Close()
+		}, nil
+	}
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+	ethClient, err := rpcclient.Dial(cfg.RPCEndpoint, cfg.RPC)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create eth client: %v", err)
+	}
+	chainID, err := ethClient.ChainID(context.Background())
+	if err != nil {
+		ethClient.Close()
+		return nil, nil, nil, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	if err != nil {
+		ethClient.Close()
+		return nil, nil, nil, fmt.Errorf("failed to create transactor: %v", err)
+	}
+
+	// Both clients send with one manager, so that their nonces do not collide
+	txm := txmgr.New(ethClient, cfg.Tx)
+	ddoClient, err := ddo.NewClientWithTransactor(ethClient, cfg.ContractAddress, auth, txm)
+	if err != nil {
+		ethClient.Close()
+		return nil, nil, nil, fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	paymentsClient, err := payments.NewClientWithTransactor(ethClient, cfg.PaymentsContractAddress, auth, txm)
+	if err != nil {
+		ethClient.Close()
+		return nil, nil, nil, fmt.Errorf("failed to create payments contract client: %v", err)
+	}
+
+	apiCfg.PrivateKey, apiCfg.Auth = privateKey, auth
+	return ddoClient, paymentsClient, ethClient.Close, nil
+}
@@ -0,0 +1,246 @@
+package sp
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+func ApplyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "apply",
+		Usage: "Reconcile a storage provider with a YAML/JSON spec file",
+		Description: `Reads the desired SP configuration from a spec file, compares it with the
+on-chain state and prints a plan of the contract calls needed. Only the
+differences are executed. Tokens registered on-chain but missing from the
+spec are removed.`,
+		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
+			&cli.StringFlag{
+				Name:     "file",
+				Aliases:  []string{"f"},
+				Usage:    "SP spec file (.yaml, .yml or .json)",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only print the plan without sending transactions",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Apply the plan without asking for confirmation",
+			},
+		},
+		Action: config.Action(executeApplySP),
+	}
+}
+
+func executeApplySP(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	// Validate required configuration
+	if missing := cfg.MissingConfig(); len(missing) > 0 {
+		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
+	}
+
+	spec, err := utils.LoadSPSpec(c.String("file"))
+	if err != nil {
+		return err
+	}
+
+	// Resolve spec prices to on-chain units using each token's decimals
+	metas := make(map[common.Address]*types.TokenMetadata)
+	desired := types.SPRegistrationParams{
+		ActorId:        spec.ActorId,
+		PaymentAddress: common.HexToAddress(spec.PaymentAddress),
+		MinPieceSize:   spec.MinPieceSize,
+		MaxPieceSize:   spec.MaxPieceSize,
+		MinTermLength:  spec.MinTermLength,
+		MaxTermLength:  spec.MaxTermLength,
+	}
+	for _, t := range spec.Tokens {
+		tokenAddr := common.HexToAddress(t.Token)
+		meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddr)
+		if err != nil {
+			return fmt.Errorf("failed to get metadata for token %s: %v", t.Token, err)
+		}
+		metas[tokenAddr] = meta
+
+		price, err := utils.ConvertUSDPerTBPerMonthToBytesPerEpoch(t.PriceUSDPerTBPerMonth, meta.Decimals)
+		if err != nil {
+			return fmt.Errorf("invalid price for token %s: %v", t.Token, err)
+		}
+
+		isActive := true
+		if t.IsActive != nil {
+			isActive = *t.IsActive
+		}
+		desired.TokenConfigs = append(desired.TokenConfigs, types.TokenConfig{
+			Token:                tokenAddr,
+			PricePerBytePerEpoch: price,
+			IsActive:             isActive,
+		})
+	}
+
+	ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	current, err := ddoClient.GetSPConfig(spec.ActorId)
+	if err != nil {
+		return fmt.Errorf("failed to get current SP config: %v", err)
+	}
+	// GetSPConfig reports no tokens when they cannot be read, which would plan to add
+	// every spec token again and remove none
+	if current != nil {
+		current.SupportedTokens, err = ddoClient.GetSPSupportedTokensFromContract(spec.ActorId)
+		if err != nil {
+			return fmt.Errorf("failed to get current SP tokens: %v", err)
+		}
+	}
+
+	formatPrice := func(tokenAddr common.Address, price *big.Int) string {
+		meta, ok := metas[tokenAddr]
+		if !ok {
+			meta = utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddr)
+		}
+		return utils.FormatPriceBothFormats(price, meta)
+	}
+
+	steps := utils.PlanSPChanges(desired, current, formatPrice)
+	for _, step := range steps {
+		if _, ok := metas[step.Token]; !ok && step.Token != (common.Address{}) {
+			metas[step.Token] = utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, step.Token)
+		}
+	}
+	result := types.SPApplyResult{
+		ActorId:      spec.ActorId,
+		Steps:        steps,
+		Transactions: []types.TxResult{},
+	}
+	if result.Steps == nil {
+		result.Steps = []types.SPPlanStep{}
+	}
+	printSPPlan(w, spec.ActorId, steps, metas)
+
+	if current != nil && !current.IsActive {
+		fmt.Fprintf(w, "⚠️  Storage provider %d is deactivated on-chain; applying the spec does not reactivate it\n", spec.ActorId)
+	}
+
+	if len(steps) == 0 {
+		fmt.Fprintf(w, "✅ No changes - storage provider %d matches the spec\n", spec.ActorId)
+		return output.Render(c, result, nil)
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Fprintf(w, "🎯 Dry Run - no transactions sent\n")
+		return output.Render(c, result, nil)
+	}
+
+	if !c.Bool("yes") {
+		ok, err := output.Confirm(w, fmt.Sprintf("Apply %d change(s)?", len(steps)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintf(w, "Aborted - no transactions sent\n")
+			return output.Render(c, result, nil)
+		}
+	}
+
+	for i, step := range steps {
+		fmt.Fprintf(w, "🚀 [%d/%d] %s %s\n", i+1, len(steps), step.Action, planStepTarget(step, metas))
+
+		var txHash string
+		switch step.Action {
+		case types.SPActionRegister:
+			txHash, err = ddoClient.RegisterSP(desired)
+		case types.SPActionUpdateConfig:
+			txHash, err = ddoClient.UpdateSPConfig(desired.ActorId, desired.PaymentAddress,
+				desired.MinPieceSize, desired.MaxPieceSize, desired.MinTermLength, desired.MaxTermLength)
+		case types.SPActionAddToken:
+			txHash, err = ddoClient.AddSPToken(desired.ActorId, step.Token, step.PricePerBytePerEpoch)
+		case types.SPActionUpdateToken:
+			txHash, err = ddoClient.UpdateSPToken(desired.ActorId, step.Token, step.PricePerBytePerEpoch, step.IsActive)
+		case types.SPActionRemoveToken:
+			txHash, err = ddoClient.RemoveSPToken(desired.ActorId, step.Token)
+		default:
+			err = fmt.Errorf("unknown plan action %q", step.Action)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s: %v (%d of %d change(s) applied)", step.Action, err, i, len(steps))
+		}
+
+		fmt.Fprintf(w, "   Transaction Hash: %s\n", txHash)
+		tx := output.WaitTx(ddoClient.TxManager(), txHash)
+		result.Transactions = append(result.Transactions, tx)
+		if tx.Status != types.TxStatusSuccess {
+			reason := tx.Error
+			if tx.Status == types.TxStatusReverted {
+				reason = "reverted"
+			}
+			return fmt.Errorf("%s transaction %s failed: %s (%d of %d change(s) applied)", step.Action, txHash, reason, i, len(steps))
+		}
+	}
+	result.Applied = true
+
+	fmt.Fprintf(w, "✅ Applied %d change(s) to storage provider %d\n", len(steps), spec.ActorId)
+
+	return output.Render(c, result, nil)
+}
+
+// printSPPlan prints a plan in the style of `terraform plan`
+func printSPPlan(w io.Writer, actorId uint64, steps []types.SPPlanStep, metas map[common.Address]*types.TokenMetadata) {
+	fmt.Fprintf(w, "📝 Plan for storage provider %d:\n", actorId)
+
+	var adds, changes, removes int
+	for _, step := range steps {
+		symbol := "~"
+		switch step.Action {
+		case types.SPActionRegister, types.SPActionAddToken:
+			symbol = "+"
+			adds++
+		case types.SPActionRemoveToken:
+			symbol = "-"
+			removes++
+		default:
+			changes++
+		}
+
+		fmt.Fprintf(w, "   %s %s %s\n", symbol, step.Action, planStepTarget(step, metas))
+		for _, ch := range step.Changes {
+			if ch.From == "" {
+				fmt.Fprintf(w, "         %s: %s\n", ch.Field, ch.To)
+			} else {
+				fmt.Fprintf(w, "         %s: %s → %s\n", ch.Field, ch.From, ch.To)
+			}
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Plan: %d to add, %d to change, %d to remove.\n", adds, changes, removes)
+	fmt.Fprintln(w)
+}
+
+// planStepTarget describes the token a plan step acts on, if any
+func planStepTarget(step types.SPPlanStep, metas map[common.Address]*types.TokenMetadata) string {
+	if step.Token == (common.Address{}) {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", step.Token.Hex(), metas[step.Token].Symbol)
+}
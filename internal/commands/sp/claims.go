@@ -0,0 +1,131 @@
+package sp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// defaultExpiringDays is the window used to highlight claims nearing their maximum term
+const defaultExpiringDays = 30
+
+func ClaimsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "claims",
+		Usage: "List verified registry claims for a storage provider's allocations",
+		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.RPCFlag(),
+			&cli.Uint64Flag{
+				Name:     "provider",
+				Aliases:  []string{"p"},
+				Usage:    "Storage provider ID",
+				Required: true,
+			},
+			&cli.Uint64Flag{
+				Name:  "expiring-within",
+				Usage: "Highlight claims whose maximum term ends within this many days",
+				Value: defaultExpiringDays,
+			},
+			&cli.BoolFlag{
+				Name:  "expiring-only",
+				Usage: "Only list claims nearing their maximum term",
+			},
+		},
+		Action: config.Action(executeClaims),
+	}
+}
+
+func executeClaims(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	// Validate required configuration (only need contract and RPC for queries)
+	if err := cfg.RequireContract(); err != nil {
+		return err
+	}
+
+	providerId := c.Uint64("provider")
+	expiringWithin := int64(c.Uint64("expiring-within") * utils.EPOCHS_PER_DAY)
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	header, err := ddoClient.GetEthClient().HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block header: %v", err)
+	}
+	currentEpoch := header.Number.Uint64()
+	currentTime := time.Unix(int64(header.Time), 0).UTC()
+
+	claims, err := utils.GetProviderClaims(ddoClient, providerId, currentEpoch, currentTime, expiringWithin)
+	if err != nil {
+		return err
+	}
+
+	var expiring int
+	for _, cl := range claims {
+		if cl.Expiring {
+			expiring++
+		}
+	}
+	if c.Bool("expiring-only") {
+		filtered := claims[:0]
+		for _, cl := range claims {
+			if cl.Expiring {
+				filtered = append(filtered, cl)
+			}
+		}
+		claims = filtered
+	}
+
+	return output.Render(c, claims, func() {
+		fmt.Fprintf(w, "📜 Claims for Storage Provider %d:\n", providerId)
+		fmt.Fprintf(w, "   Current Epoch: %d (%s)\n", currentEpoch, currentTime.Format(time.RFC3339))
+		fmt.Fprintf(w, "   Claims: %d (%d ending within %d days)\n", len(claims), expiring, c.Uint64("expiring-within"))
+		fmt.Fprintln(w)
+
+		if len(claims) == 0 {
+			fmt.Fprintf(w, "📭 No claims found\n")
+			return
+		}
+		for _, cl := range claims {
+			printClaim(w, cl)
+		}
+	})
+}
+
+func printClaim(w io.Writer, cl types.ProviderClaim) {
+	marker := ""
+	if cl.Expiring {
+		marker = " ⚠️"
+	}
+
+	fmt.Fprintf(w, "   Allocation %d%s:\n", cl.AllocationId, marker)
+	if cl.PieceCid != "" {
+		fmt.Fprintf(w, "      Piece CID: %s\n", cl.PieceCid)
+	}
+	fmt.Fprintf(w, "      Size: %s\n", utils.FormatBytes(new(big.Int).SetUint64(cl.Size)))
+	fmt.Fprintf(w, "      Client: %s (f0%d)\n", cl.Client.Hex(), cl.ClientActorId)
+	fmt.Fprintf(w, "      Sector: %d\n", cl.Sector)
+	fmt.Fprintf(w, "      Term: start %d, min %d, max %d epochs\n", cl.TermStart, cl.TermMin, cl.TermMax)
+	if cl.EpochsRemaining > 0 {
+		fmt.Fprintf(w, "      Ends: epoch %d (~%s, %.1f days left)\n",
+			cl.TermEnd, cl.EndsAt.Format(time.RFC3339), float64(cl.EpochsRemaining)/utils.EPOCHS_PER_DAY)
+	} else {
+		fmt.Fprintf(w, "      Ends: epoch %d (ended ~%s)\n", cl.TermEnd, cl.EndsAt.Format(time.RFC3339))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,276 @@
+package sp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// defaultEarningsDays is the lookback used when --from-epoch is not given
+const defaultEarningsDays = 30
+
+// earningsReport is the JSON form of an earnings statement
+type earningsReport struct {
+	ProviderId     uint64                `json:"providerId"`
+	PaymentAddress common.Address        `json:"paymentAddress"`
+	FromEpoch      uint64                `json:"fromEpoch"`
+	ToEpoch        uint64                `json:"toEpoch"`
+	Period         string                `json:"period"`
+	Totals         []types.StatementLine `json:"totals"`
+	ByAllocation   []types.StatementLine `json:"byAllocation"`
+	ByPeriod       []types.StatementLine `json:"byPeriod"`
+	Payments       []types.PaymentRecord `json:"payments"`
+	Withdrawals    []types.WithdrawEvent `json:"withdrawals"`
+}
+
+func EarningsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "earnings",
+		Usage: "Show settlement history and an earnings statement for a storage provider",
+		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.PaymentsContractFlag(),
+			config.RPCFlag(),
+			&cli.Uint64Flag{
+				Name:     "provider",
+				Aliases:  []string{"p"},
+				Usage:    "Storage provider ID",
+				Required: true,
+			},
+			&cli.Uint64Flag{
+				Name:  "from-epoch",
+				Usage: fmt.Sprintf("First epoch to include (optional - defaults to %d days before --to-epoch)", defaultEarningsDays),
+			},
+			&cli.Uint64Flag{
+				Name:  "to-epoch",
+				Usage: "Last epoch to include (optional - defaults to current block number)",
+			},
+			&cli.StringFlag{
+				Name:  "period",
+				Usage: "Statement period: day, week or month",
+				Value: utils.PeriodMonth,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table, csv, json or yaml (overrides --output)",
+			},
+			&cli.StringFlag{
+				Name:  "group-by",
+				Usage: "Grouping used for csv output: allocation or period",
+				Value: "allocation",
+			},
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Write csv/json output to this file instead of stdout",
+			},
+		},
+		Action: config.Action(executeEarnings),
+	}
+}
+
+func executeEarnings(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	// Validate required configuration (only need contract and RPC for queries)
+	if err := cfg.RequireContract(); err != nil {
+		return err
+	}
+
+	format := output.Format(c)
+	if format != "table" && format != "csv" && format != "json" && format != "yaml" {
+		return fmt.Errorf("invalid format %q (expected table, csv, json or yaml)", format)
+	}
+	groupBy := c.String("group-by")
+	if groupBy != "allocation" && groupBy != "period" {
+		return fmt.Errorf("invalid group-by %q (expected allocation or period)", groupBy)
+	}
+	period := c.String("period")
+	if _, err := utils.PeriodKey(time.Time{}, period); err != nil {
+		return err
+	}
+
+	providerId := c.Uint64("provider")
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	spConfig, err := ddoClient.GetSPConfig(providerId)
+	if err != nil {
+		return fmt.Errorf("failed to get SP config for provider %d: %v", providerId, err)
+	}
+	if spConfig == nil {
+		return fmt.Errorf("SP %d is not registered", providerId)
+	}
+
+	if err := cfg.RequirePayments(); err != nil {
+		return err
+	}
+
+	paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create payments client: %v", err)
+	}
+	defer paymentsClient.Close()
+
+	header, err := ddoClient.GetEthClient().HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block header: %v", err)
+	}
+	currentEpoch := header.Number.Uint64()
+	currentTime := time.Unix(int64(header.Time), 0).UTC()
+
+	toEpoch := c.Uint64("to-epoch")
+	if toEpoch == 0 || toEpoch > currentEpoch {
+		toEpoch = currentEpoch
+	}
+	fromEpoch := c.Uint64("from-epoch")
+	if !c.IsSet("from-epoch") {
+		lookback := uint64(defaultEarningsDays * utils.EPOCHS_PER_DAY)
+		if toEpoch > lookback {
+			fromEpoch = toEpoch - lookback
+		}
+	}
+	if fromEpoch > toEpoch {
+		return fmt.Errorf("--from-epoch (%d) is after --to-epoch (%d)", fromEpoch, toEpoch)
+	}
+
+	allocationIds, err := ddoClient.GetAllocationIdsForProvider(providerId)
+	if err != nil {
+		return fmt.Errorf("failed to get allocation IDs for provider: %v", err)
+	}
+
+	rails, err := utils.GetAllocationRails(ddoClient, allocationIds)
+	if err != nil {
+		return err
+	}
+
+	records, err := utils.CollectPaymentRecords(paymentsClient, rails, fromEpoch, toEpoch, currentEpoch, currentTime)
+	if err != nil {
+		return fmt.Errorf("failed to collect payment records: %v", err)
+	}
+
+	withdrawals, err := paymentsClient.GetWithdrawEvents(common.Address{}, spConfig.PaymentAddress, fromEpoch, toEpoch)
+	if err != nil {
+		return fmt.Errorf("failed to get withdrawals: %v", err)
+	}
+
+	byPeriod, err := utils.SummarizeByPeriod(records, period)
+	if err != nil {
+		return err
+	}
+
+	report := earningsReport{
+		ProviderId:     providerId,
+		PaymentAddress: spConfig.PaymentAddress,
+		FromEpoch:      fromEpoch,
+		ToEpoch:        toEpoch,
+		Period:         period,
+		Totals:         utils.SummarizeByToken(records),
+		ByAllocation:   utils.SummarizeByAllocation(records),
+		ByPeriod:       byPeriod,
+		Payments:       records,
+		Withdrawals:    withdrawals,
+	}
+
+	if format == "table" {
+		printEarnings(w, cfg, &report, len(rails))
+		return nil
+	}
+
+	out := output.Writer(c)
+	if path := c.String("file"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if format != "csv" {
+		if err := utils.WriteResult(out, format, report); err != nil {
+			return fmt.Errorf("failed to write earnings report: %v", err)
+		}
+	} else {
+		lines := report.ByAllocation
+		if groupBy == "period" {
+			lines = report.ByPeriod
+		}
+		if err := utils.WriteStatementCSV(out, lines, nil); err != nil {
+			return fmt.Errorf("failed to write earnings CSV: %v", err)
+		}
+	}
+
+	if path := c.String("file"); path != "" {
+		fmt.Fprintf(w, "✅ Earnings statement written to %s\n", path)
+	}
+
+	return nil
+}
+
+func printEarnings(w io.Writer, cfg config.Config, report *earningsReport, railCount int) {
+	fmt.Fprintf(w, "💰 Earnings Statement:\n")
+	fmt.Fprintf(w, "   Provider ID: %d\n", report.ProviderId)
+	fmt.Fprintf(w, "   Payment Address: %s\n", report.PaymentAddress.Hex())
+	fmt.Fprintf(w, "   Epochs: %d - %d\n", report.FromEpoch, report.ToEpoch)
+	fmt.Fprintf(w, "   Rails: %d\n", railCount)
+	fmt.Fprintf(w, "   Payments: %d\n", len(report.Payments))
+	fmt.Fprintln(w)
+
+	if len(report.Payments) == 0 {
+		fmt.Fprintf(w, "📭 No settlements found in this range\n")
+	} else {
+		fmt.Fprintf(w, "📊 Totals:\n")
+		for _, l := range report.Totals {
+			printStatementLine(w, cfg, fmt.Sprintf("Token %s (%s)", utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, l.Token).Symbol, l.Token.Hex()), l)
+		}
+		fmt.Fprintln(w)
+
+		fmt.Fprintf(w, "📅 By %s:\n", report.Period)
+		for _, l := range report.ByPeriod {
+			printStatementLine(w, cfg, fmt.Sprintf("%s - %s", l.Period, l.Token.Hex()), l)
+		}
+		fmt.Fprintln(w)
+
+		fmt.Fprintf(w, "📦 By Allocation:\n")
+		for _, l := range report.ByAllocation {
+			printStatementLine(w, cfg, fmt.Sprintf("Allocation %d (rail %s, token %s)", l.AllocationId, l.RailId.String(), l.Token.Hex()), l)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(report.Withdrawals) > 0 {
+		fmt.Fprintf(w, "🏧 Withdrawals:\n")
+		for _, wd := range report.Withdrawals {
+			fmt.Fprintf(w, "   Block %d: %s (%s) to %s (tx %s)\n",
+				wd.BlockNumber, utils.FormatTokenAmount(wd.Amount, utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, wd.Token)), wd.Token.Hex(), wd.To.Hex(), wd.TxHash.Hex())
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func printStatementLine(w io.Writer, cfg config.Config, label string, l types.StatementLine) {
+	meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, l.Token)
+	fmt.Fprintf(w, "   %s:\n", label)
+	fmt.Fprintf(w, "      Payments: %d\n", l.Payments)
+	fmt.Fprintf(w, "      Gross: %s\n", utils.FormatTokenAmount(l.Gross, meta))
+	fmt.Fprintf(w, "      Network Fee: %s\n", utils.FormatTokenAmount(l.NetworkFee, meta))
+	fmt.Fprintf(w, "      Operator Commission: %s\n", utils.FormatTokenAmount(l.OperatorCommission, meta))
+	fmt.Fprintf(w, "      Net: %s\n", utils.FormatTokenAmount(l.Net, meta))
+}
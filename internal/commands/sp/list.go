@@ -2,12 +2,15 @@ package sp
 
 import (
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
@@ -17,34 +20,21 @@ func ListCommand() *cli.Command {
 		Aliases: []string{"ls"},
 		Usage:   "List all registered storage providers",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
 		},
-		Action: executeListSPs,
+		Action: config.Action(executeListSPs),
 	}
 }
 
-func executeListSPs(c *cli.Context) error {
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
+func executeListSPs(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
-	if config.ContractAddress == "" {
+	if cfg.ContractAddress == "" {
 		return fmt.Errorf("missing DDO contract address (use --contract flag or DDO_CONTRACT_ADDRESS env var)")
 	}
 
-	ddoClient, err := ddo.NewReadOnlyClientWithParams(config.RPCEndpoint, config.ContractAddress)
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create DDO contract client: %v", err)
 	}
@@ -55,19 +45,38 @@ func executeListSPs(c *cli.Context) error {
 		return fmt.Errorf("failed to get SP IDs: %v", err)
 	}
 
-	if len(spIds) == 0 {
-		fmt.Println("No storage providers registered.")
-		return nil
+	configs, errs := ddoClient.GetSPConfigs(spIds)
+	entries := make([]types.SPListEntry, 0, len(spIds))
+	for i, id := range spIds {
+		entry := types.SPListEntry{ActorId: id}
+		switch {
+		case errs[i] != nil:
+			entry.Error = errs[i].Error()
+		case configs[i] == nil:
+			entry.Error = "not registered"
+		default:
+			entry.SPConfig = *configs[i]
+		}
+		entries = append(entries, entry)
+	}
+
+	return output.Render(c, entries, func() { printSPList(w, entries) })
+}
+
+func printSPList(w io.Writer, entries []types.SPListEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No storage providers registered.")
+		return
 	}
 
-	fmt.Printf("Registered Storage Providers (%d total)\n", len(spIds))
-	fmt.Printf("%-12s %-44s %-20s %-8s %-8s\n", "Actor ID", "Payment Address", "Piece Size Range", "Active", "Tokens")
-	fmt.Printf("%-12s %-44s %-20s %-8s %-8s\n", "--------", "---------------", "----------------", "------", "------")
+	fmt.Fprintf(w, "Registered Storage Providers (%d total)\n", len(entries))
+	fmt.Fprintf(w, "%-12s %-44s %-20s %-8s %-8s\n", "Actor ID", "Payment Address", "Piece Size Range", "Active", "Tokens")
+	fmt.Fprintf(w, "%-12s %-44s %-20s %-8s %-8s\n", "--------", "---------------", "----------------", "------", "------")
 
-	for _, id := range spIds {
-		spConfig, err := ddoClient.GetSPConfig(id)
-		if err != nil {
-			fmt.Printf("%-12d %-44s %-20s %-8s %-8s\n", id, "error", "error", "?", "?")
+	for _, entry := range entries {
+		id, spConfig := entry.ActorId, entry.SPConfig
+		if entry.Error != "" {
+			fmt.Fprintf(w, "%-12d %-44s %-20s %-8s %-8s\n", id, "error", "error", "?", "?")
 			continue
 		}
 
@@ -80,13 +89,11 @@ func executeListSPs(c *cli.Context) error {
 			utils.FormatBytes(new(big.Int).SetUint64(spConfig.MinPieceSize)),
 			utils.FormatBytes(new(big.Int).SetUint64(spConfig.MaxPieceSize)))
 
-		fmt.Printf("%-12d %-44s %-20s %-8s %-8d\n",
+		fmt.Fprintf(w, "%-12d %-44s %-20s %-8s %-8d\n",
 			id,
 			spConfig.PaymentAddress.Hex(),
 			sizeRange,
 			active,
 			len(spConfig.SupportedTokens))
 	}
-
-	return nil
 }
@@ -11,12 +11,16 @@ func SPCommand() *cli.Command {
 		Usage:   "Storage provider management commands",
 		Subcommands: []*cli.Command{
 			ListCommand(),
+			SearchCommand(),
 			RegisterCommand(),
+			ApplyCommand(),
 			UpdateCommand(),
 			QueryCommand(),
 			DeactivateCommand(),
 			RemoveTokenCommand(),
 			SettleCommand(),
+			EarningsCommand(),
+			ClaimsCommand(),
 		},
 	}
 }
@@ -2,12 +2,15 @@ package sp
 
 import (
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
@@ -17,16 +20,8 @@ func QueryCommand() *cli.Command {
 		Aliases: []string{"q"},
 		Usage:   "Query storage provider information and configuration",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
 			&cli.Uint64Flag{
 				Name:     "actor-id",
 				Aliases:  []string{"id"},
@@ -35,34 +30,25 @@ func QueryCommand() *cli.Command {
 			},
 			&cli.BoolFlag{
 				Name:  "json",
-				Usage: "Output in JSON format",
+				Usage: "Output in JSON format (same as --output json)",
 			},
 		},
-		Action: executeQuerySP,
+		Action: config.Action(executeQuerySP),
 	}
 }
 
-func executeQuerySP(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
+func executeQuerySP(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration (only need contract and RPC for queries)
-	if config.ContractAddress == "" {
-		return fmt.Errorf("missing DDO contract address (use --contract flag or DDO_CONTRACT_ADDRESS env var)")
-	}
-	if config.RPCEndpoint == "" {
-		return fmt.Errorf("missing RPC endpoint (use --rpc flag or RPC_URL env var)")
+	if err := cfg.RequireContract(); err != nil {
+		return err
 	}
 
 	actorId := c.Uint64("actor-id")
 
 	// Create read-only contract client
-	ddoClient, err := ddo.NewReadOnlyClientWithParams(config.RPCEndpoint, config.ContractAddress)
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create DDO contract client: %v", err)
 	}
@@ -73,75 +59,70 @@ func executeQuerySP(c *cli.Context) error {
 		return fmt.Errorf("failed to get SP config: %v", err)
 	}
 
+	details := types.SPDetails{ActorId: actorId}
 	if spConfig == nil {
-		fmt.Printf("❌ Storage Provider %d is not registered\n", actorId)
-		return nil
+		return output.Render(c, details, func() {
+			fmt.Fprintf(w, "❌ Storage Provider %d is not registered\n", actorId)
+		})
 	}
 
-	// Output in JSON format if requested
-	if c.Bool("json") {
-		fmt.Printf("{\n")
-		fmt.Printf("  \"actorId\": %d,\n", actorId)
-		fmt.Printf("  \"paymentAddress\": \"%s\",\n", spConfig.PaymentAddress.Hex())
-		fmt.Printf("  \"minPieceSize\": %d,\n", spConfig.MinPieceSize)
-		fmt.Printf("  \"maxPieceSize\": %d,\n", spConfig.MaxPieceSize)
-		fmt.Printf("  \"minTermLength\": %d,\n", spConfig.MinTermLength)
-		fmt.Printf("  \"maxTermLength\": %d,\n", spConfig.MaxTermLength)
-		fmt.Printf("  \"isActive\": %t,\n", spConfig.IsActive)
-		fmt.Printf("  \"supportedTokens\": [\n")
-		for i, token := range spConfig.SupportedTokens {
-			fmt.Printf("    {\n")
-			fmt.Printf("      \"token\": \"%s\",\n", token.Token.Hex())
-			fmt.Printf("      \"pricePerBytePerEpoch\": \"%s\",\n", token.PricePerBytePerEpoch.String())
-			fmt.Printf("      \"pricePerTBPerMonth\": \"%s\",\n", utils.ConvertBytesPerEpochToTBPerMonth(token.PricePerBytePerEpoch).String())
-			fmt.Printf("      \"isActive\": %t\n", token.IsActive)
-			if i < len(spConfig.SupportedTokens)-1 {
-				fmt.Printf("    },\n")
-			} else {
-				fmt.Printf("    }\n")
-			}
+	details.Registered = true
+	details.PaymentAddress = spConfig.PaymentAddress
+	details.MinPieceSize = spConfig.MinPieceSize
+	details.MaxPieceSize = spConfig.MaxPieceSize
+	details.MinTermLength = spConfig.MinTermLength
+	details.MaxTermLength = spConfig.MaxTermLength
+	details.IsActive = spConfig.IsActive
+	details.SupportedTokens = make([]types.SPTokenDetails, len(spConfig.SupportedTokens))
+	for i, token := range spConfig.SupportedTokens {
+		details.SupportedTokens[i] = types.SPTokenDetails{
+			Token:                token.Token,
+			PricePerBytePerEpoch: token.PricePerBytePerEpoch,
+			PricePerTBPerMonth:   utils.ConvertBytesPerEpochToTBPerMonth(token.PricePerBytePerEpoch),
+			IsActive:             token.IsActive,
 		}
-		fmt.Printf("  ]\n")
-		fmt.Printf("}\n")
-		return nil
 	}
 
+	return output.Render(c, details, func() { printSPDetails(w, cfg, spConfig, actorId) })
+}
+
+func printSPDetails(w io.Writer, cfg config.Config, spConfig *types.SPConfig, actorId uint64) {
 	// Human-readable format
-	fmt.Printf("📋 Storage Provider Information\n")
-	fmt.Printf("=====================================\n\n")
+	fmt.Fprintf(w, "📋 Storage Provider Information\n")
+	fmt.Fprintf(w, "=====================================\n\n")
 
-	fmt.Printf("🆔 Basic Information:\n")
-	fmt.Printf("   Actor ID: %d\n", actorId)
-	fmt.Printf("   Payment Address: %s\n", spConfig.PaymentAddress.Hex())
-	fmt.Printf("   Status: %s\n", func() string {
+	fmt.Fprintf(w, "🆔 Basic Information:\n")
+	fmt.Fprintf(w, "   Actor ID: %d\n", actorId)
+	fmt.Fprintf(w, "   Payment Address: %s\n", spConfig.PaymentAddress.Hex())
+	fmt.Fprintf(w, "   Status: %s\n", func() string {
 		if spConfig.IsActive {
 			return "✅ Active"
 		}
 		return "❌ Inactive"
 	}())
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Printf("📏 Capacity Limits:\n")
-	fmt.Printf("   Min Piece Size: %s (%d bytes)\n",
+	fmt.Fprintf(w, "📏 Capacity Limits:\n")
+	fmt.Fprintf(w, "   Min Piece Size: %s (%d bytes)\n",
 		utils.FormatBytes(new(big.Int).SetUint64(spConfig.MinPieceSize)),
 		spConfig.MinPieceSize)
-	fmt.Printf("   Max Piece Size: %s (%d bytes)\n",
+	fmt.Fprintf(w, "   Max Piece Size: %s (%d bytes)\n",
 		utils.FormatBytes(new(big.Int).SetUint64(spConfig.MaxPieceSize)),
 		spConfig.MaxPieceSize)
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Printf("⏰ Term Limits:\n")
-	fmt.Printf("   Min Term: %d epochs (~%.1f days)\n",
+	fmt.Fprintf(w, "⏰ Term Limits:\n")
+	fmt.Fprintf(w, "   Min Term: %d epochs (~%.1f days)\n",
 		spConfig.MinTermLength,
 		float64(spConfig.MinTermLength)/2880.0)
-	fmt.Printf("   Max Term: %d epochs (~%.1f days)\n",
+	fmt.Fprintf(w, "   Max Term: %d epochs (~%.1f days)\n",
 		spConfig.MaxTermLength,
 		float64(spConfig.MaxTermLength)/2880.0)
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Printf("🪙 Supported Tokens (%d tokens):\n", len(spConfig.SupportedTokens))
+	fmt.Fprintf(w, "🪙 Supported Tokens (%d tokens):\n", len(spConfig.SupportedTokens))
 	if len(spConfig.SupportedTokens) == 0 {
-		fmt.Printf("   No tokens configured\n")
+		fmt.Fprintf(w, "   No tokens configured\n")
 	} else {
 		for i, token := range spConfig.SupportedTokens {
 			status := "✅ Active"
@@ -149,9 +130,12 @@ func executeQuerySP(c *cli.Context) error {
 				status = "❌ Inactive"
 			}
 
-			fmt.Printf("   %d. %s\n", i+1, status)
-			fmt.Printf("      Token Address: %s\n", token.Token.Hex())
-			fmt.Printf("      Price: %s\n", utils.FormatPriceBothFormats(token.PricePerBytePerEpoch))
+			meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, token.Token)
+
+			fmt.Fprintf(w, "   %d. %s\n", i+1, status)
+			fmt.Fprintf(w, "      Token: %s (%s)\n", meta.Symbol, meta.Name)
+			fmt.Fprintf(w, "      Token Address: %s\n", token.Token.Hex())
+			fmt.Fprintf(w, "      Price: %s\n", utils.FormatPriceBothFormats(token.PricePerBytePerEpoch, meta))
 
 			// Calculate example costs for common scenarios
 			exampleSizes := []uint64{
@@ -166,7 +150,7 @@ func executeQuerySP(c *cli.Context) error {
 				1036800, // 360 days
 			}
 
-			fmt.Printf("      Example Costs:\n")
+			fmt.Fprintf(w, "      Example Costs:\n")
 			for _, size := range exampleSizes {
 				if size >= spConfig.MinPieceSize && size <= spConfig.MaxPieceSize {
 					for _, term := range exampleTerms {
@@ -177,20 +161,18 @@ func executeQuerySP(c *cli.Context) error {
 							)
 							cost.Mul(cost, big.NewInt(term))
 
-							fmt.Printf("        %s for %d days: %s USDC\n",
+							fmt.Fprintf(w, "        %s for %d days: %s\n",
 								utils.FormatBytes(new(big.Int).SetUint64(size)),
 								term/2880,
-								utils.ConvertTokenUnitsToUSD(cost))
+								utils.FormatTokenAmount(cost, meta))
 						}
 					}
 				}
 			}
 
 			if i < len(spConfig.SupportedTokens)-1 {
-				fmt.Println()
+				fmt.Fprintln(w)
 			}
 		}
 	}
-
-	return nil
 }
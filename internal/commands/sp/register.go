@@ -11,7 +11,9 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
 	"github.com/Eastore-project/ddo-client/pkg/types"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
@@ -22,21 +24,9 @@ func RegisterCommand() *cli.Command {
 		Aliases: []string{"reg"},
 		Usage:   "Register a new storage provider with the DDO contract",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			// SP Configuration
 			&cli.Uint64Flag{
 				Name:     "actor-id",
@@ -90,7 +80,7 @@ func RegisterCommand() *cli.Command {
 				Usage: "Show configuration without sending transaction",
 			},
 		},
-		Action: executeRegisterSP,
+		Action: config.Action(executeRegisterSP),
 	}
 }
 
@@ -106,20 +96,11 @@ type TokenConfigFile struct {
 	Tokens []TokenConfigInput `json:"tokens"`
 }
 
-func executeRegisterSP(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
-	if pk := c.String("private-key"); pk != "" {
-		config.PrivateKey = pk
-	}
+func executeRegisterSP(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration
-	if missing := config.GetMissingConfig(); len(missing) > 0 {
+	if missing := cfg.MissingConfig(); len(missing) > 0 {
 		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
@@ -183,15 +164,22 @@ func executeRegisterSP(c *cli.Context) error {
 
 	// Convert token inputs to contract format
 	tokenConfigs := make([]types.TokenConfig, len(tokenInputs))
+	tokenMetas := make([]*types.TokenMetadata, len(tokenInputs))
 	for i, tokenInput := range tokenInputs {
 		if !common.IsHexAddress(tokenInput.Token) {
 			return fmt.Errorf("invalid token address: %s", tokenInput.Token)
 		}
 
+		meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, common.HexToAddress(tokenInput.Token))
+		if err != nil {
+			return fmt.Errorf("failed to get metadata for token %s: %v", tokenInput.Token, err)
+		}
+		tokenMetas[i] = meta
+
 		// Parse price as USD per TB per month and convert to bytes per epoch in token units
-		pricePerBytePerEpoch, err := utils.ConvertUSDPerTBPerMonthToBytesPerEpoch(tokenInput.PriceUSDPerTBPerMonth)
+		pricePerBytePerEpoch, err := utils.ConvertUSDPerTBPerMonthToBytesPerEpoch(tokenInput.PriceUSDPerTBPerMonth, meta.Decimals)
 		if err != nil {
-			return fmt.Errorf("invalid USD price format for token %s: %v", tokenInput.Token, err)
+			return fmt.Errorf("invalid price for token %s: %v", tokenInput.Token, err)
 		}
 
 		tokenConfigs[i] = types.TokenConfig{
@@ -201,9 +189,10 @@ func executeRegisterSP(c *cli.Context) error {
 		}
 
 		// Show both formats for clarity
-		fmt.Printf("   Token %s: %s\n",
+		fmt.Fprintf(w, "   Token %s (%s): %s\n",
 			tokenInput.Token,
-			utils.FormatPriceBothFormats(pricePerBytePerEpoch))
+			meta.Symbol,
+			utils.FormatPriceBothFormats(pricePerBytePerEpoch, meta))
 	}
 
 	// Create registration parameters
@@ -217,100 +206,96 @@ func executeRegisterSP(c *cli.Context) error {
 		TokenConfigs:   tokenConfigs,
 	}
 
-	fmt.Printf("📋 Storage Provider Registration Details:\n")
-	fmt.Printf("   Actor ID: %d\n", regParams.ActorId)
-	fmt.Printf("   Payment Address: %s\n", regParams.PaymentAddress.Hex())
-	fmt.Printf("   Piece Size Range: %s - %s\n",
+	fmt.Fprintf(w, "📋 Storage Provider Registration Details:\n")
+	fmt.Fprintf(w, "   Actor ID: %d\n", regParams.ActorId)
+	fmt.Fprintf(w, "   Payment Address: %s\n", regParams.PaymentAddress.Hex())
+	fmt.Fprintf(w, "   Piece Size Range: %s - %s\n",
 		utils.FormatBytes(new(big.Int).SetUint64(regParams.MinPieceSize)),
 		utils.FormatBytes(new(big.Int).SetUint64(regParams.MaxPieceSize)))
-	fmt.Printf("   Term Range: %d - %d epochs\n", regParams.MinTermLength, regParams.MaxTermLength)
-	fmt.Printf("   Term Range (days): ~%.1f - ~%.1f days\n",
+	fmt.Fprintf(w, "   Term Range: %d - %d epochs\n", regParams.MinTermLength, regParams.MaxTermLength)
+	fmt.Fprintf(w, "   Term Range (days): ~%.1f - ~%.1f days\n",
 		float64(regParams.MinTermLength)/2880.0,
 		float64(regParams.MaxTermLength)/2880.0)
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Printf("🪙 Supported Token Configurations (%d tokens):\n", len(tokenConfigs))
+	fmt.Fprintf(w, "🪙 Supported Token Configurations (%d tokens):\n", len(tokenConfigs))
 	for i, tc := range tokenConfigs {
-		fmt.Printf("   %d. Token: %s\n", i+1, tc.Token.Hex())
-		fmt.Printf("      Actual price after Rounding off (bytes per epoch): %s\n", utils.FormatPriceBothFormats(tc.PricePerBytePerEpoch))
-		fmt.Printf("      Active: %t\n", tc.IsActive)
+		fmt.Fprintf(w, "   %d. Token: %s (%s)\n", i+1, tc.Token.Hex(), tokenMetas[i].Symbol)
+		fmt.Fprintf(w, "      Actual price after Rounding off (bytes per epoch): %s\n", utils.FormatPriceBothFormats(tc.PricePerBytePerEpoch, tokenMetas[i]))
+		fmt.Fprintf(w, "      Active: %t\n", tc.IsActive)
 		if i < len(tokenConfigs)-1 {
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// If dry run, just show configuration
 	if c.Bool("dry-run") {
-		fmt.Printf("🎯 Dry Run Results:\n\n")
+		fmt.Fprintf(w, "🎯 Dry Run Results:\n\n")
 
 		// Check if SP is already registered
-		ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+		ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 		if err != nil {
 			return fmt.Errorf("failed to create DDO contract client: %v", err)
 		}
 
 		isRegistered, err := ddoClient.IsSPRegistered(actorId)
 		if err != nil {
-			fmt.Printf("⚠️  Could not check SP registration status: %v\n", err)
+			fmt.Fprintf(w, "⚠️  Could not check SP registration status: %v\n", err)
 		} else if isRegistered {
-			fmt.Printf("⚠️  Storage Provider %d is already registered\n", actorId)
+			fmt.Fprintf(w, "⚠️  Storage Provider %d is already registered\n", actorId)
 
 			// Get existing config
 			existingConfig, err := ddoClient.GetSPConfig(actorId)
 			if err != nil {
-				fmt.Printf("⚠️  Could not retrieve existing config: %v\n", err)
+				fmt.Fprintf(w, "⚠️  Could not retrieve existing config: %v\n", err)
 			} else {
-				fmt.Printf("📋 Current Configuration:\n")
-				fmt.Printf("   Payment Address: %s\n", existingConfig.PaymentAddress.Hex())
-				fmt.Printf("   Piece Size Range: %s - %s\n",
+				fmt.Fprintf(w, "📋 Current Configuration:\n")
+				fmt.Fprintf(w, "   Payment Address: %s\n", existingConfig.PaymentAddress.Hex())
+				fmt.Fprintf(w, "   Piece Size Range: %s - %s\n",
 					utils.FormatBytes(new(big.Int).SetUint64(existingConfig.MinPieceSize)),
 					utils.FormatBytes(new(big.Int).SetUint64(existingConfig.MaxPieceSize)))
-				fmt.Printf("   Term Range: %d - %d epochs\n", existingConfig.MinTermLength, existingConfig.MaxTermLength)
-				fmt.Printf("   Active: %t\n", existingConfig.IsActive)
-				fmt.Printf("   Supported Tokens: %d\n", len(existingConfig.SupportedTokens))
+				fmt.Fprintf(w, "   Term Range: %d - %d epochs\n", existingConfig.MinTermLength, existingConfig.MaxTermLength)
+				fmt.Fprintf(w, "   Active: %t\n", existingConfig.IsActive)
+				fmt.Fprintf(w, "   Supported Tokens: %d\n", len(existingConfig.SupportedTokens))
 			}
 		} else {
-			fmt.Printf("✅ Storage Provider %d is not registered yet\n", actorId)
+			fmt.Fprintf(w, "✅ Storage Provider %d is not registered yet\n", actorId)
 		}
 
-		fmt.Printf("Configuration validated successfully!\n")
-		fmt.Printf("Contract: %s\n", config.ContractAddress)
-		fmt.Printf("RPC: %s\n", config.RPCEndpoint)
-		fmt.Println()
-		fmt.Printf("📝 Next Steps:\n")
-		fmt.Printf("1. Ensure you are the contract owner\n")
-		fmt.Printf("2. Run without --dry-run to execute registration\n")
+		fmt.Fprintf(w, "Configuration validated successfully!\n")
+		fmt.Fprintf(w, "Contract: %s\n", cfg.ContractAddress)
+		fmt.Fprintf(w, "RPC: %s\n", cfg.RPCEndpoint)
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "📝 Next Steps:\n")
+		fmt.Fprintf(w, "1. Ensure you are the contract owner\n")
+		fmt.Fprintf(w, "2. Run without --dry-run to execute registration\n")
 		return nil
 	}
 
 	// Create contract client
-	ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+	ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 	if err != nil {
 		return fmt.Errorf("failed to create DDO contract client: %v", err)
 	}
 
 	// Execute the transaction
-	fmt.Printf("🚀 Registering storage provider...\n")
-	fmt.Printf("DDO Contract: %s\n", config.ContractAddress)
-	fmt.Printf("RPC: %s\n", config.RPCEndpoint)
+	fmt.Fprintf(w, "🚀 Registering storage provider...\n")
+	fmt.Fprintf(w, "DDO Contract: %s\n", cfg.ContractAddress)
+	fmt.Fprintf(w, "RPC: %s\n", cfg.RPCEndpoint)
 
 	txHash, err := ddoClient.RegisterSP(regParams)
 	if err != nil {
 		return fmt.Errorf("failed to register SP: %v", err)
 	}
 
-	fmt.Printf("✅ Registration successful!\n")
-	fmt.Printf("Transaction Hash: %s\n", txHash)
+	fmt.Fprintf(w, "✅ Registration successful!\n")
+	fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
 	// Wait for transaction to be mined using the existing client
-	fmt.Printf("⏳ Waiting for transaction to be mined...\n")
-	if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-		fmt.Printf("⚠️  Warning: transaction may not have been mined: %v\n", err)
-		return nil
-	}
-
-	fmt.Printf("✅ Registration transaction mined successfully!\n")
-
-	return nil
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	result := output.WaitTx(ddoClient.TxManager(), txHash)
+	return output.RenderTx(c, result, func() {
+		fmt.Fprintf(w, "✅ Registration transaction mined successfully!\n")
+	})
 }
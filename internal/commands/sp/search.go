@@ -0,0 +1,257 @@
+package sp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/curio"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+const (
+	// endpointCheckTimeout bounds the reachability check of one SP endpoint
+	endpointCheckTimeout = 5 * time.Second
+	// endpointCheckWorkers is the number of SP endpoints checked concurrently
+	endpointCheckWorkers = 8
+)
+
+func SearchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "search",
+		Usage: "Find storage providers matching a token, piece size and term, ranked by cost within each token",
+		Flags: []cli.Flag{
+			config.ContractFlag(),
+			config.RPCFlag(),
+			&cli.StringSliceFlag{
+				Name:    "token",
+				Aliases: []string{"t"},
+				Usage:   "Only include offers in this token (repeatable)",
+			},
+			&cli.Uint64Flag{
+				Name:    "piece-size",
+				Aliases: []string{"size"},
+				Usage:   "Piece size in bytes the provider must accept",
+			},
+			&cli.Int64Flag{
+				Name:  "term",
+				Usage: "Term length in epochs the provider must accept",
+			},
+			&cli.Int64Flag{
+				Name:  "days",
+				Usage: "Term length in days (alternative to --term)",
+			},
+			&cli.BoolFlag{
+				Name:  "include-inactive",
+				Usage: "Include inactive providers and tokens",
+			},
+			&cli.BoolFlag{
+				Name:  "check-endpoints",
+				Usage: "Check that each provider's Curio market endpoint is reachable",
+				Value: true,
+			},
+			&cli.BoolFlag{
+				Name:  "reachable-only",
+				Usage: "Only include providers whose Curio endpoint is reachable",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Show at most this many offers per token (0 for all)",
+			},
+		},
+		Action: config.Action(executeSearchSPs),
+	}
+}
+
+func executeSearchSPs(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	if err := cfg.RequireContract(); err != nil {
+		return err
+	}
+
+	if c.IsSet("term") && c.IsSet("days") {
+		return fmt.Errorf("use either --term or --days, not both")
+	}
+	if c.Bool("reachable-only") && !c.Bool("check-endpoints") {
+		return fmt.Errorf("--reachable-only requires --check-endpoints")
+	}
+
+	filter := utils.SPSearchFilter{
+		PieceSize:       c.Uint64("piece-size"),
+		TermLength:      c.Int64("term"),
+		IncludeInactive: c.Bool("include-inactive"),
+	}
+	if days := c.Int64("days"); days != 0 {
+		filter.TermLength = days * utils.EPOCHS_PER_DAY
+	}
+	for _, t := range c.StringSlice("token") {
+		if !common.IsHexAddress(t) {
+			return fmt.Errorf("invalid token address: %s", t)
+		}
+		filter.Tokens = append(filter.Tokens, common.HexToAddress(t))
+	}
+
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	decimals := func(tokenAddr common.Address) (uint8, error) {
+		meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenAddr)
+		if err != nil {
+			return 0, err
+		}
+		return meta.Decimals, nil
+	}
+
+	offers, totalProviders, err := utils.SearchSPOffers(ddoClient, filter, decimals)
+	if err != nil {
+		return fmt.Errorf("failed to search storage providers: %v", err)
+	}
+
+	if c.Bool("check-endpoints") {
+		checkOfferEndpoints(cfg, offers)
+		if c.Bool("reachable-only") {
+			reachable := offers[:0]
+			for _, o := range offers {
+				if o.Reachable {
+					reachable = append(reachable, o)
+				}
+			}
+			offers = reachable
+		}
+	}
+
+	if limit := c.Int("limit"); limit > 0 {
+		offers = utils.LimitSPOffers(offers, limit)
+	}
+
+	return output.Render(c, offers, func() {
+		printOffers(w, cfg, offers, filter, totalProviders, c.Bool("check-endpoints"))
+	})
+}
+
+// checkOfferEndpoints discovers and checks the Curio endpoint of every provider in offers
+func checkOfferEndpoints(cfg config.Config, offers []types.SPOffer) {
+	type endpointStatus struct {
+		url string
+		err error
+	}
+
+	statuses := make(map[uint64]*endpointStatus)
+	for _, o := range offers {
+		statuses[o.ProviderId] = &endpointStatus{}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, endpointCheckWorkers)
+	for id, status := range statuses {
+		wg.Add(1)
+		go func(id uint64, status *endpointStatus) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			status.url, status.err = curio.DiscoverSPURL(cfg.RPCEndpoint, cfg.RPC, id)
+			if status.err != nil {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), endpointCheckTimeout)
+			defer cancel()
+			status.err = curio.CheckReachable(ctx, status.url)
+		}(id, status)
+	}
+	wg.Wait()
+
+	for i := range offers {
+		status := statuses[offers[i].ProviderId]
+		offers[i].Endpoint = status.url
+		offers[i].Reachable = status.err == nil
+		if status.err != nil {
+			offers[i].EndpointError = status.err.Error()
+		}
+	}
+}
+
+func printOffers(w io.Writer, cfg config.Config, offers []types.SPOffer, filter utils.SPSearchFilter, totalProviders int, checkedEndpoints bool) {
+	fmt.Fprintf(w, "🔎 Storage Provider Search (%d providers registered)\n", totalProviders)
+	if filter.PieceSize != 0 {
+		fmt.Fprintf(w, "   Piece Size: %s\n", utils.FormatBytes(new(big.Int).SetUint64(filter.PieceSize)))
+	}
+	if filter.TermLength != 0 {
+		fmt.Fprintf(w, "   Term: %d epochs (~%.1f days)\n", filter.TermLength, float64(filter.TermLength)/utils.EPOCHS_PER_DAY)
+	}
+	fmt.Fprintf(w, "   Matching Offers: %d\n", len(offers))
+	fmt.Fprintln(w)
+
+	if len(offers) == 0 {
+		fmt.Fprintf(w, "📭 No providers match the search\n")
+		return
+	}
+
+	fmt.Fprintf(w, "%-4s %-10s %-12s %-28s %-24s %-20s %-9s\n", "#", "Actor ID", "Token", "Price (per TB per month)", "Total Cost", "Piece Size Range", "Endpoint")
+	fmt.Fprintf(w, "%-4s %-10s %-12s %-28s %-24s %-20s %-9s\n", "-", "--------", "-----", "------------------------", "----------", "----------------", "--------")
+	rank := 0
+	for i, o := range offers {
+		meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, o.Token)
+
+		// Offers are ranked within their token
+		rank++
+		if i > 0 && offers[i-1].Token != o.Token {
+			rank = 1
+		}
+
+		totalCost := "-"
+		if o.TotalCost != nil {
+			totalCost = utils.FormatTokenAmount(o.TotalCost, meta)
+		}
+
+		endpoint := "-"
+		if checkedEndpoints {
+			endpoint = "✅"
+			if !o.Reachable {
+				endpoint = "❌"
+			}
+		}
+
+		name := meta.Symbol
+		if !o.Active {
+			name += " (off)"
+		}
+
+		fmt.Fprintf(w, "%-4d %-10d %-12s %-28s %-24s %-20s %-9s\n",
+			rank,
+			o.ProviderId,
+			name,
+			utils.FormatTokenAmount(o.PricePerTBPerMonth, meta),
+			totalCost,
+			fmt.Sprintf("%s - %s",
+				utils.FormatBytes(new(big.Int).SetUint64(o.MinPieceSize)),
+				utils.FormatBytes(new(big.Int).SetUint64(o.MaxPieceSize))),
+			endpoint)
+	}
+
+	if checkedEndpoints {
+		fmt.Fprintln(w)
+		reported := make(map[uint64]bool)
+		for _, o := range offers {
+			if !o.Reachable && !reported[o.ProviderId] {
+				reported[o.ProviderId] = true
+				fmt.Fprintf(w, "⚠️  Provider %d endpoint: %s\n", o.ProviderId, o.EndpointError)
+			}
+		}
+	}
+}
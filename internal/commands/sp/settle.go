@@ -3,17 +3,18 @@ package sp
 import (
 	"context"
 	"fmt"
-	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
 	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/types"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
@@ -23,26 +24,10 @@ func SettleCommand() *cli.Command {
 		Aliases: []string{"settlement"},
 		Usage:   "Settle storage provider payments for allocations",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "payments-contract",
-				Aliases: []string{"pc"},
-				Usage:   "Payments contract address (optional - will fetch from DDO contract if not provided)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.PaymentsContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.Uint64Flag{
 				Name:    "provider",
 				Aliases: []string{"p"},
@@ -63,24 +48,15 @@ func SettleCommand() *cli.Command {
 				Usage: "Show what would be settled without executing transactions",
 			},
 		},
-		Action: executeSettle,
+		Action: config.Action(executeSettle),
 	}
 }
 
-func executeSettle(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
-	if pk := c.String("private-key"); pk != "" {
-		config.PrivateKey = pk
-	}
+func executeSettle(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration
-	if missing := config.GetMissingConfig(); len(missing) > 0 {
+	if missing := cfg.MissingConfig(); len(missing) > 0 {
 		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
@@ -94,14 +70,14 @@ func executeSettle(c *cli.Context) error {
 	}
 
 	// Create contract client
-	ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+	ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 	if err != nil {
 		return fmt.Errorf("failed to create DDO contract client: %v", err)
 	}
 
 	// Get current block number if until-epoch not specified
 	if untilEpoch == 0 {
-		ethClient, err := ethclient.Dial(config.RPCEndpoint)
+		ethClient, err := rpcclient.Dial(cfg.RPCEndpoint, cfg.RPC)
 		if err != nil {
 			return fmt.Errorf("failed to create eth client: %v", err)
 		}
@@ -112,11 +88,11 @@ func executeSettle(c *cli.Context) error {
 			return fmt.Errorf("failed to get current block number: %v", err)
 		}
 		untilEpoch = currentBlock
-		fmt.Printf("Using current block number as until-epoch: %d\n", untilEpoch)
+		fmt.Fprintf(w, "Using current block number as until-epoch: %d\n", untilEpoch)
 	}
 
 	// Get user address from private key for display
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey, "0x"))
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
 	if err != nil {
 		return fmt.Errorf("failed to parse private key: %v", err)
 	}
@@ -134,7 +110,7 @@ func executeSettle(c *cli.Context) error {
 	}
 
 	// Get SP configuration to find payment address and supported tokens
-	fmt.Printf("🔍 Getting SP information for provider %d...\n", targetProviderId)
+	fmt.Fprintf(w, "🔍 Getting SP information for provider %d...\n", targetProviderId)
 	spConfig, err := ddoClient.GetSPConfig(targetProviderId)
 	if err != nil {
 		return fmt.Errorf("failed to get SP config for provider %d: %v", targetProviderId, err)
@@ -143,65 +119,49 @@ func executeSettle(c *cli.Context) error {
 		return fmt.Errorf("SP %d is not registered", targetProviderId)
 	}
 
-	// Get payments contract address
-	var paymentsContractAddr common.Address
-	paymentsContractStr := c.String("payments-contract")
-	if paymentsContractStr != "" {
-		paymentsContractAddr = common.HexToAddress(paymentsContractStr)
-		fmt.Printf("Using provided payments contract address: %s\n", paymentsContractAddr.Hex())
-	} else {
-		// Get payments contract address from DDO contract
-		paymentsContractAddr, err = ddoClient.GetPaymentsContract()
-		if err != nil {
-			return fmt.Errorf("failed to get payments contract address from DDO contract: %v", err)
-		}
-		fmt.Printf("Fetched payments contract address from DDO: %s\n", paymentsContractAddr.Hex())
+	// The payments contract is configured or was discovered from the DDO contract
+	if err := cfg.RequirePayments(); err != nil {
+		return err
 	}
+	paymentsContractAddr := common.HexToAddress(cfg.PaymentsContractAddress)
 
-	// Override payments contract address in config temporarily
-	originalPaymentsContract := config.PaymentsContractAddress
-	config.PaymentsContractAddress = paymentsContractAddr.Hex()
-	defer func() {
-		config.PaymentsContractAddress = originalPaymentsContract
-	}()
-
-	// Create payments client after configuring the address
-	paymentsClient, err := payments.NewReadOnlyClientWithParams(config.RPCEndpoint, config.PaymentsContractAddress)
+	paymentsClient, err := payments.NewReadOnlyClientWithParams(cfg.RPCEndpoint, cfg.PaymentsContractAddress, cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("failed to create payments client: %v", err)
 	}
 	defer paymentsClient.Close()
 
-	fmt.Printf("🏦 Settlement Parameters:\n")
-	fmt.Printf("   User Address: %s\n", userAddress.Hex())
-	fmt.Printf("   DDO Contract: %s\n", config.ContractAddress)
-	fmt.Printf("   Payments Contract: %s\n", paymentsContractAddr.Hex())
-	fmt.Printf("   Until Epoch: %d\n", untilEpoch)
-	fmt.Printf("   SP Payment Address: %s\n", spConfig.PaymentAddress.Hex())
-	fmt.Printf("   SP Active Tokens: %d\n", len(spConfig.SupportedTokens))
-	fmt.Println()
+	fmt.Fprintf(w, "🏦 Settlement Parameters:\n")
+	fmt.Fprintf(w, "   User Address: %s\n", userAddress.Hex())
+	fmt.Fprintf(w, "   DDO Contract: %s\n", cfg.ContractAddress)
+	fmt.Fprintf(w, "   Payments Contract: %s\n", paymentsContractAddr.Hex())
+	fmt.Fprintf(w, "   Until Epoch: %d\n", untilEpoch)
+	fmt.Fprintf(w, "   SP Payment Address: %s\n", spConfig.PaymentAddress.Hex())
+	fmt.Fprintf(w, "   SP Active Tokens: %d\n", len(spConfig.SupportedTokens))
+	fmt.Fprintln(w)
 
 	// Log SP supported tokens
-	fmt.Printf("📋 SP Supported Tokens:\n")
+	fmt.Fprintf(w, "📋 SP Supported Tokens:\n")
 	for i, tokenConfig := range spConfig.SupportedTokens {
 		status := "inactive"
 		if tokenConfig.IsActive {
 			status = "active"
 		}
+		meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenConfig.Token)
 		if tokenConfig.Token.Hex() == "0x0000000000000000000000000000000000000000" {
-			fmt.Printf("   %d. Native Token (FIL) - %s (price: %s)\n",
-				i+1, status, utils.FormatPriceBothFormats(tokenConfig.PricePerBytePerEpoch))
+			fmt.Fprintf(w, "   %d. Native Token (FIL) - %s (price: %s)\n",
+				i+1, status, utils.FormatPriceBothFormats(tokenConfig.PricePerBytePerEpoch, meta))
 		} else {
-			fmt.Printf("   %d. %s - %s (price: %s)\n",
-				i+1, tokenConfig.Token.Hex(), status, utils.FormatPriceBothFormats(tokenConfig.PricePerBytePerEpoch))
+			fmt.Fprintf(w, "   %d. %s (%s) - %s (price: %s)\n",
+				i+1, meta.Symbol, tokenConfig.Token.Hex(), status, utils.FormatPriceBothFormats(tokenConfig.PricePerBytePerEpoch, meta))
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	if c.Bool("dry-run") {
 		if allocationId > 0 {
-			fmt.Printf("   Mode: Single Allocation Settlement\n")
-			fmt.Printf("   Allocation ID: %d\n", allocationId)
+			fmt.Fprintf(w, "   Mode: Single Allocation Settlement\n")
+			fmt.Fprintf(w, "   Allocation ID: %d\n", allocationId)
 
 			// Get allocation details for dry run
 			railId, providerIdFromAllocation, railView, err := ddoClient.GetAllocationRailInfo(allocationId)
@@ -209,15 +169,15 @@ func executeSettle(c *cli.Context) error {
 				return fmt.Errorf("failed to get allocation rail info: %v", err)
 			}
 
-			fmt.Printf("\n📊 Allocation Details:\n")
-			fmt.Printf("   Provider ID: %d\n", providerIdFromAllocation)
-			fmt.Printf("   Rail ID: %d\n", railId)
-			fmt.Printf("   Current Payment Rate: %s\n", railView.PaymentRate.String())
-			fmt.Printf("   Settled Up To: %d\n", railView.SettledUpTo.Uint64())
-			fmt.Printf("   Token: %s\n", railView.Token.Hex())
+			fmt.Fprintf(w, "\n📊 Allocation Details:\n")
+			fmt.Fprintf(w, "   Provider ID: %d\n", providerIdFromAllocation)
+			fmt.Fprintf(w, "   Rail ID: %d\n", railId)
+			fmt.Fprintf(w, "   Current Payment Rate: %s\n", railView.PaymentRate.String())
+			fmt.Fprintf(w, "   Settled Up To: %d\n", railView.SettledUpTo.Uint64())
+			fmt.Fprintf(w, "   Token: %s\n", railView.Token.Hex())
 		} else {
-			fmt.Printf("   Mode: Total Provider Settlement\n")
-			fmt.Printf("   Provider ID: %d\n", providerId)
+			fmt.Fprintf(w, "   Mode: Total Provider Settlement\n")
+			fmt.Fprintf(w, "   Provider ID: %d\n", providerId)
 
 			// Get all allocations for provider for dry run
 			allocationIds, err := ddoClient.GetAllocationIdsForProvider(providerId)
@@ -225,19 +185,33 @@ func executeSettle(c *cli.Context) error {
 				return fmt.Errorf("failed to get allocation IDs for provider: %v", err)
 			}
 
-			fmt.Printf("\n📋 Provider Allocation Summary:\n")
-			fmt.Printf("   Total Allocations: %d\n", len(allocationIds))
-			fmt.Printf("   Allocation IDs: %v\n", allocationIds)
+			rails, err := ddoClient.GetAllocationRailInfos(allocationIds)
+			if err != nil {
+				return fmt.Errorf("failed to get allocation rail info: %v", err)
+			}
+
+			fmt.Fprintf(w, "\n📋 Provider Allocation Summary:\n")
+			fmt.Fprintf(w, "   Total Allocations: %d\n", len(allocationIds))
+			unsettled := 0
+			for _, r := range rails {
+				if r.RailId == 0 || r.Rail.SettledUpTo.Uint64() >= untilEpoch {
+					continue
+				}
+				unsettled++
+				fmt.Fprintf(w, "   Allocation %d: rail %d settled up to epoch %d (rate %s per epoch)\n",
+					r.AllocationId, r.RailId, r.Rail.SettledUpTo.Uint64(), r.Rail.PaymentRate.String())
+			}
+			fmt.Fprintf(w, "   Allocations to settle: %d\n", unsettled)
 		}
 
-		fmt.Printf("\n📝 Next Steps:\n")
-		fmt.Printf("1. Run without --dry-run to execute settlement\n")
-		fmt.Printf("2. Ensure you have sufficient gas for the transaction(s)\n")
+		fmt.Fprintf(w, "\n📝 Next Steps:\n")
+		fmt.Fprintf(w, "1. Run without --dry-run to execute settlement\n")
+		fmt.Fprintf(w, "2. Ensure you have sufficient gas for the transaction(s)\n")
 		return nil
 	}
 
 	// Get SP account information from payments contract before settlement
-	fmt.Printf("💰 Checking SP account information before settlement...\n")
+	fmt.Fprintf(w, "💰 Checking SP account information before settlement...\n")
 	for i, tokenConfig := range spConfig.SupportedTokens {
 		if !tokenConfig.IsActive {
 			continue
@@ -245,104 +219,51 @@ func executeSettle(c *cli.Context) error {
 
 		account, err := paymentsClient.GetAccount(tokenConfig.Token, spConfig.PaymentAddress)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: failed to get account info for token %d (%s): %v\n", i+1, tokenConfig.Token.Hex(), err)
+			fmt.Fprintf(w, "⚠️  Warning: failed to get account info for token %d (%s): %v\n", i+1, tokenConfig.Token.Hex(), err)
 			continue
 		}
 
-		tokenName := tokenConfig.Token.Hex()
+		meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenConfig.Token)
+		tokenName := fmt.Sprintf("%s (%s)", meta.Symbol, tokenConfig.Token.Hex())
 		if tokenConfig.Token.Hex() == "0x0000000000000000000000000000000000000000" {
-			tokenName = "Native Token (ETH)"
+			tokenName = "Native Token (FIL)"
 		}
 
-		fmt.Printf("🔍 SP Account Info (Before) - %s:\n", tokenName)
-		fmt.Printf("   Funds: %s\n", account.Funds.String())
-		fmt.Printf("   Lockup Current: %s\n", account.LockupCurrent.String())
-		fmt.Printf("   Lockup Rate: %s\n", account.LockupRate.String())
-		fmt.Printf("   Lockup Last Settled At: %s\n", account.LockupLastSettledAt.String())
-		fmt.Println()
+		fmt.Fprintf(w, "🔍 SP Account Info (Before) - %s:\n", tokenName)
+		fmt.Fprintf(w, "   Funds: %s\n", utils.FormatTokenAmount(account.Funds, meta))
+		fmt.Fprintf(w, "   Lockup Current: %s\n", utils.FormatTokenAmount(account.LockupCurrent, meta))
+		fmt.Fprintf(w, "   Lockup Rate: %s per epoch\n", utils.FormatTokenAmount(account.LockupRate, meta))
+		fmt.Fprintf(w, "   Lockup Last Settled At: %s\n", account.LockupLastSettledAt.String())
+		fmt.Fprintln(w)
+	}
+
+	result := types.SPSettlement{
+		ProviderId:   targetProviderId,
+		AllocationId: allocationId,
+		UntilEpoch:   untilEpoch,
+		Transactions: []types.TxResult{},
+		Accounts:     []types.AccountDetails{},
 	}
 
 	// Execute settlement based on parameters
-	var txHash string
 	if allocationId > 0 {
-		// Settle specific allocation
-		fmt.Printf("💰 Settling payment for allocation %d until epoch %d...\n", allocationId, untilEpoch)
-
-		txHash, err = ddoClient.SettleSpPayment(allocationId, new(big.Int).SetUint64(untilEpoch))
-		if err != nil {
-			return fmt.Errorf("failed to settle SP payment for allocation %d: %v", allocationId, err)
-		}
-
-		fmt.Printf("Transaction Hash: %s\n", txHash)
-
-		fmt.Printf("⏳ Waiting for settlement transaction to be mined...\n")
-		if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-			return fmt.Errorf("settlement transaction failed: %v", err)
-		}
-		fmt.Printf("✅ Settlement transaction mined successfully!\n")
+		fmt.Fprintf(w, "💰 Settling payment for allocation %d until epoch %d...\n", allocationId, untilEpoch)
 	} else {
-		// Try settling all at once first
-		settleEpoch := new(big.Int).SetUint64(untilEpoch)
-		fmt.Printf("💰 Settling payments for all allocations of provider %d until epoch %d...\n", providerId, untilEpoch)
-
-		txHash, err = ddoClient.SettleSpTotalPayment(providerId, settleEpoch, big.NewInt(0), big.NewInt(0))
-		if err == nil {
-			fmt.Printf("Transaction Hash: %s\n", txHash)
-			fmt.Printf("⏳ Waiting for transaction to be mined...\n")
-			if waitErr := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); waitErr == nil {
-				fmt.Printf("✅ All allocations settled in a single transaction!\n")
-			} else {
-				fmt.Printf("⚠️  Transaction reverted, falling back to batched settlement...\n")
-				err = waitErr // trigger fallback
-			}
-		}
-
-		if err != nil {
-			// Fallback: settle in batches of 50
-			const batchSize uint64 = 50
-
-			allocationIds, err := ddoClient.GetAllocationIdsForProvider(providerId)
-			if err != nil {
-				return fmt.Errorf("failed to get allocation IDs for provider: %v", err)
-			}
-
-			total := uint64(len(allocationIds))
-			fmt.Printf("💰 Settling in batches of %d (%d total allocations)...\n", batchSize, total)
-
-			txCount := 0
-			for startIndex := uint64(0); startIndex < total; startIndex += batchSize {
-				remaining := total - startIndex
-				currentBatch := batchSize
-				if remaining < batchSize {
-					currentBatch = remaining
-				}
-
-				fmt.Printf("   Batch %d-%d of %d...\n", startIndex+1, startIndex+currentBatch, total)
-
-				batchTxHash, err := ddoClient.SettleSpTotalPayment(
-					providerId, settleEpoch,
-					new(big.Int).SetUint64(startIndex),
-					new(big.Int).SetUint64(batchSize),
-				)
-				if err != nil {
-					return fmt.Errorf("failed to settle batch starting at index %d: %v", startIndex, err)
-				}
-
-				fmt.Printf("   Transaction Hash: %s\n", batchTxHash)
-				txCount++
-
-				if err := utils.WaitForTransaction(ddoClient.GetEthClient(), batchTxHash); err != nil {
-					return fmt.Errorf("batch transaction failed: %v", err)
-				}
-			}
+		fmt.Fprintf(w, "💰 Settling payments for all allocations of provider %d until epoch %d...\n", providerId, untilEpoch)
+	}
+	fmt.Fprintf(w, "⏳ Waiting for settlement transaction(s) to be mined...\n")
 
-			fmt.Printf("✅ All %d allocations settled in %d transaction(s)!\n", total, txCount)
-		}
+	result.Transactions, err = utils.SettleSPPayments(ddoClient, providerId, allocationId, untilEpoch, func(tx types.TxResult) {
+		fmt.Fprintf(w, "   Transaction Hash: %s (%s)\n", tx.TxHash, tx.Status)
+	})
+	if err != nil {
+		return err
 	}
-	fmt.Println()
+	fmt.Fprintf(w, "✅ Settled in %d transaction(s)!\n", len(result.Transactions))
+	fmt.Fprintln(w)
 
 	// Get SP account information from payments contract after settlement
-	fmt.Printf("💰 Checking SP account information after settlement...\n")
+	fmt.Fprintf(w, "💰 Checking SP account information after settlement...\n")
 	for i, tokenConfig := range spConfig.SupportedTokens {
 		if !tokenConfig.IsActive {
 			continue
@@ -350,22 +271,29 @@ func executeSettle(c *cli.Context) error {
 
 		account, err := paymentsClient.GetAccount(tokenConfig.Token, spConfig.PaymentAddress)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: failed to get account info for token %d (%s): %v\n", i+1, tokenConfig.Token.Hex(), err)
+			fmt.Fprintf(w, "⚠️  Warning: failed to get account info for token %d (%s): %v\n", i+1, tokenConfig.Token.Hex(), err)
 			continue
 		}
 
-		tokenName := tokenConfig.Token.Hex()
+		meta := utils.LookupTokenMetadata(cfg.RPCEndpoint, cfg.RPC, tokenConfig.Token)
+		tokenName := fmt.Sprintf("%s (%s)", meta.Symbol, tokenConfig.Token.Hex())
 		if tokenConfig.Token.Hex() == "0x0000000000000000000000000000000000000000" {
-			tokenName = "Native Token (ETH)"
+			tokenName = "Native Token (FIL)"
 		}
 
-		fmt.Printf("🔍 SP Account Info (After) - %s:\n", tokenName)
-		fmt.Printf("   Funds: %s\n", account.Funds.String())
-		fmt.Printf("   Lockup Current: %s\n", account.LockupCurrent.String())
-		fmt.Printf("   Lockup Rate: %s\n", account.LockupRate.String())
-		fmt.Printf("   Lockup Last Settled At: %s\n", account.LockupLastSettledAt.String())
-		fmt.Println()
+		result.Accounts = append(result.Accounts, types.AccountDetails{
+			Token:   tokenConfig.Token,
+			Address: spConfig.PaymentAddress,
+			Account: *account,
+		})
+
+		fmt.Fprintf(w, "🔍 SP Account Info (After) - %s:\n", tokenName)
+		fmt.Fprintf(w, "   Funds: %s\n", utils.FormatTokenAmount(account.Funds, meta))
+		fmt.Fprintf(w, "   Lockup Current: %s\n", utils.FormatTokenAmount(account.LockupCurrent, meta))
+		fmt.Fprintf(w, "   Lockup Rate: %s per epoch\n", utils.FormatTokenAmount(account.LockupRate, meta))
+		fmt.Fprintf(w, "   Lockup Last Settled At: %s\n", account.LockupLastSettledAt.String())
+		fmt.Fprintln(w)
 	}
 
-	return nil
+	return output.Render(c, result, nil)
 }
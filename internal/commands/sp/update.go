@@ -9,7 +9,9 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
 	"github.com/Eastore-project/ddo-client/pkg/utils"
 )
 
@@ -32,21 +34,9 @@ func UpdateConfigCommand() *cli.Command {
 		Aliases: []string{"cfg"},
 		Usage:   "Update storage provider basic configuration",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.Uint64Flag{
 				Name:     "actor-id",
 				Aliases:  []string{"id"},
@@ -84,7 +74,7 @@ func UpdateConfigCommand() *cli.Command {
 				Usage: "Show what would be updated without sending transaction",
 			},
 		},
-		Action: executeUpdateSPConfig,
+		Action: config.Action(executeUpdateSPConfig),
 	}
 }
 
@@ -94,21 +84,9 @@ func UpdateTokenCommand() *cli.Command {
 		Aliases: []string{"tok"},
 		Usage:   "Update existing token configuration",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.Uint64Flag{
 				Name:     "actor-id",
 				Aliases:  []string{"id"},
@@ -141,7 +119,7 @@ func UpdateTokenCommand() *cli.Command {
 				Usage: "Show what would be updated without sending transaction",
 			},
 		},
-		Action: executeUpdateSPToken,
+		Action: config.Action(executeUpdateSPToken),
 	}
 }
 
@@ -151,21 +129,9 @@ func AddTokenCommand() *cli.Command {
 		Aliases: []string{"add"},
 		Usage:   "Add new token configuration",
 		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "contract",
-				Aliases: []string{"c"},
-				Usage:   "Contract address (overrides DDO_CONTRACT_ADDRESS env var)",
-			},
-			&cli.StringFlag{
-				Name:    "rpc",
-				Aliases: []string{"r"},
-				Usage:   "RPC endpoint (overrides RPC_URL env var)",
-			},
-			&cli.StringFlag{
-				Name:    "private-key",
-				Aliases: []string{"pk"},
-				Usage:   "Private key (overrides PRIVATE_KEY env var)",
-			},
+			config.ContractFlag(),
+			config.RPCFlag(),
+			config.PrivateKeyFlag(),
 			&cli.Uint64Flag{
 				Name:     "actor-id",
 				Aliases:  []string{"id"},
@@ -189,24 +155,15 @@ func AddTokenCommand() *cli.Command {
 				Usage: "Show what would be added without sending transaction",
 			},
 		},
-		Action: executeAddSPToken,
+		Action: config.Action(executeAddSPToken),
 	}
 }
 
-func executeUpdateSPConfig(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
-	if pk := c.String("private-key"); pk != "" {
-		config.PrivateKey = pk
-	}
+func executeUpdateSPConfig(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration
-	if missing := config.GetMissingConfig(); len(missing) > 0 {
+	if missing := cfg.MissingConfig(); len(missing) > 0 {
 		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
@@ -218,7 +175,7 @@ func executeUpdateSPConfig(c *cli.Context) error {
 	}
 
 	// Create contract client to get current config
-	ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+	ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 	if err != nil {
 		return fmt.Errorf("failed to create DDO contract client: %v", err)
 	}
@@ -261,26 +218,26 @@ func executeUpdateSPConfig(c *cli.Context) error {
 		return fmt.Errorf("invalid term range: min=%d, max=%d", minTerm, maxTerm)
 	}
 
-	fmt.Printf("📋 Storage Provider Config Update:\n")
-	fmt.Printf("   Actor ID: %d\n", actorId)
-	fmt.Printf("   Payment Address: %s → %s\n", currentConfig.PaymentAddress.Hex(), paymentAddress)
-	fmt.Printf("   Min Piece Size: %s → %s\n",
+	fmt.Fprintf(w, "📋 Storage Provider Config Update:\n")
+	fmt.Fprintf(w, "   Actor ID: %d\n", actorId)
+	fmt.Fprintf(w, "   Payment Address: %s → %s\n", currentConfig.PaymentAddress.Hex(), paymentAddress)
+	fmt.Fprintf(w, "   Min Piece Size: %s → %s\n",
 		utils.FormatBytes(new(big.Int).SetUint64(currentConfig.MinPieceSize)),
 		utils.FormatBytes(new(big.Int).SetUint64(minPieceSize)))
-	fmt.Printf("   Max Piece Size: %s → %s\n",
+	fmt.Fprintf(w, "   Max Piece Size: %s → %s\n",
 		utils.FormatBytes(new(big.Int).SetUint64(currentConfig.MaxPieceSize)),
 		utils.FormatBytes(new(big.Int).SetUint64(maxPieceSize)))
-	fmt.Printf("   Min Term: %d → %d epochs\n", currentConfig.MinTermLength, minTerm)
-	fmt.Printf("   Max Term: %d → %d epochs\n", currentConfig.MaxTermLength, maxTerm)
-	fmt.Println()
+	fmt.Fprintf(w, "   Min Term: %d → %d epochs\n", currentConfig.MinTermLength, minTerm)
+	fmt.Fprintf(w, "   Max Term: %d → %d epochs\n", currentConfig.MaxTermLength, maxTerm)
+	fmt.Fprintln(w)
 
 	if c.Bool("dry-run") {
-		fmt.Printf("🎯 Dry Run - Configuration validated successfully!\n")
+		fmt.Fprintf(w, "🎯 Dry Run - Configuration validated successfully!\n")
 		return nil
 	}
 
 	// Execute the transaction
-	fmt.Printf("🚀 Updating storage provider configuration...\n")
+	fmt.Fprintf(w, "🚀 Updating storage provider configuration...\n")
 
 	txHash, err := ddoClient.UpdateSPConfig(
 		actorId,
@@ -294,35 +251,22 @@ func executeUpdateSPConfig(c *cli.Context) error {
 		return fmt.Errorf("failed to update SP config: %v", err)
 	}
 
-	fmt.Printf("✅ Update successful!\n")
-	fmt.Printf("Transaction Hash: %s\n", txHash)
+	fmt.Fprintf(w, "✅ Update successful!\n")
+	fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
 	// Wait for transaction to be mined using the existing client
-	fmt.Printf("⏳ Waiting for transaction to be mined...\n")
-	if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-		fmt.Printf("⚠️  Warning: transaction may not have been mined: %v\n", err)
-		return nil
-	}
-
-	fmt.Printf("✅ Update transaction mined successfully!\n")
-
-	return nil
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	result := output.WaitTx(ddoClient.TxManager(), txHash)
+	return output.RenderTx(c, result, func() {
+		fmt.Fprintf(w, "✅ Update transaction mined successfully!\n")
+	})
 }
 
-func executeUpdateSPToken(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
-	if pk := c.String("private-key"); pk != "" {
-		config.PrivateKey = pk
-	}
+func executeUpdateSPToken(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration
-	if missing := config.GetMissingConfig(); len(missing) > 0 {
+	if missing := cfg.MissingConfig(); len(missing) > 0 {
 		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
@@ -340,32 +284,37 @@ func executeUpdateSPToken(c *cli.Context) error {
 		isActive = false
 	}
 
+	meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, common.HexToAddress(tokenAddress))
+	if err != nil {
+		return fmt.Errorf("failed to get token metadata: %v", err)
+	}
+
 	// Convert USD price to bytes per epoch
-	pricePerBytePerEpoch, err := utils.ConvertUSDPerTBPerMonthToBytesPerEpoch(priceUSD)
+	pricePerBytePerEpoch, err := utils.ConvertUSDPerTBPerMonthToBytesPerEpoch(priceUSD, meta.Decimals)
 	if err != nil {
-		return fmt.Errorf("invalid USD price format: %v", err)
+		return fmt.Errorf("invalid price: %v", err)
 	}
 
-	fmt.Printf("📋 Storage Provider Token Update:\n")
-	fmt.Printf("   Actor ID: %d\n", actorId)
-	fmt.Printf("   Token: %s\n", tokenAddress)
-	fmt.Printf("   Price: %s\n", utils.FormatPriceBothFormats(pricePerBytePerEpoch))
-	fmt.Printf("   Active: %t\n", isActive)
-	fmt.Println()
+	fmt.Fprintf(w, "📋 Storage Provider Token Update:\n")
+	fmt.Fprintf(w, "   Actor ID: %d\n", actorId)
+	fmt.Fprintf(w, "   Token: %s (%s)\n", tokenAddress, meta.Symbol)
+	fmt.Fprintf(w, "   Price: %s\n", utils.FormatPriceBothFormats(pricePerBytePerEpoch, meta))
+	fmt.Fprintf(w, "   Active: %t\n", isActive)
+	fmt.Fprintln(w)
 
 	if c.Bool("dry-run") {
-		fmt.Printf("🎯 Dry Run - Token configuration validated successfully!\n")
+		fmt.Fprintf(w, "🎯 Dry Run - Token configuration validated successfully!\n")
 		return nil
 	}
 
 	// Create contract client
-	ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+	ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 	if err != nil {
 		return fmt.Errorf("failed to create DDO contract client: %v", err)
 	}
 
 	// Execute the transaction
-	fmt.Printf("🚀 Updating token configuration...\n")
+	fmt.Fprintf(w, "🚀 Updating token configuration...\n")
 
 	txHash, err := ddoClient.UpdateSPToken(
 		actorId,
@@ -377,35 +326,22 @@ func executeUpdateSPToken(c *cli.Context) error {
 		return fmt.Errorf("failed to update SP token: %v", err)
 	}
 
-	fmt.Printf("✅ Token update successful!\n")
-	fmt.Printf("Transaction Hash: %s\n", txHash)
+	fmt.Fprintf(w, "✅ Token update successful!\n")
+	fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
 	// Wait for transaction to be mined using the existing client
-	fmt.Printf("⏳ Waiting for transaction to be mined...\n")
-	if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-		fmt.Printf("⚠️  Warning: transaction may not have been mined: %v\n", err)
-		return nil
-	}
-
-	fmt.Printf("✅ Token update transaction mined successfully!\n")
-
-	return nil
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	result := output.WaitTx(ddoClient.TxManager(), txHash)
+	return output.RenderTx(c, result, func() {
+		fmt.Fprintf(w, "✅ Token update transaction mined successfully!\n")
+	})
 }
 
-func executeAddSPToken(c *cli.Context) error {
-	// Override global config with command line flags if provided
-	if contract := c.String("contract"); contract != "" {
-		config.ContractAddress = contract
-	}
-	if rpc := c.String("rpc"); rpc != "" {
-		config.RPCEndpoint = rpc
-	}
-	if pk := c.String("private-key"); pk != "" {
-		config.PrivateKey = pk
-	}
+func executeAddSPToken(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
 
 	// Validate required configuration
-	if missing := config.GetMissingConfig(); len(missing) > 0 {
+	if missing := cfg.MissingConfig(); len(missing) > 0 {
 		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
@@ -417,32 +353,37 @@ func executeAddSPToken(c *cli.Context) error {
 		return fmt.Errorf("invalid token address: %s", tokenAddress)
 	}
 
+	meta, err := token.GetTokenMetadata(cfg.RPCEndpoint, cfg.RPC, common.HexToAddress(tokenAddress))
+	if err != nil {
+		return fmt.Errorf("failed to get token metadata: %v", err)
+	}
+
 	// Convert USD price to bytes per epoch
-	pricePerBytePerEpoch, err := utils.ConvertUSDPerTBPerMonthToBytesPerEpoch(priceUSD)
+	pricePerBytePerEpoch, err := utils.ConvertUSDPerTBPerMonthToBytesPerEpoch(priceUSD, meta.Decimals)
 	if err != nil {
-		return fmt.Errorf("invalid USD price format: %v", err)
+		return fmt.Errorf("invalid price: %v", err)
 	}
 
-	fmt.Printf("📋 Storage Provider Token Addition:\n")
-	fmt.Printf("   Actor ID: %d\n", actorId)
-	fmt.Printf("   Token: %s\n", tokenAddress)
-	fmt.Printf("   Price: %s\n", utils.FormatPriceBothFormats(pricePerBytePerEpoch))
-	fmt.Printf("   Active: true\n")
-	fmt.Println()
+	fmt.Fprintf(w, "📋 Storage Provider Token Addition:\n")
+	fmt.Fprintf(w, "   Actor ID: %d\n", actorId)
+	fmt.Fprintf(w, "   Token: %s (%s)\n", tokenAddress, meta.Symbol)
+	fmt.Fprintf(w, "   Price: %s\n", utils.FormatPriceBothFormats(pricePerBytePerEpoch, meta))
+	fmt.Fprintf(w, "   Active: true\n")
+	fmt.Fprintln(w)
 
 	if c.Bool("dry-run") {
-		fmt.Printf("🎯 Dry Run - Token configuration validated successfully!\n")
+		fmt.Fprintf(w, "🎯 Dry Run - Token configuration validated successfully!\n")
 		return nil
 	}
 
 	// Create contract client
-	ddoClient, err := ddo.NewClientWithParams(config.RPCEndpoint, config.ContractAddress, config.PrivateKey)
+	ddoClient, err := ddo.NewClientWithParams(cfg.RPCEndpoint, cfg.ContractAddress, cfg.PrivateKey, cfg.RPC, cfg.Tx)
 	if err != nil {
 		return fmt.Errorf("failed to create DDO contract client: %v", err)
 	}
 
 	// Execute the transaction
-	fmt.Printf("🚀 Adding token configuration...\n")
+	fmt.Fprintf(w, "🚀 Adding token configuration...\n")
 
 	txHash, err := ddoClient.AddSPToken(
 		actorId,
@@ -453,17 +394,13 @@ func executeAddSPToken(c *cli.Context) error {
 		return fmt.Errorf("failed to add SP token: %v", err)
 	}
 
-	fmt.Printf("✅ Token addition successful!\n")
-	fmt.Printf("Transaction Hash: %s\n", txHash)
+	fmt.Fprintf(w, "✅ Token addition successful!\n")
+	fmt.Fprintf(w, "Transaction Hash: %s\n", txHash)
 
 	// Wait for transaction to be mined using the existing client
-	fmt.Printf("⏳ Waiting for transaction to be mined...\n")
-	if err := utils.WaitForTransaction(ddoClient.GetEthClient(), txHash); err != nil {
-		fmt.Printf("⚠️  Warning: transaction may not have been mined: %v\n", err)
-		return nil
-	}
-
-	fmt.Printf("✅ Token addition transaction mined successfully!\n")
-
-	return nil
+	fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+	result := output.WaitTx(ddoClient.TxManager(), txHash)
+	return output.RenderTx(c, result, func() {
+		fmt.Fprintf(w, "✅ Token addition transaction mined successfully!\n")
+	})
 }
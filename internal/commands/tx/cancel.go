@@ -0,0 +1,20 @@
+package tx
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func CancelCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "cancel",
+		Usage:     "Replace a pending transaction with an empty transfer to yourself so it never executes",
+		ArgsUsage: "<tx-hash>",
+		Flags:     replaceFlags,
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			return replaceTx(c, cfg, types.TxKindCancel)
+		}),
+	}
+}
@@ -0,0 +1,121 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func ListCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "list",
+		Aliases: []string{"ls"},
+		Usage:   "List transactions recorded in the local journal, newest first",
+		Flags: append(txFlags, []cli.Flag{
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"n"},
+				Usage:   "Number of transactions to show (0 for all)",
+				Value:   20,
+			},
+			&cli.BoolFlag{
+				Name:  "pending",
+				Usage: "Only show transactions that are still pending",
+			},
+		}...),
+		Action: config.Action(executeList),
+	}
+}
+
+func executeList(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	entries, err := journalEntries(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := rpcclient.Dial(cfg.RPCEndpoint, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RPC endpoint: %v", err)
+	}
+	defer client.Close()
+
+	decoder, err := newDecoder(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	limit := c.Int("limit")
+	accountNonces := make(map[common.Address]uint64)
+	list := make([]types.TxListEntry, 0)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if limit > 0 && len(list) >= limit {
+			break
+		}
+		entry := entries[i]
+
+		nonce, ok := accountNonces[entry.From]
+		if !ok {
+			if nonce, err = client.NonceAt(ctx, entry.From, nil); err != nil {
+				return fmt.Errorf("failed to get account nonce: %v", err)
+			}
+			accountNonces[entry.From] = nonce
+		}
+
+		status := journalStatus(ctx, client, entry, nonce)
+		if c.Bool("pending") && status != types.TxStatusPending {
+			continue
+		}
+
+		item := types.TxListEntry{TxJournalEntry: entry, Status: status}
+		if call := decoder.DecodeCall(entry.To, entry.Data); call != nil {
+			item.Method = call.Contract + "." + call.Method
+		}
+		list = append(list, item)
+	}
+
+	return output.Render(c, list, func() { printTxList(w, list) })
+}
+
+func printTxList(w io.Writer, list []types.TxListEntry) {
+	if len(list) == 0 {
+		fmt.Fprintf(w, "📭 No transactions found\n")
+		return
+	}
+
+	fmt.Fprintf(w, "📋 Transactions (%d):\n", len(list))
+	for _, e := range list {
+		method := e.Method
+		if method == "" {
+			method = "(unknown)"
+		}
+		fmt.Fprintf(w, "\n   %s %s\n", statusLabel(e.Status), e.Hash.Hex())
+		fmt.Fprintf(w, "      Method: %s\n", method)
+		fmt.Fprintf(w, "      Nonce: %d  From: %s\n", e.Nonce, e.From.Hex())
+		if e.Kind != types.TxKindSent && e.Replaces != nil {
+			fmt.Fprintf(w, "      %s of: %s\n", kindLabel(e.Kind), e.Replaces.Hex())
+		}
+		fmt.Fprintf(w, "      Sent: %s\n", e.SentAt.Local().Format("2006-01-02 15:04:05"))
+	}
+}
+
+func kindLabel(kind string) string {
+	switch kind {
+	case types.TxKindSpeedUp:
+		return "Speedup"
+	case types.TxKindCancel:
+		return "Cancellation"
+	default:
+		return "Replacement"
+	}
+}
@@ -0,0 +1,27 @@
+package tx
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+)
+
+func TxCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "tx",
+		Aliases: []string{"transaction"},
+		Usage:   "Inspect and manage transactions sent by this client",
+		Subcommands: []*cli.Command{
+			StatusCommand(),
+			ListCommand(),
+			SpeedUpCommand(),
+			CancelCommand(),
+		},
+	}
+}
+
+var txFlags = []cli.Flag{
+	config.RPCFlag(),
+	config.ContractFlag(),
+	config.PaymentsContractFlag(),
+}
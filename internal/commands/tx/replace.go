@@ -0,0 +1,152 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+var replaceFlags = []cli.Flag{
+	config.RPCFlag(),
+	config.PrivateKeyFlag(),
+	&cli.BoolFlag{
+		Name:  "no-wait",
+		Usage: "Return once the replacement is sent instead of waiting for it to be mined",
+	},
+}
+
+// replaceTx sends a speedup or cancellation for the pending transaction given as the
+// command's argument and waits until it, or the original, is mined
+func replaceTx(c *cli.Context, cfg config.Config, action string) error {
+	w := output.Progress(c)
+
+	hash, err := parseTxHash(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := rpcclient.Dial(cfg.RPCEndpoint, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RPC endpoint: %v", err)
+	}
+	defer client.Close()
+
+	auth, err := newTransactor(client, cfg)
+	if err != nil {
+		return err
+	}
+
+	original, err := pendingTx(c, client, auth, hash)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "📝 Sending %s for transaction %s (nonce %d)...\n", action, hash.Hex(), original.Nonce())
+	mgr := txmgr.New(client, cfg.Tx)
+	var replacement *ethtypes.Transaction
+	if action == types.TxKindCancel {
+		replacement, err = mgr.Cancel(context.Background(), auth, original)
+	} else {
+		replacement, err = mgr.SpeedUp(context.Background(), auth, original)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send %s: %v", action, err)
+	}
+	fmt.Fprintf(w, "✅ Replacement sent: %s\n", replacement.Hash().Hex())
+	fmt.Fprintf(w, "   Max Fee: %s attoFIL/gas (was %s)\n", replacement.GasFeeCap(), original.GasFeeCap())
+	fmt.Fprintf(w, "   Tip: %s attoFIL/gas (was %s)\n", replacement.GasTipCap(), original.GasTipCap())
+
+	result := types.TxReplacement{
+		Action:    action,
+		Original:  hash.Hex(),
+		Nonce:     replacement.Nonce(),
+		GasTipCap: replacement.GasTipCap(),
+		GasFeeCap: replacement.GasFeeCap(),
+		Tx:        types.TxResult{TxHash: replacement.Hash().Hex(), Replaces: hash.Hex(), Status: types.TxStatusPending},
+	}
+	if !c.Bool("no-wait") {
+		// Waiting for the original returns whichever of the two transactions is mined
+		fmt.Fprintf(w, "⏳ Waiting for transaction to be mined...\n")
+		result.Tx = output.WaitTx(mgr, hash.Hex())
+		if action == types.TxKindCancel && result.Tx.Status == types.TxStatusCanceled {
+			// The cancellation being mined is what was asked for
+			result.Tx.Status, result.Tx.Error = types.TxStatusSuccess, ""
+		}
+	}
+
+	if err := output.Render(c, result, func() {
+		if c.Bool("no-wait") {
+			return
+		}
+		output.PrintTxOutcome(w, result.Tx, func() {
+			if result.Tx.Replaces == "" {
+				fmt.Fprintf(w, "⚠️  The original transaction was mined before its %s: %s\n", action, result.Tx.TxHash)
+				return
+			}
+			fmt.Fprintf(w, "✅ %s mined in block %d: %s\n", kindLabel(action), result.Tx.BlockNumber, result.Tx.TxHash)
+		})
+	}); err != nil {
+		return err
+	}
+	return output.TxError(result.Tx)
+}
+
+// pendingTx returns the pending transaction hash sent by auth. A transaction the node
+// dropped from its mempool is rebuilt from the journal.
+func pendingTx(c *cli.Context, client *ethclient.Client, auth *bind.TransactOpts, hash common.Hash) (*ethtypes.Transaction, error) {
+	ctx := context.Background()
+	tx, isPending, err := client.TransactionByHash(ctx, hash)
+	switch {
+	case err == nil && !isPending:
+		return nil, fmt.Errorf("transaction %s is already mined", hash.Hex())
+	case err == nil:
+		return tx, nil
+	case !errors.Is(err, ethereum.NotFound):
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+
+	entry := findJournalEntry(c, hash)
+	if entry == nil {
+		return nil, fmt.Errorf("transaction %s not found", hash.Hex())
+	}
+	accountNonce, err := client.NonceAt(ctx, entry.From, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account nonce: %v", err)
+	}
+	if entry.Nonce < accountNonce {
+		return nil, fmt.Errorf("nonce %d of transaction %s was already used by another transaction", entry.Nonce, hash.Hex())
+	}
+
+	if auth.From != entry.From {
+		return nil, fmt.Errorf("transaction %s was sent by %s, not by the configured key %s", hash.Hex(), entry.From.Hex(), auth.From.Hex())
+	}
+
+	// Re-sign the journaled call so the replacement can check it belongs to auth
+	tx, err = auth.Signer(auth.From, ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		ChainID:   entry.ChainID,
+		Nonce:     entry.Nonce,
+		GasTipCap: entry.GasTipCap,
+		GasFeeCap: entry.GasFeeCap,
+		Gas:       entry.Gas,
+		To:        entry.To,
+		Value:     entry.Value,
+		Data:      entry.Data,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild journaled transaction: %v", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,21 @@
+package tx
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func SpeedUpCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "speedup",
+		Aliases:   []string{"speed-up"},
+		Usage:     "Resend a pending transaction with higher fees (see --max-fee and --priority-fee)",
+		ArgsUsage: "<tx-hash>",
+		Flags:     replaceFlags,
+		Action: config.Action(func(c *cli.Context, cfg config.Config) error {
+			return replaceTx(c, cfg, types.TxKindSpeedUp)
+		}),
+	}
+}
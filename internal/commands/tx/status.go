@@ -0,0 +1,206 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/internal/output"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+func StatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "status",
+		Usage:     "Show a transaction's status, decoded call and DDO/Payments events",
+		ArgsUsage: "<tx-hash>",
+		Flags:     txFlags,
+		Action:    config.Action(executeStatus),
+	}
+}
+
+func executeStatus(c *cli.Context, cfg config.Config) error {
+	w := output.Progress(c)
+
+	hash, err := parseTxHash(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := rpcclient.Dial(cfg.RPCEndpoint, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RPC endpoint: %v", err)
+	}
+	defer client.Close()
+
+	decoder, err := newDecoder(cfg)
+	if err != nil {
+		return err
+	}
+
+	details, err := getTxDetails(c, client, decoder, hash)
+	if err != nil {
+		return err
+	}
+
+	return output.Render(c, details, func() { printTxDetails(w, details) })
+}
+
+// getTxDetails looks the transaction up on chain, falling back to the journal for one
+// the node no longer knows
+func getTxDetails(c *cli.Context, client *ethclient.Client, decoder *utils.TxDecoder, hash common.Hash) (*types.TxDetails, error) {
+	ctx := context.Background()
+
+	tx, isPending, err := client.TransactionByHash(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("failed to get transaction: %v", err)
+		}
+		entry := findJournalEntry(c, hash)
+		if entry == nil {
+			return nil, fmt.Errorf("transaction %s not found", hash.Hex())
+		}
+		return journalTxDetails(ctx, c, client, decoder, *entry)
+	}
+
+	from, err := txSender(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender: %v", err)
+	}
+	details := &types.TxDetails{
+		Hash:      hash,
+		Status:    types.TxStatusPending,
+		From:      from,
+		To:        tx.To(),
+		Nonce:     tx.Nonce(),
+		Value:     tx.Value(),
+		Gas:       tx.Gas(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: tx.GasFeeCap(),
+		Call:      decoder.DecodeCall(tx.To(), tx.Data()),
+		Events:    []types.DecodedEvent{},
+	}
+	if isPending {
+		return details, nil
+	}
+
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction receipt: %v", err)
+	}
+	details.Status = types.TxStatusSuccess
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		details.Status = types.TxStatusReverted
+	}
+	details.BlockNumber = receipt.BlockNumber.Uint64()
+	details.GasUsed = receipt.GasUsed
+	details.Events = decoder.DecodeLogs(receipt.Logs)
+	return details, nil
+}
+
+// journalTxDetails describes a journaled transaction the node does not know, usually
+// one dropped for a replacement
+func journalTxDetails(ctx context.Context, c *cli.Context, client *ethclient.Client, decoder *utils.TxDecoder, entry types.TxJournalEntry) (*types.TxDetails, error) {
+	accountNonce, err := client.NonceAt(ctx, entry.From, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account nonce: %v", err)
+	}
+
+	details := &types.TxDetails{
+		Hash:      entry.Hash,
+		Status:    journalStatus(ctx, client, entry, accountNonce),
+		From:      entry.From,
+		To:        entry.To,
+		Nonce:     entry.Nonce,
+		Value:     entry.Value,
+		Gas:       entry.Gas,
+		GasTipCap: entry.GasTipCap,
+		GasFeeCap: entry.GasFeeCap,
+		Call:      decoder.DecodeCall(entry.To, entry.Data),
+		Events:    []types.DecodedEvent{},
+	}
+
+	if entries, err := journalEntries(c); err == nil {
+		for _, e := range entries {
+			if e.Replaces != nil && *e.Replaces == entry.Hash {
+				replacedBy := e.Hash
+				details.ReplacedBy = &replacedBy
+			}
+		}
+	}
+	return details, nil
+}
+
+func printTxDetails(w io.Writer, d *types.TxDetails) {
+	fmt.Fprintf(w, "🔍 Transaction %s\n", d.Hash.Hex())
+	fmt.Fprintf(w, "   Status: %s\n", statusLabel(d.Status))
+	if d.ReplacedBy != nil {
+		fmt.Fprintf(w, "   Replaced By: %s\n", d.ReplacedBy.Hex())
+	}
+	fmt.Fprintf(w, "   From: %s\n", d.From.Hex())
+	if d.To != nil {
+		fmt.Fprintf(w, "   To: %s\n", d.To.Hex())
+	} else {
+		fmt.Fprintf(w, "   To: (contract creation)\n")
+	}
+	fmt.Fprintf(w, "   Nonce: %d\n", d.Nonce)
+	if d.Value != nil && d.Value.Sign() > 0 {
+		fmt.Fprintf(w, "   Value: %s\n", utils.FormatTokenAmount(d.Value, &token.NativeTokenMetadata))
+	}
+	fmt.Fprintf(w, "   Gas Limit: %d\n", d.Gas)
+	fmt.Fprintf(w, "   Max Fee: %s attoFIL/gas (tip %s)\n", d.GasFeeCap, d.GasTipCap)
+	if d.BlockNumber > 0 {
+		fmt.Fprintf(w, "   Block: %d\n", d.BlockNumber)
+		fmt.Fprintf(w, "   Gas Used: %d\n", d.GasUsed)
+	}
+
+	if d.Call != nil {
+		fmt.Fprintf(w, "\n📞 Call: %s.%s\n", d.Call.Contract, d.Call.Method)
+		printArgs(w, d.Call.Args, "   ")
+	}
+
+	if len(d.Events) > 0 {
+		fmt.Fprintf(w, "\n📜 Events (%d):\n", len(d.Events))
+		for i, e := range d.Events {
+			fmt.Fprintf(w, "   %d. %s.%s (%s)\n", i+1, e.Contract, e.Name, e.Address.Hex())
+			printArgs(w, e.Args, "      ")
+		}
+	}
+}
+
+// printArgs prints decoded arguments sorted by name
+func printArgs(w io.Writer, args map[string]interface{}, indent string) {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s%s: %v\n", indent, name, args[name])
+	}
+}
+
+func statusLabel(status string) string {
+	switch status {
+	case types.TxStatusSuccess:
+		return "✅ success"
+	case types.TxStatusReverted:
+		return "❌ reverted"
+	case types.TxStatusDropped:
+		return "🗑️  dropped"
+	default:
+		return "⏳ pending"
+	}
+}
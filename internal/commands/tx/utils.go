@@ -0,0 +1,108 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/internal/config"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// parseTxHash reads the transaction hash given as the command's first argument
+func parseTxHash(c *cli.Context) (common.Hash, error) {
+	if c.NArg() != 1 {
+		return common.Hash{}, fmt.Errorf("expected exactly one transaction hash")
+	}
+	arg := c.Args().First()
+	if len(strings.TrimPrefix(arg, "0x")) != 64 {
+		return common.Hash{}, fmt.Errorf("invalid transaction hash %q", arg)
+	}
+	return common.HexToHash(arg), nil
+}
+
+// journalEntries returns the transactions recorded in the journal set by --tx-journal
+func journalEntries(c *cli.Context) ([]types.TxJournalEntry, error) {
+	path := config.TxJournalPath(c)
+	if path == "" {
+		return nil, fmt.Errorf("the transaction journal is disabled (--tx-journal off)")
+	}
+	entries, err := txmgr.NewFileJournal(path).Entries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction journal: %v", err)
+	}
+	return entries, nil
+}
+
+// findJournalEntry returns the journal entry of hash, or nil if it was not recorded
+func findJournalEntry(c *cli.Context, hash common.Hash) *types.TxJournalEntry {
+	entries, err := journalEntries(c)
+	if err != nil {
+		return nil
+	}
+	for i := range entries {
+		if entries[i].Hash == hash {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+func newDecoder(cfg config.Config) (*utils.TxDecoder, error) {
+	decoder, err := utils.NewTxDecoder(common.HexToAddress(cfg.ContractAddress), common.HexToAddress(cfg.PaymentsContractAddress))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %v", err)
+	}
+	return decoder, nil
+}
+
+// newTransactor returns a transactor for the configured private key on the client's chain
+func newTransactor(client *ethclient.Client, cfg config.Config) (*bind.TransactOpts, error) {
+	if cfg.PrivateKey == "" {
+		return nil, fmt.Errorf("private key required (use --private-key flag or PRIVATE_KEY env var)")
+	}
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transactor: %v", err)
+	}
+	return auth, nil
+}
+
+// txSender recovers the address that signed tx
+func txSender(tx *ethtypes.Transaction) (common.Address, error) {
+	return ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+}
+
+// journalStatus returns the status of a journaled transaction: its receipt's status,
+// dropped once the account used its nonce for another transaction, and pending otherwise.
+// accountNonce is the sender's nonce in the latest block.
+func journalStatus(ctx context.Context, client *ethclient.Client, entry types.TxJournalEntry, accountNonce uint64) string {
+	receipt, err := client.TransactionReceipt(ctx, entry.Hash)
+	if err == nil {
+		if receipt.Status == ethtypes.ReceiptStatusSuccessful {
+			return types.TxStatusSuccess
+		}
+		return types.TxStatusReverted
+	}
+	if entry.Nonce < accountNonce {
+		return types.TxStatusDropped
+	}
+	return types.TxStatusPending
+}
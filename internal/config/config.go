@@ -1,42 +1,125 @@
 package config
 
-import "os"
+import (
+	"fmt"
+	"strings"
 
-// Global configuration variables
-var (
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
+)
+
+// DefaultRPCEndpoint is used when neither a profile nor RPC_URL sets an endpoint
+const DefaultRPCEndpoint = "http://localhost:8545"
+
+// Config is the resolved configuration of one command run. Commands receive it by
+// value and never modify it; it is built from, highest first, command flags,
+// environment variables, the selected profile and on-chain discovery.
+type Config struct {
+	Profile                 string
+	ChainID                 uint64 // 0 skips the chain ID check
 	RPCEndpoint             string
 	ContractAddress         string
 	PaymentsContractAddress string
 	PrivateKey              string
-)
+	PaymentToken            string // default payment token
+	CurioAPI                string
+	CurioUpload             bool
+	RPC                     rpcclient.Config // RPC connection policy, set from the global flags
+	Tx                      txmgr.Config     // transaction policy, set from the global flags
+}
+
+// Load builds the base configuration from the named profile (or the config file's
+// default profile) and the environment. An empty configPath reads
+// ~/.ddo-client/config.toml, which may be missing. getenv is usually os.Getenv.
+func Load(configPath, profileName string, getenv func(string) string) (Config, error) {
+	var cfg Config
 
-// LoadFromEnv loads configuration from environment variables with defaults
-func LoadFromEnv() {
-	RPCEndpoint = getEnvWithDefault("RPC_URL", "http://localhost:8545")
-	ContractAddress = getEnvWithDefault("DDO_CONTRACT_ADDRESS", "")
-	PaymentsContractAddress = getEnvWithDefault("PAYMENTS_CONTRACT_ADDRESS", "")
-	PrivateKey = getEnvWithDefault("PRIVATE_KEY", "")
+	file, err := ReadConfigFile(configPath)
+	if err != nil {
+		return cfg, err
+	}
+
+	if profileName == "" {
+		profileName = file.DefaultProfile
+	}
+	if profileName != "" {
+		profile, err := file.Profile(profileName)
+		if err != nil {
+			return cfg, err
+		}
+		cfg, err = profile.Config(profileName, getenv)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	return cfg.WithEnv(getenv), nil
 }
 
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+// WithEnv returns cfg with the values of the set environment variables
+func (cfg Config) WithEnv(getenv func(string) string) Config {
+	cfg.RPCEndpoint = envOr(getenv, "RPC_URL", cfg.RPCEndpoint)
+	if cfg.RPCEndpoint == "" {
+		cfg.RPCEndpoint = DefaultRPCEndpoint
+	}
+	cfg.ContractAddress = envOr(getenv, "DDO_CONTRACT_ADDRESS", cfg.ContractAddress)
+	cfg.PaymentsContractAddress = envOr(getenv, "PAYMENTS_CONTRACT_ADDRESS", cfg.PaymentsContractAddress)
+	cfg.PrivateKey = envOr(getenv, "PRIVATE_KEY", cfg.PrivateKey)
+	return cfg
+}
+
+func envOr(getenv func(string) string, key, defaultValue string) string {
+	if value := getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
-// Validation helpers
-func IsConfigured() bool {
-	return ContractAddress != "" && PrivateKey != ""
+// IsConfigured reports whether transactions can be sent
+func (cfg Config) IsConfigured() bool {
+	return cfg.ContractAddress != "" && cfg.PrivateKey != ""
 }
 
-func GetMissingConfig() []string {
+// MissingConfig lists what transaction commands need but is not configured
+func (cfg Config) MissingConfig() []string {
 	var missing []string
-	if ContractAddress == "" {
+	if cfg.ContractAddress == "" {
 		missing = append(missing, "DDO_CONTRACT_ADDRESS or --contract flag")
 	}
-	if PrivateKey == "" {
+	if cfg.PrivateKey == "" {
 		missing = append(missing, "PRIVATE_KEY or --private-key flag")
 	}
 	return missing
 }
+
+// RequireContract checks the configuration read-only DDO commands need
+func (cfg Config) RequireContract() error {
+	if cfg.ContractAddress == "" {
+		return fmt.Errorf("missing DDO contract address (use --contract flag or DDO_CONTRACT_ADDRESS env var)")
+	}
+	if cfg.RPCEndpoint == "" {
+		return fmt.Errorf("missing RPC endpoint (use --rpc flag or RPC_URL env var)")
+	}
+	return nil
+}
+
+// RequirePayments checks that a payments contract address was configured or discovered
+func (cfg Config) RequirePayments() error {
+	if cfg.PaymentsContractAddress == "" {
+		return fmt.Errorf("payments contract address required (use --payments-contract flag or PAYMENTS_CONTRACT_ADDRESS env var)")
+	}
+	return nil
+}
+
+// TokenOrDefault returns the token address given by a flag, or the profile's default
+// payment token when the flag is empty
+func (cfg Config) TokenOrDefault(flagName, flagValue string) (string, error) {
+	token := strings.TrimSpace(flagValue)
+	if token == "" {
+		token = cfg.PaymentToken
+	}
+	if token == "" {
+		return "", fmt.Errorf("missing token address (use --%s flag or set payment_token in the profile)", flagName)
+	}
+	return token, nil
+}
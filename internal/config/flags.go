@@ -0,0 +1,48 @@
+package config
+
+import "github.com/urfave/cli/v2"
+
+// Names of the connection flags shared by all commands
+const (
+	contractFlag         = "contract"
+	paymentsContractFlag = "payments-contract"
+	rpcFlag              = "rpc"
+	privateKeyFlag       = "private-key"
+)
+
+// ContractFlag is the --contract flag overriding the DDO contract address
+func ContractFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    contractFlag,
+		Aliases: []string{"c"},
+		Usage:   "DDO contract address (overrides DDO_CONTRACT_ADDRESS env var)",
+	}
+}
+
+// PaymentsContractFlag is the --payments-contract flag. Commands that declare it get
+// the address from the DDO contract when none is configured.
+func PaymentsContractFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    paymentsContractFlag,
+		Aliases: []string{"pc"},
+		Usage:   "Payments contract address (overrides PAYMENTS_CONTRACT_ADDRESS env var; read from the DDO contract if unset)",
+	}
+}
+
+// RPCFlag is the --rpc flag overriding the RPC endpoint
+func RPCFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    rpcFlag,
+		Aliases: []string{"r"},
+		Usage:   "RPC endpoint, or comma-separated endpoints for failover (overrides RPC_URL env var)",
+	}
+}
+
+// PrivateKeyFlag is the --private-key flag overriding the signing key
+func PrivateKeyFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    privateKeyFlag,
+		Aliases: []string{"pk"},
+		Usage:   "Private key (overrides PRIVATE_KEY env var)",
+	}
+}
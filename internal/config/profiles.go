@@ -0,0 +1,201 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Profile holds the settings of one network. Empty fields in a config file profile
+// keep the value of the built-in preset with the same name.
+type Profile struct {
+	ChainID                 uint64 `toml:"chain_id"`
+	RPCURL                  string `toml:"rpc_url"`
+	ContractAddress         string `toml:"contract_address"`
+	PaymentsContractAddress string `toml:"payments_contract_address"`
+	PaymentToken            string `toml:"payment_token"`
+	CurioAPI                string `toml:"curio_api"`
+	CurioUpload             bool   `toml:"curio_upload"`
+	// Signer references; the key itself is never stored in the config file
+	PrivateKeyEnv  string `toml:"private_key_env"`  // environment variable holding the key
+	PrivateKeyFile string `toml:"private_key_file"` // file holding the hex key
+}
+
+// File is the layout of the config file
+type File struct {
+	DefaultProfile string             `toml:"default_profile"`
+	Profiles       map[string]Profile `toml:"profiles"`
+}
+
+// Presets are the published DDO deployments
+var Presets = map[string]Profile{
+	"mainnet": {
+		ChainID:                 314,
+		RPCURL:                  "https://api.node.glif.io/rpc/v1",
+		ContractAddress:         "0x94A53ac3ca6743990ebB659F3Fe84198420d088c",
+		PaymentsContractAddress: "0x23b1e018F08BB982348b15a86ee926eEBf7F4DAa",
+		PaymentToken:            "0x80B98d3aa09ffff255c3ba4A241111Ff1262F045", // USDFC
+	},
+	"calibration": {
+		ChainID:                 314159,
+		RPCURL:                  "https://api.calibration.node.glif.io/rpc/v1",
+		ContractAddress:         "0x889fD50196BE300D06dc4b8F0F17fdB0af587095",
+		PaymentsContractAddress: "0x09a0fDc2723fAd1A7b8e3e00eE5DF73841df55a0",
+		PaymentToken:            "0xb3042734b608a1B16e9e86B374A3f3e389B4cDf0", // USDFC
+	},
+	// Devnet chain IDs and contracts vary; set them in the config file
+	"devnet": {
+		RPCURL: DefaultRPCEndpoint,
+	},
+}
+
+// DefaultConfigPath returns ~/.ddo-client/config.toml
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ddo-client", "config.toml")
+}
+
+// ReadConfigFile parses the config file at path. An empty path reads the default
+// location, which may be missing.
+func ReadConfigFile(path string) (*File, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultConfigPath()
+	}
+
+	file := &File{}
+	if path == "" {
+		return file, nil
+	}
+	if _, err := toml.DecodeFile(path, file); err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return file, nil
+		}
+		return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+	return file, nil
+}
+
+// Profile returns the named profile, merging the file's settings over a preset
+func (f *File) Profile(name string) (Profile, error) {
+	preset, isPreset := Presets[name]
+	custom, isCustom := f.Profiles[name]
+	if !isPreset && !isCustom {
+		return Profile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(f.ProfileNames(), ", "))
+	}
+	if !isCustom {
+		return preset, nil
+	}
+
+	merged := preset
+	if custom.ChainID != 0 {
+		merged.ChainID = custom.ChainID
+	}
+	if custom.RPCURL != "" {
+		merged.RPCURL = custom.RPCURL
+	}
+	if custom.ContractAddress != "" {
+		merged.ContractAddress = custom.ContractAddress
+	}
+	if custom.PaymentsContractAddress != "" {
+		merged.PaymentsContractAddress = custom.PaymentsContractAddress
+	}
+	if custom.PaymentToken != "" {
+		merged.PaymentToken = custom.PaymentToken
+	}
+	if custom.CurioAPI != "" {
+		merged.CurioAPI = custom.CurioAPI
+	}
+	merged.CurioUpload = merged.CurioUpload || custom.CurioUpload
+	if custom.PrivateKeyEnv != "" {
+		merged.PrivateKeyEnv = custom.PrivateKeyEnv
+	}
+	if custom.PrivateKeyFile != "" {
+		merged.PrivateKeyFile = custom.PrivateKeyFile
+	}
+	return merged, nil
+}
+
+// ProfileNames lists the presets and the file's profiles, sorted
+func (f *File) ProfileNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for name := range Presets {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range f.Profiles {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Config returns the configuration a profile sets, resolving its signer reference
+func (p Profile) Config(name string, getenv func(string) string) (Config, error) {
+	key, err := p.ResolvePrivateKey(getenv)
+	if err != nil {
+		return Config{}, fmt.Errorf("profile %q: %v", name, err)
+	}
+	return Config{
+		Profile:                 name,
+		ChainID:                 p.ChainID,
+		RPCEndpoint:             p.RPCURL,
+		ContractAddress:         p.ContractAddress,
+		PaymentsContractAddress: p.PaymentsContractAddress,
+		PrivateKey:              key,
+		PaymentToken:            p.PaymentToken,
+		CurioAPI:                p.CurioAPI,
+		CurioUpload:             p.CurioUpload,
+	}, nil
+}
+
+// ResolvePrivateKey reads the key a profile's signer reference points to. It returns
+// an empty key when the profile has no signer.
+func (p Profile) ResolvePrivateKey(getenv func(string) string) (string, error) {
+	if p.PrivateKeyEnv != "" {
+		if key := getenv(p.PrivateKeyEnv); key != "" {
+			return key, nil
+		}
+	}
+	if p.PrivateKeyFile == "" {
+		return "", nil
+	}
+
+	path := p.PrivateKeyFile
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand %s: %v", path, err)
+		}
+		path = filepath.Join(home, rest)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read private key file: %v", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// NetworkName returns the name of a known Filecoin chain ID
+func NetworkName(chainID uint64) string {
+	switch chainID {
+	case 314:
+		return "mainnet"
+	case 314159:
+		return "calibration"
+	default:
+		return fmt.Sprintf("chain %d", chainID)
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	fileContract = "0x00000000000000000000000000000000000000f1"
+	envContract  = "0x00000000000000000000000000000000000000e1"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envFunc(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestLoad(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte("0xfilekey\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	file := writeConfigFile(t, `
+default_profile = "local"
+
+[profiles.local]
+chain_id = 31415926
+rpc_url = "http://127.0.0.1:1234/rpc/v1"
+contract_address = "`+fileContract+`"
+private_key_env = "LOCAL_KEY"
+
+[profiles.calibration]
+rpc_url = "http://calibration.example/rpc"
+curio_upload = true
+
+[profiles.keyfile]
+private_key_file = "`+keyFile+`"
+`)
+
+	tests := []struct {
+		name    string
+		profile string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "default profile from the file",
+			env:  map[string]string{"LOCAL_KEY": "0xenvkey"},
+			want: Config{Profile: "local", ChainID: 31415926, RPCEndpoint: "http://127.0.0.1:1234/rpc/v1", ContractAddress: fileContract, PrivateKey: "0xenvkey"},
+		},
+		{
+			name: "environment over profile",
+			env:  map[string]string{"RPC_URL": "http://env/rpc", "DDO_CONTRACT_ADDRESS": envContract, "PRIVATE_KEY": "0xkey"},
+			want: Config{Profile: "local", ChainID: 31415926, RPCEndpoint: "http://env/rpc", ContractAddress: envContract, PrivateKey: "0xkey"},
+		},
+		{
+			name:    "file settings merged over a preset",
+			profile: "calibration",
+			want: Config{
+				Profile:                 "calibration",
+				ChainID:                 314159,
+				RPCEndpoint:             "http://calibration.example/rpc",
+				ContractAddress:         Presets["calibration"].ContractAddress,
+				PaymentsContractAddress: Presets["calibration"].PaymentsContractAddress,
+				PaymentToken:            Presets["calibration"].PaymentToken,
+				CurioUpload:             true,
+			},
+		},
+		{
+			name:    "preset absent from the file",
+			profile: "mainnet",
+			want: Config{
+				Profile:                 "mainnet",
+				ChainID:                 314,
+				RPCEndpoint:             Presets["mainnet"].RPCURL,
+				ContractAddress:         Presets["mainnet"].ContractAddress,
+				PaymentsContractAddress: Presets["mainnet"].PaymentsContractAddress,
+				PaymentToken:            Presets["mainnet"].PaymentToken,
+			},
+		},
+		{
+			name:    "private key file",
+			profile: "keyfile",
+			want:    Config{Profile: "keyfile", RPCEndpoint: DefaultRPCEndpoint, PrivateKey: "0xfilekey"},
+		},
+		{
+			name:    "unknown profile",
+			profile: "nope",
+			wantErr: `unknown profile "nope"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(file, tt.profile, envFunc(tt.env))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Load = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadWithoutProfile(t *testing.T) {
+	got, err := Load(writeConfigFile(t, ""), "", envFunc(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (Config{RPCEndpoint: DefaultRPCEndpoint}) {
+		t.Errorf("Load = %+v, want only the default RPC endpoint", got)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.toml"), "", envFunc(nil)); err == nil {
+		t.Error("expected an error for a missing explicit config file")
+	}
+}
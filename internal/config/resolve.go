@@ -0,0 +1,149 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+)
+
+// metadataKey holds the base Config in cli.App.Metadata
+const metadataKey = "ddo-client/config"
+
+// SetBase stores the base configuration commands resolve their flags against
+func SetBase(app *cli.App, cfg Config) {
+	if app.Metadata == nil {
+		app.Metadata = make(map[string]interface{})
+	}
+	app.Metadata[metadataKey] = cfg
+}
+
+// Base returns the configuration stored with SetBase, or the environment-only
+// defaults when none was stored
+func Base(c *cli.Context) Config {
+	if cfg, ok := c.App.Metadata[metadataKey].(Config); ok {
+		return cfg
+	}
+	return Config{}.WithEnv(func(string) string { return "" })
+}
+
+// WithFlags returns cfg with the values of the shared connection flags set on the command
+func (cfg Config) WithFlags(c *cli.Context) Config {
+	if v := c.String(contractFlag); v != "" {
+		cfg.ContractAddress = v
+	}
+	if v := c.String(paymentsContractFlag); v != "" {
+		cfg.PaymentsContractAddress = v
+	}
+	if v := c.String(rpcFlag); v != "" {
+		cfg.RPCEndpoint = v
+	}
+	if v := c.String(privateKeyFlag); v != "" {
+		cfg.PrivateKey = v
+	}
+	return cfg
+}
+
+// Resolve returns the configuration of the running command, with the RPC and
+// transaction policies of the global flags. When the command takes a payments contract that is not
+// configured, it is looked up from the DDO contract.
+func Resolve(c *cli.Context) (Config, error) {
+	cfg := Base(c).WithFlags(c)
+
+	rpcCfg, err := RPCClientConfig(c)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.RPC = rpcCfg
+	txCfg, err := TxManagerConfig(c)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Tx = txCfg
+
+	if err := cfg.CheckChainID(c.Context); err != nil {
+		return cfg, err
+	}
+
+	if cfg.PaymentsContractAddress == "" && cfg.ContractAddress != "" && hasFlag(c.Command, paymentsContractFlag) {
+		addr, err := DiscoverPaymentsContract(cfg.RPCEndpoint, cfg.RPC, cfg.ContractAddress)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.PaymentsContractAddress = addr
+	}
+	return cfg, nil
+}
+
+// Action adapts a command action that takes the resolved configuration
+func Action(fn func(c *cli.Context, cfg Config) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		cfg, err := Resolve(c)
+		if err != nil {
+			return err
+		}
+		return fn(c, cfg)
+	}
+}
+
+// DiscoverPaymentsContract reads the payments contract address from the DDO contract
+func DiscoverPaymentsContract(rpcEndpoint string, rpcCfg rpcclient.Config, contractAddress string) (string, error) {
+	ddoClient, err := ddo.NewReadOnlyClientWithParams(rpcEndpoint, contractAddress, rpcCfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to create DDO contract client: %v", err)
+	}
+	defer ddoClient.Close()
+
+	addr, err := ddoClient.GetPaymentsContract()
+	if err != nil {
+		return "", fmt.Errorf("failed to get payments contract address from DDO contract: %v", err)
+	}
+	return addr.Hex(), nil
+}
+
+// CheckChainID refuses an RPC endpoint on a different chain than the profile's. It
+// does nothing when the profile sets no chain ID.
+func (cfg Config) CheckChainID(ctx context.Context) error {
+	if cfg.ChainID == 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	client, err := rpcclient.DialContext(ctx, cfg.RPCEndpoint, cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %v", cfg.RPCEndpoint, err)
+	}
+	defer client.Close()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID from %s: %v", cfg.RPCEndpoint, err)
+	}
+	if !chainID.IsUint64() || chainID.Uint64() != cfg.ChainID {
+		return fmt.Errorf("RPC %s is on %s, but profile %q expects %s (chain ID %d)",
+			cfg.RPCEndpoint, NetworkName(chainID.Uint64()), cfg.Profile, NetworkName(cfg.ChainID), cfg.ChainID)
+	}
+	return nil
+}
+
+func hasFlag(cmd *cli.Command, name string) bool {
+	if cmd == nil {
+		return false
+	}
+	for _, f := range cmd.Flags {
+		for _, n := range f.Names() {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
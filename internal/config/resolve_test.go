@@ -0,0 +1,165 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagContract = "0x00000000000000000000000000000000000000a1"
+	paymentsAddr = "0x00000000000000000000000000000000000000b1"
+)
+
+// newRPCServer answers eth_chainId with chainID and every eth_call with the payments
+// contract address
+func newRPCServer(t *testing.T, chainID string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result string
+		switch req.Method {
+		case "eth_chainId":
+			result = chainID
+		case "eth_call":
+			result = "0x" + common.Bytes2Hex(common.LeftPadBytes(common.HexToAddress(paymentsAddr).Bytes(), 32))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0", "id": req.ID, "error": map[string]interface{}{"code": -32601, "message": "method not found"},
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// runResolve runs a command declaring the connection flags with base as the base
+// configuration and returns the configuration it resolved
+func runResolve(t *testing.T, base Config, args ...string) (Config, error) {
+	t.Helper()
+	var got Config
+	app := &cli.App{
+		Name:  "test",
+		Flags: append(TxFlags(), RPCFlags()...),
+		Before: func(c *cli.Context) error {
+			SetBase(c.App, base)
+			return nil
+		},
+		Commands: []*cli.Command{{
+			Name:  "cmd",
+			Flags: []cli.Flag{ContractFlag(), PaymentsContractFlag(), RPCFlag(), PrivateKeyFlag()},
+			Action: Action(func(c *cli.Context, cfg Config) error {
+				got = cfg
+				return nil
+			}),
+		}},
+	}
+	err := app.RunContext(context.Background(), append([]string{"test"}, args...))
+	return got, err
+}
+
+func TestResolve(t *testing.T) {
+	srv := newRPCServer(t, "0x13a") // 314, mainnet
+
+	tests := []struct {
+		name    string
+		base    Config
+		args    []string
+		check   func(t *testing.T, cfg Config)
+		wantErr string
+	}{
+		{
+			name: "flags over the base configuration",
+			base: Config{RPCEndpoint: srv.URL, ContractAddress: envContract, PaymentsContractAddress: paymentsAddr, PrivateKey: "0xenvkey"},
+			args: []string{"cmd", "--contract", flagContract, "--pk", "0xflagkey"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.ContractAddress != flagContract || cfg.PrivateKey != "0xflagkey" || cfg.RPCEndpoint != srv.URL {
+					t.Errorf("resolved %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "global policy flags",
+			base: Config{RPCEndpoint: srv.URL, ContractAddress: envContract, PaymentsContractAddress: paymentsAddr},
+			args: []string{"--rpc-retries", "5", "--confirmations", "3", "cmd"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.RPC.Retries != 5 || cfg.Tx.Confirmations != 3 {
+					t.Errorf("RPC retries %d, confirmations %d", cfg.RPC.Retries, cfg.Tx.Confirmations)
+				}
+			},
+		},
+		{
+			name: "payments contract discovered",
+			base: Config{RPCEndpoint: srv.URL, ContractAddress: envContract},
+			args: []string{"cmd"},
+			check: func(t *testing.T, cfg Config) {
+				if !strings.EqualFold(cfg.PaymentsContractAddress, paymentsAddr) {
+					t.Errorf("payments contract = %q, want %s", cfg.PaymentsContractAddress, paymentsAddr)
+				}
+			},
+		},
+		{
+			name: "payments contract flag skips discovery",
+			base: Config{RPCEndpoint: "http://127.0.0.1:1", ContractAddress: envContract},
+			args: []string{"cmd", "--payments-contract", paymentsAddr},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.PaymentsContractAddress != paymentsAddr {
+					t.Errorf("payments contract = %q, want %s", cfg.PaymentsContractAddress, paymentsAddr)
+				}
+			},
+		},
+		{
+			name: "matching chain ID",
+			base: Config{Profile: "mainnet", ChainID: 314, RPCEndpoint: srv.URL, PaymentsContractAddress: paymentsAddr},
+			args: []string{"cmd"},
+		},
+		{
+			name:    "chain ID mismatch",
+			base:    Config{Profile: "calibration", ChainID: 314159, RPCEndpoint: srv.URL},
+			args:    []string{"cmd"},
+			wantErr: `is on mainnet, but profile "calibration" expects calibration`,
+		},
+		{
+			name:    "invalid policy flag",
+			base:    Config{RPCEndpoint: srv.URL},
+			args:    []string{"--rpc-burst", "0", "cmd"},
+			wantErr: "--rpc-burst must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := runResolve(t, tt.base, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+)
+
+// Names of the global RPC connection flags
+const (
+	rpcRetriesFlag   = "rpc-retries"
+	rpcRateLimitFlag = "rpc-rate-limit"
+	rpcBurstFlag     = "rpc-burst"
+	rpcTimeoutFlag   = "rpc-timeout"
+)
+
+// RPCFlags are the global flags setting how requests to the RPC endpoints are
+// retried, limited and timed out
+func RPCFlags() []cli.Flag {
+	defaults := rpcclient.DefaultConfig()
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:    rpcRetriesFlag,
+			Usage:   "Retries of a failed RPC read, each on the next healthy endpoint",
+			Value:   defaults.Retries,
+			EnvVars: []string{"DDO_RPC_RETRIES"},
+		},
+		&cli.Float64Flag{
+			Name:    rpcRateLimitFlag,
+			Usage:   "RPC requests per second across all endpoints (0 for no limit)",
+			Value:   defaults.RateLimit,
+			EnvVars: []string{"DDO_RPC_RATE_LIMIT"},
+		},
+		&cli.IntFlag{
+			Name:  rpcBurstFlag,
+			Usage: "RPC requests allowed at once above --rpc-rate-limit",
+			Value: defaults.Burst,
+		},
+		&cli.DurationFlag{
+			Name:    rpcTimeoutFlag,
+			Usage:   "Timeout of a single RPC request (0 for none)",
+			Value:   defaults.CallTimeout,
+			EnvVars: []string{"DDO_RPC_TIMEOUT"},
+		},
+	}
+}
+
+// RPCClientConfig returns the RPC connection policy set by the global flags
+func RPCClientConfig(c *cli.Context) (rpcclient.Config, error) {
+	cfg := rpcclient.DefaultConfig()
+	cfg.Retries = c.Int(rpcRetriesFlag)
+	cfg.RateLimit = c.Float64(rpcRateLimitFlag)
+	cfg.Burst = c.Int(rpcBurstFlag)
+	cfg.CallTimeout = c.Duration(rpcTimeoutFlag)
+
+	if cfg.Retries < 0 {
+		return cfg, fmt.Errorf("--%s must not be negative", rpcRetriesFlag)
+	}
+	if cfg.RateLimit < 0 {
+		return cfg, fmt.Errorf("--%s must not be negative", rpcRateLimitFlag)
+	}
+	if cfg.Burst < 1 {
+		return cfg, fmt.Errorf("--%s must be at least 1", rpcBurstFlag)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
+)
+
+// Names of the global transaction policy flags
+const (
+	maxFeeFlag        = "max-fee"
+	priorityFeeFlag   = "priority-fee"
+	confirmationsFlag = "confirmations"
+	txTimeoutFlag     = "tx-timeout"
+	stuckAfterFlag    = "stuck-after"
+	maxBumpsFlag      = "max-bumps"
+	txJournalFlag     = "tx-journal"
+)
+
+// TxFlags are the global flags setting how transactions are priced, replaced and confirmed
+func TxFlags() []cli.Flag {
+	defaults := txmgr.DefaultConfig()
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    maxFeeFlag,
+			Usage:   "Maximum fee per gas in attoFIL, including replacements (default: no cap)",
+			EnvVars: []string{"DDO_MAX_FEE"},
+		},
+		&cli.StringFlag{
+			Name:    priorityFeeFlag,
+			Usage:   "Priority fee per gas in attoFIL (default: the node's suggestion)",
+			EnvVars: []string{"DDO_PRIORITY_FEE"},
+		},
+		&cli.Uint64Flag{
+			Name:    confirmationsFlag,
+			Usage:   "Blocks a transaction needs, including its own, before it counts as mined",
+			Value:   defaults.Confirmations,
+			EnvVars: []string{"DDO_CONFIRMATIONS"},
+		},
+		&cli.DurationFlag{
+			Name:    txTimeoutFlag,
+			Usage:   "How long to wait for a transaction to be mined (0 to wait forever)",
+			Value:   defaults.Timeout,
+			EnvVars: []string{"DDO_TX_TIMEOUT"},
+		},
+		&cli.DurationFlag{
+			Name:    stuckAfterFlag,
+			Usage:   "Replace a transaction with higher fees when it is still pending after this long (0 to never replace)",
+			Value:   defaults.StuckAfter,
+			EnvVars: []string{"DDO_STUCK_AFTER"},
+		},
+		&cli.IntFlag{
+			Name:  maxBumpsFlag,
+			Usage: "Replacements sent for one stuck transaction at most",
+			Value: defaults.MaxBumps,
+		},
+		&cli.StringFlag{
+			Name:    txJournalFlag,
+			Usage:   "File recording the transactions sent, read by the tx commands (default: ~/.ddo-client/transactions.jsonl, \"off\" to disable)",
+			EnvVars: []string{"DDO_TX_JOURNAL"},
+		},
+	}
+}
+
+// TxManagerConfig returns the transaction policy set by the global flags
+func TxManagerConfig(c *cli.Context) (txmgr.Config, error) {
+	cfg := txmgr.DefaultConfig()
+	cfg.Confirmations = c.Uint64(confirmationsFlag)
+	cfg.Timeout = c.Duration(txTimeoutFlag)
+	cfg.StuckAfter = c.Duration(stuckAfterFlag)
+	cfg.MaxBumps = c.Int(maxBumpsFlag)
+
+	if cfg.Confirmations == 0 {
+		return cfg, fmt.Errorf("--%s must be at least 1", confirmationsFlag)
+	}
+
+	var err error
+	if cfg.MaxFeePerGas, err = parseFee(c, maxFeeFlag); err != nil {
+		return cfg, err
+	}
+	if cfg.PriorityFee, err = parseFee(c, priorityFeeFlag); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxFeePerGas != nil && cfg.PriorityFee != nil && cfg.PriorityFee.Cmp(cfg.MaxFeePerGas) > 0 {
+		return cfg, fmt.Errorf("--%s must not exceed --%s", priorityFeeFlag, maxFeeFlag)
+	}
+	if path := TxJournalPath(c); path != "" {
+		cfg.Journal = txmgr.NewFileJournal(path)
+	}
+	return cfg, nil
+}
+
+// TxJournalPath returns the transaction journal set by --tx-journal, or "" when
+// journaling is disabled
+func TxJournalPath(c *cli.Context) string {
+	path := c.String(txJournalFlag)
+	switch path {
+	case "off":
+		return ""
+	case "":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(home, ".ddo-client", "transactions.jsonl")
+	}
+	return path
+}
+
+// parseFee reads a fee flag in attoFIL; an unset flag returns nil
+func parseFee(c *cli.Context, name string) (*big.Int, error) {
+	v := c.String(name)
+	if v == "" {
+		return nil, nil
+	}
+	fee, ok := new(big.Int).SetString(v, 10)
+	if !ok || fee.Sign() < 0 {
+		return nil, fmt.Errorf("invalid --%s %q (expected a non-negative integer in attoFIL)", name, v)
+	}
+	return fee, nil
+}
@@ -0,0 +1,153 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// Flag is the global --output flag
+func Flag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "Output format: table, json or yaml",
+		Value:   utils.OutputTable,
+		EnvVars: []string{"DDO_OUTPUT"},
+	}
+}
+
+// Setup validates the global --output flag
+func Setup(c *cli.Context) error {
+	_, err := utils.ParseOutputFormat(c.String("output"))
+	return err
+}
+
+// Progress returns the writer for a command's human-readable output. In json and yaml
+// mode that is the app's error writer, so that stdout only carries the command's result.
+func Progress(c *cli.Context) io.Writer {
+	if format := Format(c); format == utils.OutputJSON || format == utils.OutputYAML {
+		if c.App != nil && c.App.ErrWriter != nil {
+			return c.App.ErrWriter
+		}
+		return os.Stderr
+	}
+	return Writer(c)
+}
+
+// Writer returns the writer command results are written to, the app's writer
+func Writer(c *cli.Context) io.Writer {
+	if c.App != nil && c.App.Writer != nil {
+		return c.App.Writer
+	}
+	return os.Stdout
+}
+
+// Format returns the output format of the running command. A command's own --format or
+// --json flag takes precedence over the global --output flag.
+func Format(c *cli.Context) string {
+	if hasLocalFlag(c, "format") && c.IsSet("format") {
+		return c.String("format")
+	}
+	if hasLocalFlag(c, "json") && c.Bool("json") {
+		return utils.OutputJSON
+	}
+	format, err := utils.ParseOutputFormat(c.String("output"))
+	if err != nil {
+		return utils.OutputTable
+	}
+	return format
+}
+
+// Render prints a command result: table mode calls table, which prints the
+// human-readable form, and json and yaml mode encode result.
+func Render(c *cli.Context, result interface{}, table func()) error {
+	format := Format(c)
+	if format == utils.OutputTable {
+		if table != nil {
+			table()
+		}
+		return nil
+	}
+	return utils.WriteResult(Writer(c), format, result)
+}
+
+// Write encodes result in format to the command's result writer
+func Write(c *cli.Context, format string, result interface{}) error {
+	return utils.WriteResult(Writer(c), format, result)
+}
+
+func hasLocalFlag(c *cli.Context, name string) bool {
+	if c.Command == nil {
+		return false
+	}
+	for _, f := range c.Command.Flags {
+		for _, n := range f.Names() {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Confirm writes a yes/no question to w, reads the answer from stdin and returns
+// true only for "y" or "yes"
+func Confirm(w io.Writer, question string) (bool, error) {
+	fmt.Fprintf(w, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("failed to read confirmation: %v", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// WaitTx waits for txHash, sent through txm, to be mined. A failure to wait is
+// reported in the result's status and error rather than returned, as the transaction
+// was already sent.
+func WaitTx(txm *txmgr.Manager, txHash string) types.TxResult {
+	receipt, err := utils.WaitForTransactionWithReceipt(txm, txHash)
+	return utils.NewTxResult(txHash, receipt, err)
+}
+
+// RenderTx prints a transaction result. In table mode success is called once the
+// transaction succeeded. A reverted transaction is returned as an error.
+func RenderTx(c *cli.Context, result types.TxResult, success func()) error {
+	if err := Render(c, result, func() { PrintTxOutcome(Writer(c), result, success) }); err != nil {
+		return err
+	}
+	return TxError(result)
+}
+
+// PrintTxOutcome prints the human-readable outcome of a transaction to w: success for
+// a successful one and a warning for one whose receipt could not be fetched
+func PrintTxOutcome(w io.Writer, result types.TxResult, success func()) {
+	switch result.Status {
+	case types.TxStatusPending:
+		fmt.Fprintf(w, "⚠️  Warning: transaction may not have been mined: %s\n", result.Error)
+	case types.TxStatusSuccess:
+		if success != nil {
+			success()
+		}
+	}
+}
+
+// TxError returns an error for a reverted or canceled transaction
+func TxError(result types.TxResult) error {
+	switch result.Status {
+	case types.TxStatusReverted:
+		return fmt.Errorf("transaction %s reverted in block %d", result.TxHash, result.BlockNumber)
+	case types.TxStatusCanceled:
+		return fmt.Errorf("transaction %s was canceled by %s", result.Replaces, result.TxHash)
+	}
+	return nil
+}
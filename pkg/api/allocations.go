@@ -0,0 +1,354 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eastore-project/fildeal/src/buffer"
+	dealutils "github.com/eastore-project/fildeal/src/deal/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ipfs/go-cid"
+
+	"github.com/Eastore-project/ddo-client/pkg/curio"
+	"github.com/Eastore-project/ddo-client/pkg/notify"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+func (s *Server) handleCreateAllocations(w http.ResponseWriter, r *http.Request) error {
+	var req AllocationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return err
+	}
+	if err := s.requireSigner(); err != nil {
+		return err
+	}
+	if len(req.Providers) == 0 {
+		return errorf(http.StatusBadRequest, "providers is required")
+	}
+	switch {
+	case req.Input == "" && req.PieceCid == "":
+		return errorf(http.StatusBadRequest, "one of input or pieceCid is required")
+	case req.Input != "" && req.PieceCid != "":
+		return errorf(http.StatusBadRequest, "input cannot be combined with pieceCid")
+	case req.PieceCid != "" && req.DownloadURL == "":
+		return errorf(http.StatusBadRequest, "pieceCid requires downloadUrl")
+	case len(req.Providers) > 1 && req.CurioAPI != "":
+		return errorf(http.StatusBadRequest, "curioApi applies to a single provider")
+	}
+	if req.Input != "" {
+		input, err := s.resolveInput(req.Input)
+		if err != nil {
+			return err
+		}
+		req.Input = input
+	}
+	token, err := s.tokenOrDefault(req.PaymentToken)
+	if err != nil {
+		return err
+	}
+
+	if req.TermMin == 0 {
+		req.TermMin = defaultTermMin
+	}
+	if req.TermMax == 0 {
+		req.TermMax = defaultTermMax
+	}
+	if req.ExpirationOffset == 0 {
+		req.ExpirationOffset = defaultExpirationOffset
+	}
+	if req.TermMin < 0 || req.TermMax < req.TermMin || req.ExpirationOffset < 0 {
+		return errorf(http.StatusBadRequest, "termMin must be positive and at most termMax")
+	}
+
+	// Pieces prepared elsewhere are validated before the job is queued
+	var piece *types.PreparedPiece
+	if req.PieceCid != "" {
+		if piece, err = utils.ExternalPiece(req.PieceCid, req.PieceSize, req.CarSize, req.DownloadURL); err != nil {
+			return errorf(http.StatusBadRequest, "invalid piece: %v", err)
+		}
+		if req.CurioUpload && piece.CarSize == 0 {
+			return errorf(http.StatusBadRequest, "curioUpload needs the carSize of a v1 piece CID")
+		}
+	}
+
+	s.submitJob(w, "allocation", func(ctx context.Context, progress func(string)) (interface{}, error) {
+		return s.createAllocations(ctx, req, token, piece, progress)
+	})
+	return nil
+}
+
+// createAllocations prepares the data, if needed, and creates one replica per provider.
+// A replica that fails does not stop the others; the job fails if any replica failed.
+func (s *Server) createAllocations(
+	ctx context.Context,
+	req AllocationRequest,
+	token common.Address,
+	piece *types.PreparedPiece,
+	progress func(string),
+) (interface{}, error) {
+	result := &AllocationJobResult{Replicas: []types.ReplicaResult{}}
+
+	if piece == nil {
+		progress("preparing data")
+		outDir, err := s.prepDir()
+		if err != nil {
+			return result, err
+		}
+		// The CAR file is only needed until it is uploaded to the providers
+		defer os.RemoveAll(outDir)
+
+		if piece, err = prepareInput(req.Input, outDir, req.DownloadURL, s.cfg.Buffer); err != nil {
+			return result, err
+		}
+	}
+	reported := *piece
+	reported.CarPath = ""
+	result.Piece = &reported
+
+	pieceCid, err := cid.Decode(piece.PieceCid)
+	if err != nil {
+		return result, fmt.Errorf("failed to decode piece CID: %w", err)
+	}
+	pieceInfo := types.PieceInfo{
+		PieceCid:            pieceCid.Bytes(),
+		Size:                piece.PieceSize,
+		TermMin:             req.TermMin,
+		TermMax:             req.TermMax,
+		ExpirationOffset:    req.ExpirationOffset,
+		DownloadURL:         piece.DownloadURL,
+		PaymentTokenAddress: token,
+	}
+
+	var failed int
+	for i, provider := range req.Providers {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		progress(fmt.Sprintf("replica %d/%d: provider %d", i+1, len(req.Providers), provider))
+
+		replica, err := s.createReplica(ctx, req, pieceInfo, piece, provider)
+		if err != nil {
+			failed++
+			replica.Error = err.Error()
+		}
+		result.Replicas = append(result.Replicas, replica)
+	}
+
+	if failed > 0 {
+		return result, fmt.Errorf("%d of %d replica(s) failed", failed, len(req.Providers))
+	}
+	return result, nil
+}
+
+// prepDir creates a directory below the data directory to prepare an input in
+func (s *Server) prepDir() (string, error) {
+	if err := os.MkdirAll(s.cfg.DataDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(s.cfg.DataDir, "prep-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return dir, nil
+}
+
+// prepareInput packs a server-side file or folder into a CAR file in outDir
+func prepareInput(input, outDir, downloadURL string, bufferConfig *buffer.Config) (*types.PreparedPiece, error) {
+	prep, err := dealutils.PrepareData(input, outDir, bufferConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare data: %w", err)
+	}
+	if downloadURL == "" {
+		downloadURL = prep.BufferInfo.URL
+	}
+	return &types.PreparedPiece{
+		PieceCid:    prep.PieceCid,
+		PieceSize:   prep.PieceSize,
+		PayloadCid:  prep.PayloadCid,
+		CarSize:     prep.CarSize,
+		CarPath:     prep.LocalPath,
+		DownloadURL: downloadURL,
+	}, nil
+}
+
+// createReplica sets up payments, creates the allocation and optionally submits the
+// deal to Curio for one provider. The result records how far the replica got.
+func (s *Server) createReplica(
+	ctx context.Context,
+	req AllocationRequest,
+	pieceInfo types.PieceInfo,
+	piece *types.PreparedPiece,
+	provider uint64,
+) (types.ReplicaResult, error) {
+	result := types.ReplicaResult{Provider: provider}
+	pieceInfo.Provider = provider
+	pieceInfos := []types.PieceInfo{pieceInfo}
+
+	allocationIds, txHash, err := s.allocate(pieceInfos, req.SkipPaymentSetup)
+	result.TxHash = txHash
+	result.AllocationIds = allocationIds
+	if err != nil {
+		return result, err
+	}
+	if !req.CurioUpload {
+		return result, nil
+	}
+
+	curioAPI := req.CurioAPI
+	if curioAPI == "" && len(req.Providers) == 1 {
+		curioAPI = s.cfg.CurioAPI
+	}
+	if curioAPI == "" {
+		if curioAPI, err = curio.DiscoverSPURL(s.cfg.RPCEndpoint, s.cfg.RPC, provider); err != nil {
+			return result, fmt.Errorf("failed to discover SP URL: %w", err)
+		}
+	}
+	providerAddr, err := curio.ProviderIDToFilecoinAddr(provider)
+	if err != nil {
+		return result, fmt.Errorf("failed to derive provider Filecoin address: %w", err)
+	}
+
+	params := curio.DDODealParams{
+		Client:         s.clientAddress(),
+		DDOContract:    s.cfg.ContractAddress,
+		VerifyContract: s.cfg.ContractAddress.Hex(),
+		Provider:       providerAddr.String(),
+	}
+	dealIds, err := curio.NewClient(curioAPI, s.cfg.PrivateKey).SubmitDDODeals(ctx, params, []types.PreparedPiece{*piece}, allocationIds, nil)
+	for i, id := range dealIds {
+		result.CurioDealIds = append(result.CurioDealIds, id.String())
+		if s.cfg.Notifier != nil {
+			client := s.clientAddress()
+			s.cfg.Notifier.WatchDeal(notify.DealWatch{
+				DealId:       id,
+				Provider:     provider,
+				AllocationId: allocationIds[i],
+				Client:       &client,
+				CurioAPI:     curioAPI,
+			})
+		}
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to submit deal to Curio: %w", err)
+	}
+	result.CurioSubmitted = true
+	return result, nil
+}
+
+// allocate sets up payments and creates the allocation requests one job at a time,
+// returning the new allocation IDs and the transaction hash
+func (s *Server) allocate(pieceInfos []types.PieceInfo, skipPaymentSetup bool) ([]uint64, string, error) {
+	s.chainMu.Lock()
+	defer s.chainMu.Unlock()
+
+	return utils.CreateAllocations(s.ddo, s.payments, pieceInfos, s.clientAddress(), s.cfg.ContractAddress, s.cfg.Auth, skipPaymentSetup, nil)
+}
+
+// resolveInput resolves a server-side input below the input directory, following
+// symlinks. Inputs that are missing or outside the directory get the same error, so
+// that callers cannot probe the server's files.
+func (s *Server) resolveInput(input string) (string, error) {
+	if s.cfg.InputDir == "" {
+		return "", errorf(http.StatusBadRequest, "server-side inputs are disabled; start the server with an input directory")
+	}
+	root, err := filepath.EvalSymlinks(s.cfg.InputDir)
+	if err != nil {
+		log.Errorw("failed to resolve input directory", "dir", s.cfg.InputDir, "error", err)
+		return "", errorf(http.StatusBadRequest, "input is not an allowed path")
+	}
+	if !filepath.IsAbs(input) {
+		input = filepath.Join(root, input)
+	}
+	resolved, err := filepath.EvalSymlinks(input)
+	if err != nil {
+		return "", errorf(http.StatusBadRequest, "input is not an allowed path")
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errorf(http.StatusBadRequest, "input is not an allowed path")
+	}
+	return resolved, nil
+}
+
+func (s *Server) handleSettle(w http.ResponseWriter, r *http.Request) error {
+	var req SettlementRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return err
+	}
+	if err := s.requireSigner(); err != nil {
+		return err
+	}
+	if req.Provider == 0 && req.AllocationId == 0 {
+		return errorf(http.StatusBadRequest, "one of provider or allocationId is required")
+	}
+
+	s.submitJob(w, "settlement", func(ctx context.Context, progress func(string)) (interface{}, error) {
+		return s.settle(ctx, req, progress)
+	})
+	return nil
+}
+
+// settle settles SP payments and reports the provider's payee accounts afterwards
+func (s *Server) settle(ctx context.Context, req SettlementRequest, progress func(string)) (interface{}, error) {
+	providerId := req.Provider
+	if req.AllocationId > 0 {
+		info, err := s.ddo.GetAllocationInfo(req.AllocationId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get allocation info: %w", err)
+		}
+		if info.Client == (common.Address{}) {
+			return nil, fmt.Errorf("allocation %d not found", req.AllocationId)
+		}
+		providerId = info.Provider
+	}
+	untilEpoch := req.UntilEpoch
+	if untilEpoch == 0 {
+		current, err := s.ddo.GetEthClient().BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current block number: %w", err)
+		}
+		untilEpoch = current
+	}
+
+	result := types.SPSettlement{
+		ProviderId:   providerId,
+		AllocationId: req.AllocationId,
+		UntilEpoch:   untilEpoch,
+		Accounts:     []types.AccountDetails{},
+	}
+
+	progress(fmt.Sprintf("settling until epoch %d", untilEpoch))
+	txs, err := utils.SettleSPPayments(s.ddo, providerId, req.AllocationId, untilEpoch, func(tx types.TxResult) {
+		progress(fmt.Sprintf("settlement transaction %s %s", tx.TxHash, tx.Status))
+	})
+	result.Transactions = txs
+	if err != nil {
+		return result, err
+	}
+
+	spConfig, err := s.ddo.GetSPConfig(providerId)
+	if err != nil || spConfig == nil {
+		return result, nil
+	}
+	for _, tc := range spConfig.SupportedTokens {
+		if !tc.IsActive {
+			continue
+		}
+		account, err := s.payments.GetAccount(tc.Token, spConfig.PaymentAddress)
+		if err != nil {
+			log.Warnw("failed to read SP account", "token", tc.Token, "error", err)
+			continue
+		}
+		result.Accounts = append(result.Accounts, types.AccountDetails{
+			Token:   tc.Token,
+			Address: spConfig.PaymentAddress,
+			Account: *account,
+		})
+	}
+	return result, nil
+}
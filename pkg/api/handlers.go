@@ -0,0 +1,280 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/curio"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// Defaults of create-from-file and quote
+const (
+	defaultTermMin          = 518400
+	defaultTermMax          = 5256000
+	defaultExpirationOffset = 172800
+)
+
+func (s *Server) handleListProviders(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	filter := utils.SPSearchFilter{IncludeInactive: q.Get("includeInactive") == "true"}
+	for _, t := range q["token"] {
+		addr, err := parseAddress("token", t)
+		if err != nil {
+			return err
+		}
+		filter.Tokens = append(filter.Tokens, addr)
+	}
+	var err error
+	if filter.PieceSize, err = parseUintParam("pieceSize", q.Get("pieceSize")); err != nil {
+		return err
+	}
+	termLength, err := parseUintParam("termLength", q.Get("termLength"))
+	if err != nil {
+		return err
+	}
+	filter.TermLength = int64(termLength)
+
+	decimals := func(tokenAddr common.Address) (uint8, error) {
+		meta, err := token.GetTokenMetadata(s.cfg.RPCEndpoint, s.cfg.RPC, tokenAddr)
+		if err != nil {
+			return 0, err
+		}
+		return meta.Decimals, nil
+	}
+	offers, _, err := utils.SearchSPOffers(s.ddo, filter, decimals)
+	if err != nil {
+		return err
+	}
+	if offers == nil {
+		offers = []types.SPOffer{}
+	}
+	writeJSON(w, http.StatusOK, offers)
+	return nil
+}
+
+func (s *Server) handleGetProvider(w http.ResponseWriter, r *http.Request) error {
+	id, err := parseUint("provider ID", r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	spConfig, err := s.ddo.GetSPConfig(id)
+	if err != nil {
+		return err
+	}
+	if spConfig == nil {
+		return errorf(http.StatusNotFound, "storage provider %d is not registered", id)
+	}
+	writeJSON(w, http.StatusOK, spConfig)
+	return nil
+}
+
+func (s *Server) handleQuote(w http.ResponseWriter, r *http.Request) error {
+	var req QuoteRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return err
+	}
+	if req.Provider == 0 {
+		return errorf(http.StatusBadRequest, "provider is required")
+	}
+	if req.DataSize == 0 {
+		return errorf(http.StatusBadRequest, "dataSize is required")
+	}
+	token, err := s.tokenOrDefault(req.Token)
+	if err != nil {
+		return err
+	}
+	from := s.clientAddress()
+	if req.From != "" {
+		if from, err = parseAddress("from", req.From); err != nil {
+			return err
+		}
+	}
+	if req.TermLength == 0 {
+		req.TermLength = defaultTermMin
+	}
+	if req.TermMax == 0 {
+		req.TermMax = defaultTermMax
+	}
+	if req.TermLength < 0 || req.TermMax < req.TermLength {
+		return errorf(http.StatusBadRequest, "termLength must be positive and at most termMax")
+	}
+
+	quote, err := utils.QuoteAllocation(s.ddo, req.Provider, token, req.DataSize, req.TermLength, req.TermMax, from)
+	if errors.Is(err, utils.ErrProviderRejects) {
+		return errorf(http.StatusUnprocessableEntity, "%v", err)
+	}
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, quote)
+	return nil
+}
+
+func (s *Server) handleGetAllocation(w http.ResponseWriter, r *http.Request) error {
+	id, err := parseUint("allocation ID", r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	details, err := utils.GetAllocationDetails(s.ddo, []uint64{id})
+	if err != nil {
+		return err
+	}
+	if !details[0].Found {
+		return errorf(http.StatusNotFound, "allocation %d not found", id)
+	}
+	writeJSON(w, http.StatusOK, details[0])
+	return nil
+}
+
+func (s *Server) handleClientAllocations(w http.ResponseWriter, r *http.Request) error {
+	client, err := parseAddress("client address", r.PathValue("address"))
+	if err != nil {
+		return err
+	}
+	ids, err := s.ddo.GetAllocationIdsForClient(client.Hex())
+	if err != nil {
+		return err
+	}
+	details := []types.AllocationDetails{}
+	if len(ids) > 0 {
+		if details, err = utils.GetAllocationDetails(s.ddo, ids); err != nil {
+			return err
+		}
+	}
+	writeJSON(w, http.StatusOK, details)
+	return nil
+}
+
+func (s *Server) handlePaymentsAccount(w http.ResponseWriter, r *http.Request) error {
+	owner, err := parseAddress("account address", r.PathValue("address"))
+	if err != nil {
+		return err
+	}
+	token, err := s.tokenOrDefault(r.URL.Query().Get("token"))
+	if err != nil {
+		return err
+	}
+
+	account, err := s.payments.GetAccount(token, owner)
+	if err != nil {
+		return err
+	}
+	header, err := s.ddo.GetEthClient().HeaderByNumber(r.Context(), nil)
+	if err != nil {
+		return err
+	}
+	health, err := utils.GetAccountHealth(s.payments, token, owner, header.Number.Uint64(), time.Unix(int64(header.Time), 0))
+	if err != nil {
+		return err
+	}
+	approval, err := s.payments.GetOperatorApproval(token, owner, s.cfg.ContractAddress)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, PaymentsAccount{
+		AccountDetails:   types.AccountDetails{Token: token, Address: owner, Account: *account},
+		Health:           health,
+		OperatorApproval: approval,
+		Operator:         s.cfg.ContractAddress,
+	})
+	return nil
+}
+
+func (s *Server) handleCurioDealStatus(w http.ResponseWriter, r *http.Request) error {
+	dealID, err := ulid.Parse(r.PathValue("dealId"))
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid deal ID: %v", err)
+	}
+	if err := s.requireSigner(); err != nil {
+		return err
+	}
+
+	q := r.URL.Query()
+	curioAPI := q.Get("curioApi")
+	if curioAPI == "" && q.Get("provider") != "" {
+		provider, err := parseUint("provider", q.Get("provider"))
+		if err != nil {
+			return err
+		}
+		if curioAPI, err = curio.DiscoverSPURL(s.cfg.RPCEndpoint, s.cfg.RPC, provider); err != nil {
+			return errorf(http.StatusBadGateway, "failed to discover the Curio API of provider %d: %v", provider, err)
+		}
+	}
+	if curioAPI == "" {
+		curioAPI = s.cfg.CurioAPI
+	}
+	if curioAPI == "" {
+		return errorf(http.StatusBadRequest, "provider or curioApi is required")
+	}
+
+	status, err := curio.NewClient(curioAPI, s.cfg.PrivateKey).DealStatus(r.Context(), dealID)
+	if err != nil {
+		return errorf(http.StatusBadGateway, "%v", err)
+	}
+	writeJSON(w, http.StatusOK, CurioDealStatus{DealId: dealID.String(), CurioAPI: curioAPI, Status: status})
+	return nil
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) error {
+	writeJSON(w, http.StatusOK, s.jobs.list())
+	return nil
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) error {
+	job, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		return errorf(http.StatusNotFound, "job %s not found", r.PathValue("id"))
+	}
+	writeJSON(w, http.StatusOK, job)
+	return nil
+}
+
+// submitJob queues a job and answers with its initial state
+func (s *Server) submitJob(w http.ResponseWriter, kind string, run jobFunc) {
+	job := s.jobs.submit(kind, run)
+	w.Header().Set("Location", "/v1/jobs/"+job.Id)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// tokenOrDefault parses a token address, falling back to the server's payment token
+func (s *Server) tokenOrDefault(token string) (common.Address, error) {
+	if token == "" {
+		token = s.cfg.PaymentToken
+	}
+	if token == "" {
+		return common.Address{}, errorf(http.StatusBadRequest, "token is required (the server has no default payment token)")
+	}
+	return parseAddress("token", token)
+}
+
+func parseAddress(name, s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, errorf(http.StatusBadRequest, "invalid %s: %q", name, s)
+	}
+	return common.HexToAddress(s), nil
+}
+
+func parseUint(name, s string) (uint64, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || v == 0 {
+		return 0, errorf(http.StatusBadRequest, "invalid %s: %q", name, s)
+	}
+	return v, nil
+}
+
+// parseUintParam parses an optional query parameter, zero when absent
+func parseUintParam(name, s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return parseUint(name, s)
+}
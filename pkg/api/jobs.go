@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// maxFinishedJobs is the number of finished jobs kept; older ones are forgotten
+const maxFinishedJobs = 1000
+
+// jobFunc runs a job. progress reports what the job is doing.
+type jobFunc func(ctx context.Context, progress func(string)) (interface{}, error)
+
+// jobStore runs jobs in the background, at most maxRunning at a time, and keeps their
+// state in memory
+type jobStore struct {
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	slots chan struct{}
+	ctx   context.Context
+	wg    sync.WaitGroup
+}
+
+func newJobStore(ctx context.Context, maxRunning int) *jobStore {
+	if maxRunning < 1 {
+		maxRunning = 1
+	}
+	return &jobStore{
+		jobs:  make(map[string]*Job),
+		slots: make(chan struct{}, maxRunning),
+		ctx:   ctx,
+	}
+}
+
+// submit queues a job and returns its initial state
+func (s *jobStore) submit(kind string, run jobFunc) Job {
+	job := &Job{
+		Id:        ulid.Make().String(),
+		Kind:      kind,
+		Status:    JobQueued,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	s.jobs[job.Id] = job
+	s.pruneLocked()
+	snapshot := *job
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.run(job, run)
+	return snapshot
+}
+
+func (s *jobStore) run(job *Job, run jobFunc) {
+	defer s.wg.Done()
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-s.ctx.Done():
+		s.finish(job, nil, s.ctx.Err())
+		return
+	}
+
+	s.update(job, func(j *Job) {
+		now := time.Now().UTC()
+		j.Status = JobRunning
+		j.StartedAt = &now
+	})
+
+	progress := func(msg string) {
+		s.update(job, func(j *Job) { j.Progress = msg })
+	}
+	result, err := run(s.ctx, progress)
+	s.finish(job, result, err)
+}
+
+func (s *jobStore) finish(job *Job, result interface{}, err error) {
+	s.update(job, func(j *Job) {
+		now := time.Now().UTC()
+		j.FinishedAt = &now
+		j.Result = result
+		j.Status = JobSucceeded
+		if err != nil {
+			j.Status = JobFailed
+			j.Error = err.Error()
+			log.Warnw("job failed", "id", j.Id, "kind", j.Kind, "error", err)
+		}
+	})
+}
+
+func (s *jobStore) update(job *Job, fn func(*Job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(job)
+}
+
+// get returns a copy of a job's state
+func (s *jobStore) get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// list returns every job known, newest first
+func (s *jobStore) list() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	// Monotonic ULIDs sort by creation time
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Id > jobs[j].Id })
+	return jobs
+}
+
+// wait blocks until every job submitted has finished
+func (s *jobStore) wait() {
+	s.wg.Wait()
+}
+
+// pruneLocked forgets the oldest finished jobs beyond maxFinishedJobs
+func (s *jobStore) pruneLocked() {
+	var finished []string
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil {
+			finished = append(finished, id)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	sort.Strings(finished)
+	for _, id := range finished[:len(finished)-maxFinishedJobs] {
+		delete(s.jobs, id)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := newJobStore(ctx, 1)
+
+	release := make(chan struct{})
+	first := store.submit("test", func(ctx context.Context, progress func(string)) (interface{}, error) {
+		progress("working")
+		<-release
+		return "done", nil
+	})
+	if first.Status != JobQueued {
+		t.Fatalf("new job status = %s, want %s", first.Status, JobQueued)
+	}
+	waitFor(t, func() bool {
+		job, _ := store.get(first.Id)
+		return job.Status == JobRunning && job.Progress == "working"
+	})
+
+	// The second job waits for the only slot
+	second := store.submit("test", func(ctx context.Context, progress func(string)) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	time.Sleep(10 * time.Millisecond)
+	if job, _ := store.get(second.Id); job.Status != JobQueued {
+		t.Fatalf("second job status = %s, want %s", job.Status, JobQueued)
+	}
+
+	close(release)
+	store.wait()
+
+	job, ok := store.get(first.Id)
+	if !ok || job.Status != JobSucceeded || job.Result != "done" || job.StartedAt == nil || job.FinishedAt == nil {
+		t.Errorf("first job = %+v", job)
+	}
+	job, _ = store.get(second.Id)
+	if job.Status != JobFailed || job.Error != "boom" {
+		t.Errorf("second job = %+v", job)
+	}
+
+	jobs := store.list()
+	if len(jobs) != 2 || jobs[0].Id != second.Id || jobs[1].Id != first.Id {
+		t.Errorf("list is not newest first: %+v", jobs)
+	}
+	if _, ok := store.get("unknown"); ok {
+		t.Error("unknown job found")
+	}
+}
+
+func TestJobStoreCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := newJobStore(ctx, 1)
+
+	release := make(chan struct{})
+	running := store.submit("test", func(ctx context.Context, progress func(string)) (interface{}, error) {
+		<-release
+		return nil, ctx.Err()
+	})
+	waitFor(t, func() bool {
+		job, _ := store.get(running.Id)
+		return job.Status == JobRunning
+	})
+	queued := store.submit("test", func(ctx context.Context, progress func(string)) (interface{}, error) {
+		t.Error("queued job ran after cancel")
+		return nil, nil
+	})
+
+	cancel()
+	// The queued job is failed without running; the running one sees the cancellation
+	waitFor(t, func() bool {
+		job, _ := store.get(queued.Id)
+		return job.Status == JobFailed
+	})
+	close(release)
+	store.wait()
+
+	job, _ := store.get(running.Id)
+	if job.Status != JobFailed || job.Error != context.Canceled.Error() {
+		t.Errorf("running job = %+v", job)
+	}
+}
+
+func TestJobStorePrune(t *testing.T) {
+	store := newJobStore(context.Background(), 4)
+	for i := 0; i < maxFinishedJobs+10; i++ {
+		store.submit("test", func(ctx context.Context, progress func(string)) (interface{}, error) {
+			return nil, nil
+		})
+	}
+	store.wait()
+
+	// Pruning happens on submit, so the last batch of finished jobs is still there
+	store.submit("test", func(ctx context.Context, progress func(string)) (interface{}, error) {
+		return nil, nil
+	})
+	store.wait()
+	if n := len(store.list()); n > maxFinishedJobs+1 {
+		t.Errorf("%d jobs kept, want at most %d", n, maxFinishedJobs+1)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/eastore-project/fildeal/src/buffer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	logging "github.com/ipfs/go-log/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/notify"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+)
+
+var log = logging.Logger("ddo/api")
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// maxBodySize is the largest request body accepted
+const maxBodySize = 1 << 20
+
+// DefaultMaxJobs is the default number of jobs that run at the same time
+const DefaultMaxJobs = 2
+
+// Config configures the API server
+type Config struct {
+	// Token is the bearer token every request but the health check and the
+	// OpenAPI spec must carry. It must not be empty.
+	Token string
+
+	RPCEndpoint      string           // used for token metadata and Curio URL discovery
+	RPC              rpcclient.Config // policy of the connections to RPCEndpoint
+	ContractAddress  common.Address   // DDO contract
+	PaymentsContract common.Address
+	PaymentToken     string // default token of quotes, accounts and allocations, if any
+	CurioAPI         string // default Curio API; discovered per provider when empty
+
+	// PrivateKey signs Curio requests and Auth signs transactions; Auth must be the
+	// transactor of the contract clients. Without them the server is read-only.
+	PrivateKey *ecdsa.PrivateKey
+	Auth       *bind.TransactOpts
+
+	// InputDir is the only directory whose files and folders requests may name as
+	// inputs. Server-side inputs are disabled when it is empty.
+	InputDir string
+
+	DataDir string         // where server-side inputs are prepared into CAR files
+	Buffer  *buffer.Config // where prepared CAR files are published for download
+	MaxJobs int            // jobs running at the same time, DefaultMaxJobs when zero
+
+	// Notifier, if set, watches the Curio deals submitted by allocation jobs
+	Notifier *notify.Notifier
+}
+
+// Server serves the client library over an authenticated HTTP API
+type Server struct {
+	cfg      Config
+	ddo      *ddo.Client
+	payments *payments.Client
+	jobs     *jobStore
+	cancel   context.CancelFunc
+
+	// chainMu serializes payment setup and allocation creation, which read and then
+	// top up the same payments account
+	chainMu sync.Mutex
+}
+
+// New creates a server for the given contract clients
+func New(cfg Config, ddoClient *ddo.Client, paymentsClient *payments.Client) *Server {
+	if cfg.MaxJobs == 0 {
+		cfg.MaxJobs = DefaultMaxJobs
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		cfg:      cfg,
+		ddo:      ddoClient,
+		payments: paymentsClient,
+		jobs:     newJobStore(ctx, cfg.MaxJobs),
+		cancel:   cancel,
+	}
+}
+
+// Close fails the jobs still queued and waits for the running ones to finish
+func (s *Server) Close() {
+	s.cancel()
+	s.jobs.wait()
+}
+
+// readOnly reports whether the server has no key to sign with
+func (s *Server) readOnly() bool {
+	return s.cfg.PrivateKey == nil || s.cfg.Auth == nil
+}
+
+// clientAddress returns the address transactions are sent from
+func (s *Server) clientAddress() common.Address {
+	if s.cfg.PrivateKey == nil {
+		return common.Address{}
+	}
+	return crypto.PubkeyToAddress(s.cfg.PrivateKey.PublicKey)
+}
+
+// route is one endpoint of the API
+type route struct {
+	pattern string // method and path, as accepted by http.ServeMux
+	handler handlerFunc
+	public  bool // served without authentication
+}
+
+// handlerFunc handles a request. A returned error is written as a JSON error response.
+type handlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (s *Server) routes() []route {
+	return []route{
+		{pattern: "GET /openapi.yaml", handler: s.handleOpenAPI, public: true},
+		{pattern: "GET /v1/health", handler: s.handleHealth, public: true},
+		{pattern: "GET /v1/providers", handler: s.handleListProviders},
+		{pattern: "GET /v1/providers/{id}", handler: s.handleGetProvider},
+		{pattern: "POST /v1/quotes", handler: s.handleQuote},
+		{pattern: "POST /v1/allocations", handler: s.handleCreateAllocations},
+		{pattern: "GET /v1/allocations/{id}", handler: s.handleGetAllocation},
+		{pattern: "GET /v1/clients/{address}/allocations", handler: s.handleClientAllocations},
+		{pattern: "GET /v1/payments/accounts/{address}", handler: s.handlePaymentsAccount},
+		{pattern: "POST /v1/settlements", handler: s.handleSettle},
+		{pattern: "GET /v1/curio/deals/{dealId}", handler: s.handleCurioDealStatus},
+		{pattern: "GET /v1/jobs", handler: s.handleListJobs},
+		{pattern: "GET /v1/jobs/{id}", handler: s.handleGetJob},
+	}
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, rt := range s.routes() {
+		h := rt.handler
+		if !rt.public {
+			h = s.authenticate(h)
+		}
+		mux.Handle(rt.pattern, serve(h))
+	}
+	mux.Handle("/", serve(func(w http.ResponseWriter, r *http.Request) error {
+		return errorf(http.StatusNotFound, "no route for %s %s", r.Method, r.URL.Path)
+	}))
+	return mux
+}
+
+// authenticate rejects requests without the server's bearer token
+func (s *Server) authenticate(next handlerFunc) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || s.cfg.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ddo-client"`)
+			return errorf(http.StatusUnauthorized, "missing or invalid bearer token")
+		}
+		return next(w, r)
+	}
+}
+
+// requireSigner fails requests that need to sign when the server is read-only
+func (s *Server) requireSigner() error {
+	if s.readOnly() {
+		return errorf(http.StatusForbidden, "the server has no private key configured and is read-only")
+	}
+	return nil
+}
+
+// httpError is an error with the status code it is served with
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+func (e *httpError) Unwrap() error { return e.err }
+
+// errorf returns an error served with the given status code
+func errorf(status int, format string, args ...interface{}) error {
+	return &httpError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// serve adapts a handlerFunc, writing its error as a JSON error response. Errors
+// without a status code are internal errors.
+func serve(h handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil {
+			return
+		}
+		status := http.StatusInternalServerError
+		var he *httpError
+		if errors.As(err, &he) {
+			status = he.status
+		}
+		if status == http.StatusInternalServerError {
+			log.Warnw("request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		}
+		writeJSON(w, status, ErrorResponse{Error: err.Error()})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Debugw("failed to write response", "error", err)
+	}
+}
+
+// decodeJSON reads a request body into v, rejecting unknown fields
+func decodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, err := w.Write(openAPISpec)
+	return err
+}
+
+// health is the body of GET /v1/health
+type health struct {
+	Status           string          `json:"status"`
+	ReadOnly         bool            `json:"readOnly"`
+	Client           *common.Address `json:"client,omitempty"`
+	Contract         common.Address  `json:"contract"`
+	PaymentsContract common.Address  `json:"paymentsContract"`
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) error {
+	h := health{
+		Status:           "ok",
+		ReadOnly:         s.readOnly(),
+		Contract:         s.cfg.ContractAddress,
+		PaymentsContract: s.cfg.PaymentsContract,
+	}
+	if !h.ReadOnly {
+		addr := s.clientAddress()
+		h.Client = &addr
+	}
+	writeJSON(w, http.StatusOK, h)
+	return nil
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gopkg.in/yaml.v3"
+)
+
+const testToken = "secret"
+
+// newTestServer returns a server without contract clients, for the requests that
+// are answered before reaching the chain
+func newTestServer(t *testing.T, signer bool) *Server {
+	t.Helper()
+	cfg := Config{
+		Token:           testToken,
+		ContractAddress: common.HexToAddress("0xdd"),
+		PaymentToken:    "0x0000000000000000000000000000000000000070",
+		InputDir:        t.TempDir(),
+		DataDir:         t.TempDir(),
+	}
+	if signer {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(314159))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.PrivateKey, cfg.Auth = key, auth
+	}
+	s := New(cfg, nil, nil)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func do(t *testing.T, s *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	s := newTestServer(t, false)
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"health is public", "/v1/health", "", http.StatusOK},
+		{"spec is public", "/openapi.yaml", "", http.StatusOK},
+		{"missing token", "/v1/jobs", "", http.StatusUnauthorized},
+		{"wrong token", "/v1/jobs", "wrong", http.StatusUnauthorized},
+		{"valid token", "/v1/jobs", testToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, s, http.MethodGet, tt.path, tt.token, "")
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+
+	// An empty server token never authenticates
+	s.cfg.Token = ""
+	req := httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("empty token: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	var h health
+	rec := do(t, newTestServer(t, false), http.MethodGet, "/v1/health", "", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &h); err != nil {
+		t.Fatal(err)
+	}
+	if !h.ReadOnly || h.Client != nil {
+		t.Errorf("read-only server: got %+v", h)
+	}
+
+	s := newTestServer(t, true)
+	rec = do(t, s, http.MethodGet, "/v1/health", "", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &h); err != nil {
+		t.Fatal(err)
+	}
+	if h.ReadOnly || h.Client == nil || *h.Client != s.clientAddress() {
+		t.Errorf("signing server: got %+v", h)
+	}
+}
+
+func TestRoutesDocumented(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("invalid OpenAPI spec: %v", err)
+	}
+
+	s := newTestServer(t, false)
+	documented := 0
+	for _, rt := range s.routes() {
+		method, path, _ := strings.Cut(rt.pattern, " ")
+		if _, ok := spec.Paths[path][strings.ToLower(method)]; !ok {
+			t.Errorf("%s is not in openapi.yaml", rt.pattern)
+		}
+		documented++
+	}
+	var specOps int
+	for _, ops := range spec.Paths {
+		specOps += len(ops)
+	}
+	if specOps != documented {
+		t.Errorf("openapi.yaml has %d operations, the server serves %d", specOps, documented)
+	}
+}
+
+func TestReadOnlyRejectsSigning(t *testing.T) {
+	s := newTestServer(t, false)
+
+	tests := []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/v1/allocations", `{"providers":[1000],"pieceCid":"x","downloadUrl":"http://x"}`},
+		{http.MethodPost, "/v1/settlements", `{"provider":1000}`},
+		{http.MethodGet, "/v1/curio/deals/01HZ0000000000000000000000?curioApi=http://127.0.0.1:1", ""},
+	}
+	for _, tt := range tests {
+		rec := do(t, s, tt.method, tt.path, testToken, tt.body)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: status = %d, want 403: %s", tt.method, tt.path, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	s := newTestServer(t, true)
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		status       int
+		errContains  string
+	}{
+		{"malformed body", http.MethodPost, "/v1/quotes", `{`, http.StatusBadRequest, "invalid request body"},
+		{"unknown field", http.MethodPost, "/v1/quotes", `{"provider":1,"dataSize":1,"size":2}`, http.StatusBadRequest, "unknown field"},
+		{"quote without provider", http.MethodPost, "/v1/quotes", `{"dataSize":1024}`, http.StatusBadRequest, "provider is required"},
+		{"quote with bad token", http.MethodPost, "/v1/quotes", `{"provider":1,"dataSize":1,"token":"0x1"}`, http.StatusBadRequest, "invalid token"},
+		{"quote term over max", http.MethodPost, "/v1/quotes", `{"provider":1,"dataSize":1,"termLength":10,"termMax":5}`, http.StatusBadRequest, "termLength"},
+		{"bad provider ID", http.MethodGet, "/v1/providers/abc", "", http.StatusBadRequest, "invalid provider ID"},
+		{"bad piece size filter", http.MethodGet, "/v1/providers?pieceSize=-1", "", http.StatusBadRequest, "invalid pieceSize"},
+		{"bad allocation ID", http.MethodGet, "/v1/allocations/0", "", http.StatusBadRequest, "invalid allocation ID"},
+		{"bad client address", http.MethodGet, "/v1/clients/0x12/allocations", "", http.StatusBadRequest, "invalid client address"},
+		{"allocation without providers", http.MethodPost, "/v1/allocations", `{"input":"/tmp"}`, http.StatusBadRequest, "providers is required"},
+		{"allocation without data", http.MethodPost, "/v1/allocations", `{"providers":[1000]}`, http.StatusBadRequest, "one of input or pieceCid"},
+		{"allocation with input and piece", http.MethodPost, "/v1/allocations", `{"providers":[1000],"input":"/tmp","pieceCid":"x"}`, http.StatusBadRequest, "cannot be combined"},
+		{"piece without URL", http.MethodPost, "/v1/allocations", `{"providers":[1000],"pieceCid":"x"}`, http.StatusBadRequest, "downloadUrl"},
+		{"missing input", http.MethodPost, "/v1/allocations", `{"providers":[1000],"input":"missing"}`, http.StatusBadRequest, "not an allowed path"},
+		{"input outside input dir", http.MethodPost, "/v1/allocations", `{"providers":[1000],"input":"/tmp"}`, http.StatusBadRequest, "not an allowed path"},
+		{"invalid piece", http.MethodPost, "/v1/allocations", `{"providers":[1000],"pieceCid":"x","downloadUrl":"http://x"}`, http.StatusBadRequest, "invalid piece"},
+		{"curio API for several providers", http.MethodPost, "/v1/allocations", `{"providers":[1000,1001],"input":"/tmp","curioApi":"http://x"}`, http.StatusBadRequest, "single provider"},
+		{"settlement without target", http.MethodPost, "/v1/settlements", `{}`, http.StatusBadRequest, "one of provider or allocationId"},
+		{"bad deal ID", http.MethodGet, "/v1/curio/deals/nope", "", http.StatusBadRequest, "invalid deal ID"},
+		{"deal without Curio API", http.MethodGet, "/v1/curio/deals/01HZ0000000000000000000000", "", http.StatusBadRequest, "provider or curioApi"},
+		{"unknown job", http.MethodGet, "/v1/jobs/nope", "", http.StatusNotFound, "not found"},
+		{"unknown route", http.MethodGet, "/v2/anything", "", http.StatusNotFound, "no route"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, s, tt.method, tt.path, testToken, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			var body ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("error body is not JSON: %v", err)
+			}
+			if !strings.Contains(body.Error, tt.errContains) {
+				t.Errorf("error = %q, want it to contain %q", body.Error, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestResolveInput(t *testing.T) {
+	s := newTestServer(t, true)
+	root, err := filepath.EvalSymlinks(s.cfg.InputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "data", "set"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "key"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "key"), filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "data"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string // empty when rejected
+	}{
+		{"relative", "data/set", filepath.Join(root, "data", "set")},
+		{"absolute", filepath.Join(root, "data"), filepath.Join(root, "data")},
+		{"the directory itself", ".", root},
+		{"symlink inside", "link/set", filepath.Join(root, "data", "set")},
+		{"symlink escaping", "escape", ""},
+		{"dot dot", "../" + filepath.Base(outside), ""},
+		{"absolute outside", filepath.Join(outside, "key"), ""},
+		{"missing", "data/missing", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.resolveInput(tt.input)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("resolveInput(%q) = %q, want an error", tt.input, got)
+				}
+				// Missing and forbidden paths are indistinguishable
+				if !strings.Contains(err.Error(), "not an allowed path") {
+					t.Errorf("error = %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveInput(%q): %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveInput(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	s.cfg.InputDir = ""
+	if _, err := s.resolveInput("data"); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("error without an input directory = %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/curio"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// JobStatus is the state of an asynchronous job
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is an operation that runs in the background, such as data preparation and upload.
+// Result is set once the job has finished; a failed job may carry a partial result.
+type Job struct {
+	Id         string      `json:"id"`
+	Kind       string      `json:"kind"`
+	Status     JobStatus   `json:"status"`
+	Progress   string      `json:"progress,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	StartedAt  *time.Time  `json:"startedAt,omitempty"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+}
+
+// QuoteRequest is the body of POST /v1/quotes
+type QuoteRequest struct {
+	Provider   uint64 `json:"provider"`
+	Token      string `json:"token,omitempty"`
+	DataSize   uint64 `json:"dataSize"`
+	TermLength int64  `json:"termLength,omitempty"`
+	TermMax    int64  `json:"termMax,omitempty"`
+	From       string `json:"from,omitempty"`
+}
+
+// AllocationRequest is the body of POST /v1/allocations. The data is either a file or
+// folder on the server, prepared into a CAR, or a piece prepared elsewhere.
+type AllocationRequest struct {
+	Providers        []uint64 `json:"providers"`
+	Input            string   `json:"input,omitempty"`
+	PieceCid         string   `json:"pieceCid,omitempty"`
+	PieceSize        uint64   `json:"pieceSize,omitempty"`
+	CarSize          uint64   `json:"carSize,omitempty"`
+	DownloadURL      string   `json:"downloadUrl,omitempty"`
+	PaymentToken     string   `json:"paymentToken,omitempty"`
+	TermMin          int64    `json:"termMin,omitempty"`
+	TermMax          int64    `json:"termMax,omitempty"`
+	ExpirationOffset int64    `json:"expirationOffset,omitempty"`
+	SkipPaymentSetup bool     `json:"skipPaymentSetup,omitempty"`
+	CurioUpload      bool     `json:"curioUpload,omitempty"`
+	CurioAPI         string   `json:"curioApi,omitempty"` // discovered per provider when empty
+}
+
+// AllocationJobResult is the result of an allocation job
+type AllocationJobResult struct {
+	Piece    *types.PreparedPiece  `json:"piece,omitempty"`
+	Replicas []types.ReplicaResult `json:"replicas"`
+}
+
+// SettlementRequest is the body of POST /v1/settlements. Without an allocation ID, all
+// allocations of the provider are settled.
+type SettlementRequest struct {
+	Provider     uint64 `json:"provider,omitempty"`
+	AllocationId uint64 `json:"allocationId,omitempty"`
+	UntilEpoch   uint64 `json:"untilEpoch,omitempty"` // defaults to the current epoch
+}
+
+// PaymentsAccount is a payments account with its runway and the DDO contract's
+// operator approval
+type PaymentsAccount struct {
+	types.AccountDetails
+	Health           *types.AccountHealth    `json:"health"`
+	OperatorApproval *types.OperatorApproval `json:"operatorApproval"`
+	Operator         common.Address          `json:"operator"`
+}
+
+// CurioDealStatus is the status of a deal as reported by a provider's Curio
+type CurioDealStatus struct {
+	DealId   string                           `json:"dealId"`
+	CurioAPI string                           `json:"curioApi"`
+	Status   *curio.DealProductStatusResponse `json:"status"`
+}
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
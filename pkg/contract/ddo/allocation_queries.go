@@ -8,6 +8,7 @@ import (
 
 	"github.com/Eastore-project/ddo-client/pkg/types"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -104,7 +105,11 @@ func (c *Client) GetAllocationInfo(allocationId uint64) (*types.AllocationInfo,
 	if err != nil {
 		return nil, fmt.Errorf("failed to call allocationInfos: %w", err)
 	}
+	return decodeAllocationInfo(result)
+}
 
+// decodeAllocationInfo converts the outputs of allocationInfos
+func decodeAllocationInfo(result []interface{}) (*types.AllocationInfo, error) {
 	// allocationInfos returns 9 individual values (not a struct tuple)
 	if len(result) < 9 {
 		return nil, fmt.Errorf("unexpected number of results from allocationInfos: %d", len(result))
@@ -125,6 +130,77 @@ func (c *Client) GetAllocationInfo(allocationId uint64) (*types.AllocationInfo,
 	return info, nil
 }
 
+// getClaimsReturn mirrors VerifRegTypes.GetClaimsReturn with the field names go-ethereum
+// derives from the ABI, so the decoded tuple converts to it directly
+type getClaimsReturn struct {
+	BatchInfo struct {
+		SuccessCount uint32
+		FailCodes    []struct {
+			Idx  uint32
+			Code uint32
+		}
+	}
+	Claims []struct {
+		Provider  uint64
+		Client    uint64
+		Data      []byte
+		Size      uint64
+		TermMin   int64
+		TermMax   int64
+		TermStart int64
+		Sector    uint64
+	}
+}
+
+// decodeGetClaimsReturn converts the raw getClaimInfo result into claims
+func decodeGetClaimsReturn(raw interface{}) (claims []types.Claim, err error) {
+	// abi.ConvertType panics if the layout does not match
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unexpected claims result type %T: %v", raw, r)
+		}
+	}()
+
+	decoded := *abi.ConvertType(raw, new(getClaimsReturn)).(*getClaimsReturn)
+
+	claims = make([]types.Claim, len(decoded.Claims))
+	for i, cl := range decoded.Claims {
+		claims[i] = types.Claim{
+			Provider:  cl.Provider,
+			Client:    cl.Client,
+			Data:      cl.Data,
+			Size:      cl.Size,
+			TermMin:   cl.TermMin,
+			TermMax:   cl.TermMax,
+			TermStart: cl.TermStart,
+			Sector:    cl.Sector,
+		}
+	}
+	return claims, nil
+}
+
+// GetClaimInfo gets a provider's claim from the verified registry. Claims keep the ID
+// of the allocation they were made from. It returns nil if the claim does not exist.
+func (c *Client) GetClaimInfo(providerId, claimId uint64) (*types.Claim, error) {
+	var result []interface{}
+	err := c.contract.Call(&bind.CallOpts{Context: context.Background()}, &result, "getClaimInfo", providerId, claimId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getClaimInfo: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("empty result from getClaimInfo")
+	}
+
+	claims, err := decodeGetClaimsReturn(result[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(claims) == 0 {
+		return nil, nil
+	}
+	return &claims[0], nil
+}
+
 // Legacy function kept for backwards compatibility
 // GetClaimInfoForClient gets claim information for a specific client address and claim ID
 func (c *Client) GetClaimInfoForClient(clientAddress string, claimId uint64) ([]types.Claim, error) {
@@ -0,0 +1,55 @@
+package ddo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func TestDecodeGetClaimsReturn(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(DDOClientABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := parsed.Methods["getClaimInfo"].Outputs
+
+	var in getClaimsReturn
+	in.BatchInfo.SuccessCount = 1
+	in.Claims = append(in.Claims, struct {
+		Provider  uint64
+		Client    uint64
+		Data      []byte
+		Size      uint64
+		TermMin   int64
+		TermMax   int64
+		TermStart int64
+		Sector    uint64
+	}{Provider: 1000, Client: 2000, Data: []byte{0x01, 0x02}, Size: 2048, TermMin: 100, TermMax: 200, TermStart: 50, Sector: 7})
+
+	packed, err := outputs.Pack(in)
+	if err != nil {
+		t.Fatalf("pack: %v", err)
+	}
+	unpacked, err := outputs.Unpack(packed)
+	if err != nil {
+		t.Fatalf("unpack: %v", err)
+	}
+
+	claims, err := decodeGetClaimsReturn(unpacked[0])
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(claims) != 1 {
+		t.Fatalf("got %d claims, want 1", len(claims))
+	}
+	got := claims[0]
+	if got.Provider != 1000 || got.Client != 2000 || got.Size != 2048 || got.TermStart != 50 || got.TermMax != 200 || got.Sector != 7 || !bytes.Equal(got.Data, []byte{0x01, 0x02}) {
+		t.Fatalf("unexpected claim: %+v", got)
+	}
+
+	if _, err := decodeGetClaimsReturn("not a tuple"); err == nil {
+		t.Fatalf("expected error for unexpected result type")
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"math/big"
 	"strings"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 
 	ddotypes "github.com/Eastore-project/ddo-client/pkg/types"
@@ -16,7 +18,7 @@ import (
 func (c *Client) CreateAllocationRequests(pieceInfos []ddotypes.PieceInfo) (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "createAllocationRequests", pieceInfos)
+	tx, err := c.transact(c.auth, "createAllocationRequests", pieceInfos)
 	if err != nil {
 		return "", fmt.Errorf("failed to send transaction: %w", err)
 	}
@@ -24,6 +26,27 @@ func (c *Client) CreateAllocationRequests(pieceInfos []ddotypes.PieceInfo) (stri
 	return tx.Hash().Hex(), nil
 }
 
+// EstimateCreateAllocationRequestsGas estimates the gas of a createAllocationRequests call
+// sent from the given address. No signing key is needed; the estimate fails if the call
+// would revert for that sender, e.g. when its payments are not set up.
+func (c *Client) EstimateCreateAllocationRequestsGas(from common.Address, pieceInfos []ddotypes.PieceInfo) (uint64, error) {
+	data, err := c.abi.Pack("createAllocationRequests", pieceInfos)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pack createAllocationRequests call: %w", err)
+	}
+
+	gas, err := c.ethClient.EstimateGas(context.Background(), ethereum.CallMsg{
+		From: from,
+		To:   &c.contractAddr,
+		Data: data,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	return gas, nil
+}
+
 // allocationCreatedEventID holds the parsed AllocationCreated event from the ABI.
 var allocationCreatedEventID abi.Event
 
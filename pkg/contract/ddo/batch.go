@@ -0,0 +1,130 @@
+package ddo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Eastore-project/ddo-client/pkg/multicall"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// callBatch calls method once per argument list through Multicall3 or JSON-RPC
+// batches and returns the unpacked outputs or the error of each call
+func (c *Client) callBatch(method string, args [][]interface{}) ([][]interface{}, []error) {
+	outputs := make([][]interface{}, len(args))
+	errs := make([]error, len(args))
+
+	calls := make([]multicall.Call, 0, len(args))
+	index := make([]int, 0, len(args)) // position in args of each call
+	for i, a := range args {
+		data, err := c.abi.Pack(method, a...)
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to pack %s: %w", method, err)
+			continue
+		}
+		calls = append(calls, multicall.Call{To: c.contractAddr, Data: data})
+		index = append(index, i)
+	}
+
+	for k, r := range c.batch.Call(context.Background(), calls) {
+		i := index[k]
+		if r.Err != nil {
+			errs[i] = fmt.Errorf("failed to call %s: %w", method, r.Err)
+			continue
+		}
+		if outputs[i], errs[i] = c.abi.Unpack(method, r.Data); errs[i] != nil {
+			errs[i] = fmt.Errorf("failed to unpack %s: %w", method, errs[i])
+		}
+	}
+	return outputs, errs
+}
+
+// GetAllocationInfos batches GetAllocationInfo for many allocations. The infos are
+// returned in the order of allocationIds; the first failure fails the whole batch.
+func (c *Client) GetAllocationInfos(allocationIds []uint64) ([]*types.AllocationInfo, error) {
+	outputs, errs := c.callBatch("allocationInfos", uint64Args(allocationIds))
+
+	infos := make([]*types.AllocationInfo, len(allocationIds))
+	for i, id := range allocationIds {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("allocation %d: %w", id, errs[i])
+		}
+		info, err := decodeAllocationInfo(outputs[i])
+		if err != nil {
+			return nil, fmt.Errorf("allocation %d: %w", id, err)
+		}
+		infos[i] = info
+	}
+	return infos, nil
+}
+
+// GetAllocationRailInfos batches GetAllocationRailInfo for many allocations. The rails
+// are returned in the order of allocationIds; the first failure fails the whole batch.
+func (c *Client) GetAllocationRailInfos(allocationIds []uint64) ([]types.AllocationRailInfo, error) {
+	outputs, errs := c.callBatch("getAllocationRailInfo", uint64Args(allocationIds))
+
+	rails := make([]types.AllocationRailInfo, len(allocationIds))
+	for i, id := range allocationIds {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("allocation %d: %w", id, errs[i])
+		}
+		railId, providerId, railView, err := decodeAllocationRailInfo(outputs[i])
+		if err != nil {
+			return nil, fmt.Errorf("allocation %d: %w", id, err)
+		}
+		rails[i] = types.AllocationRailInfo{AllocationId: id, RailId: railId, ProviderId: providerId, Rail: railView}
+	}
+	return rails, nil
+}
+
+// GetSPConfigs batches GetSPConfig for many storage providers. Configs and errors are
+// returned in the order of actorIds; an unregistered SP has a nil config and no error, and
+// an SP whose supported tokens cannot be read has a nil config and that error.
+func (c *Client) GetSPConfigs(actorIds []uint64) ([]*types.SPConfig, []error) {
+	args := uint64Args(actorIds)
+	configOutputs, errs := c.callBatch("spConfigs", args)
+	tokenOutputs, tokenErrs := c.callBatch("getSPSupportedTokens", args)
+
+	configs := make([]*types.SPConfig, len(actorIds))
+	for i := range actorIds {
+		if errs[i] != nil {
+			continue
+		}
+		configs[i], errs[i] = decodeSPConfig(configOutputs[i])
+		if configs[i] == nil {
+			continue
+		}
+
+		if tokenErrs[i] != nil {
+			configs[i], errs[i] = nil, fmt.Errorf("failed to get supported tokens: %w", tokenErrs[i])
+			continue
+		}
+		configs[i].SupportedTokens = []types.TokenConfig{}
+		if tokens := decodeSupportedTokens(tokenOutputs[i]); tokens != nil {
+			configs[i].SupportedTokens = tokens
+		}
+	}
+	return configs, errs
+}
+
+// GetSPsAllTokenPricesPerMonth batches GetSPAllTokenPricesPerMonth for many storage
+// providers. Prices and errors are returned in the order of actorIds.
+func (c *Client) GetSPsAllTokenPricesPerMonth(actorIds []uint64) ([][]types.SPTokenPrice, []error) {
+	outputs, errs := c.callBatch("getSPAllTokenPricesPerMonth", uint64Args(actorIds))
+
+	prices := make([][]types.SPTokenPrice, len(actorIds))
+	for i := range actorIds {
+		if errs[i] == nil {
+			prices[i], errs[i] = decodeSPTokenPrices(outputs[i])
+		}
+	}
+	return prices, errs
+}
+
+func uint64Args(ids []uint64) [][]interface{} {
+	args := make([][]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = []interface{}{id}
+	}
+	return args
+}
@@ -0,0 +1,106 @@
+package ddo
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Eastore-project/ddo-client/pkg/multicall"
+)
+
+// spBackend answers spConfigs and getSPSupportedTokens with one call per request:
+// SP 1 is registered with one token, SP 2 is not registered, calls for SP 3 fail and
+// SP 4 is registered but its supported tokens cannot be read
+type spBackend struct {
+	t   *testing.T
+	abi abi.ABI
+}
+
+func (b *spBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *spBackend) BatchCallContext(context.Context, []rpc.BatchElem) error {
+	return errors.New("batch requests are not supported")
+}
+
+func (b *spBackend) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := b.abi.MethodById(msg.Data[:4])
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		b.t.Fatal(err)
+	}
+
+	actorId := args[0].(uint64)
+	if actorId == 3 {
+		return nil, errors.New("execution reverted")
+	}
+
+	switch method.Name {
+	case "spConfigs":
+		payment := common.Address{}
+		if actorId == 1 || actorId == 4 {
+			payment = common.HexToAddress("0x55")
+		}
+		return method.Outputs.Pack(payment, uint64(128), uint64(1<<30), int64(100), int64(1000), true)
+	case "getSPSupportedTokens":
+		if actorId == 4 {
+			return nil, errors.New("execution reverted")
+		}
+		type tokenConfig struct {
+			Token                common.Address
+			PricePerBytePerEpoch *big.Int
+			IsActive             bool
+		}
+		return method.Outputs.Pack([]tokenConfig{{Token: common.HexToAddress("0x77"), PricePerBytePerEpoch: big.NewInt(9), IsActive: true}})
+	}
+	b.t.Fatalf("unexpected call to %s", method.Name)
+	return nil, nil
+}
+
+func TestGetSPConfigs(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(DDOClientABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		contractAddr: common.HexToAddress("0xdd0"),
+		abi:          parsed,
+		batch:        multicall.New(&spBackend{t: t, abi: parsed}, multicall.DefaultOptions()),
+	}
+
+	configs, errs := c.GetSPConfigs([]uint64{1, 2, 3, 4})
+	if len(configs) != 4 || len(errs) != 4 {
+		t.Fatalf("got %d configs and %d errors, want 4", len(configs), len(errs))
+	}
+
+	if errs[0] != nil || configs[0] == nil {
+		t.Fatalf("SP 1: config = %v, err = %v", configs[0], errs[0])
+	}
+	if configs[0].PaymentAddress != common.HexToAddress("0x55") || configs[0].MaxPieceSize != 1<<30 || !configs[0].IsActive {
+		t.Errorf("SP 1 config = %+v", configs[0])
+	}
+	if len(configs[0].SupportedTokens) != 1 || configs[0].SupportedTokens[0].PricePerBytePerEpoch.Int64() != 9 {
+		t.Errorf("SP 1 tokens = %+v", configs[0].SupportedTokens)
+	}
+
+	if errs[1] != nil || configs[1] != nil {
+		t.Errorf("SP 2: config = %v, err = %v, want an unregistered SP", configs[1], errs[1])
+	}
+	if errs[2] == nil {
+		t.Error("SP 3: expected the call error")
+	}
+	if errs[3] == nil || configs[3] != nil {
+		t.Errorf("SP 4: config = %v, err = %v, want the token read error", configs[3], errs[3])
+	}
+}
@@ -9,8 +9,13 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/Eastore-project/ddo-client/pkg/multicall"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
 )
 
 type Client struct {
@@ -18,13 +23,15 @@ type Client struct {
 	contract     *bind.BoundContract
 	contractAddr common.Address
 	auth         *bind.TransactOpts
+	txm          *txmgr.Manager
 	abi          abi.ABI
+	batch        *multicall.Caller
 	ownsClient   bool
 }
 
 // NewClientWithParams creates a new contract client with specific parameters
-func NewClientWithParams(rpcEndpoint, contractAddress, privateKey string) (*Client, error) {
-	client, err := ethclient.Dial(rpcEndpoint)
+func NewClientWithParams(rpcEndpoint, contractAddress, privateKey string, rpcCfg rpcclient.Config, txCfg txmgr.Config) (*Client, error) {
+	client, err := rpcclient.Dial(rpcEndpoint, rpcCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
 	}
@@ -57,21 +64,27 @@ func NewClientWithParams(rpcEndpoint, contractAddress, privateKey string) (*Clie
 		contract:     contract,
 		contractAddr: addr,
 		auth:         auth,
+		txm:          txmgr.New(client, txCfg),
 		abi:          parsedABI,
+		batch:        multicall.NewFromClient(client, multicall.DefaultOptions()),
 		ownsClient:   true,
 	}, nil
 }
 
 // NewClientWithTransactor creates a client using an existing ethclient and
 // pre-built TransactOpts. The caller retains ownership of ethClient and
-// must close it separately; calling Close on this client is a no-op.
-func NewClientWithTransactor(ethClient *ethclient.Client, contractAddress string, auth *bind.TransactOpts) (*Client, error) {
+// must close it separately; calling Close on this client is a no-op. txm is the
+// transaction manager of ethClient, shared by every client sending over it.
+func NewClientWithTransactor(ethClient *ethclient.Client, contractAddress string, auth *bind.TransactOpts, txm *txmgr.Manager) (*Client, error) {
 	if ethClient == nil {
 		return nil, fmt.Errorf("ethClient must not be nil")
 	}
 	if auth == nil {
 		return nil, fmt.Errorf("auth must not be nil")
 	}
+	if txm == nil {
+		return nil, fmt.Errorf("txm must not be nil")
+	}
 
 	parsedABI, err := abi.JSON(strings.NewReader(DDOClientABI))
 	if err != nil {
@@ -86,12 +99,14 @@ func NewClientWithTransactor(ethClient *ethclient.Client, contractAddress string
 		contract:     contract,
 		contractAddr: addr,
 		auth:         auth,
+		txm:          txm,
 		abi:          parsedABI,
+		batch:        multicall.NewFromClient(ethClient, multicall.DefaultOptions()),
 	}, nil
 }
 
 // NewReadOnlyClientWithParams creates a new read-only contract client with specific parameters
-func NewReadOnlyClientWithParams(rpcEndpoint, contractAddress string) (*Client, error) {
+func NewReadOnlyClientWithParams(rpcEndpoint, contractAddress string, rpcCfg rpcclient.Config) (*Client, error) {
 	if contractAddress == "" {
 		return nil, fmt.Errorf("contract address not set")
 	}
@@ -99,7 +114,7 @@ func NewReadOnlyClientWithParams(rpcEndpoint, contractAddress string) (*Client,
 		return nil, fmt.Errorf("RPC endpoint not set")
 	}
 
-	client, err := ethclient.Dial(rpcEndpoint)
+	client, err := rpcclient.Dial(rpcEndpoint, rpcCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ethereum client: %w", err)
 	}
@@ -117,6 +132,7 @@ func NewReadOnlyClientWithParams(rpcEndpoint, contractAddress string) (*Client,
 		contract:     boundContract,
 		contractAddr: addr,
 		abi:          parsedABI,
+		batch:        multicall.NewFromClient(client, multicall.DefaultOptions()),
 		ownsClient:   true,
 	}, nil
 }
@@ -149,11 +165,27 @@ func (c *Client) Close() {
 	}
 }
 
+// transact sends a contract call through the client's transaction manager
+func (c *Client) transact(opts *bind.TransactOpts, method string, params ...interface{}) (*ethtypes.Transaction, error) {
+	if c.txm == nil {
+		return nil, fmt.Errorf("client not configured for transactions (read-only mode)")
+	}
+	return c.txm.Send(context.Background(), opts, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return c.contract.Transact(opts, method, params...)
+	})
+}
+
 // GetEthClient returns the underlying Ethereum client
 func (c *Client) GetEthClient() *ethclient.Client {
 	return c.ethClient
 }
 
+// TxManager returns the transaction manager the client sends with, nil for a
+// read-only client
+func (c *Client) TxManager() *txmgr.Manager {
+	return c.txm
+}
+
 // GetAllSPIds returns all registered SP actor IDs from the ViewFacet
 func (c *Client) GetAllSPIds() ([]uint64, error) {
 	var result []interface{}
@@ -178,7 +210,7 @@ func (c *Client) GetAllSPIds() ([]uint64, error) {
 func (c *Client) DeactivateSP(actorId uint64) (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "deactivateSP", actorId)
+	tx, err := c.transact(c.auth, "deactivateSP", actorId)
 	if err != nil {
 		return "", fmt.Errorf("failed to deactivate SP: %w", err)
 	}
@@ -190,7 +222,7 @@ func (c *Client) DeactivateSP(actorId uint64) (string, error) {
 func (c *Client) RemoveSPToken(actorId uint64, token common.Address) (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "removeSPToken", actorId, token)
+	tx, err := c.transact(c.auth, "removeSPToken", actorId, token)
 	if err != nil {
 		return "", fmt.Errorf("failed to remove SP token: %w", err)
 	}
@@ -202,7 +234,7 @@ func (c *Client) RemoveSPToken(actorId uint64, token common.Address) (string, er
 func (c *Client) SetPaymentsContract(addr common.Address) (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "setPaymentsContract", addr)
+	tx, err := c.transact(c.auth, "setPaymentsContract", addr)
 	if err != nil {
 		return "", fmt.Errorf("failed to set payments contract: %w", err)
 	}
@@ -214,7 +246,7 @@ func (c *Client) SetPaymentsContract(addr common.Address) (string, error) {
 func (c *Client) SetCommissionRate(bps *big.Int) (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "setCommissionRate", bps)
+	tx, err := c.transact(c.auth, "setCommissionRate", bps)
 	if err != nil {
 		return "", fmt.Errorf("failed to set commission rate: %w", err)
 	}
@@ -226,7 +258,7 @@ func (c *Client) SetCommissionRate(bps *big.Int) (string, error) {
 func (c *Client) SetAllocationLockupAmount(amount *big.Int) (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "setAllocationLockupAmount", amount)
+	tx, err := c.transact(c.auth, "setAllocationLockupAmount", amount)
 	if err != nil {
 		return "", fmt.Errorf("failed to set allocation lockup amount: %w", err)
 	}
@@ -238,7 +270,7 @@ func (c *Client) SetAllocationLockupAmount(amount *big.Int) (string, error) {
 func (c *Client) Pause() (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "pause")
+	tx, err := c.transact(c.auth, "pause")
 	if err != nil {
 		return "", fmt.Errorf("failed to pause contract: %w", err)
 	}
@@ -250,7 +282,7 @@ func (c *Client) Pause() (string, error) {
 func (c *Client) Unpause() (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "unpause")
+	tx, err := c.transact(c.auth, "unpause")
 	if err != nil {
 		return "", fmt.Errorf("failed to unpause contract: %w", err)
 	}
@@ -282,7 +314,7 @@ func (c *Client) Paused() (bool, error) {
 func (c *Client) BlacklistSector(providerId uint64, sectorNumber uint64, blacklisted bool) (string, error) {
 	c.auth.Context = context.Background()
 
-	tx, err := c.contract.Transact(c.auth, "blacklistSector", providerId, sectorNumber, blacklisted)
+	tx, err := c.transact(c.auth, "blacklistSector", providerId, sectorNumber, blacklisted)
 	if err != nil {
 		return "", fmt.Errorf("failed to blacklist sector: %w", err)
 	}
@@ -0,0 +1,108 @@
+package ddo
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// logChunkSize is the block range queried per eth_getLogs call.
+// Public Filecoin endpoints reject ranges larger than 2880 epochs.
+const logChunkSize = 2000
+
+// GetAllocationActivatedEvents returns AllocationActivated events between fromBlock and toBlock (inclusive)
+func (c *Client) GetAllocationActivatedEvents(fromBlock, toBlock uint64) ([]types.AllocationActivatedEvent, error) {
+	event := c.abi.Events["AllocationActivated"]
+	logs, err := c.filterLogsChunked([][]common.Hash{{event.ID}}, fromBlock, toBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AllocationActivated events: %w", err)
+	}
+
+	events := make([]types.AllocationActivatedEvent, 0, len(logs))
+	for _, l := range logs {
+		values, err := c.abi.Unpack("AllocationActivated", l.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack AllocationActivated event: %w", err)
+		}
+		// Non-indexed order: [sector, railId, paymentRate]
+		events = append(events, types.AllocationActivatedEvent{
+			AllocationId: new(big.Int).SetBytes(l.Topics[1].Bytes()).Uint64(),
+			Provider:     new(big.Int).SetBytes(l.Topics[2].Bytes()).Uint64(),
+			Sector:       values[0].(uint64),
+			RailId:       values[1].(*big.Int),
+			PaymentRate:  values[2].(*big.Int),
+			BlockNumber:  l.BlockNumber,
+			TxHash:       l.TxHash,
+			LogIndex:     l.Index,
+		})
+	}
+
+	return events, nil
+}
+
+// GetRailCreatedEvents returns the RailCreated events of allocation payment rails between
+// fromBlock and toBlock (inclusive)
+func (c *Client) GetRailCreatedEvents(fromBlock, toBlock uint64) ([]types.DDORailCreatedEvent, error) {
+	event := c.abi.Events["RailCreated"]
+	logs, err := c.filterLogsChunked([][]common.Hash{{event.ID}}, fromBlock, toBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RailCreated events: %w", err)
+	}
+
+	events := make([]types.DDORailCreatedEvent, 0, len(logs))
+	for _, l := range logs {
+		values, err := c.abi.Unpack("RailCreated", l.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack RailCreated event: %w", err)
+		}
+		// Non-indexed order: [railId, providerId, allocationId]
+		events = append(events, types.DDORailCreatedEvent{
+			Client:          common.BytesToAddress(l.Topics[1].Bytes()),
+			StorageProvider: common.BytesToAddress(l.Topics[2].Bytes()),
+			Token:           common.BytesToAddress(l.Topics[3].Bytes()),
+			RailId:          values[0].(*big.Int),
+			ProviderId:      values[1].(uint64),
+			AllocationId:    values[2].(uint64),
+			BlockNumber:     l.BlockNumber,
+			TxHash:          l.TxHash,
+			LogIndex:        l.Index,
+		})
+	}
+
+	return events, nil
+}
+
+// filterLogsChunked queries logs emitted by the DDO contract in block ranges of logChunkSize
+func (c *Client) filterLogsChunked(topics [][]common.Hash, fromBlock, toBlock uint64) ([]ethtypes.Log, error) {
+	var all []ethtypes.Log
+	for start := fromBlock; start <= toBlock; start += logChunkSize {
+		end := start + logChunkSize - 1
+		if end > toBlock {
+			end = toBlock
+		}
+
+		logs, err := c.ethClient.FilterLogs(context.Background(), ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: []common.Address{c.contractAddr},
+			Topics:    topics,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("blocks %d-%d: %w", start, end, err)
+		}
+
+		for _, l := range logs {
+			if !l.Removed {
+				all = append(all, l)
+			}
+		}
+	}
+
+	return all, nil
+}
@@ -17,7 +17,11 @@ func (c *Client) GetAllocationRailInfo(allocationId uint64) (uint64, uint64, *ty
 	if err != nil {
 		return 0, 0, nil, fmt.Errorf("failed to call getAllocationRailInfo: %w", err)
 	}
+	return decodeAllocationRailInfo(results)
+}
 
+// decodeAllocationRailInfo converts the outputs of getAllocationRailInfo
+func decodeAllocationRailInfo(results []interface{}) (uint64, uint64, *types.RailView, error) {
 	if len(results) < 3 {
 		return 0, 0, nil, fmt.Errorf("unexpected number of results from getAllocationRailInfo")
 	}
@@ -65,7 +69,7 @@ func (c *Client) SettleSpPayment(allocationId uint64, untilEpoch *big.Int) (stri
 		return "", fmt.Errorf("client not configured for transactions (no private key)")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "settleSpPayment", allocationId, untilEpoch)
+	tx, err := c.transact(c.auth, "settleSpPayment", allocationId, untilEpoch)
 	if err != nil {
 		return "", fmt.Errorf("failed to call settleSpPayment: %w", err)
 	}
@@ -80,7 +84,7 @@ func (c *Client) SettleSpTotalPayment(providerId uint64, untilEpoch *big.Int, st
 		return "", fmt.Errorf("client not configured for transactions (no private key)")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "settleSpTotalPayment", providerId, untilEpoch, startIndex, batchSize)
+	tx, err := c.transact(c.auth, "settleSpTotalPayment", providerId, untilEpoch, startIndex, batchSize)
 	if err != nil {
 		return "", fmt.Errorf("failed to call settleSpTotalPayment: %w", err)
 	}
@@ -61,7 +61,11 @@ func (c *Client) GetSPSupportedTokensFromContract(actorId uint64) ([]types.Token
 	if err != nil {
 		return nil, fmt.Errorf("failed to call getSPSupportedTokens: %w", err)
 	}
+	return decodeSupportedTokens(supportedTokensRaw), nil
+}
 
+// decodeSupportedTokens converts the outputs of getSPSupportedTokens
+func decodeSupportedTokens(supportedTokensRaw []interface{}) []types.TokenConfig {
 	// Parse supported tokens - the result is an array containing one element which is the TokenConfig array
 	var supportedTokens []types.TokenConfig
 	if len(supportedTokensRaw) > 0 {
@@ -84,7 +88,51 @@ func (c *Client) GetSPSupportedTokensFromContract(actorId uint64) ([]types.Token
 		}
 	}
 
-	return supportedTokens, nil
+	return supportedTokens
+}
+
+// GetSPAllTokenPricesPerMonth gets the price per TB per month of every token a storage provider supports
+func (c *Client) GetSPAllTokenPricesPerMonth(actorId uint64) ([]types.SPTokenPrice, error) {
+	var result []interface{}
+	err := c.contract.Call(nil, &result, "getSPAllTokenPricesPerMonth", actorId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getSPAllTokenPricesPerMonth: %w", err)
+	}
+	return decodeSPTokenPrices(result)
+}
+
+// decodeSPTokenPrices converts the outputs of getSPAllTokenPricesPerMonth
+func decodeSPTokenPrices(result []interface{}) ([]types.SPTokenPrice, error) {
+
+	if len(result) < 3 {
+		return nil, fmt.Errorf("unexpected result length: expected 3, got %d", len(result))
+	}
+
+	tokens, ok := result[0].([]common.Address)
+	if !ok {
+		return nil, fmt.Errorf("invalid tokens type: %T", result[0])
+	}
+	prices, ok := result[1].([]*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("invalid prices type: %T", result[1])
+	}
+	active, ok := result[2].([]bool)
+	if !ok {
+		return nil, fmt.Errorf("invalid active status type: %T", result[2])
+	}
+	if len(prices) != len(tokens) || len(active) != len(tokens) {
+		return nil, fmt.Errorf("mismatched result lengths: %d tokens, %d prices, %d active flags", len(tokens), len(prices), len(active))
+	}
+
+	tokenPrices := make([]types.SPTokenPrice, len(tokens))
+	for i := range tokens {
+		tokenPrices[i] = types.SPTokenPrice{
+			Token:              tokens[i],
+			PricePerTBPerMonth: prices[i],
+			IsActive:           active[i],
+		}
+	}
+	return tokenPrices, nil
 }
 
 // GetSPConfig retrieves the storage provider configuration using the public spConfigs mapping
@@ -97,6 +145,25 @@ func (c *Client) GetSPConfig(actorId uint64) (*types.SPConfig, error) {
 		return nil, fmt.Errorf("failed to call spConfigs: %w", err)
 	}
 
+	config, err := decodeSPConfig(result)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	// Get supported tokens using the dedicated function
+	supportedTokens, err := c.GetSPSupportedTokensFromContract(actorId)
+	if err != nil {
+		log.Debugw("failed to get supported tokens", "error", err)
+		supportedTokens = []types.TokenConfig{}
+	}
+	config.SupportedTokens = supportedTokens
+
+	return config, nil
+}
+
+// decodeSPConfig converts the outputs of spConfigs, without the supported tokens. An
+// unregistered SP returns nil.
+func decodeSPConfig(result []interface{}) (*types.SPConfig, error) {
 	// Based on debug output, we expect 6 fields:
 	// [0] paymentAddress, [1] minPieceSize, [2] maxPieceSize, [3] minTermLength, [4] maxTermLength, [5] isActive
 	if len(result) < 6 {
@@ -140,21 +207,13 @@ func (c *Client) GetSPConfig(actorId uint64) (*types.SPConfig, error) {
 		return nil, fmt.Errorf("invalid is active type: %T", result[5])
 	}
 
-	// Get supported tokens using the dedicated function
-	supportedTokens, err := c.GetSPSupportedTokensFromContract(actorId)
-	if err != nil {
-		log.Debugw("failed to get supported tokens", "error", err)
-		supportedTokens = []types.TokenConfig{}
-	}
-
 	config := &types.SPConfig{
-		PaymentAddress:  paymentAddress,
-		MinPieceSize:    minPieceSize,
-		MaxPieceSize:    maxPieceSize,
-		MinTermLength:   minTermLength,
-		MaxTermLength:   maxTermLength,
-		SupportedTokens: supportedTokens,
-		IsActive:        isActive,
+		PaymentAddress: paymentAddress,
+		MinPieceSize:   minPieceSize,
+		MaxPieceSize:   maxPieceSize,
+		MinTermLength:  minTermLength,
+		MaxTermLength:  maxTermLength,
+		IsActive:       isActive,
 	}
 
 	return config, nil
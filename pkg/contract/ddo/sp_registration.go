@@ -34,7 +34,7 @@ func (c *Client) RegisterSP(params types.SPRegistrationParams) (string, error) {
 		}
 	}
 
-	tx, err := c.contract.Transact(c.auth, "registerSP",
+	tx, err := c.transact(c.auth, "registerSP",
 		params.ActorId,
 		params.PaymentAddress,
 		params.MinPieceSize,
@@ -56,7 +56,7 @@ func (c *Client) UpdateSPConfig(actorId uint64, paymentAddress common.Address, m
 		return "", fmt.Errorf("client not configured for transactions (read-only mode)")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "updateSPConfig",
+	tx, err := c.transact(c.auth, "updateSPConfig",
 		actorId,
 		paymentAddress,
 		minPieceSize,
@@ -77,7 +77,7 @@ func (c *Client) AddSPToken(actorId uint64, token common.Address, pricePerBytePe
 		return "", fmt.Errorf("client not configured for transactions (read-only mode)")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "addSPToken",
+	tx, err := c.transact(c.auth, "addSPToken",
 		actorId,
 		token,
 		pricePerBytePerEpoch,
@@ -95,7 +95,7 @@ func (c *Client) UpdateSPToken(actorId uint64, token common.Address, pricePerByt
 		return "", fmt.Errorf("client not configured for transactions (read-only mode)")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "updateSPToken",
+	tx, err := c.transact(c.auth, "updateSPToken",
 		actorId,
 		token,
 		pricePerBytePerEpoch,
@@ -0,0 +1,59 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Eastore-project/ddo-client/pkg/multicall"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// callBatch calls method once per argument list through Multicall3 or JSON-RPC
+// batches and returns the unpacked outputs or the error of each call
+func (c *Client) callBatch(method string, args [][]interface{}) ([][]interface{}, []error) {
+	outputs := make([][]interface{}, len(args))
+	errs := make([]error, len(args))
+
+	calls := make([]multicall.Call, 0, len(args))
+	index := make([]int, 0, len(args)) // position in args of each call
+	for i, a := range args {
+		data, err := c.abi.Pack(method, a...)
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to pack %s: %w", method, err)
+			continue
+		}
+		calls = append(calls, multicall.Call{To: c.contractAddr, Data: data})
+		index = append(index, i)
+	}
+
+	for k, r := range c.batch.Call(context.Background(), calls) {
+		i := index[k]
+		if r.Err != nil {
+			errs[i] = fmt.Errorf("failed to call %s: %w", method, r.Err)
+			continue
+		}
+		if outputs[i], errs[i] = c.abi.Unpack(method, r.Data); errs[i] != nil {
+			errs[i] = fmt.Errorf("failed to unpack %s: %w", method, errs[i])
+		}
+	}
+	return outputs, errs
+}
+
+// GetRails batches GetRail for many rails. Rails and errors are returned in the
+// order of railIds.
+func (c *Client) GetRails(railIds []*big.Int) ([]*types.RailView, []error) {
+	args := make([][]interface{}, len(railIds))
+	for i, id := range railIds {
+		args[i] = []interface{}{id}
+	}
+	outputs, errs := c.callBatch("getRail", args)
+
+	rails := make([]*types.RailView, len(railIds))
+	for i := range railIds {
+		if errs[i] == nil {
+			rails[i], errs[i] = decodeRail(outputs[i])
+		}
+	}
+	return rails, errs
+}
@@ -0,0 +1,104 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Eastore-project/ddo-client/pkg/multicall"
+)
+
+// railBackend answers getRail with one call per request; calls for rail 3 fail
+type railBackend struct {
+	t   *testing.T
+	abi abi.ABI
+}
+
+func (b *railBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *railBackend) BatchCallContext(context.Context, []rpc.BatchElem) error {
+	return errors.New("batch requests are not supported")
+}
+
+func (b *railBackend) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := b.abi.MethodById(msg.Data[:4])
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	if method.Name != "getRail" {
+		b.t.Fatalf("unexpected call to %s", method.Name)
+	}
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		b.t.Fatal(err)
+	}
+
+	railId := args[0].(*big.Int)
+	if railId.Int64() == 3 {
+		return nil, errors.New("execution reverted")
+	}
+
+	type rail struct {
+		Token               common.Address
+		From                common.Address
+		To                  common.Address
+		Operator            common.Address
+		Validator           common.Address
+		PaymentRate         *big.Int
+		LockupPeriod        *big.Int
+		LockupFixed         *big.Int
+		SettledUpTo         *big.Int
+		EndEpoch            *big.Int
+		CommissionRateBps   *big.Int
+		ServiceFeeRecipient common.Address
+	}
+	return method.Outputs.Pack(rail{
+		Token:               common.HexToAddress("0x70"),
+		From:                common.HexToAddress("0xc1"),
+		To:                  common.HexToAddress("0xa1"),
+		PaymentRate:         new(big.Int).Mul(railId, big.NewInt(10)),
+		LockupPeriod:        big.NewInt(2880),
+		LockupFixed:         big.NewInt(0),
+		SettledUpTo:         big.NewInt(100),
+		EndEpoch:            big.NewInt(0),
+		CommissionRateBps:   big.NewInt(0),
+		ServiceFeeRecipient: common.Address{},
+	})
+}
+
+func TestGetRails(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(PaymentsABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		contractAddr: common.HexToAddress("0xfa"),
+		abi:          parsed,
+		batch:        multicall.New(&railBackend{t: t, abi: parsed}, multicall.DefaultOptions()),
+	}
+
+	rails, errs := c.GetRails([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	if len(rails) != 3 || len(errs) != 3 {
+		t.Fatalf("got %d rails and %d errors, want 3", len(rails), len(errs))
+	}
+	for i, want := range []int64{10, 20} {
+		if errs[i] != nil || rails[i] == nil {
+			t.Fatalf("rail %d: rail = %v, err = %v", i+1, rails[i], errs[i])
+		}
+		if rails[i].PaymentRate.Int64() != want || rails[i].From != common.HexToAddress("0xc1") {
+			t.Errorf("rail %d = %+v", i+1, rails[i])
+		}
+	}
+	if errs[2] == nil {
+		t.Error("rail 3: expected the call error")
+	}
+}
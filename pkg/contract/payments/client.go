@@ -8,8 +8,13 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/Eastore-project/ddo-client/pkg/multicall"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
 )
 
 type Client struct {
@@ -17,13 +22,15 @@ type Client struct {
 	contract     *bind.BoundContract
 	contractAddr common.Address
 	auth         *bind.TransactOpts
+	txm          *txmgr.Manager
 	abi          abi.ABI
+	batch        *multicall.Caller
 	ownsClient   bool
 }
 
 // NewClientWithParams creates a new payments contract client with specific parameters
-func NewClientWithParams(rpcEndpoint, contractAddress, privateKey string) (*Client, error) {
-	client, err := ethclient.Dial(rpcEndpoint)
+func NewClientWithParams(rpcEndpoint, contractAddress, privateKey string, rpcCfg rpcclient.Config, txCfg txmgr.Config) (*Client, error) {
+	client, err := rpcclient.Dial(rpcEndpoint, rpcCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
 	}
@@ -56,21 +63,27 @@ func NewClientWithParams(rpcEndpoint, contractAddress, privateKey string) (*Clie
 		contract:     contract,
 		contractAddr: addr,
 		auth:         auth,
+		txm:          txmgr.New(client, txCfg),
 		abi:          parsedABI,
+		batch:        multicall.NewFromClient(client, multicall.DefaultOptions()),
 		ownsClient:   true,
 	}, nil
 }
 
 // NewClientWithTransactor creates a client using an existing ethclient and
 // pre-built TransactOpts. The caller retains ownership of ethClient and
-// must close it separately; calling Close on this client is a no-op.
-func NewClientWithTransactor(ethClient *ethclient.Client, contractAddress string, auth *bind.TransactOpts) (*Client, error) {
+// must close it separately; calling Close on this client is a no-op. txm is the
+// transaction manager of ethClient, shared by every client sending over it.
+func NewClientWithTransactor(ethClient *ethclient.Client, contractAddress string, auth *bind.TransactOpts, txm *txmgr.Manager) (*Client, error) {
 	if ethClient == nil {
 		return nil, fmt.Errorf("ethClient must not be nil")
 	}
 	if auth == nil {
 		return nil, fmt.Errorf("auth must not be nil")
 	}
+	if txm == nil {
+		return nil, fmt.Errorf("txm must not be nil")
+	}
 
 	parsedABI, err := abi.JSON(strings.NewReader(PaymentsABI))
 	if err != nil {
@@ -85,12 +98,14 @@ func NewClientWithTransactor(ethClient *ethclient.Client, contractAddress string
 		contract:     contract,
 		contractAddr: addr,
 		auth:         auth,
+		txm:          txm,
 		abi:          parsedABI,
+		batch:        multicall.NewFromClient(ethClient, multicall.DefaultOptions()),
 	}, nil
 }
 
 // NewReadOnlyClientWithParams creates a new read-only payments contract client with specific parameters
-func NewReadOnlyClientWithParams(rpcEndpoint, contractAddress string) (*Client, error) {
+func NewReadOnlyClientWithParams(rpcEndpoint, contractAddress string, rpcCfg rpcclient.Config) (*Client, error) {
 	if contractAddress == "" {
 		return nil, fmt.Errorf("payments contract address not provided")
 	}
@@ -98,7 +113,7 @@ func NewReadOnlyClientWithParams(rpcEndpoint, contractAddress string) (*Client,
 		return nil, fmt.Errorf("RPC endpoint not set")
 	}
 
-	client, err := ethclient.Dial(rpcEndpoint)
+	client, err := rpcclient.Dial(rpcEndpoint, rpcCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ethereum client: %w", err)
 	}
@@ -116,6 +131,7 @@ func NewReadOnlyClientWithParams(rpcEndpoint, contractAddress string) (*Client,
 		contract:     boundContract,
 		contractAddr: contractAddr,
 		abi:          parsedABI,
+		batch:        multicall.NewFromClient(client, multicall.DefaultOptions()),
 		ownsClient:   true,
 	}, nil
 }
@@ -125,11 +141,27 @@ func (c *Client) GetContractAddress() common.Address {
 	return c.contractAddr
 }
 
+// transact sends a contract call through the client's transaction manager
+func (c *Client) transact(opts *bind.TransactOpts, method string, params ...interface{}) (*ethtypes.Transaction, error) {
+	if c.txm == nil {
+		return nil, fmt.Errorf("client not configured for transactions (read-only mode)")
+	}
+	return c.txm.Send(context.Background(), opts, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return c.contract.Transact(opts, method, params...)
+	})
+}
+
 // GetEthClient returns the underlying Ethereum client
 func (c *Client) GetEthClient() *ethclient.Client {
 	return c.ethClient
 }
 
+// TxManager returns the transaction manager the client sends with, nil for a
+// read-only client
+func (c *Client) TxManager() *txmgr.Manager {
+	return c.txm
+}
+
 // Close closes the Ethereum client connection if this client owns it.
 // Clients created with NewClientWithTransactor do not own the connection.
 func (c *Client) Close() {
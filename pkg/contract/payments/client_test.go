@@ -11,6 +11,8 @@ import (
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
 )
 
 func testAuth() *bind.TransactOpts {
@@ -32,7 +34,7 @@ func dialTestServer(t *testing.T) *ethclient.Client {
 }
 
 func TestNewClientWithTransactor_NilEthClient(t *testing.T) {
-	_, err := NewClientWithTransactor(nil, "0xdead", testAuth())
+	_, err := NewClientWithTransactor(nil, "0xdead", testAuth(), nil)
 	if err == nil || !strings.Contains(err.Error(), "ethClient") {
 		t.Fatalf("expected ethClient error, got: %v", err)
 	}
@@ -42,17 +44,27 @@ func TestNewClientWithTransactor_NilAuth(t *testing.T) {
 	ec := dialTestServer(t)
 	defer ec.Close()
 
-	_, err := NewClientWithTransactor(ec, "0xdead", nil)
+	_, err := NewClientWithTransactor(ec, "0xdead", nil, txmgr.New(ec, txmgr.DefaultConfig()))
 	if err == nil || !strings.Contains(err.Error(), "auth") {
 		t.Fatalf("expected auth error, got: %v", err)
 	}
 }
 
+func TestNewClientWithTransactor_NilTxManager(t *testing.T) {
+	ec := dialTestServer(t)
+	defer ec.Close()
+
+	_, err := NewClientWithTransactor(ec, "0xdead", testAuth(), nil)
+	if err == nil || !strings.Contains(err.Error(), "txm") {
+		t.Fatalf("expected txm error, got: %v", err)
+	}
+}
+
 func TestCloseIsNoOpForNonOwningClient(t *testing.T) {
 	ec := dialTestServer(t)
 	defer ec.Close()
 
-	c, err := NewClientWithTransactor(ec, "0xdead", testAuth())
+	c, err := NewClientWithTransactor(ec, "0xdead", testAuth(), txmgr.New(ec, txmgr.DefaultConfig()))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,197 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+const (
+	// DefaultLogChunkSize is the block range queried per eth_getLogs call.
+	// Public Filecoin endpoints reject ranges larger than 2880 epochs.
+	DefaultLogChunkSize = 2000
+	// maxTopicsPerQuery bounds how many rail IDs are OR-ed into a single topic filter
+	maxTopicsPerQuery = 100
+)
+
+// GetRailSettledEvents returns RailSettled events for the given rails between fromBlock and toBlock (inclusive)
+func (c *Client) GetRailSettledEvents(railIds []*big.Int, fromBlock, toBlock uint64) ([]types.RailSettledEvent, error) {
+	logs, err := c.filterRailLogs("RailSettled", railIds, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]types.RailSettledEvent, 0, len(logs))
+	for _, l := range logs {
+		values, err := c.abi.Unpack("RailSettled", l.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack RailSettled event: %w", err)
+		}
+		// Non-indexed order: [totalSettledAmount, totalNetPayeeAmount, operatorCommission, networkFee, settledUpTo]
+		events = append(events, types.RailSettledEvent{
+			RailId:              new(big.Int).SetBytes(l.Topics[1].Bytes()),
+			TotalSettledAmount:  values[0].(*big.Int),
+			TotalNetPayeeAmount: values[1].(*big.Int),
+			OperatorCommission:  values[2].(*big.Int),
+			NetworkFee:          values[3].(*big.Int),
+			SettledUpTo:         values[4].(*big.Int),
+			BlockNumber:         l.BlockNumber,
+			TxHash:              l.TxHash,
+		})
+	}
+
+	return events, nil
+}
+
+// GetRailOneTimePaymentEvents returns RailOneTimePaymentProcessed events for the given rails
+func (c *Client) GetRailOneTimePaymentEvents(railIds []*big.Int, fromBlock, toBlock uint64) ([]types.RailOneTimePaymentEvent, error) {
+	logs, err := c.filterRailLogs("RailOneTimePaymentProcessed", railIds, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]types.RailOneTimePaymentEvent, 0, len(logs))
+	for _, l := range logs {
+		values, err := c.abi.Unpack("RailOneTimePaymentProcessed", l.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack RailOneTimePaymentProcessed event: %w", err)
+		}
+		// Non-indexed order: [netPayeeAmount, operatorCommission, networkFee]
+		events = append(events, types.RailOneTimePaymentEvent{
+			RailId:             new(big.Int).SetBytes(l.Topics[1].Bytes()),
+			NetPayeeAmount:     values[0].(*big.Int),
+			OperatorCommission: values[1].(*big.Int),
+			NetworkFee:         values[2].(*big.Int),
+			BlockNumber:        l.BlockNumber,
+			TxHash:             l.TxHash,
+		})
+	}
+
+	return events, nil
+}
+
+// GetWithdrawEvents returns WithdrawRecorded events for withdrawals from an account, optionally
+// limited to a single token (pass the zero address for all tokens)
+func (c *Client) GetWithdrawEvents(token, from common.Address, fromBlock, toBlock uint64) ([]types.WithdrawEvent, error) {
+	event := c.abi.Events["WithdrawRecorded"]
+
+	var tokenTopics []common.Hash
+	if token != (common.Address{}) {
+		tokenTopics = []common.Hash{common.BytesToHash(token.Bytes())}
+	}
+	topics := [][]common.Hash{{event.ID}, tokenTopics, {common.BytesToHash(from.Bytes())}}
+
+	logs, err := c.filterLogsChunked(topics, fromBlock, toBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WithdrawRecorded events: %w", err)
+	}
+
+	events := make([]types.WithdrawEvent, 0, len(logs))
+	for _, l := range logs {
+		values, err := c.abi.Unpack("WithdrawRecorded", l.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack WithdrawRecorded event: %w", err)
+		}
+		events = append(events, types.WithdrawEvent{
+			Token:       common.BytesToAddress(l.Topics[1].Bytes()),
+			From:        common.BytesToAddress(l.Topics[2].Bytes()),
+			To:          common.BytesToAddress(l.Topics[3].Bytes()),
+			Amount:      values[0].(*big.Int),
+			BlockNumber: l.BlockNumber,
+			TxHash:      l.TxHash,
+		})
+	}
+
+	return events, nil
+}
+
+// GetRailTerminatedEvents returns all RailTerminated events between fromBlock and toBlock (inclusive)
+func (c *Client) GetRailTerminatedEvents(fromBlock, toBlock uint64) ([]types.RailTerminatedEvent, error) {
+	event := c.abi.Events["RailTerminated"]
+	logs, err := c.filterLogsChunked([][]common.Hash{{event.ID}}, fromBlock, toBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RailTerminated events: %w", err)
+	}
+
+	events := make([]types.RailTerminatedEvent, 0, len(logs))
+	for _, l := range logs {
+		values, err := c.abi.Unpack("RailTerminated", l.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack RailTerminated event: %w", err)
+		}
+		events = append(events, types.RailTerminatedEvent{
+			RailId:      new(big.Int).SetBytes(l.Topics[1].Bytes()),
+			By:          common.BytesToAddress(l.Topics[2].Bytes()),
+			EndEpoch:    values[0].(*big.Int),
+			BlockNumber: l.BlockNumber,
+			TxHash:      l.TxHash,
+			LogIndex:    l.Index,
+		})
+	}
+
+	return events, nil
+}
+
+// filterRailLogs fetches logs of an event whose first indexed parameter is the rail ID
+func (c *Client) filterRailLogs(eventName string, railIds []*big.Int, fromBlock, toBlock uint64) ([]ethtypes.Log, error) {
+	event, ok := c.abi.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found in Payments ABI", eventName)
+	}
+
+	var all []ethtypes.Log
+	for start := 0; start < len(railIds); start += maxTopicsPerQuery {
+		end := start + maxTopicsPerQuery
+		if end > len(railIds) {
+			end = len(railIds)
+		}
+
+		railTopics := make([]common.Hash, 0, end-start)
+		for _, id := range railIds[start:end] {
+			railTopics = append(railTopics, common.BigToHash(id))
+		}
+
+		logs, err := c.filterLogsChunked([][]common.Hash{{event.ID}, railTopics}, fromBlock, toBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s events: %w", eventName, err)
+		}
+		all = append(all, logs...)
+	}
+
+	return all, nil
+}
+
+// filterLogsChunked queries logs emitted by the payments contract in block ranges of DefaultLogChunkSize
+func (c *Client) filterLogsChunked(topics [][]common.Hash, fromBlock, toBlock uint64) ([]ethtypes.Log, error) {
+	var all []ethtypes.Log
+	for start := fromBlock; start <= toBlock; start += DefaultLogChunkSize {
+		end := start + DefaultLogChunkSize - 1
+		if end > toBlock {
+			end = toBlock
+		}
+
+		logs, err := c.ethClient.FilterLogs(context.Background(), ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: []common.Address{c.contractAddr},
+			Topics:    topics,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("blocks %d-%d: %w", start, end, err)
+		}
+
+		for _, l := range logs {
+			if !l.Removed {
+				all = append(all, l)
+			}
+		}
+	}
+
+	return all, nil
+}
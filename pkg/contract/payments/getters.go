@@ -94,8 +94,15 @@ func (c *Client) GetRail(railId *big.Int) (*types.RailView, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get rail: %w", err)
 	}
+	return decodeRail(result)
+}
 
-	railStruct := result[0].(struct {
+// decodeRail decodes the outputs of getRail
+func decodeRail(result []interface{}) (*types.RailView, error) {
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no result returned from getRail call")
+	}
+	railStruct, ok := result[0].(struct {
 		Token               common.Address `json:"token"`
 		From                common.Address `json:"from"`
 		To                  common.Address `json:"to"`
@@ -109,6 +116,9 @@ func (c *Client) GetRail(railId *big.Int) (*types.RailView, error) {
 		CommissionRateBps   *big.Int       `json:"commissionRateBps"`
 		ServiceFeeRecipient common.Address `json:"serviceFeeRecipient"`
 	})
+	if !ok {
+		return nil, fmt.Errorf("unexpected getRail result type %T", result[0])
+	}
 
 	return &types.RailView{
 		Token:               railStruct.Token,
@@ -126,64 +136,117 @@ func (c *Client) GetRail(railId *big.Int) (*types.RailView, error) {
 	}, nil
 }
 
+// railsPageSize is the page size used when listing rails for a payer or payee
+const railsPageSize = 100
+
 // GetRailsForPayerAndToken returns all rails for a payer and specific token
 func (c *Client) GetRailsForPayerAndToken(payer, token common.Address) ([]*types.RailInfo, error) {
-	var result []interface{}
-	err := c.contract.Call(&bind.CallOpts{Context: context.Background()}, &result, "getRailsForPayerAndToken", payer, token)
+	rails, err := c.getRailsPaged("getRailsForPayerAndToken", payer, token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get rails for payer and token: %w", err)
 	}
+	return rails, nil
+}
 
-	if len(result) == 0 {
-		return []*types.RailInfo{}, nil
+// GetRailsForPayeeAndToken returns all rails for a payee and specific token
+func (c *Client) GetRailsForPayeeAndToken(payee, token common.Address) ([]*types.RailInfo, error) {
+	rails, err := c.getRailsPaged("getRailsForPayeeAndToken", payee, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rails for payee and token: %w", err)
 	}
+	return rails, nil
+}
 
-	// Parse the array of RailInfo structs
-	railInfoStructs := result[0].([]struct {
-		RailId       *big.Int `json:"railId"`
-		IsTerminated bool     `json:"isTerminated"`
-		EndEpoch     *big.Int `json:"endEpoch"`
-	})
+// getRailsPaged walks a paginated rail listing (offset, limit) -> (results, nextOffset, total)
+func (c *Client) getRailsPaged(method string, party, token common.Address) ([]*types.RailInfo, error) {
+	railInfos := []*types.RailInfo{}
+	offset := big.NewInt(0)
+	limit := big.NewInt(railsPageSize)
+
+	for {
+		var result []interface{}
+		err := c.contract.Call(&bind.CallOpts{Context: context.Background()}, &result, method, party, token, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result) < 3 {
+			return nil, fmt.Errorf("unexpected number of results from %s: got %d, expected 3", method, len(result))
+		}
+
+		// Parse the array of RailInfo structs
+		railInfoStructs := result[0].([]struct {
+			RailId       *big.Int `json:"railId"`
+			IsTerminated bool     `json:"isTerminated"`
+			EndEpoch     *big.Int `json:"endEpoch"`
+		})
+		for _, r := range railInfoStructs {
+			railInfos = append(railInfos, &types.RailInfo{
+				RailId:       r.RailId,
+				IsTerminated: r.IsTerminated,
+				EndEpoch:     r.EndEpoch,
+			})
+		}
 
-	railInfos := make([]*types.RailInfo, len(railInfoStructs))
-	for i, r := range railInfoStructs {
-		railInfos[i] = &types.RailInfo{
-			RailId:       r.RailId,
-			IsTerminated: r.IsTerminated,
-			EndEpoch:     r.EndEpoch,
+		nextOffset := result[1].(*big.Int)
+		total := result[2].(*big.Int)
+		if len(railInfoStructs) == 0 || nextOffset.Cmp(total) >= 0 || nextOffset.Cmp(offset) <= 0 {
+			break
 		}
+		offset = nextOffset
 	}
 
 	return railInfos, nil
 }
 
-// GetRailsForPayeeAndToken returns all rails for a payee and specific token
-func (c *Client) GetRailsForPayeeAndToken(payee, token common.Address) ([]*types.RailInfo, error) {
+// GetAccountInfoIfSettled returns the account state as it would be after settling
+// its lockup up to the current epoch, including the epoch until which it is funded
+func (c *Client) GetAccountInfoIfSettled(token, owner common.Address) (*types.AccountSettledInfo, error) {
 	var result []interface{}
-	err := c.contract.Call(&bind.CallOpts{Context: context.Background()}, &result, "getRailsForPayeeAndToken", payee, token)
+	err := c.contract.Call(&bind.CallOpts{Context: context.Background()}, &result, "getAccountInfoIfSettled", token, owner)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rails for payee and token: %w", err)
+		return nil, fmt.Errorf("failed to get settled account info: %w", err)
 	}
 
-	if len(result) == 0 {
-		return []*types.RailInfo{}, nil
+	if len(result) < 4 {
+		return nil, fmt.Errorf("unexpected number of results from getAccountInfoIfSettled: got %d, expected 4", len(result))
 	}
 
-	// Parse the array of RailInfo structs
-	railInfoStructs := result[0].([]struct {
-		RailId       *big.Int `json:"railId"`
-		IsTerminated bool     `json:"isTerminated"`
-		EndEpoch     *big.Int `json:"endEpoch"`
-	})
+	// Order: [fundedUntilEpoch, currentFunds, availableFunds, currentLockupRate]
+	return &types.AccountSettledInfo{
+		FundedUntilEpoch:  result[0].(*big.Int),
+		CurrentFunds:      result[1].(*big.Int),
+		AvailableFunds:    result[2].(*big.Int),
+		CurrentLockupRate: result[3].(*big.Int),
+	}, nil
+}
 
-	railInfos := make([]*types.RailInfo, len(railInfoStructs))
-	for i, r := range railInfoStructs {
-		railInfos[i] = &types.RailInfo{
-			RailId:       r.RailId,
-			IsTerminated: r.IsTerminated,
-			EndEpoch:     r.EndEpoch,
-		}
+// GetAuctionInfo returns the state of the network fee auction for a token
+func (c *Client) GetAuctionInfo(token common.Address) (*types.AuctionInfo, error) {
+	var result []interface{}
+	err := c.contract.Call(&bind.CallOpts{Context: context.Background()}, &result, "auctionInfo", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction info: %w", err)
 	}
 
-	return railInfos, nil
+	if len(result) < 2 {
+		return nil, fmt.Errorf("unexpected number of results from auctionInfo: got %d, expected 2", len(result))
+	}
+
+	// Order: [startPrice, startTime]
+	return &types.AuctionInfo{
+		Token:      token,
+		StartPrice: result[0].(*big.Int),
+		StartTime:  result[1].(*big.Int),
+	}, nil
+}
+
+// GetAccumulatedFees returns the network fees collected for a token, which the
+// contract holds in its own account until they are auctioned off via burnForFees
+func (c *Client) GetAccumulatedFees(token common.Address) (*big.Int, error) {
+	account, err := c.GetAccount(token, c.contractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accumulated fees: %w", err)
+	}
+	return account.Funds, nil
 }
@@ -1,6 +1,7 @@
 package payments
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 
@@ -21,7 +22,7 @@ func (c *Client) SetOperatorApproval(
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "setOperatorApproval",
+	tx, err := c.transact(c.auth, "setOperatorApproval",
 		token, operator, approved, rateAllowance, lockupAllowance, maxLockupPeriod)
 	if err != nil {
 		return "", fmt.Errorf("failed to set operator approval: %w", err)
@@ -42,7 +43,7 @@ func (c *Client) Deposit(token common.Address, to common.Address, amount *big.In
 		opts.Value = amount
 	}
 
-	tx, err := c.contract.Transact(&opts, "deposit", token, to, amount)
+	tx, err := c.transact(&opts, "deposit", token, to, amount)
 	if err != nil {
 		return "", fmt.Errorf("failed to deposit: %w", err)
 	}
@@ -56,7 +57,7 @@ func (c *Client) Withdraw(token common.Address, amount *big.Int) (string, error)
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "withdraw", token, amount)
+	tx, err := c.transact(c.auth, "withdraw", token, amount)
 	if err != nil {
 		return "", fmt.Errorf("failed to withdraw: %w", err)
 	}
@@ -70,7 +71,7 @@ func (c *Client) WithdrawTo(token common.Address, to common.Address, amount *big
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "withdrawTo", token, to, amount)
+	tx, err := c.transact(c.auth, "withdrawTo", token, to, amount)
 	if err != nil {
 		return "", fmt.Errorf("failed to withdraw to address: %w", err)
 	}
@@ -90,7 +91,7 @@ func (c *Client) CreateRail(
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "createRail", token, from, to, validator, commissionRateBps)
+	tx, err := c.transact(c.auth, "createRail", token, from, to, validator, commissionRateBps)
 	if err != nil {
 		return "", fmt.Errorf("failed to create rail: %w", err)
 	}
@@ -104,7 +105,7 @@ func (c *Client) ModifyRailLockup(railId *big.Int, period *big.Int, lockupFixed
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "modifyRailLockup", railId, period, lockupFixed)
+	tx, err := c.transact(c.auth, "modifyRailLockup", railId, period, lockupFixed)
 	if err != nil {
 		return "", fmt.Errorf("failed to modify rail lockup: %w", err)
 	}
@@ -118,7 +119,7 @@ func (c *Client) ModifyRailPayment(railId *big.Int, newRate *big.Int, oneTimePay
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "modifyRailPayment", railId, newRate, oneTimePayment)
+	tx, err := c.transact(c.auth, "modifyRailPayment", railId, newRate, oneTimePayment)
 	if err != nil {
 		return "", fmt.Errorf("failed to modify rail payment: %w", err)
 	}
@@ -132,7 +133,7 @@ func (c *Client) TerminateRail(railId *big.Int) (string, error) {
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "terminateRail", railId)
+	tx, err := c.transact(c.auth, "terminateRail", railId)
 	if err != nil {
 		return "", fmt.Errorf("failed to terminate rail: %w", err)
 	}
@@ -146,7 +147,7 @@ func (c *Client) SettleRail(railId *big.Int, untilEpoch *big.Int) (string, error
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "settleRail", railId, untilEpoch)
+	tx, err := c.transact(c.auth, "settleRail", railId, untilEpoch)
 	if err != nil {
 		return "", fmt.Errorf("failed to settle rail: %w", err)
 	}
@@ -154,13 +155,14 @@ func (c *Client) SettleRail(railId *big.Int, untilEpoch *big.Int) (string, error
 	return tx.Hash().Hex(), nil
 }
 
-// SettleTerminatedRailWithoutArbitration settles a terminated rail without arbitration
-func (c *Client) SettleTerminatedRailWithoutArbitration(railId *big.Int) (string, error) {
+// SettleTerminatedRailWithoutValidation settles a terminated rail past its end epoch
+// without consulting the validator. Only the rail payer may call this.
+func (c *Client) SettleTerminatedRailWithoutValidation(railId *big.Int) (string, error) {
 	if c.auth == nil {
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "settleTerminatedRailWithoutArbitration", railId)
+	tx, err := c.transact(c.auth, "settleTerminatedRailWithoutValidation", railId)
 	if err != nil {
 		return "", fmt.Errorf("failed to settle terminated rail: %w", err)
 	}
@@ -168,23 +170,31 @@ func (c *Client) SettleTerminatedRailWithoutArbitration(railId *big.Int) (string
 	return tx.Hash().Hex(), nil
 }
 
-// WithdrawFees allows the contract owner to withdraw accumulated fees
-func (c *Client) WithdrawFees(token common.Address, to common.Address, amount *big.Int) (string, error) {
+// BurnForFees buys accumulated network fees of a token in the fee auction. The native
+// token sent as value is burned and must cover the current auction price; requested
+// fee tokens are transferred to recipient.
+func (c *Client) BurnForFees(token common.Address, recipient common.Address, requested *big.Int, value *big.Int) (string, error) {
 	if c.auth == nil {
 		return "", fmt.Errorf("client not configured for transactions")
 	}
 
-	tx, err := c.contract.Transact(c.auth, "withdrawFees", token, to, amount)
+	opts := *c.auth
+	opts.Value = value
+
+	tx, err := c.transact(&opts, "burnForFees", token, recipient, requested)
 	if err != nil {
-		return "", fmt.Errorf("failed to withdraw fees: %w", err)
+		return "", fmt.Errorf("failed to burn for fees: %w", err)
 	}
 
 	return tx.Hash().Hex(), nil
 }
 
-// WaitForTransaction waits for a transaction to be mined and returns the receipt
+// WaitForTransaction waits for a transaction sent by this client to be mined and
+// returns the receipt. A reverted transaction returns its receipt and a
+// *txmgr.RevertedError.
 func (c *Client) WaitForTransaction(txHash string) (*types.Receipt, error) {
-	// Note: This is a placeholder implementation
-	// In a real implementation, you would need the actual transaction object
-	return nil, fmt.Errorf("WaitForTransaction not fully implemented - use specific transaction methods")
+	if c.txm == nil {
+		return nil, fmt.Errorf("client not configured for transactions (read-only mode)")
+	}
+	return c.txm.Wait(context.Background(), common.HexToHash(txHash))
 }
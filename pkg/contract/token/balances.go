@@ -7,13 +7,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
 	"github.com/Eastore-project/ddo-client/pkg/types"
 )
 
 var log = logging.Logger("ddo/token")
 
 // GetTokenBalances gets the token balances for an address from the supported tokens
-func GetTokenBalances(rpcEndpoint string, supportedTokens []types.TokenConfig, address common.Address) ([]types.TokenBalance, error) {
+func GetTokenBalances(rpcEndpoint string, rpcCfg rpcclient.Config, supportedTokens []types.TokenConfig, address common.Address) ([]types.TokenBalance, error) {
 	var balances []types.TokenBalance
 
 	for _, tokenConfig := range supportedTokens {
@@ -31,7 +32,7 @@ func GetTokenBalances(rpcEndpoint string, supportedTokens []types.TokenConfig, a
 		}
 
 		// Create read-only ERC20 client
-		erc20Client, err := NewERC20ReadOnlyClient(rpcEndpoint, tokenConfig.Token.Hex())
+		erc20Client, err := NewERC20ReadOnlyClient(rpcEndpoint, tokenConfig.Token.Hex(), rpcCfg)
 		if err != nil {
 			log.Warnw("failed to create ERC20 client",
 				"token", tokenConfig.Token.Hex(), "error", err)
@@ -58,8 +59,8 @@ func GetTokenBalances(rpcEndpoint string, supportedTokens []types.TokenConfig, a
 }
 
 // GetTokenBalanceResult gets token balances and returns a structured result
-func GetTokenBalanceResult(rpcEndpoint string, supportedTokens []types.TokenConfig, address common.Address) (*types.TokenBalanceResult, error) {
-	balances, err := GetTokenBalances(rpcEndpoint, supportedTokens, address)
+func GetTokenBalanceResult(rpcEndpoint string, rpcCfg rpcclient.Config, supportedTokens []types.TokenConfig, address common.Address) (*types.TokenBalanceResult, error) {
+	balances, err := GetTokenBalances(rpcEndpoint, rpcCfg, supportedTokens, address)
 	if err != nil {
 		return nil, err
 	}
@@ -9,8 +9,12 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
 )
 
 // ERC20Client handles interactions with ERC20 tokens
@@ -19,12 +23,34 @@ type ERC20Client struct {
 	contract   *bind.BoundContract
 	tokenAddr  common.Address
 	auth       *bind.TransactOpts
+	txm        *txmgr.Manager
 	abi        abi.ABI
 	ownsClient bool
 }
 
-// Standard ERC20 ABI (minimal interface for allowance and approve)
+// Standard ERC20 ABI (minimal interface for allowance, approve, token metadata and events)
 const ERC20ABI = `[
+	{
+		"inputs": [],
+		"name": "decimals",
+		"outputs": [{"name": "", "type": "uint8"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "symbol",
+		"outputs": [{"name": "", "type": "string"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "name",
+		"outputs": [{"name": "", "type": "string"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
 	{
 		"inputs": [
 			{"name": "owner", "type": "address"},
@@ -63,12 +89,22 @@ const ERC20ABI = `[
 		],
 		"name": "Approval",
 		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "from", "type": "address"},
+			{"indexed": true, "name": "to", "type": "address"},
+			{"indexed": false, "name": "value", "type": "uint256"}
+		],
+		"name": "Transfer",
+		"type": "event"
 	}
 ]`
 
 // NewERC20ClientWithParams creates a new ERC20 client with specific parameters
-func NewERC20ClientWithParams(rpcEndpoint, tokenAddress, privateKey string) (*ERC20Client, error) {
-	client, err := ethclient.Dial(rpcEndpoint)
+func NewERC20ClientWithParams(rpcEndpoint, tokenAddress, privateKey string, rpcCfg rpcclient.Config, txCfg txmgr.Config) (*ERC20Client, error) {
+	client, err := rpcclient.Dial(rpcEndpoint, rpcCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
 	}
@@ -101,6 +137,7 @@ func NewERC20ClientWithParams(rpcEndpoint, tokenAddress, privateKey string) (*ER
 		contract:   contract,
 		tokenAddr:  tokenAddr,
 		auth:       auth,
+		txm:        txmgr.New(client, txCfg),
 		abi:        parsedABI,
 		ownsClient: true,
 	}, nil
@@ -108,14 +145,18 @@ func NewERC20ClientWithParams(rpcEndpoint, tokenAddress, privateKey string) (*ER
 
 // NewERC20ClientWithTransactor creates an ERC20 client using an existing
 // ethclient and pre-built TransactOpts. The caller retains ownership of
-// ethClient and must close it separately.
-func NewERC20ClientWithTransactor(ethClient *ethclient.Client, tokenAddress string, auth *bind.TransactOpts) (*ERC20Client, error) {
+// ethClient and must close it separately. txm is the transaction manager of
+// ethClient, shared by every client sending over it.
+func NewERC20ClientWithTransactor(ethClient *ethclient.Client, tokenAddress string, auth *bind.TransactOpts, txm *txmgr.Manager) (*ERC20Client, error) {
 	if ethClient == nil {
 		return nil, fmt.Errorf("ethClient must not be nil")
 	}
 	if auth == nil {
 		return nil, fmt.Errorf("auth must not be nil")
 	}
+	if txm == nil {
+		return nil, fmt.Errorf("txm must not be nil")
+	}
 
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
 	if err != nil {
@@ -130,13 +171,14 @@ func NewERC20ClientWithTransactor(ethClient *ethclient.Client, tokenAddress stri
 		contract:  contract,
 		tokenAddr: tokenAddr,
 		auth:      auth,
+		txm:       txm,
 		abi:       parsedABI,
 	}, nil
 }
 
 // NewERC20ReadOnlyClient creates a new ERC20 client for read-only operations
-func NewERC20ReadOnlyClient(rpcEndpoint, tokenAddress string) (*ERC20Client, error) {
-	client, err := ethclient.Dial(rpcEndpoint)
+func NewERC20ReadOnlyClient(rpcEndpoint, tokenAddress string, rpcCfg rpcclient.Config) (*ERC20Client, error) {
+	client, err := rpcclient.Dial(rpcEndpoint, rpcCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
 	}
@@ -198,13 +240,62 @@ func (e *ERC20Client) GetBalance(account common.Address) (*big.Int, error) {
 	return balance, nil
 }
 
+// GetDecimals returns the number of decimals of the token
+func (e *ERC20Client) GetDecimals() (uint8, error) {
+	var result []interface{}
+	err := e.contract.Call(&bind.CallOpts{}, &result, "decimals")
+	if err != nil {
+		return 0, fmt.Errorf("failed to call decimals: %w", err)
+	}
+
+	if len(result) == 0 {
+		return 0, fmt.Errorf("no result returned from decimals call")
+	}
+
+	decimals, ok := result[0].(uint8)
+	if !ok {
+		return 0, fmt.Errorf("failed to parse decimals result: %T", result[0])
+	}
+
+	return decimals, nil
+}
+
+// GetSymbol returns the token symbol
+func (e *ERC20Client) GetSymbol() (string, error) {
+	return e.callString("symbol")
+}
+
+// GetName returns the token name
+func (e *ERC20Client) GetName() (string, error) {
+	return e.callString("name")
+}
+
+func (e *ERC20Client) callString(method string) (string, error) {
+	var result []interface{}
+	err := e.contract.Call(&bind.CallOpts{}, &result, method)
+	if err != nil {
+		return "", fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	if len(result) == 0 {
+		return "", fmt.Errorf("no result returned from %s call", method)
+	}
+
+	value, ok := result[0].(string)
+	if !ok {
+		return "", fmt.Errorf("failed to parse %s result: %T", method, result[0])
+	}
+
+	return value, nil
+}
+
 // Approve sets the allowance for a spender
 func (e *ERC20Client) Approve(spender common.Address, amount *big.Int) (string, error) {
 	if e.auth == nil {
 		return "", fmt.Errorf("client not configured for transactions (no private key)")
 	}
 
-	tx, err := e.contract.Transact(e.auth, "approve", spender, amount)
+	tx, err := e.transact(e.auth, "approve", spender, amount)
 	if err != nil {
 		return "", fmt.Errorf("failed to send approve transaction: %w", err)
 	}
@@ -251,11 +342,27 @@ func (e *ERC20Client) GetTokenAddress() common.Address {
 	return e.tokenAddr
 }
 
+// transact sends a contract call through the client's transaction manager
+func (e *ERC20Client) transact(opts *bind.TransactOpts, method string, params ...interface{}) (*ethtypes.Transaction, error) {
+	if e.txm == nil {
+		return nil, fmt.Errorf("client not configured for transactions (read-only mode)")
+	}
+	return e.txm.Send(context.Background(), opts, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return e.contract.Transact(opts, method, params...)
+	})
+}
+
 // GetEthClient returns the underlying Ethereum client
 func (e *ERC20Client) GetEthClient() *ethclient.Client {
 	return e.ethClient
 }
 
+// TxManager returns the transaction manager the client sends with, nil for a
+// read-only client
+func (e *ERC20Client) TxManager() *txmgr.Manager {
+	return e.txm
+}
+
 // Close closes the Ethereum client connection if this client owns it.
 // Clients created with NewERC20ClientWithTransactor do not own the connection.
 func (e *ERC20Client) Close() {
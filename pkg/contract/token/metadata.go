@@ -0,0 +1,77 @@
+package token
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// NativeTokenMetadata describes FIL, which the contracts address as the zero address
+var NativeTokenMetadata = types.TokenMetadata{
+	Name:     "Filecoin",
+	Symbol:   "FIL",
+	Decimals: 18,
+}
+
+var (
+	metadataMu    sync.Mutex
+	metadataCache = make(map[string]*types.TokenMetadata)
+)
+
+// GetTokenMetadata returns the name, symbol and decimals of a token. Results are
+// cached per RPC endpoint and token for the lifetime of the process.
+func GetTokenMetadata(rpcEndpoint string, rpcCfg rpcclient.Config, tokenAddr common.Address) (*types.TokenMetadata, error) {
+	if tokenAddr == (common.Address{}) {
+		meta := NativeTokenMetadata
+		return &meta, nil
+	}
+
+	key := rpcEndpoint + "|" + tokenAddr.Hex()
+
+	metadataMu.Lock()
+	cached, ok := metadataCache[key]
+	metadataMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	erc20Client, err := NewERC20ReadOnlyClient(rpcEndpoint, tokenAddr.Hex(), rpcCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ERC20 client: %w", err)
+	}
+	defer erc20Client.Close()
+
+	decimals, err := erc20Client.GetDecimals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decimals of token %s: %w", tokenAddr.Hex(), err)
+	}
+
+	// Symbol and name are optional in ERC20, so fall back to the address
+	symbol, err := erc20Client.GetSymbol()
+	if err != nil || symbol == "" {
+		log.Warnw("failed to get token symbol", "token", tokenAddr.Hex(), "error", err)
+		symbol = tokenAddr.Hex()
+	}
+	name, err := erc20Client.GetName()
+	if err != nil {
+		log.Warnw("failed to get token name", "token", tokenAddr.Hex(), "error", err)
+		name = symbol
+	}
+
+	meta := &types.TokenMetadata{
+		Address:  tokenAddr,
+		Name:     name,
+		Symbol:   symbol,
+		Decimals: decimals,
+	}
+
+	metadataMu.Lock()
+	metadataCache[key] = meta
+	metadataMu.Unlock()
+
+	return meta, nil
+}
@@ -0,0 +1,166 @@
+package curio
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	eabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ipfs/go-cid"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/curio/cidconv"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// ddoDealDuration is the deal duration requested from Curio, in epochs
+const ddoDealDuration = 518400
+
+// DDODealParams describes the MK20 deal for a piece allocated through the DDO contract.
+type DDODealParams struct {
+	AllocationID   uint64
+	PieceCid       string         // piece CID v1
+	CarSize        uint64         // unpadded size of the piece data
+	DownloadURL    string         // where Curio fetches the data when it is not uploaded
+	Upload         bool           // the data is uploaded with UploadSerial rather than fetched
+	Raw            bool           // the data is raw bytes, such as an aggregate, rather than a CAR
+	Client         common.Address // the client that created the allocation, the piece manager
+	DDOContract    common.Address // the on-chain allocation owner, the deal's client
+	VerifyContract string         // contract Curio calls to verify the deal, usually the DDO contract
+	Provider       string         // Filecoin address of the provider
+}
+
+// NewDDODeal builds the MK20 deal for an allocation, with a new deal ID.
+func NewDDODeal(p DDODealParams) (*Deal, error) {
+	pieceCidV1, err := cid.Decode(p.PieceCid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode piece CID: %w", err)
+	}
+	pieceCidV2, err := cidconv.PieceCidV2FromV1(pieceCidV1, p.CarSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert piece CID to V2: %w", err)
+	}
+
+	clientFilAddr, err := EthToFilecoinDelegated(p.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive client Filecoin address: %w", err)
+	}
+	ddoFilAddr, err := EthToFilecoinDelegated(p.DDOContract)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive DDO contract Filecoin address: %w", err)
+	}
+
+	data := &DataSource{PieceCID: pieceCidV2}
+	if p.Upload {
+		data.SourceHttpPut = &DataSourcePut{}
+	} else {
+		data.SourceHTTP = &DataSourceHTTP{URLs: []HttpUrl{{URL: p.DownloadURL}}}
+	}
+
+	// Raw pieces cannot be indexed by Curio
+	data.Format = PieceDataFormat{Car: &FormatCar{}}
+	indexing := true
+	if p.Raw {
+		data.Format = PieceDataFormat{Raw: &FormatBytes{}}
+		indexing = false
+	}
+
+	// The contract verifies the deal with getDealId(uint64 allocationId)
+	uint64Ty, _ := eabi.NewType("uint64", "", nil)
+	verifyParams, err := eabi.Arguments{{Type: uint64Ty}}.Pack(p.AllocationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ABI-encode verify params for allocation %d: %w", p.AllocationID, err)
+	}
+
+	entropy := rand.New(rand.NewSource(time.Now().UnixNano()))
+	allocationID := p.AllocationID
+
+	return &Deal{
+		Identifier: ulid.MustNew(ulid.Timestamp(time.Now()), entropy),
+		Client:     ddoFilAddr.String(),
+		Data:       data,
+		Products: Products{
+			DDOV1: &DDOV1{
+				Provider:                   p.Provider,
+				PieceManager:               clientFilAddr.String(),
+				Duration:                   ddoDealDuration,
+				AllocationId:               &allocationID,
+				ContractAddress:            p.VerifyContract,
+				ContractVerifyMethod:       "getDealId",
+				ContractVerifyMethodParams: verifyParams,
+				NotificationAddress:        ddoFilAddr.String(),
+				NotificationPayload:        CborEncodeUint64(p.AllocationID),
+			},
+			RetrievalV1: &RetrievalV1{
+				Indexing: indexing,
+			},
+		},
+	}, nil
+}
+
+// SubmitDeal stores the deal and, when carPath is set, uploads and finalizes the
+// file's data for it.
+func (c *Client) SubmitDeal(ctx context.Context, deal *Deal, carPath string) error {
+	if err := c.Store(ctx, deal); err != nil {
+		return fmt.Errorf("failed to store deal %s: %w", deal.Identifier.String(), err)
+	}
+	if carPath == "" {
+		return nil
+	}
+
+	carFile, err := os.Open(carPath)
+	if err != nil {
+		return fmt.Errorf("failed to open CAR file: %w", err)
+	}
+	defer carFile.Close()
+
+	if err := c.UploadSerial(ctx, deal.Identifier, carFile); err != nil {
+		return fmt.Errorf("failed to upload CAR file: %w", err)
+	}
+	if err := c.UploadSerialFinalize(ctx, deal.Identifier); err != nil {
+		return fmt.Errorf("failed to finalize upload: %w", err)
+	}
+	return nil
+}
+
+// SubmitDDODeals submits a deal for each allocation, created in the order of pieces,
+// with the fields of p that every deal shares. Pieces with a local CAR file are
+// uploaded; the others are fetched from their download URL. onDeal, if set, is called
+// before each deal is stored. It returns the IDs of the deals submitted.
+func (c *Client) SubmitDDODeals(
+	ctx context.Context,
+	p DDODealParams,
+	pieces []types.PreparedPiece,
+	allocationIDs []uint64,
+	onDeal func(piece types.PreparedPiece, deal *Deal),
+) ([]ulid.ULID, error) {
+	if len(allocationIDs) != len(pieces) {
+		return nil, fmt.Errorf("got %d allocation(s) for %d piece(s)", len(allocationIDs), len(pieces))
+	}
+
+	dealIDs := make([]ulid.ULID, 0, len(pieces))
+	for i, piece := range pieces {
+		p.AllocationID = allocationIDs[i]
+		p.PieceCid = piece.PieceCid
+		p.CarSize = piece.CarSize
+		p.DownloadURL = piece.DownloadURL
+		p.Upload = piece.CarPath != ""
+		p.Raw = len(piece.SubPieces) > 0
+
+		deal, err := NewDDODeal(p)
+		if err != nil {
+			return dealIDs, err
+		}
+		if onDeal != nil {
+			onDeal(piece, deal)
+		}
+		if err := c.SubmitDeal(ctx, deal, piece.CarPath); err != nil {
+			return dealIDs, err
+		}
+		dealIDs = append(dealIDs, deal.Identifier)
+	}
+	return dealIDs, nil
+}
@@ -2,6 +2,7 @@ package curio
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,8 @@ import (
 	"strings"
 
 	multiaddr "github.com/multiformats/go-multiaddr"
+
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
 )
 
 // jsonRPCRequest is a minimal JSON-RPC 2.0 request.
@@ -40,7 +43,7 @@ type minerInfoResult struct {
 
 // DiscoverSPURL queries the Filecoin node for the SP's on-chain multiaddrs
 // and returns the HTTP market URL if one is announced.
-func DiscoverSPURL(rpcURL string, providerID uint64) (string, error) {
+func DiscoverSPURL(rpcURL string, rpcCfg rpcclient.Config, providerID uint64) (string, error) {
 	minerAddr := fmt.Sprintf("f0%d", providerID)
 
 	reqBody := jsonRPCRequest{
@@ -55,7 +58,11 @@ func DiscoverSPURL(rpcURL string, providerID uint64) (string, error) {
 		return "", fmt.Errorf("failed to marshal RPC request: %w", err)
 	}
 
-	resp, err := http.Post(rpcURL, "application/json", bytes.NewReader(bodyBytes))
+	client, err := rpcclient.HTTPClient(context.Background(), rpcURL, rpcCfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to set up RPC client: %w", err)
+	}
+	resp, err := client.Post(rpcclient.ParseEndpoints(rpcURL)[0], "application/json", bytes.NewReader(bodyBytes))
 	if err != nil {
 		return "", fmt.Errorf("RPC request failed: %w", err)
 	}
@@ -192,3 +199,23 @@ func multiaddrToFallbackURL(ma multiaddr.Multiaddr) (string, error) {
 
 	return fmt.Sprintf("http://%s:%s", host, port), nil
 }
+
+// CheckReachable reports whether the Curio market API at baseURL answers HTTP requests.
+// Any response below 500 counts as reachable, since unauthenticated requests may be rejected.
+func CheckReachable(ctx context.Context, baseURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+marketPath+"/info", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
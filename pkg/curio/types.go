@@ -49,12 +49,16 @@ type DataSource struct {
 
 // PieceDataFormat specifies the format of the piece data.
 type PieceDataFormat struct {
-	Car *FormatCar `json:"car,omitempty"`
+	Car *FormatCar   `json:"car,omitempty"`
+	Raw *FormatBytes `json:"raw,omitempty"`
 }
 
 // FormatCar indicates CAR format.
 type FormatCar struct{}
 
+// FormatBytes indicates raw bytes, used for aggregate pieces.
+type FormatBytes struct{}
+
 // DataSourcePut indicates the client will push data via HTTP PUT.
 type DataSourcePut struct{}
 
@@ -68,6 +72,12 @@ type HttpUrl struct {
 	URL string `json:"url"`
 }
 
+// Terminal states of a deal product as reported by MK20
+const (
+	DealStateComplete = "complete"
+	DealStateFailed   = "failed"
+)
+
 // DealStatusResponse represents the status of a deal product.
 type DealStatusResponse struct {
 	State    string `json:"status"`
@@ -0,0 +1,163 @@
+package exporter
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	logging "github.com/ipfs/go-log/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+var log = logging.Logger("ddo/exporter")
+
+// DDO is the part of the DDO contract client read by the exporter
+type DDO interface {
+	Paused() (bool, error)
+	GetAllocationIdsForClient(clientAddress string) ([]uint64, error)
+	GetAllocationIdsForProvider(providerId uint64) ([]uint64, error)
+	GetAllocationInfos(allocationIds []uint64) ([]*types.AllocationInfo, error)
+	GetSPConfigs(actorIds []uint64) ([]*types.SPConfig, []error)
+}
+
+// Payments is the part of the Payments contract client read by the exporter
+type Payments interface {
+	GetAccount(token, account common.Address) (*types.Account, error)
+	GetAccountInfoIfSettled(token, owner common.Address) (*types.AccountSettledInfo, error)
+	GetOperatorApproval(token, client, operator common.Address) (*types.OperatorApproval, error)
+	GetRailsForPayerAndToken(payer, token common.Address) ([]*types.RailInfo, error)
+	GetRailsForPayeeAndToken(payee, token common.Address) ([]*types.RailInfo, error)
+	GetRails(railIds []*big.Int) ([]*types.RailView, []error)
+}
+
+// Chain reports the current epoch
+type Chain interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Config selects the accounts and providers an exporter reports on
+type Config struct {
+	Interval  time.Duration    // time between two refreshes
+	Clients   []common.Address // payer accounts, with their allocations
+	Tokens    []common.Address // payment tokens of the clients
+	Operator  common.Address   // operator whose approvals by the clients are reported, usually the DDO contract
+	Providers []uint64         // storage provider actor IDs, with their allocations and payment accounts
+}
+
+// DefaultInterval is the refresh interval used when Config.Interval is not set
+const DefaultInterval = time.Minute
+
+// Exporter periodically reads the DDO and Payments contracts and exposes the values
+// as Prometheus metrics. Scrapes are served from the last refresh, so they never
+// wait on the chain.
+type Exporter struct {
+	ddo      DDO
+	payments Payments
+	chain    Chain
+	cfg      Config
+
+	mu      sync.RWMutex
+	metrics []prometheus.Metric // values read by the last successful refresh
+
+	errors      *prometheus.CounterVec
+	duration    prometheus.Gauge
+	lastRefresh prometheus.Gauge
+}
+
+// New returns an exporter reading from the given clients. Call Refresh or Run to
+// read the contracts.
+func New(ddo DDO, payments Payments, chain Chain, cfg Config) *Exporter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	// A repeated client, token or provider would export the same series twice,
+	// which fails the whole scrape
+	cfg.Clients = unique(cfg.Clients)
+	cfg.Tokens = unique(cfg.Tokens)
+	cfg.Providers = unique(cfg.Providers)
+	return &Exporter{
+		ddo:      ddo,
+		payments: payments,
+		chain:    chain,
+		cfg:      cfg,
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ddo_exporter_read_errors_total",
+			Help: "Contract reads that failed, by what was being read",
+		}, []string{"source"}),
+		duration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ddo_exporter_refresh_duration_seconds",
+			Help: "Time the last refresh took",
+		}),
+		lastRefresh: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ddo_exporter_last_refresh_timestamp_seconds",
+			Help: "Unix time of the last refresh",
+		}),
+	}
+}
+
+// unique returns values without repeats, in the order they first appear
+func unique[T comparable](values []T) []T {
+	seen := make(map[T]bool, len(values))
+	result := make([]T, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Describe implements prometheus.Collector
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range descs {
+		ch <- desc
+	}
+	e.errors.Describe(ch)
+	e.duration.Describe(ch)
+	e.lastRefresh.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.RLock()
+	metrics := e.metrics
+	e.mu.RUnlock()
+
+	for _, m := range metrics {
+		ch <- m
+	}
+	e.errors.Collect(ch)
+	e.duration.Collect(ch)
+	e.lastRefresh.Collect(ch)
+}
+
+// Handler serves the exporter's metrics, with the Go runtime and process metrics
+func (e *Exporter) Handler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(e, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Run refreshes the metrics now and then every Config.Interval until ctx is done
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := e.Refresh(ctx); err != nil {
+			log.Warnw("metrics refresh incomplete", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+var (
+	client   = common.HexToAddress("0xc1")
+	payee    = common.HexToAddress("0xa1")
+	token    = common.HexToAddress("0x70")
+	operator = common.HexToAddress("0xdd")
+)
+
+type fakeDDO struct{}
+
+func (fakeDDO) Paused() (bool, error) { return false, nil }
+
+func (fakeDDO) GetAllocationIdsForClient(string) ([]uint64, error) { return []uint64{1, 2, 3}, nil }
+
+func (fakeDDO) GetAllocationIdsForProvider(providerId uint64) ([]uint64, error) {
+	if providerId == 2000 {
+		return nil, errors.New("provider lookup failed")
+	}
+	return []uint64{1, 2}, nil
+}
+
+func (fakeDDO) GetAllocationInfos(ids []uint64) ([]*types.AllocationInfo, error) {
+	infos := make([]*types.AllocationInfo, len(ids))
+	for i, id := range ids {
+		infos[i] = &types.AllocationInfo{Client: client, Provider: 1000, Activated: id != 3, PieceSize: 1 << 20}
+	}
+	return infos, nil
+}
+
+func (fakeDDO) GetSPConfigs(ids []uint64) ([]*types.SPConfig, []error) {
+	configs := make([]*types.SPConfig, len(ids))
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		if id == 1000 {
+			configs[i] = &types.SPConfig{
+				PaymentAddress:  payee,
+				MinPieceSize:    1 << 20,
+				MaxPieceSize:    1 << 35,
+				SupportedTokens: []types.TokenConfig{{Token: token, PricePerBytePerEpoch: big.NewInt(7), IsActive: true}},
+				IsActive:        true,
+			}
+		}
+	}
+	return configs, errs
+}
+
+// fakePayments has one active rail and one terminated rail from client to payee
+type fakePayments struct{}
+
+func (fakePayments) GetAccount(token, account common.Address) (*types.Account, error) {
+	return &types.Account{Funds: big.NewInt(1000), LockupCurrent: big.NewInt(300), LockupRate: big.NewInt(2), LockupLastSettledAt: big.NewInt(90)}, nil
+}
+
+func (fakePayments) GetAccountInfoIfSettled(token, owner common.Address) (*types.AccountSettledInfo, error) {
+	return &types.AccountSettledInfo{FundedUntilEpoch: big.NewInt(450), CurrentFunds: big.NewInt(1000), AvailableFunds: big.NewInt(700), CurrentLockupRate: big.NewInt(2)}, nil
+}
+
+func (fakePayments) GetOperatorApproval(token, client, operator common.Address) (*types.OperatorApproval, error) {
+	return &types.OperatorApproval{IsApproved: true, RateAllowance: big.NewInt(10), LockupAllowance: big.NewInt(500), RateUsage: big.NewInt(2), LockupUsage: big.NewInt(300), MaxLockupPeriod: big.NewInt(2880)}, nil
+}
+
+func (fakePayments) rails() ([]*types.RailInfo, error) {
+	return []*types.RailInfo{
+		{RailId: big.NewInt(1), EndEpoch: big.NewInt(0)},
+		{RailId: big.NewInt(2), IsTerminated: true, EndEpoch: big.NewInt(95)},
+	}, nil
+}
+
+func (p fakePayments) GetRailsForPayerAndToken(payer, token common.Address) ([]*types.RailInfo, error) {
+	return p.rails()
+}
+
+func (p fakePayments) GetRailsForPayeeAndToken(payee, token common.Address) ([]*types.RailInfo, error) {
+	return p.rails()
+}
+
+func (fakePayments) GetRails(railIds []*big.Int) ([]*types.RailView, []error) {
+	rails := make([]*types.RailView, len(railIds))
+	for i, railId := range railIds {
+		rails[i] = &types.RailView{
+			Token: token, From: client, To: payee, Operator: operator,
+			PaymentRate: big.NewInt(2), LockupPeriod: big.NewInt(100), LockupFixed: big.NewInt(0),
+			SettledUpTo: big.NewInt(80), EndEpoch: big.NewInt(0), CommissionRateBps: big.NewInt(0),
+		}
+		if railId.Int64() == 2 {
+			rails[i].EndEpoch = big.NewInt(95)
+		}
+	}
+	return rails, make([]error, len(railIds))
+}
+
+type fakeChain uint64
+
+func (c fakeChain) BlockNumber(context.Context) (uint64, error) { return uint64(c), nil }
+
+func TestRefresh(t *testing.T) {
+	e := New(fakeDDO{}, fakePayments{}, fakeChain(100), Config{
+		Clients:   []common.Address{client},
+		Tokens:    []common.Address{token},
+		Operator:  operator,
+		Providers: []uint64{1000, 2000},
+	})
+
+	err := e.Refresh(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 reads failed") {
+		t.Fatalf("Refresh error = %v, want the failed provider lookup", err)
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(e)
+	// Rail 1 is unsettled for 20 epochs and rail 2 up to its end epoch, 15 epochs,
+	// at 2 per epoch. Only rail 1 still streams.
+	expected := `
+# HELP ddo_account_burn_rate Payment rate per epoch of the rails the account pays for
+# TYPE ddo_account_burn_rate gauge
+ddo_account_burn_rate{address="0x00000000000000000000000000000000000000C1",token="0x0000000000000000000000000000000000000070"} 2
+# HELP ddo_account_funded_until_epoch Epoch the payer account runs out of funds at its lockup rate, not set without one
+# TYPE ddo_account_funded_until_epoch gauge
+ddo_account_funded_until_epoch{address="0x00000000000000000000000000000000000000C1",token="0x0000000000000000000000000000000000000070"} 450
+# HELP ddo_client_allocations Allocations of the client, by state
+# TYPE ddo_client_allocations gauge
+ddo_client_allocations{client="0x00000000000000000000000000000000000000C1",state="activated"} 2
+ddo_client_allocations{client="0x00000000000000000000000000000000000000C1",state="pending"} 1
+# HELP ddo_operator_rate_usage Payment rate per epoch the operator set up for the client
+# TYPE ddo_operator_rate_usage gauge
+ddo_operator_rate_usage{client="0x00000000000000000000000000000000000000C1",operator="0x00000000000000000000000000000000000000dd",token="0x0000000000000000000000000000000000000070"} 2
+# HELP ddo_provider_allocations Allocations with the storage provider, by state
+# TYPE ddo_provider_allocations gauge
+ddo_provider_allocations{provider="1000",state="activated"} 2
+ddo_provider_allocations{provider="1000",state="pending"} 0
+# HELP ddo_rails Rails of the account, by its role and the rail state
+# TYPE ddo_rails gauge
+ddo_rails{address="0x00000000000000000000000000000000000000A1",role="payee",state="active",token="0x0000000000000000000000000000000000000070"} 1
+ddo_rails{address="0x00000000000000000000000000000000000000A1",role="payee",state="terminated",token="0x0000000000000000000000000000000000000070"} 1
+ddo_rails{address="0x00000000000000000000000000000000000000C1",role="payer",state="active",token="0x0000000000000000000000000000000000000070"} 1
+ddo_rails{address="0x00000000000000000000000000000000000000C1",role="payer",state="terminated",token="0x0000000000000000000000000000000000000070"} 1
+# HELP ddo_rails_unsettled_amount Amount streamed on the account's rails but not settled yet
+# TYPE ddo_rails_unsettled_amount gauge
+ddo_rails_unsettled_amount{address="0x00000000000000000000000000000000000000A1",role="payee",token="0x0000000000000000000000000000000000000070"} 70
+ddo_rails_unsettled_amount{address="0x00000000000000000000000000000000000000C1",role="payer",token="0x0000000000000000000000000000000000000070"} 70
+# HELP ddo_sp_registered Whether the storage provider is registered with the DDO contract
+# TYPE ddo_sp_registered gauge
+ddo_sp_registered{provider="1000"} 1
+ddo_sp_registered{provider="2000"} 0
+# HELP ddo_sp_token_price Price per byte per epoch the storage provider charges in the token
+# TYPE ddo_sp_token_price gauge
+ddo_sp_token_price{provider="1000",token="0x0000000000000000000000000000000000000070"} 7
+# HELP ddo_exporter_read_errors_total Contract reads that failed, by what was being read
+# TYPE ddo_exporter_read_errors_total counter
+ddo_exporter_read_errors_total{source="provider_allocations"} 1
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"ddo_account_burn_rate", "ddo_account_funded_until_epoch", "ddo_client_allocations",
+		"ddo_operator_rate_usage", "ddo_provider_allocations", "ddo_rails", "ddo_rails_unsettled_amount",
+		"ddo_sp_registered", "ddo_sp_token_price", "ddo_exporter_read_errors_total")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRefreshWithRepeatedConfig(t *testing.T) {
+	e := New(fakeDDO{}, fakePayments{}, fakeChain(100), Config{
+		Clients:   []common.Address{client, client},
+		Tokens:    []common.Address{token, token},
+		Operator:  operator,
+		Providers: []uint64{1000, 1000},
+	})
+	if err := e.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(e)
+	if _, err := registry.Gather(); err != nil {
+		t.Fatalf("gather failed with repeated flags: %v", err)
+	}
+}
+
+type failingChain struct{}
+
+func (failingChain) BlockNumber(context.Context) (uint64, error) {
+	return 0, errors.New("node unreachable")
+}
+
+func TestRefreshKeepsMetricsWhenChainIsUnreachable(t *testing.T) {
+	e := New(fakeDDO{}, fakePayments{}, fakeChain(100), Config{Providers: []uint64{1000}})
+	if err := e.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	before := testutil.CollectAndCount(e)
+
+	e.chain = failingChain{}
+	if err := e.Refresh(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	// The epoch read error adds a series to the error counter
+	if after := testutil.CollectAndCount(e); after != before+1 {
+		t.Errorf("exported %d series after a failed refresh, want %d", after, before+1)
+	}
+}
@@ -0,0 +1,366 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+// Token amounts are exported in the token's base units
+var (
+	pausedDesc = prometheus.NewDesc("ddo_contract_paused",
+		"Whether the DDO contract is paused", nil, nil)
+	epochDesc = prometheus.NewDesc("ddo_chain_epoch",
+		"Current chain epoch", nil, nil)
+
+	accountFundsDesc = prometheus.NewDesc("ddo_account_funds",
+		"Funds deposited in the payments account", []string{"address", "token"}, nil)
+	accountLockupDesc = prometheus.NewDesc("ddo_account_lockup_current",
+		"Funds of the payments account locked up for its rails", []string{"address", "token"}, nil)
+	accountLockupRateDesc = prometheus.NewDesc("ddo_account_lockup_rate",
+		"Lockup added to the payments account per epoch", []string{"address", "token"}, nil)
+	accountAvailableDesc = prometheus.NewDesc("ddo_account_available_funds",
+		"Funds of the payer account not locked up once settled to the current epoch", []string{"address", "token"}, nil)
+	accountFundedUntilDesc = prometheus.NewDesc("ddo_account_funded_until_epoch",
+		"Epoch the payer account runs out of funds at its lockup rate, not set without one", []string{"address", "token"}, nil)
+	accountBurnRateDesc = prometheus.NewDesc("ddo_account_burn_rate",
+		"Payment rate per epoch of the rails the account pays for", []string{"address", "token"}, nil)
+
+	railsDesc = prometheus.NewDesc("ddo_rails",
+		"Rails of the account, by its role and the rail state", []string{"address", "token", "role", "state"}, nil)
+	railsUnsettledDesc = prometheus.NewDesc("ddo_rails_unsettled_amount",
+		"Amount streamed on the account's rails but not settled yet", []string{"address", "token", "role"}, nil)
+
+	operatorApprovedDesc = prometheus.NewDesc("ddo_operator_approved",
+		"Whether the client approved the operator", []string{"client", "token", "operator"}, nil)
+	operatorRateAllowanceDesc = prometheus.NewDesc("ddo_operator_rate_allowance",
+		"Payment rate per epoch the operator may set up for the client", []string{"client", "token", "operator"}, nil)
+	operatorRateUsageDesc = prometheus.NewDesc("ddo_operator_rate_usage",
+		"Payment rate per epoch the operator set up for the client", []string{"client", "token", "operator"}, nil)
+	operatorLockupAllowanceDesc = prometheus.NewDesc("ddo_operator_lockup_allowance",
+		"Lockup the operator may set up for the client", []string{"client", "token", "operator"}, nil)
+	operatorLockupUsageDesc = prometheus.NewDesc("ddo_operator_lockup_usage",
+		"Lockup the operator set up for the client", []string{"client", "token", "operator"}, nil)
+
+	clientAllocationsDesc = prometheus.NewDesc("ddo_client_allocations",
+		"Allocations of the client, by state", []string{"client", "state"}, nil)
+	clientAllocatedBytesDesc = prometheus.NewDesc("ddo_client_allocated_bytes",
+		"Piece size of the client's allocations, by state", []string{"client", "state"}, nil)
+	providerAllocationsDesc = prometheus.NewDesc("ddo_provider_allocations",
+		"Allocations with the storage provider, by state", []string{"provider", "state"}, nil)
+	providerAllocatedBytesDesc = prometheus.NewDesc("ddo_provider_allocated_bytes",
+		"Piece size of the allocations with the storage provider, by state", []string{"provider", "state"}, nil)
+
+	spRegisteredDesc = prometheus.NewDesc("ddo_sp_registered",
+		"Whether the storage provider is registered with the DDO contract", []string{"provider"}, nil)
+	spActiveDesc = prometheus.NewDesc("ddo_sp_active",
+		"Whether the storage provider accepts new allocations", []string{"provider"}, nil)
+	spMinPieceSizeDesc = prometheus.NewDesc("ddo_sp_min_piece_size_bytes",
+		"Smallest piece the storage provider accepts", []string{"provider"}, nil)
+	spMaxPieceSizeDesc = prometheus.NewDesc("ddo_sp_max_piece_size_bytes",
+		"Largest piece the storage provider accepts", []string{"provider"}, nil)
+	spMinTermDesc = prometheus.NewDesc("ddo_sp_min_term_epochs",
+		"Shortest term the storage provider accepts", []string{"provider"}, nil)
+	spMaxTermDesc = prometheus.NewDesc("ddo_sp_max_term_epochs",
+		"Longest term the storage provider accepts", []string{"provider"}, nil)
+	spTokenPriceDesc = prometheus.NewDesc("ddo_sp_token_price",
+		"Price per byte per epoch the storage provider charges in the token", []string{"provider", "token"}, nil)
+	spTokenActiveDesc = prometheus.NewDesc("ddo_sp_token_active",
+		"Whether the storage provider accepts payment in the token", []string{"provider", "token"}, nil)
+)
+
+var descs = []*prometheus.Desc{
+	pausedDesc, epochDesc,
+	accountFundsDesc, accountLockupDesc, accountLockupRateDesc, accountAvailableDesc, accountFundedUntilDesc, accountBurnRateDesc,
+	railsDesc, railsUnsettledDesc,
+	operatorApprovedDesc, operatorRateAllowanceDesc, operatorRateUsageDesc, operatorLockupAllowanceDesc, operatorLockupUsageDesc,
+	clientAllocationsDesc, clientAllocatedBytesDesc, providerAllocationsDesc, providerAllocatedBytesDesc,
+	spRegisteredDesc, spActiveDesc, spMinPieceSizeDesc, spMaxPieceSizeDesc, spMinTermDesc, spMaxTermDesc, spTokenPriceDesc, spTokenActiveDesc,
+}
+
+// Allocation states
+const (
+	allocationPending   = "pending"
+	allocationActivated = "activated"
+)
+
+// Account roles on a rail
+const (
+	rolePayer = "payer"
+	rolePayee = "payee"
+)
+
+// snapshot collects the metrics of one refresh
+type snapshot struct {
+	metrics []prometheus.Metric
+	failed  int
+}
+
+func (s *snapshot) gauge(desc *prometheus.Desc, value float64, labels ...string) {
+	s.metrics = append(s.metrics, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...))
+}
+
+// account is a payments account to report on, with the roles to report its rails for
+type account struct {
+	address common.Address
+	token   common.Address
+	payer   bool
+	payee   bool
+}
+
+// Refresh reads the contracts and replaces the exported metrics. Values that cannot be
+// read are left out and counted in ddo_exporter_read_errors_total; the error then
+// reports how many reads failed.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	start := time.Now()
+	epoch, err := e.chain.BlockNumber(ctx)
+	if err != nil {
+		e.errors.WithLabelValues("epoch").Inc()
+		return fmt.Errorf("failed to get current epoch: %w", err)
+	}
+
+	s := &snapshot{}
+	s.gauge(epochDesc, float64(epoch))
+
+	if paused, err := e.ddo.Paused(); err != nil {
+		e.fail(s, "paused", err)
+	} else {
+		s.gauge(pausedDesc, boolValue(paused))
+	}
+
+	var accounts []*account
+	addAccount := func(address, token common.Address, payer, payee bool) {
+		for _, a := range accounts {
+			if a.address == address && a.token == token {
+				a.payer = a.payer || payer
+				a.payee = a.payee || payee
+				return
+			}
+		}
+		accounts = append(accounts, &account{address: address, token: token, payer: payer, payee: payee})
+	}
+
+	for _, client := range e.cfg.Clients {
+		for _, token := range e.cfg.Tokens {
+			addAccount(client, token, true, false)
+			if e.cfg.Operator != (common.Address{}) {
+				e.refreshOperatorApproval(s, client, token)
+			}
+		}
+		ids, err := e.ddo.GetAllocationIdsForClient(client.Hex())
+		if err != nil {
+			e.fail(s, "client_allocations", err, "client", client.Hex())
+			continue
+		}
+		e.refreshAllocations(s, clientAllocationsDesc, clientAllocatedBytesDesc, client.Hex(), ids)
+	}
+
+	if len(e.cfg.Providers) > 0 {
+		configs, errs := e.ddo.GetSPConfigs(e.cfg.Providers)
+		for i, provider := range e.cfg.Providers {
+			label := strconv.FormatUint(provider, 10)
+			if errs[i] != nil {
+				e.fail(s, "sp_config", errs[i], "provider", provider)
+			} else {
+				e.refreshSPConfig(s, label, configs[i])
+				if configs[i] != nil {
+					for _, t := range configs[i].SupportedTokens {
+						addAccount(configs[i].PaymentAddress, t.Token, false, true)
+					}
+				}
+			}
+
+			ids, err := e.ddo.GetAllocationIdsForProvider(provider)
+			if err != nil {
+				e.fail(s, "provider_allocations", err, "provider", provider)
+				continue
+			}
+			e.refreshAllocations(s, providerAllocationsDesc, providerAllocatedBytesDesc, label, ids)
+		}
+	}
+
+	for _, a := range accounts {
+		e.refreshAccount(s, a, epoch)
+	}
+
+	e.mu.Lock()
+	e.metrics = s.metrics
+	e.mu.Unlock()
+	e.duration.Set(time.Since(start).Seconds())
+	e.lastRefresh.SetToCurrentTime()
+
+	if s.failed > 0 {
+		return fmt.Errorf("%d reads failed", s.failed)
+	}
+	return nil
+}
+
+// fail counts a failed read
+func (e *Exporter) fail(s *snapshot, source string, err error, keysAndValues ...interface{}) {
+	s.failed++
+	e.errors.WithLabelValues(source).Inc()
+	log.Warnw("failed to read metric", append([]interface{}{"source", source, "error", err}, keysAndValues...)...)
+}
+
+func (e *Exporter) refreshOperatorApproval(s *snapshot, client, token common.Address) {
+	approval, err := e.payments.GetOperatorApproval(token, client, e.cfg.Operator)
+	if err != nil {
+		e.fail(s, "operator_approval", err, "client", client.Hex(), "token", token.Hex())
+		return
+	}
+	labels := []string{client.Hex(), token.Hex(), e.cfg.Operator.Hex()}
+	s.gauge(operatorApprovedDesc, boolValue(approval.IsApproved), labels...)
+	s.gauge(operatorRateAllowanceDesc, amount(approval.RateAllowance), labels...)
+	s.gauge(operatorRateUsageDesc, amount(approval.RateUsage), labels...)
+	s.gauge(operatorLockupAllowanceDesc, amount(approval.LockupAllowance), labels...)
+	s.gauge(operatorLockupUsageDesc, amount(approval.LockupUsage), labels...)
+}
+
+// refreshAllocations reports the allocations of a client or provider by state
+func (e *Exporter) refreshAllocations(s *snapshot, countDesc, bytesDesc *prometheus.Desc, label string, ids []uint64) {
+	infos, err := e.ddo.GetAllocationInfos(ids)
+	if err != nil {
+		e.fail(s, "allocation_info", err, "owner", label)
+		return
+	}
+
+	counts := map[string]int{allocationPending: 0, allocationActivated: 0}
+	sizes := map[string]uint64{allocationPending: 0, allocationActivated: 0}
+	for _, info := range infos {
+		state := allocationPending
+		if info.Activated {
+			state = allocationActivated
+		}
+		counts[state]++
+		sizes[state] += info.PieceSize
+	}
+	for _, state := range []string{allocationPending, allocationActivated} {
+		s.gauge(countDesc, float64(counts[state]), label, state)
+		s.gauge(bytesDesc, float64(sizes[state]), label, state)
+	}
+}
+
+// refreshSPConfig reports a provider's configuration; config is nil for an
+// unregistered provider
+func (e *Exporter) refreshSPConfig(s *snapshot, provider string, config *types.SPConfig) {
+	s.gauge(spRegisteredDesc, boolValue(config != nil), provider)
+	if config == nil {
+		return
+	}
+	s.gauge(spActiveDesc, boolValue(config.IsActive), provider)
+	s.gauge(spMinPieceSizeDesc, float64(config.MinPieceSize), provider)
+	s.gauge(spMaxPieceSizeDesc, float64(config.MaxPieceSize), provider)
+	s.gauge(spMinTermDesc, float64(config.MinTermLength), provider)
+	s.gauge(spMaxTermDesc, float64(config.MaxTermLength), provider)
+	for _, t := range config.SupportedTokens {
+		s.gauge(spTokenPriceDesc, amount(t.PricePerBytePerEpoch), provider, t.Token.Hex())
+		s.gauge(spTokenActiveDesc, boolValue(t.IsActive), provider, t.Token.Hex())
+	}
+}
+
+// refreshAccount reports a payments account and the rails it pays or is paid by
+func (e *Exporter) refreshAccount(s *snapshot, a *account, epoch uint64) {
+	address, token := a.address.Hex(), a.token.Hex()
+
+	acct, err := e.payments.GetAccount(a.token, a.address)
+	if err != nil {
+		e.fail(s, "account", err, "address", address, "token", token)
+	} else {
+		s.gauge(accountFundsDesc, amount(acct.Funds), address, token)
+		s.gauge(accountLockupDesc, amount(acct.LockupCurrent), address, token)
+		s.gauge(accountLockupRateDesc, amount(acct.LockupRate), address, token)
+	}
+
+	if a.payer {
+		if info, err := e.payments.GetAccountInfoIfSettled(a.token, a.address); err != nil {
+			e.fail(s, "account_settled", err, "address", address, "token", token)
+		} else {
+			s.gauge(accountAvailableDesc, amount(info.AvailableFunds), address, token)
+			// With no lockup rate the contract reports the account as funded forever
+			if info.CurrentLockupRate.Sign() > 0 {
+				s.gauge(accountFundedUntilDesc, amount(info.FundedUntilEpoch), address, token)
+			}
+		}
+
+		rails, err := e.payments.GetRailsForPayerAndToken(a.address, a.token)
+		if err != nil {
+			e.fail(s, "rails", err, "address", address, "token", token, "role", rolePayer)
+		} else {
+			burnRate := e.refreshRails(s, address, token, rolePayer, rails, epoch)
+			s.gauge(accountBurnRateDesc, amount(burnRate), address, token)
+		}
+	}
+
+	if a.payee {
+		rails, err := e.payments.GetRailsForPayeeAndToken(a.address, a.token)
+		if err != nil {
+			e.fail(s, "rails", err, "address", address, "token", token, "role", rolePayee)
+		} else {
+			e.refreshRails(s, address, token, rolePayee, rails, epoch)
+		}
+	}
+}
+
+// refreshRails reports the rails of an account in one role and returns the payment
+// rate of those still streaming
+func (e *Exporter) refreshRails(s *snapshot, address, token, role string, rails []*types.RailInfo, epoch uint64) *big.Int {
+	current := new(big.Int).SetUint64(epoch)
+	rate := big.NewInt(0)
+	unsettled := big.NewInt(0)
+	var active, terminated int
+
+	railIds := make([]*big.Int, len(rails))
+	for i, r := range rails {
+		railIds[i] = r.RailId
+	}
+	views, errs := e.payments.GetRails(railIds)
+
+	for i, r := range rails {
+		if r.IsTerminated {
+			terminated++
+		} else {
+			active++
+		}
+
+		rail, err := views[i], errs[i]
+		if err != nil {
+			e.fail(s, "rail", err, "railId", r.RailId.String())
+			continue
+		}
+		// Terminated rails keep streaming until their end epoch
+		if !r.IsTerminated || r.EndEpoch.Cmp(current) > 0 {
+			rate.Add(rate, rail.PaymentRate)
+		}
+		projection := utils.ProjectRailSettlement(r.RailId, rail, current, nil, nil)
+		unsettled.Add(unsettled, projection.GrossPayout)
+	}
+
+	s.gauge(railsDesc, float64(active), address, token, role, "active")
+	s.gauge(railsDesc, float64(terminated), address, token, role, "terminated")
+	s.gauge(railsUnsettledDesc, amount(unsettled), address, token, role)
+	return rate
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// amount converts a token amount in base units to a metric value
+func amount(v *big.Int) float64 {
+	if v == nil {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt(v).Float64()
+	return f
+}
@@ -0,0 +1,276 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	logging "github.com/ipfs/go-log/v2"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var log = logging.Logger("ddo/multicall")
+
+// Multicall3Address is the address Multicall3 is deployed at on most EVM chains,
+// including Filecoin mainnet and calibration
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// multicall3ABI is the part of the Multicall3 ABI used to batch view calls
+const multicall3ABI = `[{
+	"type": "function",
+	"name": "aggregate3",
+	"stateMutability": "payable",
+	"inputs": [{
+		"name": "calls",
+		"type": "tuple[]",
+		"components": [
+			{"name": "target", "type": "address"},
+			{"name": "allowFailure", "type": "bool"},
+			{"name": "callData", "type": "bytes"}
+		]
+	}],
+	"outputs": [{
+		"name": "returnData",
+		"type": "tuple[]",
+		"components": [
+			{"name": "success", "type": "bool"},
+			{"name": "returnData", "type": "bytes"}
+		]
+	}]
+}]`
+
+var parsedMulticall3ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		panic(fmt.Sprintf("invalid Multicall3 ABI: %v", err))
+	}
+	return parsed
+}()
+
+// call3 and result3 mirror Multicall3's Call3 and Result structs
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type result3 struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Call is a view call to a contract
+type Call struct {
+	To   common.Address
+	Data []byte
+}
+
+// Result is the outcome of a Call: the returned data, or why the call failed
+type Result struct {
+	Data []byte
+	Err  error
+}
+
+// Backend is the node connection calls are batched over
+type Backend interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// Options sets how calls are batched
+type Options struct {
+	BatchSize        int  // calls per Multicall3 call or JSON-RPC batch
+	Concurrency      int  // batches in flight at once
+	DisableMulticall bool // only use JSON-RPC batches, even where Multicall3 is deployed
+}
+
+// DefaultOptions returns batches of 50 calls with 4 in flight
+func DefaultOptions() Options {
+	return Options{BatchSize: 50, Concurrency: 4}
+}
+
+// Caller batches view calls through Multicall3 when it is deployed and through
+// JSON-RPC batch requests otherwise. Batches that fail as a whole are retried with
+// the next method, down to one call per request.
+type Caller struct {
+	backend Backend
+	opts    Options
+
+	detect    sync.Once
+	multicall bool
+}
+
+// New returns a caller batching over backend
+func New(backend Backend, opts Options) *Caller {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultOptions().BatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultOptions().Concurrency
+	}
+	return &Caller{backend: backend, opts: opts}
+}
+
+// NewFromClient returns a caller batching over an ethclient connection
+func NewFromClient(client *ethclient.Client, opts Options) *Caller {
+	return New(ethBackend{client}, opts)
+}
+
+// ethBackend adds JSON-RPC batching to an ethclient connection
+type ethBackend struct {
+	*ethclient.Client
+}
+
+func (b ethBackend) BatchCallContext(ctx context.Context, elems []rpc.BatchElem) error {
+	return b.Client.Client().BatchCallContext(ctx, elems)
+}
+
+// Call runs calls against the latest block and returns their results in the same order
+func (c *Caller) Call(ctx context.Context, calls []Call) []Result {
+	results := make([]Result, len(calls))
+	if len(calls) == 0 {
+		return results
+	}
+
+	useMulticall := c.useMulticall(ctx)
+	sem := make(chan struct{}, c.opts.Concurrency)
+	var wg sync.WaitGroup
+	for start := 0; start < len(calls); start += c.opts.BatchSize {
+		end := start + c.opts.BatchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.callBatch(ctx, useMulticall, calls[start:end], results[start:end])
+		}(start, end)
+	}
+	wg.Wait()
+	return results
+}
+
+// callBatch fills results with the outcome of calls, falling back from Multicall3 to a
+// JSON-RPC batch to single calls as each fails as a whole
+func (c *Caller) callBatch(ctx context.Context, useMulticall bool, calls []Call, results []Result) {
+	if useMulticall {
+		err := c.aggregate(ctx, calls, results)
+		if err == nil {
+			return
+		}
+		log.Debugw("multicall failed, falling back to a JSON-RPC batch", "calls", len(calls), "error", err)
+	}
+
+	err := c.batch(ctx, calls, results)
+	if err == nil {
+		return
+	}
+	log.Debugw("JSON-RPC batch failed, falling back to single calls", "calls", len(calls), "error", err)
+
+	for i, call := range calls {
+		to := call.To
+		data, err := c.backend.CallContract(ctx, ethereum.CallMsg{To: &to, Data: call.Data}, nil)
+		results[i] = Result{Data: data, Err: err}
+	}
+}
+
+// aggregate runs calls in a single Multicall3 aggregate3 call
+func (c *Caller) aggregate(ctx context.Context, calls []Call, results []Result) error {
+	args := make([]call3, len(calls))
+	for i, call := range calls {
+		args[i] = call3{Target: call.To, AllowFailure: true, CallData: call.Data}
+	}
+	input, err := parsedMulticall3ABI.Pack("aggregate3", args)
+	if err != nil {
+		return fmt.Errorf("failed to pack aggregate3: %w", err)
+	}
+
+	to := Multicall3Address
+	output, err := c.backend.CallContract(ctx, ethereum.CallMsg{To: &to, Data: input}, nil)
+	if err != nil {
+		return err
+	}
+
+	var returned []result3
+	if err := parsedMulticall3ABI.UnpackIntoInterface(&returned, "aggregate3", output); err != nil {
+		return fmt.Errorf("failed to unpack aggregate3: %w", err)
+	}
+	if len(returned) != len(calls) {
+		return fmt.Errorf("aggregate3 returned %d results for %d calls", len(returned), len(calls))
+	}
+
+	for i, r := range returned {
+		if r.Success {
+			results[i] = Result{Data: r.ReturnData}
+		} else {
+			results[i] = Result{Err: revertError(r.ReturnData)}
+		}
+	}
+	return nil
+}
+
+// batch runs calls as eth_call requests in a single JSON-RPC batch
+func (c *Caller) batch(ctx context.Context, calls []Call, results []Result) error {
+	elems := make([]rpc.BatchElem, len(calls))
+	data := make([]hexutil.Bytes, len(calls))
+	for i, call := range calls {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args: []interface{}{
+				map[string]interface{}{"to": call.To, "data": hexutil.Bytes(call.Data)},
+				"latest",
+			},
+			Result: &data[i],
+		}
+	}
+	if err := c.backend.BatchCallContext(ctx, elems); err != nil {
+		return err
+	}
+
+	for i, elem := range elems {
+		results[i] = Result{Data: data[i], Err: elem.Error}
+	}
+	return nil
+}
+
+// useMulticall reports whether Multicall3 is deployed, checking once per caller
+func (c *Caller) useMulticall(ctx context.Context) bool {
+	if c.opts.DisableMulticall {
+		return false
+	}
+	c.detect.Do(func() {
+		code, err := c.backend.CodeAt(ctx, Multicall3Address, nil)
+		if err != nil {
+			log.Debugw("failed to check for Multicall3", "error", err)
+			return
+		}
+		c.multicall = len(code) > 0
+	})
+	return c.multicall
+}
+
+// ErrReverted is returned for a call that reverted inside a multicall
+var ErrReverted = errors.New("execution reverted")
+
+func revertError(data []byte) error {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return fmt.Errorf("%w: %s", ErrReverted, reason)
+	}
+	if len(data) > 0 {
+		return fmt.Errorf("%w: %s", ErrReverted, hexutil.Encode(data))
+	}
+	return ErrReverted
+}
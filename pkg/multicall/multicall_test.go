@@ -0,0 +1,158 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var revertingTarget = common.HexToAddress("0xbad")
+
+// fakeBackend runs a call by returning its data reversed, or reverting for calls to
+// revertingTarget. It executes aggregate3 when multicall is set.
+type fakeBackend struct {
+	mu        sync.Mutex
+	multicall bool
+	noBatch   bool
+	single    int
+	batches   int
+	aggregate int
+}
+
+func execute(call Call) ([]byte, error) {
+	if call.To == revertingTarget {
+		return nil, errors.New("execution reverted")
+	}
+	out := make([]byte, len(call.Data))
+	for i, b := range call.Data {
+		out[len(out)-1-i] = b
+	}
+	return out, nil
+}
+
+func (b *fakeBackend) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	if b.multicall && account == Multicall3Address {
+		return []byte{1}, nil
+	}
+	return nil, nil
+}
+
+func (b *fakeBackend) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if *msg.To != Multicall3Address {
+		b.mu.Lock()
+		b.single++
+		b.mu.Unlock()
+		return execute(Call{To: *msg.To, Data: msg.Data})
+	}
+
+	b.mu.Lock()
+	b.aggregate++
+	b.mu.Unlock()
+	method := parsedMulticall3ABI.Methods["aggregate3"]
+	values, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	var calls []call3
+	if err := method.Inputs.Copy(&calls, values); err != nil {
+		return nil, err
+	}
+	results := make([]result3, len(calls))
+	for i, call := range calls {
+		data, err := execute(Call{To: call.Target, Data: call.CallData})
+		results[i] = result3{Success: err == nil, ReturnData: data}
+	}
+	return method.Outputs.Pack(results)
+}
+
+func (b *fakeBackend) BatchCallContext(_ context.Context, elems []rpc.BatchElem) error {
+	if b.noBatch {
+		return errors.New("batch requests are not supported")
+	}
+	b.mu.Lock()
+	b.batches++
+	b.mu.Unlock()
+	for i := range elems {
+		args := elems[i].Args[0].(map[string]interface{})
+		data, err := execute(Call{To: args["to"].(common.Address), Data: args["data"].(hexutil.Bytes)})
+		*elems[i].Result.(*hexutil.Bytes) = data
+		elems[i].Error = err
+	}
+	return nil
+}
+
+func testCalls(n int) []Call {
+	calls := make([]Call, n)
+	for i := range calls {
+		calls[i] = Call{To: common.HexToAddress("0x1"), Data: []byte{byte(i), 0xff}}
+	}
+	calls[n/2].To = revertingTarget
+	return calls
+}
+
+func checkResults(t *testing.T, calls []Call, results []Result) {
+	t.Helper()
+	if len(results) != len(calls) {
+		t.Fatalf("got %d results for %d calls", len(results), len(calls))
+	}
+	for i, r := range results {
+		if calls[i].To == revertingTarget {
+			if r.Err == nil {
+				t.Errorf("result %d: expected an error for the reverting call", i)
+			}
+			continue
+		}
+		if r.Err != nil || len(r.Data) != 2 || r.Data[0] != 0xff || r.Data[1] != byte(i) {
+			t.Errorf("result %d = %x, %v, want ff%02x", i, r.Data, r.Err, i)
+		}
+	}
+}
+
+func TestCallUsesMulticall(t *testing.T) {
+	b := &fakeBackend{multicall: true}
+	calls := testCalls(25)
+	results := New(b, Options{BatchSize: 10, Concurrency: 2}).Call(context.Background(), calls)
+
+	checkResults(t, calls, results)
+	if !errors.Is(results[12].Err, ErrReverted) {
+		t.Errorf("err = %v, want ErrReverted", results[12].Err)
+	}
+	if b.aggregate != 3 || b.batches != 0 || b.single != 0 {
+		t.Errorf("sent %d multicalls, %d batches and %d single calls, want 3 multicalls", b.aggregate, b.batches, b.single)
+	}
+}
+
+func TestCallUsesJSONRPCBatches(t *testing.T) {
+	b := &fakeBackend{}
+	calls := testCalls(25)
+	results := New(b, Options{BatchSize: 10}).Call(context.Background(), calls)
+
+	checkResults(t, calls, results)
+	if b.aggregate != 0 || b.batches != 3 || b.single != 0 {
+		t.Errorf("sent %d multicalls, %d batches and %d single calls, want 3 batches", b.aggregate, b.batches, b.single)
+	}
+
+	b = &fakeBackend{multicall: true}
+	New(b, Options{BatchSize: 10, DisableMulticall: true}).Call(context.Background(), calls)
+	if b.aggregate != 0 || b.batches != 3 {
+		t.Errorf("sent %d multicalls and %d batches with multicall disabled, want 3 batches", b.aggregate, b.batches)
+	}
+}
+
+func TestCallFallsBackToSingleCalls(t *testing.T) {
+	b := &fakeBackend{noBatch: true}
+	calls := testCalls(7)
+	results := New(b, DefaultOptions()).Call(context.Background(), calls)
+
+	checkResults(t, calls, results)
+	if b.single != len(calls) {
+		t.Errorf("sent %d single calls, want %d", b.single, len(calls))
+	}
+}
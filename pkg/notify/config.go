@@ -0,0 +1,259 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/oklog/ulid/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultInterval is the polling interval used when Config.Interval is not set
+	DefaultInterval = time.Minute
+	// DefaultMaxAttempts is the number of deliveries tried before an event is dead-lettered
+	DefaultMaxAttempts = 5
+	// DefaultTimeout bounds a single webhook request
+	DefaultTimeout = 10 * time.Second
+	// maxBlocksPerPoll bounds the block range read by one poll, so that catching up
+	// after downtime progresses (and persists) in steps
+	maxBlocksPerPoll = 20000
+)
+
+// Config selects the events a notifier watches and the webhooks it delivers them to
+type Config struct {
+	Interval       time.Duration  // time between two polls
+	Confirmations  uint64         // blocks an event must be buried under before it is reported
+	StartBlock     uint64         // first block read when there is no saved state (0 starts at the chain head)
+	StateFile      string         // where the last read block, watched deals and runway alerts are kept ("" keeps them in memory)
+	DeadLetterFile string         // JSON lines file receiving deliveries that failed every attempt
+	Operator       common.Address // rail.terminated is only reported for rails operated by this address, usually the DDO contract
+
+	RunwayThresholdDays float64   // funds.low fires when an account's runway drops below this many days
+	Accounts            []Account // payer accounts whose runway is watched
+	Deals               []DealWatch
+	Webhooks            []Webhook
+}
+
+// Account is a payer account of one token
+type Account struct {
+	Owner common.Address `json:"owner"`
+	Token common.Address `json:"token"`
+}
+
+// DealWatch is a Curio deal whose status is watched for failures. Without a Curio
+// API the provider's is discovered on chain.
+type DealWatch struct {
+	DealId       ulid.ULID       `json:"dealId"`
+	Provider     uint64          `json:"provider"`
+	AllocationId uint64          `json:"allocationId,omitempty"`
+	Client       *common.Address `json:"client,omitempty"`
+	CurioAPI     string          `json:"curioApi,omitempty"`
+}
+
+// Webhook is an endpoint receiving the events that pass its filter
+type Webhook struct {
+	Name        string
+	URL         string
+	Secret      string      // HMAC-SHA256 key signing the deliveries; "" sends them unsigned
+	Events      []EventType // empty subscribes to every event type
+	Filter      Filter
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+// Filter restricts a webhook to events about some clients, providers or tokens. Each
+// non-empty list must match; an event that does not carry the field never matches it.
+type Filter struct {
+	Clients   []common.Address
+	Providers []uint64
+	Tokens    []common.Address
+}
+
+// fileConfig is the notifier configuration file
+type fileConfig struct {
+	Interval       string `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Confirmations  uint64 `json:"confirmations,omitempty" yaml:"confirmations,omitempty"`
+	StartBlock     uint64 `json:"startBlock,omitempty" yaml:"startBlock,omitempty"`
+	StateFile      string `json:"stateFile,omitempty" yaml:"stateFile,omitempty"`
+	DeadLetterFile string `json:"deadLetterFile,omitempty" yaml:"deadLetterFile,omitempty"`
+	Runway         *struct {
+		ThresholdDays float64 `json:"thresholdDays" yaml:"thresholdDays"`
+		Accounts      []struct {
+			Address string `json:"address" yaml:"address"`
+			Token   string `json:"token" yaml:"token"`
+		} `json:"accounts" yaml:"accounts"`
+	} `json:"runway,omitempty" yaml:"runway,omitempty"`
+	CurioDeals []struct {
+		DealId       string `json:"dealId" yaml:"dealId"`
+		Provider     uint64 `json:"provider" yaml:"provider"`
+		AllocationId uint64 `json:"allocationId,omitempty" yaml:"allocationId,omitempty"`
+		CurioAPI     string `json:"curioApi,omitempty" yaml:"curioApi,omitempty"`
+	} `json:"curioDeals,omitempty" yaml:"curioDeals,omitempty"`
+	Webhooks []struct {
+		Name        string   `json:"name" yaml:"name"`
+		URL         string   `json:"url" yaml:"url"`
+		Secret      string   `json:"secret,omitempty" yaml:"secret,omitempty"` // $VAR and ${VAR} are read from the environment
+		Events      []string `json:"events,omitempty" yaml:"events,omitempty"`
+		Clients     []string `json:"clients,omitempty" yaml:"clients,omitempty"`
+		Providers   []uint64 `json:"providers,omitempty" yaml:"providers,omitempty"`
+		Tokens      []string `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+		MaxAttempts int      `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+		Timeout     string   `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	} `json:"webhooks" yaml:"webhooks"`
+}
+
+// LoadConfig reads a notifier configuration from a YAML or JSON (by .json extension) file
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read notifier config: %w", err)
+	}
+
+	var file fileConfig
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&file)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&file)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse notifier config: %w", err)
+	}
+
+	cfg := Config{
+		Confirmations:  file.Confirmations,
+		StartBlock:     file.StartBlock,
+		StateFile:      file.StateFile,
+		DeadLetterFile: file.DeadLetterFile,
+	}
+	if file.Interval != "" {
+		if cfg.Interval, err = time.ParseDuration(file.Interval); err != nil {
+			return Config{}, fmt.Errorf("invalid interval %q: %w", file.Interval, err)
+		}
+	}
+
+	if file.Runway != nil {
+		cfg.RunwayThresholdDays = file.Runway.ThresholdDays
+		for _, a := range file.Runway.Accounts {
+			if !common.IsHexAddress(a.Address) {
+				return Config{}, fmt.Errorf("invalid runway account address: %q", a.Address)
+			}
+			if !common.IsHexAddress(a.Token) {
+				return Config{}, fmt.Errorf("invalid token of runway account %s: %q", a.Address, a.Token)
+			}
+			cfg.Accounts = append(cfg.Accounts, Account{Owner: common.HexToAddress(a.Address), Token: common.HexToAddress(a.Token)})
+		}
+	}
+
+	for _, d := range file.CurioDeals {
+		id, err := ulid.Parse(d.DealId)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid Curio deal ID %q: %w", d.DealId, err)
+		}
+		cfg.Deals = append(cfg.Deals, DealWatch{DealId: id, Provider: d.Provider, AllocationId: d.AllocationId, CurioAPI: d.CurioAPI})
+	}
+
+	for i, w := range file.Webhooks {
+		hook := Webhook{
+			Name:        w.Name,
+			URL:         w.URL,
+			Secret:      os.ExpandEnv(w.Secret),
+			Filter:      Filter{Providers: w.Providers},
+			MaxAttempts: w.MaxAttempts,
+		}
+		if hook.Name == "" {
+			hook.Name = fmt.Sprintf("webhook-%d", i+1)
+		}
+		for _, e := range w.Events {
+			hook.Events = append(hook.Events, EventType(e))
+		}
+		for _, addr := range w.Clients {
+			if !common.IsHexAddress(addr) {
+				return Config{}, fmt.Errorf("webhook %s: invalid client address: %q", hook.Name, addr)
+			}
+			hook.Filter.Clients = append(hook.Filter.Clients, common.HexToAddress(addr))
+		}
+		for _, addr := range w.Tokens {
+			if !common.IsHexAddress(addr) {
+				return Config{}, fmt.Errorf("webhook %s: invalid token address: %q", hook.Name, addr)
+			}
+			hook.Filter.Tokens = append(hook.Filter.Tokens, common.HexToAddress(addr))
+		}
+		if w.Timeout != "" {
+			if hook.Timeout, err = time.ParseDuration(w.Timeout); err != nil {
+				return Config{}, fmt.Errorf("webhook %s: invalid timeout %q: %w", hook.Name, w.Timeout, err)
+			}
+		}
+		cfg.Webhooks = append(cfg.Webhooks, hook)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks that the configuration can be run
+func (cfg Config) Validate() error {
+	if len(cfg.Webhooks) == 0 {
+		return fmt.Errorf("no webhooks configured")
+	}
+	if cfg.Interval < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	if len(cfg.Accounts) > 0 && cfg.RunwayThresholdDays <= 0 {
+		return fmt.Errorf("runway accounts need a positive thresholdDays")
+	}
+
+	names := make(map[string]bool)
+	for _, w := range cfg.Webhooks {
+		if names[w.Name] {
+			return fmt.Errorf("duplicate webhook name %q", w.Name)
+		}
+		names[w.Name] = true
+
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook %s: invalid URL %q", w.Name, w.URL)
+		}
+		for _, e := range w.Events {
+			if !e.valid() {
+				return fmt.Errorf("webhook %s: unknown event type %q (expected one of %s)", w.Name, e, strings.Join(eventTypeNames(), ", "))
+			}
+		}
+		if w.MaxAttempts < 0 || w.Timeout < 0 {
+			return fmt.Errorf("webhook %s: maxAttempts and timeout must not be negative", w.Name)
+		}
+	}
+	return nil
+}
+
+// withDefaults fills in the unset intervals and retry settings
+func (cfg Config) withDefaults() Config {
+	if cfg.Interval == 0 {
+		cfg.Interval = DefaultInterval
+	}
+	webhooks := make([]Webhook, len(cfg.Webhooks))
+	for i, w := range cfg.Webhooks {
+		if w.MaxAttempts == 0 {
+			w.MaxAttempts = DefaultMaxAttempts
+		}
+		if w.Timeout == 0 {
+			w.Timeout = DefaultTimeout
+		}
+		webhooks[i] = w
+	}
+	cfg.Webhooks = webhooks
+	return cfg
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"sync"
+
+	"github.com/Eastore-project/ddo-client/pkg/curio"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+)
+
+// curioSource queries deal status on the providers' Curio MK20 APIs
+type curioSource struct {
+	rpcEndpoint string
+	rpcCfg      rpcclient.Config
+	privateKey  *ecdsa.PrivateKey
+
+	mu   sync.Mutex
+	apis map[uint64]string // discovered Curio APIs by provider
+}
+
+// NewCurio returns a Curio source authenticating with privateKey, the key the deals
+// were submitted with. The Curio API of a deal without one is discovered from its
+// provider's on-chain multiaddrs through rpcEndpoint, with the policy rpcCfg.
+func NewCurio(rpcEndpoint string, rpcCfg rpcclient.Config, privateKey *ecdsa.PrivateKey) Curio {
+	return &curioSource{rpcEndpoint: rpcEndpoint, rpcCfg: rpcCfg, privateKey: privateKey, apis: make(map[uint64]string)}
+}
+
+func (c *curioSource) DealStatus(ctx context.Context, deal DealWatch) (*curio.DealStatusResponse, error) {
+	curioAPI, err := c.curioAPI(deal)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := curio.NewClient(curioAPI, c.privateKey).DealStatus(ctx, deal.DealId)
+	if err != nil {
+		return nil, err
+	}
+	if resp.DDOV1 == nil {
+		return nil, fmt.Errorf("deal %s has no DDO status", deal.DealId)
+	}
+	return resp.DDOV1, nil
+}
+
+func (c *curioSource) curioAPI(deal DealWatch) (string, error) {
+	if deal.CurioAPI != "" {
+		return deal.CurioAPI, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if api, ok := c.apis[deal.Provider]; ok {
+		return api, nil
+	}
+	api, err := curio.DiscoverSPURL(c.rpcEndpoint, c.rpcCfg, deal.Provider)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover the Curio API of provider %d: %w", deal.Provider, err)
+	}
+	c.apis[deal.Provider] = api
+	return api, nil
+}
@@ -0,0 +1,235 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers of a webhook delivery
+const (
+	HeaderEvent     = "X-DDO-Event"
+	HeaderDelivery  = "X-DDO-Delivery"
+	HeaderTimestamp = "X-DDO-Timestamp"
+	HeaderSignature = "X-DDO-Signature"
+)
+
+const (
+	// queueSize is the number of events a webhook can fall behind by before new
+	// ones go straight to the dead-letter file
+	queueSize = 1024
+	// retryBase and retryMax bound the exponential backoff between attempts
+	retryBase = 2 * time.Second
+	retryMax  = 5 * time.Minute
+)
+
+// Sign returns the signature header of a delivery: the hex HMAC-SHA256, keyed by
+// the webhook secret, of the timestamp header, a dot and the body
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a delivery's signature header in constant time. Receivers should
+// also reject timestamps too far from their clock to prevent replays.
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// Delivery is a delivery that failed every attempt, as written to the dead-letter file
+type Delivery struct {
+	Webhook  string    `json:"webhook"`
+	URL      string    `json:"url"`
+	Event    Event     `json:"event"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// deadLetters appends failed deliveries to a JSON lines file
+type deadLetters struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (d *deadLetters) record(delivery Delivery) {
+	log.Errorw("webhook delivery failed", "webhook", delivery.Webhook, "event", delivery.Event.Id, "attempts", delivery.Attempts, "error", delivery.Error)
+	if d.path == "" {
+		return
+	}
+	if err := d.append(delivery); err != nil {
+		log.Errorw("failed to write dead letter", "path", d.path, "error", err)
+	}
+}
+
+func (d *deadLetters) append(delivery Delivery) error {
+	line, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+	f, err := os.OpenFile(d.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead-letter file: %w", err)
+	}
+	return nil
+}
+
+// deliverer sends the events of one webhook in order, retrying each with backoff
+type deliverer struct {
+	hook      Webhook
+	client    *http.Client
+	dead      *deadLetters
+	queue     chan Event
+	retryBase time.Duration
+	done      func(e Event) // called once e is delivered or dead-lettered, if set
+}
+
+func newDeliverer(hook Webhook, dead *deadLetters) *deliverer {
+	return &deliverer{
+		hook:      hook,
+		client:    &http.Client{Timeout: hook.Timeout},
+		dead:      dead,
+		queue:     make(chan Event, queueSize),
+		retryBase: retryBase,
+	}
+}
+
+// enqueue queues e without blocking the poller
+func (d *deliverer) enqueue(e Event) {
+	select {
+	case d.queue <- e:
+	default:
+		d.dead.record(Delivery{Webhook: d.hook.Name, URL: d.hook.URL, Event: e, Error: "delivery queue full", FailedAt: time.Now()})
+		d.finish(e)
+	}
+}
+
+func (d *deliverer) finish(e Event) {
+	if d.done != nil {
+		d.done(e)
+	}
+}
+
+// run delivers queued events until ctx is done. Events still queued then are
+// dead-lettered, so that stopping the notifier loses nothing.
+func (d *deliverer) run(ctx context.Context) {
+	for {
+		select {
+		case e := <-d.queue:
+			if ctx.Err() != nil {
+				d.stopped(e)
+				continue
+			}
+			d.deliver(ctx, e)
+			d.finish(e)
+		case <-ctx.Done():
+			for {
+				select {
+				case e := <-d.queue:
+					d.stopped(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (d *deliverer) stopped(e Event) {
+	d.dead.record(Delivery{Webhook: d.hook.Name, URL: d.hook.URL, Event: e, Error: "notifier stopped", FailedAt: time.Now()})
+	d.finish(e)
+}
+
+// deliver posts e until it is accepted, the attempts are used up or the response
+// says retrying is pointless, then dead-letters it on failure
+func (d *deliverer) deliver(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		d.dead.record(Delivery{Webhook: d.hook.Name, URL: d.hook.URL, Event: e, Error: err.Error(), FailedAt: time.Now()})
+		return err
+	}
+
+	backoff := d.retryBase
+	attempt := 0
+	for {
+		attempt++
+		retry, err := d.post(ctx, e, body)
+		if err == nil {
+			log.Debugw("webhook delivered", "webhook", d.hook.Name, "event", e.Id, "attempt", attempt)
+			return nil
+		}
+		if !retry || attempt >= d.hook.MaxAttempts || ctx.Err() != nil {
+			d.dead.record(Delivery{Webhook: d.hook.Name, URL: d.hook.URL, Event: e, Attempts: attempt, Error: err.Error(), FailedAt: time.Now()})
+			return err
+		}
+
+		log.Warnw("webhook delivery failed, retrying", "webhook", d.hook.Name, "event", e.Id, "attempt", attempt, "in", backoff, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			d.dead.record(Delivery{Webhook: d.hook.Name, URL: d.hook.URL, Event: e, Attempts: attempt, Error: err.Error(), FailedAt: time.Now()})
+			return err
+		}
+		backoff *= 2
+		if backoff > retryMax {
+			backoff = retryMax
+		}
+	}
+}
+
+// post sends one attempt. Network errors, 408, 429 and 5xx responses are retried;
+// other 4xx responses are not.
+func (d *deliverer) post(ctx context.Context, e Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ddo-client")
+	req.Header.Set(HeaderEvent, string(e.Type))
+	req.Header.Set(HeaderDelivery, e.Id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	if d.hook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(d.hook.Secret, timestamp, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook responded %s", resp.Status)
+}
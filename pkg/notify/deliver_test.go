@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	sig := Sign("secret", 1700000000, body)
+	if sig[:7] != "sha256=" {
+		t.Fatalf("signature %q lacks the sha256= prefix", sig)
+	}
+	if !Verify("secret", sig, 1700000000, body) {
+		t.Error("valid signature rejected")
+	}
+	if Verify("other", sig, 1700000000, body) {
+		t.Error("signature accepted with the wrong secret")
+	}
+	if Verify("secret", sig, 1700000001, body) {
+		t.Error("signature accepted with another timestamp")
+	}
+	if Verify("secret", sig, 1700000000, []byte(`{"id":"2"}`)) {
+		t.Error("signature accepted for another body")
+	}
+}
+
+func newTestDeliverer(t *testing.T, url string, maxAttempts int) (*deliverer, string) {
+	t.Helper()
+	deadPath := filepath.Join(t.TempDir(), "dead.jsonl")
+	d := newDeliverer(Webhook{Name: "test", URL: url, Secret: "secret", MaxAttempts: maxAttempts, Timeout: time.Second}, &deadLetters{path: deadPath})
+	d.retryBase = time.Millisecond
+	return d, deadPath
+}
+
+func readDeadLetters(t *testing.T, path string) []Delivery {
+	t.Helper()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var deliveries []Delivery
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var d Delivery
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			t.Fatalf("invalid dead letter %q: %v", scanner.Text(), err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries
+}
+
+func TestDeliverRetriesAndSigns(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if !Verify("secret", r.Header.Get(HeaderSignature), ts, body) {
+			t.Errorf("bad signature on attempt %d", calls.Load()+1)
+		}
+		if r.Header.Get(HeaderEvent) != string(EventFundsLow) || r.Header.Get(HeaderDelivery) != "evt-1" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	d, deadPath := newTestDeliverer(t, srv.URL, 5)
+	if err := d.deliver(context.Background(), Event{Id: "evt-1", Type: EventFundsLow}); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("%d attempts, want 3", calls.Load())
+	}
+	if dead := readDeadLetters(t, deadPath); len(dead) != 0 {
+		t.Errorf("delivered event was dead-lettered: %+v", dead)
+	}
+}
+
+func TestDeliverDeadLetters(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		attempts int32
+	}{
+		{"retries exhausted", http.StatusInternalServerError, 3},
+		{"rate limited", http.StatusTooManyRequests, 3},
+		{"rejected", http.StatusBadRequest, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			d, deadPath := newTestDeliverer(t, srv.URL, 3)
+			if err := d.deliver(context.Background(), Event{Id: "evt-1", Type: EventRailCreated}); err == nil {
+				t.Fatal("expected a delivery error")
+			}
+			if calls.Load() != tt.attempts {
+				t.Errorf("%d attempts, want %d", calls.Load(), tt.attempts)
+			}
+			dead := readDeadLetters(t, deadPath)
+			if len(dead) != 1 || dead[0].Event.Id != "evt-1" || dead[0].Webhook != "test" || dead[0].Attempts != int(tt.attempts) {
+				t.Errorf("dead letters = %+v", dead)
+			}
+		})
+	}
+}
+
+func TestDelivererStopDeadLettersQueue(t *testing.T) {
+	d, deadPath := newTestDeliverer(t, "http://127.0.0.1:1", 1)
+	d.enqueue(Event{Id: "a"})
+	d.enqueue(Event{Id: "b"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d.run(ctx)
+
+	dead := readDeadLetters(t, deadPath)
+	if len(dead) != 2 || dead[0].Error != "notifier stopped" {
+		t.Errorf("dead letters = %+v", dead)
+	}
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// EventType names an event delivered to webhooks
+type EventType string
+
+const (
+	EventAllocationActivated EventType = "allocation.activated"
+	EventRailCreated         EventType = "rail.created"
+	EventRailTerminated      EventType = "rail.terminated"
+	EventFundsLow            EventType = "funds.low"
+	EventCurioDealFailed     EventType = "curio.deal_failed"
+	// EventTest is sent to every webhook by Notifier.Test, regardless of its filter
+	EventTest EventType = "test"
+)
+
+var eventTypes = []EventType{EventAllocationActivated, EventRailCreated, EventRailTerminated, EventFundsLow, EventCurioDealFailed}
+
+func (t EventType) valid() bool {
+	for _, known := range eventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func eventTypeNames() []string {
+	names := make([]string, len(eventTypes))
+	for i, t := range eventTypes {
+		names[i] = string(t)
+	}
+	return names
+}
+
+// Event is the body of a webhook delivery. Client, Provider and Token are set when
+// the event concerns them and are what webhook filters match on. Id is stable across
+// retries and restarts, so receivers can drop duplicates.
+type Event struct {
+	Id       string          `json:"id"`
+	Type     EventType       `json:"type"`
+	Time     time.Time       `json:"time"`
+	Client   *common.Address `json:"client,omitempty"`
+	Provider uint64          `json:"provider,omitempty"`
+	Token    *common.Address `json:"token,omitempty"`
+	Data     interface{}     `json:"data"`
+}
+
+// RailTerminated is the data of a rail.terminated event
+type RailTerminated struct {
+	types.RailTerminatedEvent
+	Rail *types.RailView `json:"rail"`
+}
+
+// FundsLow is the data of a funds.low event
+type FundsLow struct {
+	ThresholdDays float64              `json:"thresholdDays"`
+	Health        *types.AccountHealth `json:"health"`
+}
+
+// DealFailed is the data of a curio.deal_failed event
+type DealFailed struct {
+	Deal   DealWatch `json:"deal"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// wants reports whether the webhook subscribes to the event type
+func (w Webhook) wants(t EventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the webhook receives e
+func (w Webhook) matches(e Event) bool {
+	if !w.wants(e.Type) {
+		return false
+	}
+	f := w.Filter
+	if len(f.Clients) > 0 && (e.Client == nil || !containsAddress(f.Clients, *e.Client)) {
+		return false
+	}
+	if len(f.Tokens) > 0 && (e.Token == nil || !containsAddress(f.Tokens, *e.Token)) {
+		return false
+	}
+	if len(f.Providers) > 0 && (e.Provider == 0 || !containsProvider(f.Providers, e.Provider)) {
+		return false
+	}
+	return true
+}
+
+func containsProvider(list []uint64, provider uint64) bool {
+	for _, p := range list {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddress(list []common.Address, addr common.Address) bool {
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
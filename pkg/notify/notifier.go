@@ -0,0 +1,536 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	logging "github.com/ipfs/go-log/v2"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/curio"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+	"github.com/Eastore-project/ddo-client/pkg/utils"
+)
+
+var log = logging.Logger("ddo/notify")
+
+// DDO is the part of the DDO contract client read by the notifier
+type DDO interface {
+	GetAllocationActivatedEvents(fromBlock, toBlock uint64) ([]types.AllocationActivatedEvent, error)
+	GetRailCreatedEvents(fromBlock, toBlock uint64) ([]types.DDORailCreatedEvent, error)
+	GetAllocationInfos(allocationIds []uint64) ([]*types.AllocationInfo, error)
+	GetSPConfigs(actorIds []uint64) ([]*types.SPConfig, []error)
+}
+
+// Payments is the part of the Payments contract client read by the notifier
+type Payments interface {
+	utils.AccountReader
+	GetRailTerminatedEvents(fromBlock, toBlock uint64) ([]types.RailTerminatedEvent, error)
+}
+
+// Chain reports the chain head
+type Chain interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error)
+}
+
+// Curio reports the status of a deal on its provider's Curio
+type Curio interface {
+	DealStatus(ctx context.Context, deal DealWatch) (*curio.DealStatusResponse, error)
+}
+
+// Notifier polls the DDO and Payments contracts, payer runways and Curio deals, and
+// delivers the resulting events to webhooks. Progress and the events not yet
+// delivered are kept in Config.StateFile, so that a restart neither misses nor
+// repeats events.
+type Notifier struct {
+	ddo      DDO
+	payments Payments
+	chain    Chain
+	curio    Curio
+	cfg      Config
+
+	dead       *deadLetters
+	deliverers []*deliverer
+
+	mu     sync.Mutex
+	state  *state
+	payees map[common.Address]uint64 // payment addresses of the providers in webhook filters
+}
+
+// New returns a notifier reading from the given clients, resuming from the saved
+// state if there is one. Events left undelivered by the previous run are queued
+// again. curio may be nil when no deals are watched.
+func New(ddo DDO, payments Payments, chain Chain, curio Curio, cfg Config) (*Notifier, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg = cfg.withDefaults()
+
+	st, err := loadState(cfg.StateFile)
+	if err != nil {
+		return nil, err
+	}
+	st.pruneFinished(cfg.Deals)
+	for _, d := range cfg.Deals {
+		st.addDeal(d)
+	}
+
+	n := &Notifier{
+		ddo:      ddo,
+		payments: payments,
+		chain:    chain,
+		curio:    curio,
+		cfg:      cfg,
+		dead:     &deadLetters{path: cfg.DeadLetterFile},
+		state:    st,
+	}
+	for _, hook := range cfg.Webhooks {
+		d := newDeliverer(hook, n.dead)
+		d.done = func(e Event) { n.delivered(d.hook.Name, e) }
+		n.deliverers = append(n.deliverers, d)
+	}
+	n.requeue()
+	return n, nil
+}
+
+// requeue queues the saved undelivered events again. Events of a webhook that is no
+// longer configured are dead-lettered.
+func (n *Notifier) requeue() {
+	byName := make(map[string]*deliverer)
+	for _, d := range n.deliverers {
+		byName[d.hook.Name] = d
+	}
+	for _, q := range append([]queuedEvent(nil), n.state.Queue...) {
+		if d, ok := byName[q.Webhook]; ok {
+			d.enqueue(q.Event)
+			continue
+		}
+		n.dead.record(Delivery{Webhook: q.Webhook, Event: q.Event, Error: "webhook no longer configured", FailedAt: time.Now()})
+		n.delivered(q.Webhook, q.Event)
+	}
+}
+
+// delivered drops an event that was delivered or dead-lettered from the saved queue
+func (n *Notifier) delivered(webhook string, e Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.state.dequeue(webhook, e.Id) {
+		n.saveStateLocked()
+	}
+}
+
+// Run polls now and then every Config.Interval until ctx is done. Deliveries still
+// queued when it returns are written to the dead-letter file.
+func (n *Notifier) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, d := range n.deliverers {
+		wg.Add(1)
+		go func(d *deliverer) {
+			defer wg.Done()
+			d.run(ctx)
+		}(d)
+	}
+	defer wg.Wait()
+
+	ticker := time.NewTicker(n.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := n.Poll(ctx); err != nil && ctx.Err() == nil {
+			log.Warnw("notifier poll incomplete", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// WatchDeal adds a Curio deal to the watched deals until it completes or fails
+func (n *Notifier) WatchDeal(deal DealWatch) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.state.addDeal(deal) {
+		n.saveStateLocked()
+	}
+}
+
+// Poll reads the events since the last poll and queues them for delivery. The queued
+// events are saved together with the last block read. Chain events are only consumed
+// when every contract read succeeded, so a failed poll is retried from the same block
+// by the next one.
+func (n *Notifier) Poll(ctx context.Context) error {
+	head, err := n.chain.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get chain head: %w", err)
+	}
+
+	events, to, chainErr := n.chainEvents(head)
+	events = append(events, n.runwayEvents(head)...)
+	events = append(events, n.dealEvents(ctx)...)
+
+	for _, e := range events {
+		n.dispatch(e)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if chainErr == nil {
+		n.state.LastBlock = to
+	}
+	n.saveStateLocked()
+	return chainErr
+}
+
+// Test sends a test event to every webhook, regardless of its filter, with a single
+// attempt and without dead-lettering. It returns the failures by webhook name.
+func (n *Notifier) Test(ctx context.Context) map[string]error {
+	e := Event{
+		Id:   fmt.Sprintf("test-%d", time.Now().UnixNano()),
+		Type: EventTest,
+		Time: time.Now().UTC(),
+		Data: map[string]string{"message": "test delivery from ddo notify"},
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return map[string]error{"": err}
+	}
+	failed := make(map[string]error)
+	for _, d := range n.deliverers {
+		if _, err := d.post(ctx, e, body); err != nil {
+			failed[d.hook.Name] = err
+		}
+	}
+	return failed
+}
+
+func (n *Notifier) dispatch(e Event) {
+	for _, d := range n.deliverers {
+		if d.hook.matches(e) {
+			log.Infow("event", "type", e.Type, "id", e.Id, "webhook", d.hook.Name)
+			n.mu.Lock()
+			n.state.Queue = append(n.state.Queue, queuedEvent{Webhook: d.hook.Name, Event: e})
+			n.mu.Unlock()
+			d.enqueue(e)
+		}
+	}
+}
+
+// wants reports whether any webhook subscribes to the event type
+func (n *Notifier) wants(t EventType) bool {
+	for _, w := range n.cfg.Webhooks {
+		if w.wants(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// orderedEvent is a chain event with its position, to deliver events in chain order
+type orderedEvent struct {
+	block uint64
+	index uint
+	event Event
+}
+
+// chainEvents reads the contract events of the confirmed blocks after the last
+// poll. It returns them in chain order with the last block read.
+func (n *Notifier) chainEvents(head *ethtypes.Header) ([]Event, uint64, error) {
+	n.mu.Lock()
+	last := n.state.LastBlock
+	n.mu.Unlock()
+
+	headBlock := head.Number.Uint64()
+	if headBlock < n.cfg.Confirmations {
+		return nil, last, nil
+	}
+	safe := headBlock - n.cfg.Confirmations
+
+	from := last + 1
+	if last == 0 {
+		if n.cfg.StartBlock == 0 {
+			// Without saved state start at the head instead of replaying history
+			return nil, safe, nil
+		}
+		from = n.cfg.StartBlock
+	}
+	if from > safe {
+		return nil, last, nil
+	}
+	to := safe
+	if to-from >= maxBlocksPerPoll {
+		to = from + maxBlocksPerPoll - 1
+	}
+
+	headTime := time.Unix(int64(head.Time), 0).UTC()
+	blockTime := func(block uint64) time.Time {
+		return utils.EpochToTime(block, headBlock, headTime)
+	}
+
+	var ordered []orderedEvent
+	if n.wants(EventAllocationActivated) {
+		evs, err := n.allocationActivatedEvents(from, to, blockTime)
+		if err != nil {
+			return nil, last, err
+		}
+		ordered = append(ordered, evs...)
+	}
+	if n.wants(EventRailCreated) {
+		evs, err := n.railCreatedEvents(from, to, blockTime)
+		if err != nil {
+			return nil, last, err
+		}
+		ordered = append(ordered, evs...)
+	}
+	if n.wants(EventRailTerminated) {
+		evs, err := n.railTerminatedEvents(from, to, blockTime)
+		if err != nil {
+			return nil, last, err
+		}
+		ordered = append(ordered, evs...)
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].block != ordered[j].block {
+			return ordered[i].block < ordered[j].block
+		}
+		return ordered[i].index < ordered[j].index
+	})
+	events := make([]Event, len(ordered))
+	for i, o := range ordered {
+		events[i] = o.event
+	}
+	return events, to, nil
+}
+
+func (n *Notifier) allocationActivatedEvents(from, to uint64, blockTime func(uint64) time.Time) ([]orderedEvent, error) {
+	activated, err := n.ddo.GetAllocationActivatedEvents(from, to)
+	if err != nil || len(activated) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint64, len(activated))
+	for i, a := range activated {
+		ids[i] = a.AllocationId
+	}
+	infos, err := n.ddo.GetAllocationInfos(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activated allocations: %w", err)
+	}
+
+	events := make([]orderedEvent, len(activated))
+	for i, a := range activated {
+		a := a
+		e := Event{
+			Id:       logEventId(a.TxHash, a.LogIndex),
+			Type:     EventAllocationActivated,
+			Time:     blockTime(a.BlockNumber),
+			Provider: a.Provider,
+			Data:     &a,
+		}
+		if info := infos[i]; info != nil {
+			e.Client, e.Token = &info.Client, &info.PaymentToken
+		}
+		events[i] = orderedEvent{block: a.BlockNumber, index: a.LogIndex, event: e}
+	}
+	return events, nil
+}
+
+func (n *Notifier) railCreatedEvents(from, to uint64, blockTime func(uint64) time.Time) ([]orderedEvent, error) {
+	created, err := n.ddo.GetRailCreatedEvents(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]orderedEvent, len(created))
+	for i, r := range created {
+		r := r
+		events[i] = orderedEvent{block: r.BlockNumber, index: r.LogIndex, event: Event{
+			Id:       logEventId(r.TxHash, r.LogIndex),
+			Type:     EventRailCreated,
+			Time:     blockTime(r.BlockNumber),
+			Client:   &r.Client,
+			Provider: r.ProviderId,
+			Token:    &r.Token,
+			Data:     &r,
+		}}
+	}
+	return events, nil
+}
+
+// railTerminatedEvents reports the terminations of rails operated by Config.Operator.
+// The Payments contract does not know providers, so the provider is only set for
+// payees that are the payment address of a provider in a webhook filter.
+func (n *Notifier) railTerminatedEvents(from, to uint64, blockTime func(uint64) time.Time) ([]orderedEvent, error) {
+	terminated, err := n.payments.GetRailTerminatedEvents(from, to)
+	if err != nil || len(terminated) == 0 {
+		return nil, err
+	}
+	payees, err := n.providerPayees()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []orderedEvent
+	for _, t := range terminated {
+		rail, err := n.payments.GetRail(t.RailId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get terminated rail %s: %w", t.RailId.String(), err)
+		}
+		if n.cfg.Operator != (common.Address{}) && rail.Operator != n.cfg.Operator {
+			continue
+		}
+		events = append(events, orderedEvent{block: t.BlockNumber, index: t.LogIndex, event: Event{
+			Id:       logEventId(t.TxHash, t.LogIndex),
+			Type:     EventRailTerminated,
+			Time:     blockTime(t.BlockNumber),
+			Client:   &rail.From,
+			Provider: payees[rail.To],
+			Token:    &rail.Token,
+			Data:     &RailTerminated{RailTerminatedEvent: t, Rail: rail},
+		}})
+	}
+	return events, nil
+}
+
+// providerPayees resolves the payment addresses of the providers that webhooks
+// filter on, once
+func (n *Notifier) providerPayees() (map[common.Address]uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.payees != nil {
+		return n.payees, nil
+	}
+
+	var providers []uint64
+	seen := make(map[uint64]bool)
+	for _, w := range n.cfg.Webhooks {
+		for _, p := range w.Filter.Providers {
+			if !seen[p] {
+				seen[p] = true
+				providers = append(providers, p)
+			}
+		}
+	}
+
+	payees := make(map[common.Address]uint64)
+	if len(providers) > 0 {
+		configs, errs := n.ddo.GetSPConfigs(providers)
+		for i, p := range providers {
+			if errs[i] != nil {
+				return nil, fmt.Errorf("failed to get payment address of provider %d: %w", p, errs[i])
+			}
+			if configs[i] != nil {
+				payees[configs[i].PaymentAddress] = p
+			}
+		}
+	}
+	n.payees = payees
+	return payees, nil
+}
+
+// runwayEvents reports the watched accounts whose runway dropped below the threshold
+// since the last poll. An account is reported again only after its runway recovered.
+func (n *Notifier) runwayEvents(head *ethtypes.Header) []Event {
+	if len(n.cfg.Accounts) == 0 || !n.wants(EventFundsLow) {
+		return nil
+	}
+
+	epoch := head.Number.Uint64()
+	headTime := time.Unix(int64(head.Time), 0).UTC()
+
+	var events []Event
+	for _, a := range n.cfg.Accounts {
+		a := a
+		health, err := utils.GetAccountHealth(n.payments, a.Token, a.Owner, epoch, headTime)
+		if err != nil {
+			log.Warnw("failed to get account health", "owner", a.Owner, "token", a.Token, "error", err)
+			continue
+		}
+		low := health.RunwayEpochs != nil && health.RunwayDays < n.cfg.RunwayThresholdDays
+
+		key := a.Owner.Hex() + "/" + a.Token.Hex()
+		n.mu.Lock()
+		alerted := n.state.LowFunds[key]
+		if low {
+			n.state.LowFunds[key] = true
+		} else {
+			delete(n.state.LowFunds, key)
+		}
+		n.mu.Unlock()
+
+		if low && !alerted {
+			events = append(events, Event{
+				Id:     fmt.Sprintf("funds.low-%s-%s-%d", a.Owner.Hex(), a.Token.Hex(), epoch),
+				Type:   EventFundsLow,
+				Time:   headTime,
+				Client: &a.Owner,
+				Token:  &a.Token,
+				Data:   &FundsLow{ThresholdDays: n.cfg.RunwayThresholdDays, Health: health},
+			})
+		}
+	}
+	return events
+}
+
+// dealEvents checks the watched Curio deals, reporting the failed ones. Deals are
+// watched until they complete or fail.
+func (n *Notifier) dealEvents(ctx context.Context) []Event {
+	n.mu.Lock()
+	deals := append([]DealWatch(nil), n.state.Deals...)
+	n.mu.Unlock()
+	if len(deals) == 0 || n.curio == nil || !n.wants(EventCurioDealFailed) {
+		return nil
+	}
+
+	var events []Event
+	for _, d := range deals {
+		status, err := n.curio.DealStatus(ctx, d)
+		if err != nil {
+			log.Warnw("failed to get Curio deal status", "deal", d.DealId, "provider", d.Provider, "error", err)
+			continue
+		}
+
+		switch status.State {
+		case curio.DealStateFailed:
+			events = append(events, Event{
+				Id:       "curio.deal_failed-" + d.DealId.String(),
+				Type:     EventCurioDealFailed,
+				Time:     time.Now().UTC(),
+				Client:   d.Client,
+				Provider: d.Provider,
+				Data:     &DealFailed{Deal: d, Status: status.State, Error: status.ErrorMsg},
+			})
+		case curio.DealStateComplete:
+		default:
+			continue
+		}
+
+		n.mu.Lock()
+		n.state.finishDeal(d.DealId, n.configuredDeal(d.DealId))
+		n.mu.Unlock()
+	}
+	return events
+}
+
+// configuredDeal reports whether the deal is watched by the config file
+func (n *Notifier) configuredDeal(id ulid.ULID) bool {
+	for _, d := range n.cfg.Deals {
+		if d.DealId == id {
+			return true
+		}
+	}
+	return false
+}
+
+// logEventId identifies a contract event by its log
+func logEventId(txHash common.Hash, logIndex uint) string {
+	return fmt.Sprintf("%s-%d", txHash.Hex(), logIndex)
+}
@@ -0,0 +1,444 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/Eastore-project/ddo-client/pkg/curio"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+var (
+	client      = common.HexToAddress("0xc1")
+	otherClient = common.HexToAddress("0xc2")
+	payee       = common.HexToAddress("0xa1")
+	token       = common.HexToAddress("0x70")
+	operator    = common.HexToAddress("0xdd")
+)
+
+type fakeDDO struct {
+	activated []types.AllocationActivatedEvent
+	created   []types.DDORailCreatedEvent
+	err       error
+	ranges    [][2]uint64
+}
+
+func (f *fakeDDO) GetAllocationActivatedEvents(from, to uint64) ([]types.AllocationActivatedEvent, error) {
+	f.ranges = append(f.ranges, [2]uint64{from, to})
+	return f.activated, f.err
+}
+
+func (f *fakeDDO) GetRailCreatedEvents(from, to uint64) ([]types.DDORailCreatedEvent, error) {
+	return f.created, nil
+}
+
+func (f *fakeDDO) GetAllocationInfos(ids []uint64) ([]*types.AllocationInfo, error) {
+	infos := make([]*types.AllocationInfo, len(ids))
+	for i := range ids {
+		infos[i] = &types.AllocationInfo{Client: client, Provider: 1000, PaymentToken: token}
+	}
+	return infos, nil
+}
+
+func (f *fakeDDO) GetSPConfigs(ids []uint64) ([]*types.SPConfig, []error) {
+	configs := make([]*types.SPConfig, len(ids))
+	for i, id := range ids {
+		if id == 1000 {
+			configs[i] = &types.SPConfig{PaymentAddress: payee}
+		}
+	}
+	return configs, make([]error, len(ids))
+}
+
+type fakePayments struct {
+	terminated   []types.RailTerminatedEvent
+	fundedUntil  int64
+	railOperator common.Address
+}
+
+func (f *fakePayments) GetRailTerminatedEvents(from, to uint64) ([]types.RailTerminatedEvent, error) {
+	return f.terminated, nil
+}
+
+func (f *fakePayments) GetAccountInfoIfSettled(token, owner common.Address) (*types.AccountSettledInfo, error) {
+	return &types.AccountSettledInfo{
+		FundedUntilEpoch:  big.NewInt(f.fundedUntil),
+		CurrentFunds:      big.NewInt(1000),
+		AvailableFunds:    big.NewInt(500),
+		CurrentLockupRate: big.NewInt(1),
+	}, nil
+}
+
+func (f *fakePayments) GetRailsForPayerAndToken(payer, token common.Address) ([]*types.RailInfo, error) {
+	return nil, nil
+}
+
+func (f *fakePayments) GetRail(railId *big.Int) (*types.RailView, error) {
+	return &types.RailView{Token: token, From: client, To: payee, Operator: f.railOperator}, nil
+}
+
+type fakeChain struct{ head uint64 }
+
+func (f *fakeChain) HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
+	return &ethtypes.Header{Number: new(big.Int).SetUint64(f.head), Time: 1700000000}, nil
+}
+
+type fakeCurio map[ulid.ULID]string
+
+func (f fakeCurio) DealStatus(ctx context.Context, deal DealWatch) (*curio.DealStatusResponse, error) {
+	state, ok := f[deal.DealId]
+	if !ok {
+		return nil, errors.New("unreachable")
+	}
+	return &curio.DealStatusResponse{State: state, ErrorMsg: "piece commp mismatch"}, nil
+}
+
+// queued drains the events queued for each webhook, by webhook name
+func queued(n *Notifier) map[string][]Event {
+	out := make(map[string][]Event)
+	for _, d := range n.deliverers {
+		for len(d.queue) > 0 {
+			out[d.hook.Name] = append(out[d.hook.Name], <-d.queue)
+		}
+	}
+	return out
+}
+
+func testConfig(t *testing.T) Config {
+	return Config{
+		Confirmations: 10,
+		StateFile:     filepath.Join(t.TempDir(), "state.json"),
+		Operator:      operator,
+		Webhooks: []Webhook{
+			{Name: "all", URL: "http://127.0.0.1:1/all"},
+			{Name: "provider", URL: "http://127.0.0.1:1/sp", Events: []EventType{EventRailTerminated, EventAllocationActivated}, Filter: Filter{Providers: []uint64{1000}}},
+			{Name: "other-client", URL: "http://127.0.0.1:1/c2", Filter: Filter{Clients: []common.Address{otherClient}}},
+		},
+	}
+}
+
+func TestPollChainEvents(t *testing.T) {
+	ddo := &fakeDDO{
+		activated: []types.AllocationActivatedEvent{{AllocationId: 7, Provider: 1000, RailId: big.NewInt(3), BlockNumber: 95, TxHash: common.HexToHash("0x1"), LogIndex: 2}},
+		created:   []types.DDORailCreatedEvent{{Client: client, StorageProvider: payee, Token: token, RailId: big.NewInt(3), ProviderId: 1000, AllocationId: 7, BlockNumber: 92, TxHash: common.HexToHash("0x2")}},
+	}
+	payments := &fakePayments{
+		railOperator: operator,
+		terminated:   []types.RailTerminatedEvent{{RailId: big.NewInt(3), EndEpoch: big.NewInt(500), BlockNumber: 95, TxHash: common.HexToHash("0x1"), LogIndex: 1}},
+	}
+	chain := &fakeChain{head: 100}
+	cfg := testConfig(t)
+
+	n, err := New(ddo, payments, chain, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without saved state the first poll starts at the confirmed head
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(ddo.ranges) != 0 || n.state.LastBlock != 90 {
+		t.Fatalf("first poll read %v, last block %d", ddo.ranges, n.state.LastBlock)
+	}
+
+	chain.head = 110
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(ddo.ranges) != 1 || ddo.ranges[0] != [2]uint64{91, 100} {
+		t.Fatalf("read ranges %v, want [91 100]", ddo.ranges)
+	}
+
+	got := queued(n)
+	all := got["all"]
+	if len(all) != 3 {
+		t.Fatalf("all: %d events, want 3", len(all))
+	}
+	// Chain order: block 92, then block 95 log 1 and log 2
+	if all[0].Type != EventRailCreated || all[1].Type != EventRailTerminated || all[2].Type != EventAllocationActivated {
+		t.Errorf("events out of chain order: %s, %s, %s", all[0].Type, all[1].Type, all[2].Type)
+	}
+	if all[2].Client == nil || *all[2].Client != client || all[2].Token == nil || *all[2].Token != token {
+		t.Errorf("activated event lacks client and token: %+v", all[2])
+	}
+	if all[1].Provider != 1000 {
+		t.Errorf("terminated rail provider = %d, want 1000 from the payee", all[1].Provider)
+	}
+	if len(got["provider"]) != 2 {
+		t.Errorf("provider webhook got %d events, want 2 (rail.created is not subscribed)", len(got["provider"]))
+	}
+	if len(got["other-client"]) != 0 {
+		t.Errorf("other-client webhook got %d events", len(got["other-client"]))
+	}
+
+	// The state is saved, so a new notifier resumes after block 100
+	n, err = New(ddo, payments, chain, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.state.LastBlock != 100 {
+		t.Errorf("resumed at block %d, want 100", n.state.LastBlock)
+	}
+}
+
+func TestPollChainErrorRetries(t *testing.T) {
+	ddo := &fakeDDO{err: errors.New("rpc down")}
+	cfg := testConfig(t)
+	cfg.StartBlock = 50
+
+	n, err := New(ddo, &fakePayments{railOperator: operator}, &fakeChain{head: 100}, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Poll(context.Background()); err == nil || !strings.Contains(err.Error(), "rpc down") {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+	if n.state.LastBlock != 0 {
+		t.Fatalf("last block advanced to %d after a failed read", n.state.LastBlock)
+	}
+
+	ddo.err = nil
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if ddo.ranges[1] != [2]uint64{50, 90} || n.state.LastBlock != 90 {
+		t.Errorf("retry read %v, last block %d", ddo.ranges[1], n.state.LastBlock)
+	}
+}
+
+func TestPollSkipsForeignRails(t *testing.T) {
+	payments := &fakePayments{
+		railOperator: common.HexToAddress("0xee"),
+		terminated:   []types.RailTerminatedEvent{{RailId: big.NewInt(3), EndEpoch: big.NewInt(500), BlockNumber: 95}},
+	}
+	cfg := testConfig(t)
+	cfg.StartBlock = 90
+
+	n, err := New(&fakeDDO{}, payments, &fakeChain{head: 110}, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := queued(n); len(got) != 0 {
+		t.Errorf("rail of another operator reported: %+v", got)
+	}
+}
+
+func TestPollRunway(t *testing.T) {
+	payments := &fakePayments{fundedUntil: 100 + 2880*10}
+	chain := &fakeChain{head: 100}
+	cfg := testConfig(t)
+	cfg.RunwayThresholdDays = 7
+	cfg.Accounts = []Account{{Owner: client, Token: token}}
+
+	n, err := New(&fakeDDO{}, payments, chain, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lowEvents := func() []Event {
+		t.Helper()
+		if err := n.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return queued(n)["all"]
+	}
+
+	if events := lowEvents(); len(events) != 0 {
+		t.Fatalf("10 days of runway reported as low: %+v", events)
+	}
+	payments.fundedUntil = 100 + 2880*5
+	events := lowEvents()
+	if len(events) != 1 || events[0].Type != EventFundsLow {
+		t.Fatalf("5 days of runway: %+v", events)
+	}
+	if data := events[0].Data.(*FundsLow); data.Health.RunwayDays != 5 || data.ThresholdDays != 7 {
+		t.Errorf("funds.low data = %+v", data)
+	}
+	// Still low: not reported again until the runway recovers
+	if events := lowEvents(); len(events) != 0 {
+		t.Errorf("low runway reported twice: %+v", events)
+	}
+	payments.fundedUntil = 100 + 2880*30
+	lowEvents()
+	payments.fundedUntil = 100 + 2880*3
+	if events := lowEvents(); len(events) != 1 {
+		t.Errorf("runway dropping again: %d events, want 1", len(events))
+	}
+}
+
+func TestPollCurioDeals(t *testing.T) {
+	failed, complete, pending := ulid.Make(), ulid.Make(), ulid.Make()
+	statuses := fakeCurio{failed: curio.DealStateFailed, complete: curio.DealStateComplete, pending: "sealing"}
+	cfg := testConfig(t)
+	cfg.Deals = []DealWatch{{DealId: failed, Provider: 1000}, {DealId: complete, Provider: 1000}}
+
+	n, err := New(&fakeDDO{}, &fakePayments{}, &fakeChain{head: 100}, statuses, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.WatchDeal(DealWatch{DealId: pending, Provider: 1000, Client: &otherClient})
+	n.WatchDeal(DealWatch{DealId: pending, Provider: 1000})
+
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got := queued(n)
+	if len(got["all"]) != 1 || got["all"][0].Id != "curio.deal_failed-"+failed.String() {
+		t.Fatalf("deal events = %+v", got["all"])
+	}
+	if data := got["all"][0].Data.(*DealFailed); data.Error != "piece commp mismatch" {
+		t.Errorf("deal_failed data = %+v", data)
+	}
+	if len(n.state.Deals) != 1 || n.state.Deals[0].DealId != pending {
+		t.Errorf("watched deals after poll = %+v, want only the pending one", n.state.Deals)
+	}
+
+	// Finished deals of the config are not watched again after a restart
+	n, err = New(&fakeDDO{}, &fakePayments{}, &fakeChain{head: 100}, statuses, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.state.Deals) != 1 {
+		t.Errorf("restarted notifier watches %+v", n.state.Deals)
+	}
+
+	// Deals removed from the config are forgotten
+	cfg.Deals = cfg.Deals[:1]
+	n, err = New(&fakeDDO{}, &fakePayments{}, &fakeChain{head: 100}, statuses, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.state.Finished) != 1 || n.state.Finished[0] != failed {
+		t.Errorf("finished deals = %v, want only %s", n.state.Finished, failed)
+	}
+}
+
+func TestQueueSurvivesRestart(t *testing.T) {
+	ddo := &fakeDDO{
+		activated: []types.AllocationActivatedEvent{{AllocationId: 7, Provider: 1000, RailId: big.NewInt(3), BlockNumber: 95}},
+	}
+	cfg := testConfig(t)
+	cfg.StartBlock = 91
+
+	n, err := New(ddo, &fakePayments{railOperator: operator}, &fakeChain{head: 110}, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(n.state.Queue) != 2 {
+		t.Fatalf("saved queue = %+v, want the event for all and provider", n.state.Queue)
+	}
+
+	// Events never delivered are queued again by a new notifier
+	n, err = New(ddo, &fakePayments{railOperator: operator}, &fakeChain{head: 110}, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := queued(n)
+	if len(got["all"]) != 1 || len(got["provider"]) != 1 || got["all"][0].Type != EventAllocationActivated {
+		t.Fatalf("requeued events = %+v", got)
+	}
+
+	// Delivering or dead-lettering drops them from the saved queue
+	for _, d := range n.deliverers {
+		if d.hook.Name == "all" {
+			d.finish(got["all"][0])
+		}
+	}
+	st, err := loadState(cfg.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Queue) != 1 || st.Queue[0].Webhook != "provider" {
+		t.Errorf("saved queue after delivery = %+v", st.Queue)
+	}
+
+	// Events of a removed webhook are dead-lettered
+	cfg.Webhooks = cfg.Webhooks[:1]
+	cfg.DeadLetterFile = filepath.Join(t.TempDir(), "dead.jsonl")
+	n, err = New(ddo, &fakePayments{railOperator: operator}, &fakeChain{head: 110}, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.state.Queue) != 0 {
+		t.Errorf("queue kept %+v", n.state.Queue)
+	}
+	if dead := readDeadLetters(t, cfg.DeadLetterFile); len(dead) != 1 || dead[0].Webhook != "provider" {
+		t.Errorf("dead letters = %+v", dead)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("TEST_WEBHOOK_SECRET", "s3cret")
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cfg, err := LoadConfig(write("notify.yaml", `
+interval: 30s
+confirmations: 5
+runway:
+  thresholdDays: 7
+  accounts:
+    - address: "0x00000000000000000000000000000000000000c1"
+      token: "0x0000000000000000000000000000000000000070"
+curioDeals:
+  - dealId: 01HZ0000000000000000000000
+    provider: 1000
+webhooks:
+  - url: https://hooks.example.com/ddo
+    secret: ${TEST_WEBHOOK_SECRET}
+    events: [allocation.activated, funds.low]
+    providers: [1000]
+    timeout: 5s
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := cfg.Webhooks[0]
+	if cfg.Interval.String() != "30s" || cfg.Confirmations != 5 || len(cfg.Accounts) != 1 || len(cfg.Deals) != 1 {
+		t.Errorf("config = %+v", cfg)
+	}
+	if w.Name != "webhook-1" || w.Secret != "s3cret" || len(w.Events) != 2 || w.Filter.Providers[0] != 1000 || w.Timeout.String() != "5s" {
+		t.Errorf("webhook = %+v", w)
+	}
+
+	tests := []struct {
+		name, file, content, errContains string
+	}{
+		{"no webhooks", "a.yaml", "interval: 1m\n", "no webhooks"},
+		{"unknown field", "b.yaml", "webhook: []\n", "field webhook not found"},
+		{"unknown event", "c.yaml", "webhooks:\n  - url: http://x\n    events: [deal.done]\n", "unknown event type"},
+		{"bad url", "d.yaml", "webhooks:\n  - url: ftp://x\n", "invalid URL"},
+		{"bad client", "e.json", `{"webhooks":[{"url":"http://x","clients":["0x1"]}]}`, "invalid client address"},
+		{"runway without threshold", "f.yaml", "runway:\n  accounts:\n    - address: \"0x00000000000000000000000000000000000000c1\"\n      token: \"0x0000000000000000000000000000000000000070\"\nwebhooks:\n  - url: http://x\n", "thresholdDays"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(write(tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
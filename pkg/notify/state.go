@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// state is what a notifier persists between runs
+type state struct {
+	path string
+
+	LastBlock uint64          `json:"lastBlock"`          // last block whose events were queued
+	LowFunds  map[string]bool `json:"lowFunds,omitempty"` // accounts (owner/token) below the runway threshold
+	Deals     []DealWatch     `json:"deals,omitempty"`    // Curio deals still watched
+	Finished  []ulid.ULID     `json:"finished,omitempty"` // deals of the config file that completed or failed, not to be watched again
+	Queue     []queuedEvent   `json:"queue,omitempty"`    // events not yet delivered or dead-lettered
+}
+
+// queuedEvent is an event waiting for delivery to one webhook
+type queuedEvent struct {
+	Webhook string `json:"webhook"`
+	Event   Event  `json:"event"`
+}
+
+// loadState reads the state file; a missing file is an empty state
+func loadState(path string) (*state, error) {
+	st := &state{path: path, LowFunds: make(map[string]bool)}
+	if path == "" {
+		return st, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifier state: %w", err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("failed to parse notifier state %s: %w", path, err)
+	}
+	if st.LowFunds == nil {
+		st.LowFunds = make(map[string]bool)
+	}
+	return st, nil
+}
+
+// save writes the state file through a temporary file, so a crash never leaves it
+// half written
+func (st *state) save() error {
+	if st.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode notifier state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(st.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp := st.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write notifier state: %w", err)
+	}
+	if err := os.Rename(tmp, st.path); err != nil {
+		return fmt.Errorf("failed to write notifier state: %w", err)
+	}
+	return nil
+}
+
+// addDeal watches a deal unless it is already watched or finished
+func (st *state) addDeal(deal DealWatch) bool {
+	for _, id := range st.Finished {
+		if id == deal.DealId {
+			return false
+		}
+	}
+	for _, d := range st.Deals {
+		if d.DealId == deal.DealId {
+			return false
+		}
+	}
+	st.Deals = append(st.Deals, deal)
+	return true
+}
+
+// finishDeal stops watching a deal. Only deals of the config file, which are added
+// again on every start, are remembered as finished.
+func (st *state) finishDeal(id ulid.ULID, configured bool) {
+	for i, d := range st.Deals {
+		if d.DealId == id {
+			st.Deals = append(st.Deals[:i], st.Deals[i+1:]...)
+			break
+		}
+	}
+	if configured {
+		st.Finished = append(st.Finished, id)
+	}
+}
+
+// pruneFinished forgets the finished deals that are no longer in the config file
+func (st *state) pruneFinished(configured []DealWatch) {
+	keep := st.Finished[:0]
+	for _, id := range st.Finished {
+		for _, d := range configured {
+			if d.DealId == id {
+				keep = append(keep, id)
+				break
+			}
+		}
+	}
+	st.Finished = keep
+}
+
+// dequeue removes the queued delivery of the event id to webhook
+func (st *state) dequeue(webhook, id string) bool {
+	for i, q := range st.Queue {
+		if q.Webhook == webhook && q.Event.Id == id {
+			st.Queue = append(st.Queue[:i], st.Queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) saveStateLocked() {
+	if err := n.state.save(); err != nil {
+		log.Errorw("failed to save notifier state", "error", err)
+	}
+}
@@ -0,0 +1,61 @@
+package rpcclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// healthCheckTimeout bounds the health check of a new set of endpoints
+const healthCheckTimeout = 5 * time.Second
+
+// Dial connects to the comma-separated RPC endpoints with the policy cfg. See DialContext.
+func Dial(endpoints string, cfg Config) (*ethclient.Client, error) {
+	return DialContext(context.Background(), endpoints, cfg)
+}
+
+// DialContext connects to the comma-separated RPC endpoints, in order of preference,
+// with the policy cfg. HTTP(S) endpoints get failover, retries, rate limiting and
+// per-call timeouts; a WebSocket or IPC endpoint is dialed directly and must be the only one.
+func DialContext(ctx context.Context, endpoints string, cfg Config) (*ethclient.Client, error) {
+	list := ParseEndpoints(endpoints)
+	if len(list) == 1 && !isHTTP(list[0]) {
+		return ethclient.DialContext(ctx, list[0])
+	}
+
+	httpClient, err := HTTPClient(ctx, endpoints, cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := rpc.DialOptions(ctx, list[0], rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// HTTPClient returns an HTTP client sending JSON-RPC requests to the comma-separated
+// endpoints with the policy cfg, whatever URL they are addressed to. Several endpoints
+// are health checked before it is returned. The endpoint health and rate limit are
+// shared by the requests of the returned client only.
+func HTTPClient(ctx context.Context, endpoints string, cfg Config) (*http.Client, error) {
+	list := ParseEndpoints(endpoints)
+	transport, err := NewTransport(list, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) > 1 {
+		ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+		transport.CheckHealth(ctx)
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+func isHTTP(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://")
+}
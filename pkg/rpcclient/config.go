@@ -0,0 +1,41 @@
+package rpcclient
+
+import (
+	"strings"
+	"time"
+)
+
+// Config sets how requests to the RPC endpoints are retried, limited and timed out
+type Config struct {
+	Retries     int           // retries of a failed read, each on the next healthy endpoint
+	MinBackoff  time.Duration // base of the jittered exponential backoff between retries
+	MaxBackoff  time.Duration // longest wait between retries
+	RateLimit   float64       // requests per second across all endpoints, 0 for no limit
+	Burst       int           // requests allowed at once above the rate limit
+	CallTimeout time.Duration // timeout of a single HTTP request, 0 for none
+	Cooldown    time.Duration // how long a failing endpoint is skipped
+}
+
+// DefaultConfig returns 3 retries with 250ms-5s backoff, 30s per request, no rate
+// limit and a 30s cooldown for failing endpoints
+func DefaultConfig() Config {
+	return Config{
+		Retries:     3,
+		MinBackoff:  250 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+		Burst:       1,
+		CallTimeout: 30 * time.Second,
+		Cooldown:    30 * time.Second,
+	}
+}
+
+// ParseEndpoints splits a comma-separated list of RPC endpoints, in order of preference
+func ParseEndpoints(s string) []string {
+	var endpoints []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
@@ -0,0 +1,355 @@
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+)
+
+var log = logging.Logger("ddo/rpc")
+
+// endpoint is an RPC endpoint and its health
+type endpoint struct {
+	url       *url.URL
+	failures  int       // consecutive failures
+	skipUntil time.Time // set after a failure, zero while healthy
+	disabled  bool      // on the wrong chain
+}
+
+// Transport sends JSON-RPC requests over HTTP to the first healthy of several
+// endpoints. Reads are retried with jittered backoff on the next endpoint after a
+// network error, a timeout, a 429 or a 5xx. Other requests, like sending a
+// transaction, are only retried when they could not reach the endpoint at all.
+type Transport struct {
+	cfg       Config
+	base      http.RoundTripper
+	limiter   *limiter
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+// NewTransport returns a transport for the given HTTP(S) endpoints, in order of preference
+func NewTransport(endpoints []string, cfg Config) (*Transport, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no RPC endpoint configured")
+	}
+	t := &Transport{cfg: cfg, base: http.DefaultTransport, limiter: newLimiter(cfg.RateLimit, cfg.Burst)}
+	for _, e := range endpoints {
+		u, err := url.Parse(e)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid RPC endpoint %q (expected an http or https URL)", e)
+		}
+		t.endpoints = append(t.endpoints, &endpoint{url: u})
+	}
+	return t, nil
+}
+
+// retryableError is a failure worth retrying on another endpoint
+type retryableError struct {
+	err        error
+	retryAfter time.Duration // requested by the endpoint, 0 if not
+	sent       bool          // the request may have reached the endpoint
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// RoundTrip sends req, which must be a JSON-RPC request, ignoring its URL in favour of
+// the endpoints
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	idempotent := isIdempotent(body)
+
+	ctx := req.Context()
+	var tried []*endpoint
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		e := t.pick(tried)
+		tried = append(tried, e)
+		resp, err := t.send(req, e, body)
+		if err == nil {
+			t.markHealthy(e)
+			return resp, nil
+		}
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) {
+			return nil, err
+		}
+		t.markFailed(e)
+		if attempt >= t.cfg.Retries || (!idempotent && retryable.sent) || ctx.Err() != nil {
+			return nil, retryable.err
+		}
+
+		delay := t.backoff(attempt)
+		if retryable.retryAfter > delay {
+			delay = retryable.retryAfter
+		}
+		log.Debugw("retrying RPC request", "endpoint", e.url.Host, "attempt", attempt+1, "delay", delay, "error", retryable.err)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, retryable.err
+		}
+	}
+}
+
+// send makes one attempt at req on endpoint e
+func (t *Transport) send(req *http.Request, e *endpoint, body []byte) (*http.Response, error) {
+	ctx := req.Context()
+	var cancel context.CancelFunc = func() {}
+	if t.cfg.CallTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.cfg.CallTimeout)
+	}
+
+	out := req.Clone(ctx)
+	out.URL = e.url
+	out.Host = e.url.Host
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		cancel()
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		return nil, &retryableError{err: fmt.Errorf("%s: %w", e.url.Host, err), sent: !isDialError(err)}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+		cancel()
+		return nil, &retryableError{
+			err:        fmt.Errorf("%s: %s", e.url.Host, resp.Status),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			sent:       resp.StatusCode != http.StatusTooManyRequests,
+		}
+	}
+
+	// The per-call timeout lasts until the response body is read
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// pick returns the preferred healthy endpoint not yet tried for this request. When
+// every endpoint is failing, the one skipped for the least time is retried.
+func (t *Transport) pick(tried []*endpoint) *endpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var fallback *endpoint
+	for _, e := range t.endpoints {
+		if e.disabled {
+			continue
+		}
+		if now.After(e.skipUntil) && !contains(tried, e) {
+			return e
+		}
+		if fallback == nil || e.skipUntil.Before(fallback.skipUntil) {
+			fallback = e
+		}
+	}
+	if fallback == nil {
+		// Every endpoint is disabled; keep using the preferred one
+		return t.endpoints[0]
+	}
+	return fallback
+}
+
+func (t *Transport) markHealthy(e *endpoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e.failures > 0 {
+		log.Infow("RPC endpoint recovered", "endpoint", e.url.Host)
+	}
+	e.failures = 0
+	e.skipUntil = time.Time{}
+}
+
+func (t *Transport) markFailed(e *endpoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e.failures++
+	e.skipUntil = time.Now().Add(t.cfg.Cooldown)
+	if len(t.endpoints) > 1 {
+		log.Warnw("RPC endpoint failing, using the next one", "endpoint", e.url.Host, "failures", e.failures)
+	}
+}
+
+// backoff returns a random delay up to MinBackoff doubled per attempt, capped at MaxBackoff
+func (t *Transport) backoff(attempt int) time.Duration {
+	max := t.cfg.MinBackoff << attempt
+	if max <= 0 || (t.cfg.MaxBackoff > 0 && max > t.cfg.MaxBackoff) {
+		max = t.cfg.MaxBackoff
+	}
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)) + 1)
+}
+
+// CheckHealth asks every endpoint for its chain ID. Endpoints that do not answer are
+// skipped for the cooldown; endpoints on another chain than the preferred answering
+// one are no longer used.
+func (t *Transport) CheckHealth(ctx context.Context) {
+	chainIDs := make([]string, len(t.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range t.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			id, err := t.chainID(ctx, e)
+			if err != nil {
+				log.Warnw("RPC endpoint failed health check", "endpoint", e.url.Host, "error", err)
+				t.markFailed(e)
+				return
+			}
+			chainIDs[i] = id
+		}(i, e)
+	}
+	wg.Wait()
+
+	var expected string
+	for i, id := range chainIDs {
+		if id == "" {
+			continue
+		}
+		if expected == "" {
+			expected = id
+			continue
+		}
+		if id != expected {
+			log.Warnw("RPC endpoint is on another chain, not using it", "endpoint", t.endpoints[i].url.Host, "chainId", id, "expected", expected)
+			t.mu.Lock()
+			t.endpoints[i].disabled = true
+			t.mu.Unlock()
+		}
+	}
+}
+
+// chainID calls eth_chainId on e directly, without retries
+func (t *Transport) chainID(ctx context.Context, e *endpoint) (string, error) {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.send(req, e, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	if out.Error != nil {
+		return "", errors.New(out.Error.Message)
+	}
+	return out.Result, nil
+}
+
+// isIdempotent reports whether a JSON-RPC request, single or batched, only reads
+// state and can be sent again safely
+func isIdempotent(body []byte) bool {
+	type call struct {
+		Method string `json:"method"`
+	}
+	var calls []call
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &calls); err != nil {
+			return false
+		}
+	} else {
+		var c call
+		if err := json.Unmarshal(trimmed, &c); err != nil {
+			return false
+		}
+		calls = append(calls, c)
+	}
+
+	for _, c := range calls {
+		if strings.HasPrefix(c.Method, "eth_send") || strings.HasPrefix(c.Method, "Filecoin.MpoolPush") {
+			return false
+		}
+	}
+	return true
+}
+
+// isDialError reports whether err happened before the request was sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if seconds, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+func contains(endpoints []*endpoint, e *endpoint) bool {
+	for _, x := range endpoints {
+		if x == e {
+			return true
+		}
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelBody releases the per-call timeout once the response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
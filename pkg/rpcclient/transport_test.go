@@ -0,0 +1,196 @@
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testServer answers every request with result, after failing the first failures
+// requests with status
+type testServer struct {
+	*httptest.Server
+	hits atomic.Int32
+}
+
+func newTestServer(t *testing.T, failures int32, status int, result string) *testServer {
+	t.Helper()
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.hits.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":"`+result+`"}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.MinBackoff = time.Millisecond
+	cfg.MaxBackoff = 5 * time.Millisecond
+	return cfg
+}
+
+func post(t *testing.T, transport http.RoundTripper, method string) (string, error) {
+	t.Helper()
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":[]}`)
+	req, err := http.NewRequest(http.MethodPost, "http://ignored", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	return string(out), err
+}
+
+func TestTransportFailsOver(t *testing.T) {
+	primary := newTestServer(t, 100, http.StatusServiceUnavailable, "")
+	backup := newTestServer(t, 0, 0, "0x1")
+	transport, err := NewTransport([]string{primary.URL, backup.URL}, testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := post(t, transport, "eth_blockNumber"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The failing primary is skipped during its cooldown
+	if primary.hits.Load() != 1 || backup.hits.Load() != 3 {
+		t.Errorf("primary got %d requests and backup %d, want 1 and 3", primary.hits.Load(), backup.hits.Load())
+	}
+}
+
+func TestTransportRetriesRateLimitedReads(t *testing.T) {
+	s := newTestServer(t, 2, http.StatusTooManyRequests, "0x1")
+	transport, err := NewTransport([]string{s.URL}, testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := post(t, transport, "eth_call"); err != nil {
+		t.Fatal(err)
+	}
+	if s.hits.Load() != 3 {
+		t.Errorf("server got %d requests, want 3", s.hits.Load())
+	}
+
+	// Giving up after the configured retries
+	s = newTestServer(t, 100, http.StatusBadGateway, "")
+	cfg := testConfig()
+	cfg.Retries = 2
+	transport, _ = NewTransport([]string{s.URL}, cfg)
+	if _, err := post(t, transport, "eth_call"); err == nil {
+		t.Error("expected an error once retries are exhausted")
+	}
+	if s.hits.Load() != 3 {
+		t.Errorf("server got %d requests, want 3", s.hits.Load())
+	}
+}
+
+func TestTransportDoesNotResendTransactions(t *testing.T) {
+	s := newTestServer(t, 1, http.StatusInternalServerError, "0x1")
+	transport, err := NewTransport([]string{s.URL}, testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := post(t, transport, "eth_sendRawTransaction"); err == nil {
+		t.Error("expected the error of the first attempt")
+	}
+	if s.hits.Load() != 1 {
+		t.Errorf("server got %d requests, want 1", s.hits.Load())
+	}
+}
+
+func TestTransportCallTimeout(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+	backup := newTestServer(t, 0, 0, "0x1")
+
+	cfg := testConfig()
+	cfg.CallTimeout = 20 * time.Millisecond
+	transport, err := NewTransport([]string{slow.URL, backup.URL}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := post(t, transport, "eth_chainId"); err != nil {
+		t.Fatal(err)
+	}
+	if backup.hits.Load() != 1 {
+		t.Errorf("backup got %d requests, want 1", backup.hits.Load())
+	}
+}
+
+func TestCheckHealthDisablesOtherChains(t *testing.T) {
+	primary := newTestServer(t, 0, 0, "0x4cb2f")
+	other := newTestServer(t, 0, 0, "0x13a")
+	transport, err := NewTransport([]string{primary.URL, other.URL}, testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport.CheckHealth(context.Background())
+	if transport.endpoints[0].disabled || !transport.endpoints[1].disabled {
+		t.Errorf("disabled = %v/%v, want only the endpoint on another chain", transport.endpoints[0].disabled, transport.endpoints[1].disabled)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(50, 1)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first request is free, the other four wait 20ms each
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("5 requests at 50/s took %s, want at least 80ms", elapsed)
+	}
+
+	if newLimiter(0, 1).wait(context.Background()) != nil {
+		t.Error("a disabled limiter should never block")
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	for body, want := range map[string]bool{
+		`{"method":"eth_call"}`:                                    true,
+		`[{"method":"eth_call"},{"method":"eth_getBalance"}]`:      true,
+		`{"method":"eth_sendRawTransaction"}`:                      false,
+		`[{"method":"eth_call"},{"method":"eth_sendTransaction"}]`: false,
+		`{"method":"Filecoin.MpoolPushMessage"}`:                   false,
+		`not json`:                                                 false,
+	} {
+		if got := isIdempotent([]byte(body)); got != want {
+			t.Errorf("isIdempotent(%s) = %v, want %v", body, got, want)
+		}
+	}
+}
+
+func TestParseEndpoints(t *testing.T) {
+	got := ParseEndpoints(" https://a/rpc/v1, ,https://b ")
+	if len(got) != 2 || got[0] != "https://a/rpc/v1" || got[1] != "https://b" {
+		t.Errorf("ParseEndpoints = %q", got)
+	}
+}
@@ -0,0 +1,97 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrFeeCapReached is returned when a replacement would have to pay more than the
+// configured maximum fee per gas
+var ErrFeeCapReached = errors.New("replacement fee exceeds the maximum fee per gas")
+
+// suggestFees returns the priority fee and fee cap of a new transaction: twice the
+// current base fee plus the priority fee, capped at MaxFeePerGas. It returns nil fees
+// for a chain without EIP-1559, leaving the gas price to the node.
+func (m *Manager) suggestFees(ctx context.Context) (tip, feeCap *big.Int, err error) {
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get latest block header: %w", err)
+	}
+	if head.BaseFee == nil {
+		return nil, nil, nil
+	}
+
+	tip, err = m.priorityFee(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	feeCap = new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+
+	if max := m.cfg.MaxFeePerGas; max != nil && feeCap.Cmp(max) > 0 {
+		if max.Cmp(head.BaseFee) < 0 {
+			return nil, nil, fmt.Errorf("maximum fee per gas %s is below the current base fee %s", max, head.BaseFee)
+		}
+		feeCap = new(big.Int).Set(max)
+		if tip.Cmp(feeCap) > 0 {
+			tip = new(big.Int).Set(feeCap)
+		}
+	}
+	return tip, feeCap, nil
+}
+
+// priorityFee returns the configured priority fee, or the node's suggestion
+func (m *Manager) priorityFee(ctx context.Context) (*big.Int, error) {
+	if m.cfg.PriorityFee != nil {
+		return new(big.Int).Set(m.cfg.PriorityFee), nil
+	}
+	tip, err := m.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggested priority fee: %w", err)
+	}
+	return tip, nil
+}
+
+// bumpFees returns the fees of a replacement for a transaction paying oldTip and
+// oldFeeCap. Both are raised by at least percent, and to what a new transaction would
+// pay if that is more. It returns ErrFeeCapReached when the raised fees exceed max.
+func bumpFees(oldTip, oldFeeCap, suggestedTip, suggestedFeeCap *big.Int, percent uint64, max *big.Int) (tip, feeCap *big.Int, err error) {
+	minTip := bumpByPercent(oldTip, percent)
+	minFeeCap := bumpByPercent(oldFeeCap, percent)
+
+	tip, feeCap = minTip, minFeeCap
+	if suggestedTip != nil && suggestedTip.Cmp(tip) > 0 {
+		tip = new(big.Int).Set(suggestedTip)
+	}
+	if suggestedFeeCap != nil && suggestedFeeCap.Cmp(feeCap) > 0 {
+		feeCap = new(big.Int).Set(suggestedFeeCap)
+	}
+	if feeCap.Cmp(tip) < 0 {
+		feeCap = new(big.Int).Set(tip)
+	}
+
+	if max != nil && feeCap.Cmp(max) > 0 {
+		if max.Cmp(minFeeCap) < 0 {
+			return nil, nil, fmt.Errorf("%w: need %s, maximum is %s", ErrFeeCapReached, minFeeCap, max)
+		}
+		feeCap = new(big.Int).Set(max)
+		if tip.Cmp(feeCap) > 0 {
+			tip = new(big.Int).Set(feeCap)
+		}
+		if tip.Cmp(minTip) < 0 {
+			return nil, nil, fmt.Errorf("%w: need a priority fee of %s, maximum is %s", ErrFeeCapReached, minTip, max)
+		}
+	}
+	return tip, feeCap, nil
+}
+
+// bumpByPercent returns v raised by percent, and by at least 1
+func bumpByPercent(v *big.Int, percent uint64) *big.Int {
+	bumped := new(big.Int).Mul(v, new(big.Int).SetUint64(100+percent))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(v) <= 0 {
+		bumped.Add(v, big.NewInt(1))
+	}
+	return bumped
+}
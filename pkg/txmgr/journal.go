@@ -0,0 +1,129 @@
+package txmgr
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// Journal records the transactions a manager sends
+type Journal interface {
+	Record(entry types.TxJournalEntry) error
+}
+
+// FileJournal is a journal kept as one JSON object per line, appended to as
+// transactions are sent
+type FileJournal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileJournal returns the journal kept in the file at path
+func NewFileJournal(path string) *FileJournal {
+	return &FileJournal{path: path}
+}
+
+// Path returns the journal's file path
+func (j *FileJournal) Path() string {
+	return j.path
+}
+
+// Record appends entry to the journal, creating the file if needed
+func (j *FileJournal) Record(entry types.TxJournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// Entries returns the recorded transactions, oldest first. A missing journal has none.
+func (j *FileJournal) Entries() ([]types.TxJournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.Open(j.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []types.TxJournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // call data can be large
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry types.TxJournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid journal entry on line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// NewJournalEntry describes tx, sent by from, for the journal. replaces is the hash of
+// the transaction it replaces, if any.
+func NewJournalEntry(tx *ethtypes.Transaction, from common.Address, kind string, replaces *common.Hash) types.TxJournalEntry {
+	return types.TxJournalEntry{
+		Hash:      tx.Hash(),
+		Kind:      kind,
+		Replaces:  replaces,
+		ChainID:   tx.ChainId(),
+		From:      from,
+		To:        tx.To(),
+		Nonce:     tx.Nonce(),
+		Value:     tx.Value(),
+		Gas:       tx.Gas(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: tx.GasFeeCap(),
+		Data:      tx.Data(),
+		SentAt:    time.Now().UTC(),
+	}
+}
+
+// record adds tx to the configured journal. A failure is only logged, as the
+// transaction was already sent.
+func (m *Manager) record(tx *ethtypes.Transaction, from common.Address, kind string, replaces *common.Hash) {
+	if m.cfg.Journal == nil {
+		return
+	}
+	if err := m.cfg.Journal.Record(NewJournalEntry(tx, from, kind, replaces)); err != nil {
+		log.Warnw("failed to record transaction in journal", "txHash", tx.Hash().Hex(), "error", err)
+	}
+}
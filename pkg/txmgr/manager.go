@@ -0,0 +1,213 @@
+package txmgr
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+var log = logging.Logger("ddo/txmgr")
+
+// Backend is the part of an Ethereum client the manager uses. *ethclient.Client
+// implements it.
+type Backend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethtypes.Receipt, error)
+}
+
+// Config is the fee, replacement and confirmation policy of a Manager
+type Config struct {
+	MaxFeePerGas  *big.Int      // cap on the fee per gas, nil for no cap
+	PriorityFee   *big.Int      // priority fee per gas, nil to use the node's suggestion
+	Confirmations uint64        // blocks a receipt needs, including its own, before it is final
+	Timeout       time.Duration // how long Wait waits for a receipt, 0 for no limit
+	StuckAfter    time.Duration // how long a transaction may stay pending before it is replaced, 0 to never replace
+	BumpPercent   uint64        // fee increase of a replacement, in percent
+	MaxBumps      int           // replacements sent for one transaction at most
+	PollInterval  time.Duration // how often receipts are polled
+	Journal       Journal       // records sent transactions, nil to not record them
+}
+
+// DefaultConfig returns the policy used when none is set. Filecoin produces a block
+// every 30 seconds and its mempool only accepts a replacement paying 25% more.
+func DefaultConfig() Config {
+	return Config{
+		Confirmations: 1,
+		Timeout:       15 * time.Minute,
+		StuckAfter:    5 * time.Minute,
+		BumpPercent:   25,
+		MaxBumps:      3,
+		PollInterval:  5 * time.Second,
+	}
+}
+
+// Manager sends the transactions of all clients sharing one backend. It assigns nonces
+// locally so that transactions can be sent back-to-back, prices them with EIP-1559 fee
+// caps, replaces transactions stuck in the mempool and waits for their receipts with a
+// confirmation depth and a timeout.
+type Manager struct {
+	backend Backend
+	cfg     Config
+
+	mu      sync.Mutex
+	nonces  map[common.Address]uint64 // next nonce of each sender
+	pending map[common.Hash]*pendingTx
+}
+
+// pendingTx is a sent transaction and the replacements sent for it
+type pendingTx struct {
+	auth    *bind.TransactOpts
+	tx      *ethtypes.Transaction // latest version
+	hashes  []common.Hash         // every version sent, oldest first
+	sentAt  time.Time             // when the latest version was sent
+	bumps   int
+	cancels map[common.Hash]bool // versions that cancel the call
+}
+
+// New creates a manager for backend with the given policy. The clients sending over
+// one connection should share its manager, so that their nonces are tracked together.
+func New(backend Backend, cfg Config) *Manager {
+	return &Manager{
+		backend: backend,
+		cfg:     cfg,
+		nonces:  make(map[common.Address]uint64),
+		pending: make(map[common.Hash]*pendingTx),
+	}
+}
+
+// Config returns the manager's policy
+func (m *Manager) Config() Config {
+	return m.cfg
+}
+
+// Send sends the transaction built by send, usually a bound contract's Transact, with
+// a locally assigned nonce and EIP-1559 fees. A nonce or fee already set in auth is
+// kept. The transaction is tracked so that Wait can replace it if it gets stuck.
+func (m *Manager) Send(ctx context.Context, auth *bind.TransactOpts, send func(opts *bind.TransactOpts) (*ethtypes.Transaction, error)) (*ethtypes.Transaction, error) {
+	if auth == nil {
+		return nil, fmt.Errorf("client not configured for transactions (read-only mode)")
+	}
+
+	opts := *auth
+	opts.Context = ctx
+	reserved := opts.Nonce == nil
+	if reserved {
+		nonce, err := m.reserveNonce(ctx, auth.From)
+		if err != nil {
+			return nil, err
+		}
+		opts.Nonce = new(big.Int).SetUint64(nonce)
+	}
+
+	// The fee lookup and the send run without the lock, so that one slow call does
+	// not hold up the other senders
+	tx, err := m.priceAndSend(ctx, &opts, send)
+	if err != nil {
+		m.mu.Lock()
+		if reserved && !isNonceError(err) && m.nonces[auth.From] == opts.Nonce.Uint64()+1 {
+			// Nothing was reserved since; hand the nonce to the next send
+			m.nonces[auth.From] = opts.Nonce.Uint64()
+		} else if reserved || isNonceError(err) {
+			// The nonce is taken or left a gap; read it from the node next time
+			delete(m.nonces, auth.From)
+		}
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if next := tx.Nonce() + 1; next > m.nonces[auth.From] {
+		m.nonces[auth.From] = next
+	}
+	m.track(&pendingTx{auth: auth, tx: tx, hashes: []common.Hash{tx.Hash()}, sentAt: time.Now()})
+	m.mu.Unlock()
+
+	m.record(tx, auth.From, types.TxKindSent, nil)
+	log.Infow("transaction sent", "txHash", tx.Hash().Hex(), "nonce", tx.Nonce(), "feeCap", tx.GasFeeCap(), "tip", tx.GasTipCap())
+	return tx, nil
+}
+
+// priceAndSend sets the fees of opts, unless one is already set, and sends the
+// transaction built by send
+func (m *Manager) priceAndSend(ctx context.Context, opts *bind.TransactOpts, send func(opts *bind.TransactOpts) (*ethtypes.Transaction, error)) (*ethtypes.Transaction, error) {
+	if opts.GasPrice == nil && opts.GasFeeCap == nil {
+		tip, feeCap, err := m.suggestFees(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts.GasTipCap, opts.GasFeeCap = tip, feeCap
+	}
+	return send(opts)
+}
+
+// reserveNonce assigns the next nonce of from to the caller. Only the reservation
+// holds the lock.
+func (m *Manager) reserveNonce(ctx context.Context, from common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nonce, err := m.nextNonce(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+	m.nonces[from] = nonce + 1
+	return nonce, nil
+}
+
+// nextNonce returns the nonce of from's next transaction: the node's pending nonce,
+// or the locally tracked one when transactions sent since are not yet visible
+func (m *Manager) nextNonce(ctx context.Context, from common.Address) (uint64, error) {
+	nonce, err := m.backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce of %s: %w", from.Hex(), err)
+	}
+	if local, ok := m.nonces[from]; ok && local > nonce {
+		nonce = local
+	}
+	return nonce, nil
+}
+
+func (m *Manager) track(p *pendingTx) {
+	for _, h := range p.hashes {
+		m.pending[h] = p
+	}
+}
+
+func (m *Manager) forget(p *pendingTx) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, h := range p.hashes {
+		delete(m.pending, h)
+	}
+}
+
+func (m *Manager) lookup(hash common.Hash) *pendingTx {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pending[hash]
+}
+
+// isNonceError reports whether a send failed because the nonce was already used
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
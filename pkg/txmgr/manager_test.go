@@ -0,0 +1,479 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// fakeBackend is an in-memory chain. A sent transaction is mined when mine returns true
+// for it.
+type fakeBackend struct {
+	mu           sync.Mutex
+	pendingNonce uint64
+	baseFee      *big.Int
+	tip          *big.Int
+	head         uint64
+	sent         []*ethtypes.Transaction
+	receipts     map[common.Hash]*ethtypes.Receipt
+	mine         func(tx *ethtypes.Transaction) bool
+	status       uint64
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		baseFee:  big.NewInt(100),
+		tip:      big.NewInt(10),
+		head:     10,
+		receipts: make(map[common.Hash]*ethtypes.Receipt),
+		mine:     func(*ethtypes.Transaction) bool { return true },
+		status:   ethtypes.ReceiptStatusSuccessful,
+	}
+}
+
+func (b *fakeBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pendingNonce, nil
+}
+
+func (b *fakeBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return new(big.Int).Set(b.tip), nil
+}
+
+func (b *fakeBackend) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return new(big.Int).Add(b.baseFee, b.tip), nil
+}
+
+func (b *fakeBackend) HeaderByNumber(context.Context, *big.Int) (*ethtypes.Header, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &ethtypes.Header{Number: new(big.Int).SetUint64(b.head), BaseFee: b.baseFee}, nil
+}
+
+func (b *fakeBackend) BlockNumber(context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.head++
+	return b.head, nil
+}
+
+func (b *fakeBackend) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+
+func (b *fakeBackend) SendTransaction(_ context.Context, tx *ethtypes.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, tx)
+	if b.mine(tx) {
+		b.receipts[tx.Hash()] = &ethtypes.Receipt{
+			Status:      b.status,
+			TxHash:      tx.Hash(),
+			BlockNumber: new(big.Int).SetUint64(b.head),
+		}
+	}
+	return nil
+}
+
+func (b *fakeBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*ethtypes.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r, ok := b.receipts[hash]; ok {
+		return r, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func newTransactor(t *testing.T) *bind.TransactOpts {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(314159))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth
+}
+
+// sendCall builds, signs and sends a transaction the way a bound contract does
+func sendCall(b *fakeBackend) func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+	to := common.HexToAddress("0x1234")
+	return func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		tx, err := opts.Signer(opts.From, ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID:   big.NewInt(314159),
+			Nonce:     opts.Nonce.Uint64(),
+			GasTipCap: opts.GasTipCap,
+			GasFeeCap: opts.GasFeeCap,
+			Gas:       21000,
+			To:        &to,
+			Data:      []byte{1, 2, 3},
+		}))
+		if err != nil {
+			return nil, err
+		}
+		return tx, b.SendTransaction(opts.Context, tx)
+	}
+}
+
+func TestSendAssignsNonces(t *testing.T) {
+	b := newFakeBackend()
+	b.pendingNonce = 5
+	m := New(b, DefaultConfig())
+	auth := newTransactor(t)
+
+	for want := uint64(5); want < 8; want++ {
+		tx, err := m.Send(context.Background(), auth, sendCall(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.Nonce() != want {
+			t.Errorf("nonce = %d, want %d", tx.Nonce(), want)
+		}
+		if tx.GasTipCap().Int64() != 10 || tx.GasFeeCap().Int64() != 210 {
+			t.Errorf("fees = %s/%s, want 10/210", tx.GasTipCap(), tx.GasFeeCap())
+		}
+	}
+
+	// The node's nonce wins once it is ahead, e.g. after sends from another process
+	b.pendingNonce = 20
+	tx, err := m.Send(context.Background(), auth, sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 20 {
+		t.Errorf("nonce = %d, want 20", tx.Nonce())
+	}
+}
+
+func TestSendConcurrentNonces(t *testing.T) {
+	b := newFakeBackend()
+	m := New(b, DefaultConfig())
+	auth := newTransactor(t)
+
+	const senders = 10
+	nonces := make(chan uint64, senders)
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := m.Send(context.Background(), auth, sendCall(b))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			nonces <- tx.Nonce()
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := make(map[uint64]bool)
+	for nonce := range nonces {
+		if seen[nonce] || nonce >= senders {
+			t.Errorf("nonce %d assigned twice or out of range", nonce)
+		}
+		seen[nonce] = true
+	}
+}
+
+func TestSendReleasesNonceOnError(t *testing.T) {
+	b := newFakeBackend()
+	m := New(b, DefaultConfig())
+	auth := newTransactor(t)
+
+	_, err := m.Send(context.Background(), auth, func(*bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return nil, errors.New("execution reverted")
+	})
+	if err == nil {
+		t.Fatal("expected the send error")
+	}
+	tx, err := m.Send(context.Background(), auth, sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 0 {
+		t.Errorf("nonce = %d, want the released nonce 0", tx.Nonce())
+	}
+}
+
+func TestSendResetsNonceAfterNonceError(t *testing.T) {
+	b := newFakeBackend()
+	m := New(b, DefaultConfig())
+	auth := newTransactor(t)
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.Send(context.Background(), auth, sendCall(b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := m.Send(context.Background(), auth, func(*bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return nil, errors.New("nonce too low")
+	})
+	if err == nil {
+		t.Fatal("expected the send error")
+	}
+
+	// The second transaction was dropped by the node
+	b.pendingNonce = 1
+	tx, err := m.Send(context.Background(), auth, sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 1 {
+		t.Errorf("nonce = %d, want 1", tx.Nonce())
+	}
+}
+
+func TestSendCapsFees(t *testing.T) {
+	b := newFakeBackend()
+	cfg := DefaultConfig()
+	cfg.MaxFeePerGas = big.NewInt(150)
+	m := New(b, cfg)
+	auth := newTransactor(t)
+
+	tx, err := m.Send(context.Background(), auth, sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.GasFeeCap().Int64() != 150 {
+		t.Errorf("fee cap = %s, want 150", tx.GasFeeCap())
+	}
+
+	cfg.MaxFeePerGas = big.NewInt(50)
+	m = New(b, cfg)
+	if _, err := m.Send(context.Background(), auth, sendCall(b)); err == nil {
+		t.Error("expected an error for a maximum fee below the base fee")
+	}
+}
+
+func TestWaitReportsRevert(t *testing.T) {
+	b := newFakeBackend()
+	b.status = ethtypes.ReceiptStatusFailed
+	m := New(b, DefaultConfig())
+
+	tx, err := m.Send(context.Background(), newTransactor(t), sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := m.Wait(context.Background(), tx.Hash())
+	var reverted *RevertedError
+	if !errors.As(err, &reverted) {
+		t.Fatalf("err = %v, want a RevertedError", err)
+	}
+	if receipt == nil || reverted.TxHash != tx.Hash() {
+		t.Errorf("receipt = %v, reverted = %+v", receipt, reverted)
+	}
+}
+
+func TestWaitReplacesStuckTransaction(t *testing.T) {
+	b := newFakeBackend()
+	// Only replacements get mined
+	b.mine = func(tx *ethtypes.Transaction) bool { return len(b.sent) > 1 }
+
+	cfg := DefaultConfig()
+	cfg.StuckAfter = time.Nanosecond
+	cfg.PollInterval = time.Millisecond
+	m := New(b, cfg)
+
+	tx, err := m.Send(context.Background(), newTransactor(t), sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := m.Wait(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b.sent) != 2 {
+		t.Fatalf("sent %d transactions, want 2", len(b.sent))
+	}
+	replacement := b.sent[1]
+	if receipt.TxHash != replacement.Hash() {
+		t.Errorf("receipt is for %s, want the replacement %s", receipt.TxHash, replacement.Hash())
+	}
+	if replacement.Nonce() != tx.Nonce() || string(replacement.Data()) != string(tx.Data()) {
+		t.Error("replacement does not repeat the call with the same nonce")
+	}
+	if replacement.GasTipCap().Int64() < 12 || replacement.GasFeeCap().Int64() < 262 {
+		t.Errorf("replacement fees = %s/%s, want at least 25%% more than 10/210", replacement.GasTipCap(), replacement.GasFeeCap())
+	}
+}
+
+func TestWaitConfirmations(t *testing.T) {
+	b := newFakeBackend()
+	cfg := DefaultConfig()
+	cfg.Confirmations = 3
+	cfg.PollInterval = time.Millisecond
+	m := New(b, cfg)
+
+	tx, err := m.Send(context.Background(), newTransactor(t), sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := m.Wait(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head := b.head; head+1 < receipt.BlockNumber.Uint64()+3 {
+		t.Errorf("returned at head %d, before 3 confirmations of block %d", head, receipt.BlockNumber)
+	}
+}
+
+func TestWaitDoesNotReplaceMinedTransaction(t *testing.T) {
+	b := newFakeBackend()
+	cfg := DefaultConfig()
+	cfg.Confirmations = 3
+	cfg.StuckAfter = time.Nanosecond
+	cfg.PollInterval = time.Millisecond
+	m := New(b, cfg)
+
+	tx, err := m.Send(context.Background(), newTransactor(t), sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := m.Wait(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.sent) != 1 {
+		t.Errorf("sent %d transactions, want no replacement of the mined one", len(b.sent))
+	}
+	if receipt.TxHash != tx.Hash() {
+		t.Errorf("receipt is for %s, want %s", receipt.TxHash, tx.Hash())
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	b := newFakeBackend()
+	b.mine = func(*ethtypes.Transaction) bool { return false }
+	cfg := DefaultConfig()
+	cfg.Timeout = 20 * time.Millisecond
+	cfg.StuckAfter = 0
+	cfg.PollInterval = time.Millisecond
+	m := New(b, cfg)
+
+	tx, err := m.Send(context.Background(), newTransactor(t), sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Wait(context.Background(), tx.Hash()); !errors.Is(err, ErrTimeout) {
+		t.Errorf("err = %v, want ErrTimeout", err)
+	}
+	if p := m.lookup(tx.Hash()); p != nil {
+		t.Error("transaction still tracked after the timeout")
+	}
+}
+
+func TestCancelReplacesWithSelfTransfer(t *testing.T) {
+	b := newFakeBackend()
+	b.mine = func(tx *ethtypes.Transaction) bool { return len(b.sent) > 1 }
+	journal := NewFileJournal(filepath.Join(t.TempDir(), "transactions.jsonl"))
+	cfg := DefaultConfig()
+	cfg.PollInterval = time.Millisecond
+	cfg.Journal = journal
+	m := New(b, cfg)
+	auth := newTransactor(t)
+
+	tx, err := m.Send(context.Background(), auth, sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel, err := m.Cancel(context.Background(), auth, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancel.Nonce() != tx.Nonce() || *cancel.To() != auth.From || cancel.Value().Sign() != 0 || len(cancel.Data()) != 0 {
+		t.Error("cancellation is not an empty self-transfer with the same nonce")
+	}
+	if cancel.GasTipCap().Cmp(tx.GasTipCap()) <= 0 || cancel.GasFeeCap().Cmp(tx.GasFeeCap()) <= 0 {
+		t.Errorf("cancellation fees = %s/%s, want more than %s/%s", cancel.GasTipCap(), cancel.GasFeeCap(), tx.GasTipCap(), tx.GasFeeCap())
+	}
+
+	// Waiting for the original reports the cancellation that replaced it
+	receipt, err := m.Wait(context.Background(), tx.Hash())
+	var canceled *CanceledError
+	if !errors.As(err, &canceled) {
+		t.Fatalf("err = %v, want a CanceledError", err)
+	}
+	if receipt == nil || receipt.TxHash != cancel.Hash() || canceled.TxHash != tx.Hash() || canceled.Cancellation != cancel.Hash() {
+		t.Errorf("receipt = %v, canceled = %+v, want the cancellation %s", receipt, canceled, cancel.Hash())
+	}
+
+	entries, err := journal.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("journal has %d entries, want 2", len(entries))
+	}
+	if entries[0].Hash != tx.Hash() || entries[0].Kind != types.TxKindSent {
+		t.Errorf("first entry = %+v, want the sent transaction", entries[0])
+	}
+	if entries[1].Hash != cancel.Hash() || entries[1].Kind != types.TxKindCancel || entries[1].Replaces == nil || *entries[1].Replaces != tx.Hash() {
+		t.Errorf("second entry = %+v, want the cancellation of %s", entries[1], tx.Hash())
+	}
+}
+
+func TestSpeedUpRejectsOtherSender(t *testing.T) {
+	b := newFakeBackend()
+	m := New(b, DefaultConfig())
+
+	tx, err := m.Send(context.Background(), newTransactor(t), sendCall(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.SpeedUp(context.Background(), newTransactor(t), tx); err == nil {
+		t.Error("expected an error for a transaction sent by another key")
+	}
+	if len(b.sent) != 1 {
+		t.Errorf("sent %d transactions, want 1", len(b.sent))
+	}
+}
+
+func TestBumpFees(t *testing.T) {
+	tip, feeCap, err := bumpFees(big.NewInt(100), big.NewInt(1000), big.NewInt(50), big.NewInt(500), 25, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Int64() != 125 || feeCap.Int64() != 1250 {
+		t.Errorf("bumped fees = %s/%s, want 125/1250", tip, feeCap)
+	}
+
+	// A higher network price wins over the minimum bump
+	tip, feeCap, err = bumpFees(big.NewInt(100), big.NewInt(1000), big.NewInt(300), big.NewInt(2000), 25, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Int64() != 300 || feeCap.Int64() != 2000 {
+		t.Errorf("bumped fees = %s/%s, want 300/2000", tip, feeCap)
+	}
+
+	// The cap limits a bump but cannot undercut the minimum
+	if _, feeCap, err = bumpFees(big.NewInt(100), big.NewInt(1000), nil, big.NewInt(2000), 25, big.NewInt(1500)); err != nil || feeCap.Int64() != 1500 {
+		t.Errorf("capped fee cap = %v, err = %v, want 1500", feeCap, err)
+	}
+	if _, _, err = bumpFees(big.NewInt(100), big.NewInt(1000), nil, nil, 25, big.NewInt(1200)); !errors.Is(err, ErrFeeCapReached) {
+		t.Errorf("err = %v, want ErrFeeCapReached", err)
+	}
+
+	// Tiny values still increase
+	if tip, _, _ := bumpFees(big.NewInt(1), big.NewInt(1), nil, nil, 25, nil); tip.Int64() != 2 {
+		t.Errorf("bumped tip = %s, want 2", tip)
+	}
+}
@@ -0,0 +1,131 @@
+package txmgr
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// SpeedUp replaces the pending transaction tx with the same call paying higher fees
+func (m *Manager) SpeedUp(ctx context.Context, auth *bind.TransactOpts, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+	return m.Replace(ctx, auth, tx, tx.To(), tx.Value(), tx.Data(), tx.Gas(), types.TxKindSpeedUp)
+}
+
+// Cancel replaces the pending transaction tx with an empty transfer from the sender to
+// itself, so that the original call is never executed
+func (m *Manager) Cancel(ctx context.Context, auth *bind.TransactOpts, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+	to := auth.From
+	gas, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{From: auth.From, To: &to, Value: big.NewInt(0)})
+	if err != nil {
+		log.Warnw("failed to estimate gas of cancellation, reusing the original gas limit", "error", err)
+		gas = tx.Gas()
+	}
+	return m.Replace(ctx, auth, tx, &to, big.NewInt(0), nil, gas, types.TxKindCancel)
+}
+
+// Replace sends a transaction with tx's nonce and the given call, paying enough more
+// than tx to replace it in the mempool. auth must hold the key that sent tx. Waiting
+// for tx or the replacement returns whichever gets mined; waiting for tx returns a
+// *CanceledError when a cancellation got mined.
+func (m *Manager) Replace(ctx context.Context, auth *bind.TransactOpts, tx *ethtypes.Transaction, to *common.Address, value *big.Int, data []byte, gas uint64, kind string) (*ethtypes.Transaction, error) {
+	if auth == nil {
+		return nil, fmt.Errorf("client not configured for transactions (read-only mode)")
+	}
+	sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender of %s: %w", tx.Hash().Hex(), err)
+	}
+	if sender != auth.From {
+		return nil, fmt.Errorf("transaction %s was sent by %s, not by the configured key %s", tx.Hash().Hex(), sender.Hex(), auth.From.Hex())
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	replacement, err := m.sendReplacement(ctx, auth, tx, to, value, data, gas, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := m.pending[tx.Hash()]
+	if !ok {
+		p = &pendingTx{auth: auth, hashes: []common.Hash{tx.Hash()}}
+	}
+	p.tx = replacement
+	p.hashes = append(p.hashes, replacement.Hash())
+	p.sentAt = time.Now()
+	if kind == types.TxKindCancel || p.cancels[tx.Hash()] {
+		if p.cancels == nil {
+			p.cancels = make(map[common.Hash]bool)
+		}
+		p.cancels[replacement.Hash()] = true
+	}
+	m.track(p)
+	return replacement, nil
+}
+
+// sendReplacement signs and sends a replacement for old and records it in the journal.
+// The caller holds m.mu.
+func (m *Manager) sendReplacement(ctx context.Context, auth *bind.TransactOpts, old *ethtypes.Transaction, to *common.Address, value *big.Int, data []byte, gas uint64, kind string) (*ethtypes.Transaction, error) {
+	replacement, err := m.replacement(ctx, auth, old, to, value, data, gas)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.backend.SendTransaction(ctx, replacement); err != nil {
+		return nil, fmt.Errorf("failed to send replacement: %w", err)
+	}
+
+	replaces := old.Hash()
+	m.record(replacement, auth.From, kind, &replaces)
+	return replacement, nil
+}
+
+// replacement signs a transaction with old's nonce that pays enough more than old to
+// replace it in the mempool
+func (m *Manager) replacement(ctx context.Context, auth *bind.TransactOpts, old *ethtypes.Transaction, to *common.Address, value *big.Int, data []byte, gas uint64) (*ethtypes.Transaction, error) {
+	var inner ethtypes.TxData
+	if old.Type() == ethtypes.LegacyTxType {
+		suggested, err := m.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get suggested gas price: %w", err)
+		}
+		_, gasPrice, err := bumpFees(old.GasPrice(), old.GasPrice(), suggested, suggested, m.cfg.BumpPercent, m.cfg.MaxFeePerGas)
+		if err != nil {
+			return nil, err
+		}
+		inner = &ethtypes.LegacyTx{Nonce: old.Nonce(), GasPrice: gasPrice, Gas: gas, To: to, Value: value, Data: data}
+	} else {
+		suggestedTip, suggestedFeeCap, err := m.suggestFees(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tip, feeCap, err := bumpFees(old.GasTipCap(), old.GasFeeCap(), suggestedTip, suggestedFeeCap, m.cfg.BumpPercent, m.cfg.MaxFeePerGas)
+		if err != nil {
+			return nil, err
+		}
+		inner = &ethtypes.DynamicFeeTx{
+			ChainID:   old.ChainId(),
+			Nonce:     old.Nonce(),
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		}
+	}
+
+	signed, err := auth.Signer(auth.From, ethtypes.NewTx(inner))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign replacement: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,178 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// ErrTimeout is returned when a transaction is not mined within the configured timeout
+var ErrTimeout = errors.New("timed out waiting for transaction")
+
+// RevertedError is returned for a transaction that was mined but reverted
+type RevertedError struct {
+	TxHash      common.Hash
+	BlockNumber uint64
+}
+
+func (e *RevertedError) Error() string {
+	return fmt.Sprintf("transaction %s reverted in block %d", e.TxHash.Hex(), e.BlockNumber)
+}
+
+// CanceledError is returned when the cancellation sent for a transaction was mined
+// in its place, so that its call was never executed
+type CanceledError struct {
+	TxHash       common.Hash
+	Cancellation common.Hash
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("transaction %s was canceled by %s", e.TxHash.Hex(), e.Cancellation.Hex())
+}
+
+// Wait waits until the transaction hash, or a replacement sent for it, is mined with
+// the configured number of confirmations. A transaction sent by this manager that
+// stays pending longer than StuckAfter is replaced with higher fees. A reverted
+// transaction returns its receipt together with a *RevertedError, and one whose
+// cancellation was mined instead returns the cancellation's receipt together with a
+// *CanceledError. The transaction is no longer tracked once Wait returns.
+func (m *Manager) Wait(ctx context.Context, hash common.Hash) (*ethtypes.Receipt, error) {
+	if m.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.cfg.Timeout)
+		defer cancel()
+	}
+
+	p := m.lookup(hash)
+	if p != nil {
+		defer m.forget(p)
+	}
+	for {
+		receipt, confirmed, err := m.findReceipt(ctx, hash, p)
+		if err != nil {
+			return nil, err
+		}
+		if confirmed {
+			if p != nil && receipt.TxHash != hash && m.isCancellation(p, receipt.TxHash) {
+				return receipt, &CanceledError{TxHash: hash, Cancellation: receipt.TxHash}
+			}
+			if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+				return receipt, &RevertedError{TxHash: receipt.TxHash, BlockNumber: receipt.BlockNumber.Uint64()}
+			}
+			return receipt, nil
+		}
+
+		// A mined transaction waiting for confirmations is not stuck; replacing it
+		// would only send a transaction that can never be mined
+		if receipt == nil && p != nil && m.isStuck(p) {
+			if err := m.bump(ctx, p); err != nil {
+				log.Warnw("failed to replace stuck transaction", "txHash", hash.Hex(), "error", err)
+			}
+		}
+
+		if err := m.sleep(ctx); err != nil {
+			return nil, m.waitError(hash, err)
+		}
+	}
+}
+
+// findReceipt returns the receipt of any version of a transaction once it is mined, or
+// nil while none is, and whether the receipt has enough confirmations
+func (m *Manager) findReceipt(ctx context.Context, hash common.Hash, p *pendingTx) (*ethtypes.Receipt, bool, error) {
+	hashes := []common.Hash{hash}
+	if p != nil {
+		m.mu.Lock()
+		hashes = append([]common.Hash(nil), p.hashes...)
+		m.mu.Unlock()
+	}
+
+	for _, h := range hashes {
+		receipt, err := m.backend.TransactionReceipt(ctx, h)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, false, m.waitError(hash, ctx.Err())
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				log.Debugw("failed to get transaction receipt", "txHash", h.Hex(), "error", err)
+			}
+			continue
+		}
+		if m.cfg.Confirmations > 1 {
+			head, err := m.backend.BlockNumber(ctx)
+			if err != nil || head+1 < receipt.BlockNumber.Uint64()+m.cfg.Confirmations {
+				log.Debugw("transaction mined, waiting for confirmations", "txHash", h.Hex(),
+					"block", receipt.BlockNumber, "confirmations", m.cfg.Confirmations)
+				return receipt, false, nil
+			}
+		}
+		return receipt, true, nil
+	}
+	return nil, false, nil
+}
+
+// isCancellation reports whether hash is a cancellation sent for p
+func (m *Manager) isCancellation(p *pendingTx, hash common.Hash) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return p.cancels[hash]
+}
+
+func (m *Manager) isStuck(p *pendingTx) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg.StuckAfter > 0 && p.bumps < m.cfg.MaxBumps && time.Since(p.sentAt) >= m.cfg.StuckAfter
+}
+
+// bump replaces a stuck transaction with the same call paying higher fees
+func (m *Manager) bump(ctx context.Context, p *pendingTx) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old := p.tx
+	replacement, err := m.sendReplacement(ctx, p.auth, old, old.To(), old.Value(), old.Data(), old.Gas(), types.TxKindReplacement)
+	if err != nil {
+		return err
+	}
+
+	p.tx = replacement
+	p.hashes = append(p.hashes, replacement.Hash())
+	p.sentAt = time.Now()
+	p.bumps++
+	if p.cancels[old.Hash()] {
+		p.cancels[replacement.Hash()] = true
+	}
+	m.pending[replacement.Hash()] = p
+	log.Infow("replaced stuck transaction", "txHash", old.Hash().Hex(), "replacement", replacement.Hash().Hex(),
+		"feeCap", replacement.GasFeeCap(), "tip", replacement.GasTipCap())
+	return nil
+}
+
+func (m *Manager) sleep(ctx context.Context) error {
+	interval := m.cfg.PollInterval
+	if interval <= 0 {
+		interval = DefaultConfig().PollInterval
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// waitError reports why waiting for hash stopped
+func (m *Manager) waitError(hash common.Hash, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && m.cfg.Timeout > 0 {
+		return fmt.Errorf("%w %s after %s", ErrTimeout, hash.Hex(), m.cfg.Timeout)
+	}
+	return fmt.Errorf("stopped waiting for transaction %s: %w", hash.Hex(), err)
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -40,6 +41,20 @@ type Claim struct {
 	Sector    uint64 `json:"sector"`    // FilActorId
 }
 
+// ClaimInfo is a claim as reported by query-claim-info
+type ClaimInfo struct {
+	Index     int    `json:"index"`
+	Provider  uint64 `json:"provider"`
+	Client    uint64 `json:"client"`
+	Data      string `json:"data"`               // hex encoded
+	PieceCid  string `json:"pieceCid,omitempty"` // decoded from Data when possible
+	Size      uint64 `json:"size"`
+	TermMin   int64  `json:"termMin"`
+	TermMax   int64  `json:"termMax"`
+	TermStart int64  `json:"termStart"`
+	Sector    uint64 `json:"sector"`
+}
+
 // TokenConfig represents a token configuration for storage providers
 type TokenConfig struct {
 	Token                common.Address `json:"token"`
@@ -71,6 +86,39 @@ type AllocationInfo struct {
 	SectorNumber         uint64         `json:"sectorNumber"`
 }
 
+// AllocationDetails is an allocation's on-chain state together with its payment rail
+type AllocationDetails struct {
+	AllocationId         uint64         `json:"allocationId"`
+	Found                bool           `json:"found"`
+	Client               common.Address `json:"client"`
+	Provider             uint64         `json:"provider"`
+	Activated            bool           `json:"activated"`
+	PieceCidHash         common.Hash    `json:"pieceCidHash"`
+	PaymentToken         common.Address `json:"paymentToken"`
+	PieceSize            uint64         `json:"pieceSize"`
+	SectorNumber         uint64         `json:"sectorNumber"` // 0 until activated
+	PricePerBytePerEpoch *big.Int       `json:"pricePerBytePerEpoch"`
+	RailId               *big.Int       `json:"railId"`
+	Rail                 *RailView      `json:"rail,omitempty"`
+}
+
+// AllocationList is the allocations of a client or a provider
+type AllocationList struct {
+	Client        *common.Address     `json:"client,omitempty"`
+	Provider      uint64              `json:"provider,omitempty"`
+	Count         int                 `json:"count"`
+	AllocationIds []uint64            `json:"allocationIds,omitempty"` // omitted with --count-only
+	Allocations   []AllocationDetails `json:"allocations,omitempty"`   // set with --details
+}
+
+// AllocationRailInfo is an allocation's provider and payment rail as returned by getAllocationRailInfo
+type AllocationRailInfo struct {
+	AllocationId uint64    `json:"allocationId"`
+	RailId       uint64    `json:"railId"`
+	ProviderId   uint64    `json:"providerId"`
+	Rail         *RailView `json:"rail"`
+}
+
 // SPRegistrationParams contains all parameters needed for SP registration
 type SPRegistrationParams struct {
 	ActorId        uint64         `json:"actorId"`
@@ -81,3 +129,262 @@ type SPRegistrationParams struct {
 	MaxTermLength  int64          `json:"maxTermLength"`
 	TokenConfigs   []TokenConfig  `json:"tokenConfigs"`
 }
+
+// SPSpecToken is a token entry of a declarative storage provider spec
+type SPSpecToken struct {
+	Token                 string `json:"token" yaml:"token"`
+	PriceUSDPerTBPerMonth string `json:"priceUSDPerTBPerMonth" yaml:"priceUSDPerTBPerMonth"`
+	IsActive              *bool  `json:"isActive,omitempty" yaml:"isActive,omitempty"` // defaults to true
+}
+
+// SPSpec is the desired state of a storage provider, reconciled by `sp apply`
+type SPSpec struct {
+	ActorId        uint64        `json:"actorId" yaml:"actorId"`
+	PaymentAddress string        `json:"paymentAddress" yaml:"paymentAddress"`
+	MinPieceSize   uint64        `json:"minPieceSize" yaml:"minPieceSize"`
+	MaxPieceSize   uint64        `json:"maxPieceSize" yaml:"maxPieceSize"`
+	MinTermLength  int64         `json:"minTermLength" yaml:"minTermLength"`
+	MaxTermLength  int64         `json:"maxTermLength" yaml:"maxTermLength"`
+	Tokens         []SPSpecToken `json:"tokens" yaml:"tokens"`
+}
+
+// SPPlanAction is a contract call needed to move a storage provider to its spec
+type SPPlanAction string
+
+const (
+	SPActionRegister     SPPlanAction = "register"
+	SPActionUpdateConfig SPPlanAction = "update-config"
+	SPActionAddToken     SPPlanAction = "add-token"
+	SPActionUpdateToken  SPPlanAction = "update-token"
+	SPActionRemoveToken  SPPlanAction = "remove-token"
+)
+
+// SPFieldChange is a single field that a plan step changes
+type SPFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// SPPlanStep is one contract call of an `sp apply` plan. Token, price and active
+// flag are only set for token steps.
+type SPPlanStep struct {
+	Action               SPPlanAction    `json:"action"`
+	Token                common.Address  `json:"token,omitempty"`
+	PricePerBytePerEpoch *big.Int        `json:"pricePerBytePerEpoch,omitempty"`
+	IsActive             bool            `json:"isActive"`
+	Changes              []SPFieldChange `json:"changes"`
+}
+
+// SPApplyResult is the outcome of `sp apply`: the plan and the transactions sent for it.
+// Applied is false when nothing was sent, e.g. in a dry run.
+type SPApplyResult struct {
+	ActorId      uint64       `json:"actorId"`
+	Steps        []SPPlanStep `json:"steps"`
+	Applied      bool         `json:"applied"`
+	Transactions []TxResult   `json:"transactions"`
+}
+
+// ProviderClaim is a storage provider's verified registry claim for a DDO allocation
+type ProviderClaim struct {
+	AllocationId    uint64         `json:"allocationId"`
+	Client          common.Address `json:"client"`
+	ClientActorId   uint64         `json:"clientActorId"`
+	PieceCid        string         `json:"pieceCid"`
+	Size            uint64         `json:"size"`
+	Sector          uint64         `json:"sector"`
+	TermMin         int64          `json:"termMin"`
+	TermMax         int64          `json:"termMax"`
+	TermStart       int64          `json:"termStart"`
+	TermEnd         int64          `json:"termEnd"` // TermStart + TermMax
+	EpochsRemaining int64          `json:"epochsRemaining"`
+	EndsAt          time.Time      `json:"endsAt"`
+	Expiring        bool           `json:"expiring"` // ends within the requested window or already ended
+}
+
+// SPTokenPrice is a storage provider's monthly price for one token as reported by the contract
+type SPTokenPrice struct {
+	Token              common.Address `json:"token"`
+	PricePerTBPerMonth *big.Int       `json:"pricePerTBPerMonth"`
+	IsActive           bool           `json:"isActive"`
+}
+
+// SPListEntry is a registered storage provider as listed by `sp list`. Error is set
+// when its configuration could not be read.
+type SPListEntry struct {
+	ActorId uint64 `json:"actorId"`
+	SPConfig
+	Error string `json:"error,omitempty"`
+}
+
+// SPDetails is a storage provider's registration as reported by `sp query`
+type SPDetails struct {
+	ActorId         uint64           `json:"actorId"`
+	Registered      bool             `json:"registered"`
+	PaymentAddress  common.Address   `json:"paymentAddress"`
+	MinPieceSize    uint64           `json:"minPieceSize"`
+	MaxPieceSize    uint64           `json:"maxPieceSize"`
+	MinTermLength   int64            `json:"minTermLength"`
+	MaxTermLength   int64            `json:"maxTermLength"`
+	IsActive        bool             `json:"isActive"`
+	SupportedTokens []SPTokenDetails `json:"supportedTokens"`
+}
+
+// SPTokenDetails is the price of one token accepted by a storage provider
+type SPTokenDetails struct {
+	Token                common.Address `json:"token"`
+	PricePerBytePerEpoch *big.Int       `json:"pricePerBytePerEpoch"`
+	PricePerTBPerMonth   *big.Int       `json:"pricePerTBPerMonth"`
+	IsActive             bool           `json:"isActive"`
+}
+
+// SPSettlement is the outcome of `sp settle`: the settlement transactions sent and the
+// provider's payee accounts afterwards
+type SPSettlement struct {
+	ProviderId   uint64           `json:"providerId"`
+	AllocationId uint64           `json:"allocationId,omitempty"`
+	UntilEpoch   uint64           `json:"untilEpoch"`
+	Transactions []TxResult       `json:"transactions"`
+	Accounts     []AccountDetails `json:"accounts"`
+}
+
+// SPOffer is one storage provider and token combination found by `sp search`
+type SPOffer struct {
+	ProviderId           uint64         `json:"providerId"`
+	PaymentAddress       common.Address `json:"paymentAddress"`
+	Active               bool           `json:"active"` // both the SP and the token are active
+	MinPieceSize         uint64         `json:"minPieceSize"`
+	MaxPieceSize         uint64         `json:"maxPieceSize"`
+	MinTermLength        int64          `json:"minTermLength"`
+	MaxTermLength        int64          `json:"maxTermLength"`
+	Token                common.Address `json:"token"`
+	Decimals             uint8          `json:"decimals"`
+	PricePerBytePerEpoch *big.Int       `json:"pricePerBytePerEpoch"`
+	PricePerTBPerMonth   *big.Int       `json:"pricePerTBPerMonth"`
+	TotalCost            *big.Int       `json:"totalCost,omitempty"` // only set when a piece size and term are given
+	Endpoint             string         `json:"endpoint,omitempty"`
+	Reachable            bool           `json:"reachable"`
+	EndpointError        string         `json:"endpointError,omitempty"`
+}
+
+// AllocationQuote is the estimated cost of one allocation, computed by `allocations quote`
+// without preparing the data
+type AllocationQuote struct {
+	Provider               uint64         `json:"provider"`
+	Token                  common.Address `json:"token"`
+	DataSize               uint64         `json:"dataSize"`
+	PieceSize              uint64         `json:"pieceSize"` // padded piece size
+	TermLength             int64          `json:"termLength"`
+	PricePerBytePerEpoch   *big.Int       `json:"pricePerBytePerEpoch"`
+	StorageCost            *big.Int       `json:"storageCost"` // cost over the whole term
+	MonthlyCost            *big.Int       `json:"monthlyCost"`
+	AllocationLockupAmount *big.Int       `json:"allocationLockupAmount"`
+	RequiredDeposit        *big.Int       `json:"requiredDeposit"`
+	RateAllowance          *big.Int       `json:"rateAllowance"`
+	LockupAllowance        *big.Int       `json:"lockupAllowance"`
+	GasLimit               uint64         `json:"gasLimit,omitempty"`
+	GasPrice               *big.Int       `json:"gasPrice,omitempty"`
+	GasCost                *big.Int       `json:"gasCost,omitempty"` // in FIL, attoFIL units
+	GasError               string         `json:"gasError,omitempty"`
+}
+
+// ReplicaResult is the outcome of creating one replica of a dataset with a storage provider
+type ReplicaResult struct {
+	Provider       uint64   `json:"provider"`
+	TxHash         string   `json:"txHash,omitempty"`
+	AllocationIds  []uint64 `json:"allocationIds,omitempty"`
+	CurioSubmitted bool     `json:"curioSubmitted"`
+	CurioDealIds   []string `json:"curioDealIds,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// FileRange is a byte range of an input file packed into a piece
+type FileRange struct {
+	Path  string `json:"path"`
+	Size  int64  `json:"size"` // size of the whole file
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+}
+
+// PreparedPiece is a CAR file prepared for an allocation and the piece it forms
+type PreparedPiece struct {
+	PieceCid    string      `json:"pieceCid"`
+	PieceSize   uint64      `json:"pieceSize"`
+	PayloadCid  string      `json:"payloadCid,omitempty"`
+	CarSize     uint64      `json:"carSize"`
+	CarPath     string      `json:"carPath"`
+	DownloadURL string      `json:"downloadUrl,omitempty"`
+	Files       []FileRange `json:"files,omitempty"`     // only set when the input was split
+	SubPieces   []SubPiece  `json:"subPieces,omitempty"` // only set for aggregate pieces
+}
+
+// SubPiece is a CAR packed into an aggregate piece. Offset is in padded piece bytes.
+type SubPiece struct {
+	Source     string `json:"source"`
+	PieceCid   string `json:"pieceCid"`
+	PieceSize  uint64 `json:"pieceSize"`
+	PayloadCid string `json:"payloadCid,omitempty"`
+	CarSize    uint64 `json:"carSize"`
+	CarPath    string `json:"carPath,omitempty"`
+	Offset     uint64 `json:"offset"`
+}
+
+// PieceManifest maps the files of a prepared input to its pieces, and the sub-pieces
+// of an aggregate piece to their offsets
+type PieceManifest struct {
+	Input  string          `json:"input"`
+	Pieces []PreparedPiece `json:"pieces"`
+}
+
+// PieceCommitment is the piece CID of some data, computed locally
+type PieceCommitment struct {
+	PieceCidV1  string `json:"pieceCidV1"`
+	PieceCidV2  string `json:"pieceCidV2,omitempty"` // empty below 127 bytes
+	PieceSize   uint64 `json:"pieceSize"`            // padded piece size
+	PayloadSize uint64 `json:"payloadSize"`          // bytes read
+}
+
+// PieceVerification compares locally computed piece data with an allocation on-chain
+type PieceVerification struct {
+	AllocationId         uint64          `json:"allocationId"`
+	Source               string          `json:"source"`
+	Commitment           PieceCommitment `json:"commitment"`
+	PaddedPieceCid       string          `json:"paddedPieceCid,omitempty"` // set when the piece was padded to the allocation size
+	PieceCidHash         string          `json:"pieceCidHash"`
+	PieceSize            uint64          `json:"pieceSize"` // after padding
+	ExpectedPieceCidHash string          `json:"expectedPieceCidHash"`
+	ExpectedPieceSize    uint64          `json:"expectedPieceSize"`
+	HashMatches          bool            `json:"hashMatches"`
+	SizeMatches          bool            `json:"sizeMatches"`
+	ClaimChecked         bool            `json:"claimChecked"`
+	ClaimPieceCid        string          `json:"claimPieceCid,omitempty"`
+	ClaimSize            uint64          `json:"claimSize,omitempty"`
+	ClaimMatches         bool            `json:"claimMatches"`
+	Match                bool            `json:"match"`
+}
+
+// AllocationActivatedEvent represents an AllocationActivated event emitted by the DDO contract
+type AllocationActivatedEvent struct {
+	AllocationId uint64      `json:"allocationId"`
+	Provider     uint64      `json:"provider"`
+	Sector       uint64      `json:"sector"`
+	RailId       *big.Int    `json:"railId"`
+	PaymentRate  *big.Int    `json:"paymentRate"`
+	BlockNumber  uint64      `json:"blockNumber"`
+	TxHash       common.Hash `json:"txHash"`
+	LogIndex     uint        `json:"logIndex"`
+}
+
+// DDORailCreatedEvent represents a RailCreated event emitted by the DDO contract when
+// it opens the payment rail of an allocation
+type DDORailCreatedEvent struct {
+	Client          common.Address `json:"client"`
+	StorageProvider common.Address `json:"storageProvider"` // the provider's payment address
+	Token           common.Address `json:"token"`
+	RailId          *big.Int       `json:"railId"`
+	ProviderId      uint64         `json:"providerId"`
+	AllocationId    uint64         `json:"allocationId"`
+	BlockNumber     uint64         `json:"blockNumber"`
+	TxHash          common.Hash    `json:"txHash"`
+	LogIndex        uint           `json:"logIndex"`
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -14,6 +15,14 @@ type Account struct {
 	LockupLastSettledAt *big.Int `json:"lockupLastSettledAt"`
 }
 
+// AccountSettledInfo represents the return values of getAccountInfoIfSettled
+type AccountSettledInfo struct {
+	FundedUntilEpoch  *big.Int `json:"fundedUntilEpoch"`
+	CurrentFunds      *big.Int `json:"currentFunds"`
+	AvailableFunds    *big.Int `json:"availableFunds"`
+	CurrentLockupRate *big.Int `json:"currentLockupRate"`
+}
+
 // OperatorApproval represents the OperatorApproval struct from the Payments contract
 type OperatorApproval struct {
 	IsApproved      bool     `json:"isApproved"`
@@ -24,6 +33,48 @@ type OperatorApproval struct {
 	MaxLockupPeriod *big.Int `json:"maxLockupPeriod"`
 }
 
+// PaymentsContractInfo is the configuration of a Payments contract
+type PaymentsContractInfo struct {
+	Address               common.Address `json:"address"`
+	CommissionMaxBps      *big.Int       `json:"commissionMaxBps"`
+	NetworkFeeNumerator   *big.Int       `json:"networkFeeNumerator"`
+	NetworkFeeDenominator *big.Int       `json:"networkFeeDenominator"`
+}
+
+// AccountDetails is a payer or payee account for one token
+type AccountDetails struct {
+	Token   common.Address `json:"token"`
+	Address common.Address `json:"address"`
+	Account
+}
+
+// OperatorApprovalDetails is an operator's approval for an account and token, with the
+// allowances still available. After an update it holds the new approval.
+type OperatorApprovalDetails struct {
+	Token    common.Address `json:"token"`
+	Account  common.Address `json:"account"`
+	Operator common.Address `json:"operator"`
+	OperatorApproval
+	RateAvailable   *big.Int  `json:"rateAvailable"`
+	LockupAvailable *big.Int  `json:"lockupAvailable"`
+	Tx              *TxResult `json:"tx,omitempty"` // set by set-operator-allowance when a transaction was sent
+}
+
+// WithdrawResult is the outcome of withdrawing funds from a payments account
+type WithdrawResult struct {
+	Token  common.Address `json:"token"`
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Amount *big.Int       `json:"amount"`
+	Tx     TxResult       `json:"tx"`
+}
+
+// RailDetails is a rail with its ID
+type RailDetails struct {
+	RailId *big.Int `json:"railId"`
+	RailView
+}
+
 // RailView represents the RailView struct from the Payments contract
 type RailView struct {
 	Token               common.Address `json:"token"`
@@ -57,9 +108,111 @@ type SettlementResult struct {
 	Note                    string   `json:"note"`
 }
 
-// AccumulatedFeesResult represents the return from getAllAccumulatedFees
-type AccumulatedFeesResult struct {
-	Tokens  []common.Address `json:"tokens"`
-	Amounts []*big.Int       `json:"amounts"`
-	Count   *big.Int         `json:"count"`
+// AuctionInfo is the state of the Dutch auction selling a token's accumulated network fees
+type AuctionInfo struct {
+	Token      common.Address `json:"token"`
+	StartPrice *big.Int       `json:"startPrice"`
+	StartTime  *big.Int       `json:"startTime"`
+}
+
+// FeeAuctionStatus combines the accumulated fees of a token with its estimated auction price
+type FeeAuctionStatus struct {
+	Token           common.Address `json:"token"`
+	AccumulatedFees *big.Int       `json:"accumulatedFees"`
+	StartPrice      *big.Int       `json:"startPrice"`
+	StartTime       time.Time      `json:"startTime"`
+	CurrentPrice    *big.Int       `json:"currentPrice"`
+}
+
+// NetworkFees is the payments contract's network fee rate with the fee auction of each queried token
+type NetworkFees struct {
+	NetworkFeeNumerator   *big.Int            `json:"networkFeeNumerator"`
+	NetworkFeeDenominator *big.Int            `json:"networkFeeDenominator"`
+	BlockTime             time.Time           `json:"blockTime"`
+	Tokens                []*FeeAuctionStatus `json:"tokens"`
+}
+
+// RailProjection describes the expected outcome of settling a rail up to a
+// given epoch, split the same way the Payments contract splits a settlement
+type RailProjection struct {
+	RailId             *big.Int `json:"railId"`
+	FromEpoch          *big.Int `json:"fromEpoch"`
+	UntilEpoch         *big.Int `json:"untilEpoch"`
+	GrossPayout        *big.Int `json:"grossPayout"`
+	NetworkFee         *big.Int `json:"networkFee"`
+	OperatorCommission *big.Int `json:"operatorCommission"`
+	NetPayout          *big.Int `json:"netPayout"`
+	LockupRefund       *big.Int `json:"lockupRefund"`
+}
+
+// RailActionResult is the outcome of terminating, settling or finalizing a rail
+type RailActionResult struct {
+	Action       string          `json:"action"` // terminate, settle or finalize
+	RailId       *big.Int        `json:"railId"`
+	AllocationId uint64          `json:"allocationId,omitempty"`
+	Projection   *RailProjection `json:"projection"`
+	Tx           *TxResult       `json:"tx,omitempty"`          // not set when no transaction was sent
+	SettledUpTo  *big.Int        `json:"settledUpTo,omitempty"` // rail state after the transaction
+	EndEpoch     *big.Int        `json:"endEpoch,omitempty"`
+}
+
+// AccountHealth summarizes how long a payer account can keep funding its rails
+type AccountHealth struct {
+	Token            common.Address `json:"token"`
+	Owner            common.Address `json:"owner"`
+	CurrentEpoch     uint64         `json:"currentEpoch"`
+	CurrentFunds     *big.Int       `json:"currentFunds"`
+	AvailableFunds   *big.Int       `json:"availableFunds"`
+	LockupRate       *big.Int       `json:"lockupRate"`
+	BurnRatePerEpoch *big.Int       `json:"burnRatePerEpoch"`
+	BurnRatePerDay   *big.Int       `json:"burnRatePerDay"`
+	ActiveRails      int            `json:"activeRails"`
+	TerminatedRails  int            `json:"terminatedRails"`
+	FundedUntilEpoch *big.Int       `json:"fundedUntilEpoch,omitempty"`
+	RunwayEpochs     *big.Int       `json:"runwayEpochs,omitempty"`
+	RunwayDays       float64        `json:"runwayDays"`
+	RunsOutAt        *time.Time     `json:"runsOutAt,omitempty"`
+	Unlimited        bool           `json:"unlimited"`
+}
+
+// RailSettledEvent represents a RailSettled event emitted by the Payments contract
+type RailSettledEvent struct {
+	RailId              *big.Int    `json:"railId"`
+	TotalSettledAmount  *big.Int    `json:"totalSettledAmount"`
+	TotalNetPayeeAmount *big.Int    `json:"totalNetPayeeAmount"`
+	OperatorCommission  *big.Int    `json:"operatorCommission"`
+	NetworkFee          *big.Int    `json:"networkFee"`
+	SettledUpTo         *big.Int    `json:"settledUpTo"`
+	BlockNumber         uint64      `json:"blockNumber"`
+	TxHash              common.Hash `json:"txHash"`
+}
+
+// RailOneTimePaymentEvent represents a RailOneTimePaymentProcessed event emitted by the Payments contract
+type RailOneTimePaymentEvent struct {
+	RailId             *big.Int    `json:"railId"`
+	NetPayeeAmount     *big.Int    `json:"netPayeeAmount"`
+	OperatorCommission *big.Int    `json:"operatorCommission"`
+	NetworkFee         *big.Int    `json:"networkFee"`
+	BlockNumber        uint64      `json:"blockNumber"`
+	TxHash             common.Hash `json:"txHash"`
+}
+
+// WithdrawEvent represents a WithdrawRecorded event emitted by the Payments contract
+type WithdrawEvent struct {
+	Token       common.Address `json:"token"`
+	From        common.Address `json:"from"`
+	To          common.Address `json:"to"`
+	Amount      *big.Int       `json:"amount"`
+	BlockNumber uint64         `json:"blockNumber"`
+	TxHash      common.Hash    `json:"txHash"`
+}
+
+// RailTerminatedEvent represents a RailTerminated event emitted by the Payments contract
+type RailTerminatedEvent struct {
+	RailId      *big.Int       `json:"railId"`
+	By          common.Address `json:"by"`
+	EndEpoch    *big.Int       `json:"endEpoch"`
+	BlockNumber uint64         `json:"blockNumber"`
+	TxHash      common.Hash    `json:"txHash"`
+	LogIndex    uint           `json:"logIndex"`
 }
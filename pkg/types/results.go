@@ -0,0 +1,31 @@
+package types
+
+// Transaction statuses reported in TxResult
+const (
+	TxStatusSuccess  = "success"
+	TxStatusReverted = "reverted"
+	TxStatusPending  = "pending"  // sent, but the receipt could not be fetched
+	TxStatusCanceled = "canceled" // a cancellation was mined in its place
+)
+
+// TxResult is the outcome of a transaction sent by a command
+type TxResult struct {
+	TxHash      string `json:"txHash"`
+	Replaces    string `json:"replaces,omitempty"` // hash of the stuck transaction this one replaced
+	Status      string `json:"status"`
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
+	GasUsed     uint64 `json:"gasUsed,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ContractStatus is the pause state of the DDO contract
+type ContractStatus struct {
+	Paused bool `json:"paused"`
+}
+
+// SectorBlacklistStatus reports whether a provider's sector is blacklisted
+type SectorBlacklistStatus struct {
+	Provider    uint64 `json:"provider"`
+	Sector      uint64 `json:"sector"`
+	Blacklisted bool   `json:"blacklisted"`
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AllocationRail links a DDO allocation to its payment rail
+type AllocationRail struct {
+	AllocationId uint64         `json:"allocationId"`
+	ProviderId   uint64         `json:"providerId"`
+	RailId       *big.Int       `json:"railId"`
+	Client       common.Address `json:"client"`
+	Payee        common.Address `json:"payee"`
+	Token        common.Address `json:"token"`
+}
+
+// PaymentRecord is a single payment made over a rail, either a settlement or a one-time payment
+type PaymentRecord struct {
+	Kind               string         `json:"kind"`
+	AllocationId       uint64         `json:"allocationId"`
+	ProviderId         uint64         `json:"providerId"`
+	RailId             *big.Int       `json:"railId"`
+	Client             common.Address `json:"client"`
+	Token              common.Address `json:"token"`
+	Gross              *big.Int       `json:"gross"`
+	NetworkFee         *big.Int       `json:"networkFee"`
+	OperatorCommission *big.Int       `json:"operatorCommission"`
+	Net                *big.Int       `json:"net"`
+	SettledUpTo        *big.Int       `json:"settledUpTo,omitempty"`
+	BlockNumber        uint64         `json:"blockNumber"`
+	Time               time.Time      `json:"time"`
+	TxHash             common.Hash    `json:"txHash"`
+}
+
+// StatementLine aggregates payment records that share a grouping key.
+// Fields that are not part of the grouping are left empty.
+type StatementLine struct {
+	Period             string         `json:"period,omitempty"`
+	AllocationId       uint64         `json:"allocationId,omitempty"`
+	ProviderId         uint64         `json:"providerId,omitempty"`
+	RailId             *big.Int       `json:"railId,omitempty"`
+	Token              common.Address `json:"token"`
+	Payments           int            `json:"payments"`
+	Gross              *big.Int       `json:"gross"`
+	NetworkFee         *big.Int       `json:"networkFee"`
+	OperatorCommission *big.Int       `json:"operatorCommission"`
+	Net                *big.Int       `json:"net"`
+}
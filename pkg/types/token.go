@@ -26,3 +26,22 @@ type TokenBalanceComparison struct {
 	Difference    *big.Int       `json:"difference"`
 	HasChange     bool           `json:"hasChange"`
 }
+
+// TokenMetadata holds the ERC20 metadata needed to format and parse token amounts
+type TokenMetadata struct {
+	Address  common.Address `json:"address"`
+	Name     string         `json:"name"`
+	Symbol   string         `json:"symbol"`
+	Decimals uint8          `json:"decimals"`
+}
+
+// TokenApproval is an owner's allowance of a token for the payments contract, along
+// with the approval transaction when one was sent
+type TokenApproval struct {
+	Owner     common.Address `json:"owner"`
+	Token     common.Address `json:"token"`
+	Spender   common.Address `json:"spender"`
+	Balance   *big.Int       `json:"balance"`
+	Allowance *big.Int       `json:"allowance"` // after the approval, when one was sent
+	Tx        *TxResult      `json:"tx,omitempty"`
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TxStatusDropped is the status of a transaction whose nonce was used by another
+// transaction, usually a replacement
+const TxStatusDropped = "dropped"
+
+// Kinds of transactions recorded in the transaction journal
+const (
+	TxKindSent        = "sent"
+	TxKindReplacement = "replacement" // sent by the transaction manager for a stuck transaction
+	TxKindSpeedUp     = "speedup"
+	TxKindCancel      = "cancel"
+)
+
+// TxJournalEntry is a transaction recorded in the local transaction journal
+type TxJournalEntry struct {
+	Hash      common.Hash     `json:"hash"`
+	Kind      string          `json:"kind"`
+	Replaces  *common.Hash    `json:"replaces,omitempty"`
+	ChainID   *big.Int        `json:"chainId"`
+	From      common.Address  `json:"from"`
+	To        *common.Address `json:"to,omitempty"`
+	Nonce     uint64          `json:"nonce"`
+	Value     *big.Int        `json:"value"`
+	Gas       uint64          `json:"gas"`
+	GasTipCap *big.Int        `json:"gasTipCap"`
+	GasFeeCap *big.Int        `json:"gasFeeCap"`
+	Data      hexutil.Bytes   `json:"data"`
+	SentAt    time.Time       `json:"sentAt"`
+}
+
+// TxListEntry is a journal entry with its current status, as listed by `tx list`
+type TxListEntry struct {
+	TxJournalEntry
+	Method string `json:"method,omitempty"`
+	Status string `json:"status"`
+}
+
+// DecodedCall is a contract call decoded with a known ABI
+type DecodedCall struct {
+	Contract string                 `json:"contract"` // DDO, Payments or ERC20
+	Method   string                 `json:"method"`
+	Args     map[string]interface{} `json:"args"`
+}
+
+// DecodedEvent is a receipt log decoded with a known ABI
+type DecodedEvent struct {
+	Contract string                 `json:"contract"`
+	Address  common.Address         `json:"address"`
+	Name     string                 `json:"name"`
+	Args     map[string]interface{} `json:"args"`
+}
+
+// TxDetails is a transaction and its receipt as reported by `tx status`
+type TxDetails struct {
+	Hash        common.Hash     `json:"hash"`
+	Status      string          `json:"status"`               // pending, success, reverted or dropped
+	ReplacedBy  *common.Hash    `json:"replacedBy,omitempty"` // journaled replacement of a dropped transaction
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to,omitempty"`
+	Nonce       uint64          `json:"nonce"`
+	Value       *big.Int        `json:"value"`
+	Gas         uint64          `json:"gas"`
+	GasTipCap   *big.Int        `json:"gasTipCap"`
+	GasFeeCap   *big.Int        `json:"gasFeeCap"`
+	BlockNumber uint64          `json:"blockNumber,omitempty"`
+	GasUsed     uint64          `json:"gasUsed,omitempty"`
+	Call        *DecodedCall    `json:"call,omitempty"`
+	Events      []DecodedEvent  `json:"events"`
+}
+
+// TxReplacement is the outcome of `tx speedup` or `tx cancel`
+type TxReplacement struct {
+	Action    string   `json:"action"` // speedup or cancel
+	Original  string   `json:"original"`
+	Nonce     uint64   `json:"nonce"`
+	GasTipCap *big.Int `json:"gasTipCap"`
+	GasFeeCap *big.Int `json:"gasFeeCap"`
+	Tx        TxResult `json:"tx"`
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	commp "github.com/filecoin-project/go-fil-commp-hashhash"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// CarPieceCommitment computes the piece CID and padded piece size of an existing CAR file,
+// along with its size
+func CarPieceCommitment(path string) (string, uint64, uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	commitment, err := ComputePieceCommitment(f)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("%s: %w", path, err)
+	}
+	return commitment.PieceCidV1, commitment.PieceSize, commitment.PayloadSize, nil
+}
+
+// LayoutSubPieces orders sub-pieces from largest to smallest and sets their offsets in
+// the aggregate piece. Placing the largest first keeps every sub-piece aligned to its own
+// size, so its commP is a subtree of the aggregate's. It returns the padded size of the
+// laid out sub-pieces.
+func LayoutSubPieces(subs []types.SubPiece) ([]types.SubPiece, uint64) {
+	ordered := make([]types.SubPiece, len(subs))
+	copy(ordered, subs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].PieceSize > ordered[j].PieceSize
+	})
+
+	var offset uint64
+	for i := range ordered {
+		ordered[i].Offset = offset
+		offset += ordered[i].PieceSize
+	}
+	return ordered, offset
+}
+
+// WriteAggregate writes laid out sub-pieces to w, each CAR zero-filled up to its unpadded
+// piece size, and returns the aggregate's commP, padded piece size and the bytes written.
+// The piece is padded up to minPieceSize when smaller.
+func WriteAggregate(w io.Writer, subs []types.SubPiece, minPieceSize uint64) ([]byte, uint64, uint64, error) {
+	cp := new(commp.Calc)
+	out := io.MultiWriter(w, cp)
+
+	var written uint64
+	for _, sub := range subs {
+		f, err := os.Open(sub.CarPath)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to open %s: %w", sub.CarPath, err)
+		}
+		n, err := io.Copy(out, f)
+		f.Close()
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to copy %s: %w", sub.CarPath, err)
+		}
+
+		unpadded := sub.PieceSize / 128 * 127
+		if uint64(n) > unpadded {
+			return nil, 0, 0, fmt.Errorf("%s is larger than its piece size %d", sub.CarPath, sub.PieceSize)
+		}
+		if _, err := io.CopyN(out, zeroReader{}, int64(unpadded)-n); err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to pad %s: %w", sub.CarPath, err)
+		}
+		written += unpadded
+	}
+
+	rawCommP, pieceSize, err := cp.Digest()
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to compute aggregate commP: %w", err)
+	}
+	if pieceSize < minPieceSize {
+		rawCommP, err = commp.PadCommP(rawCommP, pieceSize, minPieceSize)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to pad aggregate commP: %w", err)
+		}
+		pieceSize = minPieceSize
+	}
+	return rawCommP, pieceSize, written, nil
+}
+
+// zeroReader is an endless source of zero bytes
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
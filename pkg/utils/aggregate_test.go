@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	commcid "github.com/filecoin-project/go-fil-commcid"
+	commp "github.com/filecoin-project/go-fil-commp-hashhash"
+	"github.com/ipfs/go-cid"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestLayoutSubPieces(t *testing.T) {
+	ordered, size := LayoutSubPieces([]types.SubPiece{
+		{Source: "a", PieceSize: 128},
+		{Source: "b", PieceSize: 512},
+		{Source: "c", PieceSize: 256},
+		{Source: "d", PieceSize: 128},
+	})
+	if size != 1024 {
+		t.Errorf("size = %d, want 1024", size)
+	}
+
+	want := []struct {
+		source string
+		offset uint64
+	}{{"b", 0}, {"c", 512}, {"a", 768}, {"d", 896}}
+	for i, w := range want {
+		if ordered[i].Source != w.source || ordered[i].Offset != w.offset {
+			t.Errorf("ordered[%d] = %s at %d, want %s at %d", i, ordered[i].Source, ordered[i].Offset, w.source, w.offset)
+		}
+		if ordered[i].Offset%ordered[i].PieceSize != 0 {
+			t.Errorf("%s is not aligned to its size", ordered[i].Source)
+		}
+	}
+}
+
+func TestWriteAggregate(t *testing.T) {
+	dir := t.TempDir()
+	rng := rand.New(rand.NewSource(1))
+	writeRandom := func(name string, size int) string {
+		data := make([]byte, size)
+		rng.Read(data)
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	var subs []types.SubPiece
+	for _, f := range []struct {
+		name string
+		size int
+	}{{"small.car", 100}, {"large.car", 200}} {
+		path := writeRandom(f.name, f.size)
+		pieceCid, pieceSize, carSize, err := CarPieceCommitment(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		subs = append(subs, types.SubPiece{Source: path, CarPath: path, PieceCid: pieceCid, PieceSize: pieceSize, CarSize: carSize})
+	}
+	if subs[0].PieceSize != 128 || subs[1].PieceSize != 256 {
+		t.Fatalf("sub-piece sizes = %d, %d, want 128, 256", subs[0].PieceSize, subs[1].PieceSize)
+	}
+
+	ordered, _ := LayoutSubPieces(subs)
+	var out bytes.Buffer
+	rawCommP, pieceSize, written, err := WriteAggregate(&out, ordered, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pieceSize != 512 || written != 381 || uint64(out.Len()) != written {
+		t.Fatalf("aggregate = %d byte piece, %d bytes written (%d in buffer), want 512 and 381", pieceSize, written, out.Len())
+	}
+
+	// The aggregate root is built from the sub-piece commitments and a zero-filled 128 byte leaf
+	node := func(left, right []byte) []byte {
+		sum := sha256.Sum256(append(append([]byte{}, left...), right...))
+		sum[31] &= 0x3f
+		return sum[:]
+	}
+	commOf := func(pieceCid string) []byte {
+		c, err := cid.Decode(pieceCid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := commcid.CIDToDataCommitmentV1(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+	zeroCalc := new(commp.Calc)
+	zeroCalc.Write(make([]byte, 127))
+	zeroComm, _, err := zeroCalc.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := node(commOf(ordered[0].PieceCid), node(commOf(ordered[1].PieceCid), zeroComm))
+	if !bytes.Equal(rawCommP, want) {
+		t.Errorf("aggregate commP = %x, want %x", rawCommP, want)
+	}
+
+	// Padding up to a minimum piece size
+	_, pieceSize, _, err = WriteAggregate(&bytes.Buffer{}, ordered, 2048)
+	if err != nil || pieceSize != 2048 {
+		t.Errorf("padded aggregate = %d, %v, want 2048", pieceSize, err)
+	}
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// NewAllocationDetails combines an allocation's state and its payment rail
+func NewAllocationDetails(allocationId uint64, info *types.AllocationInfo, rail *types.RailView) types.AllocationDetails {
+	return types.AllocationDetails{
+		AllocationId:         allocationId,
+		Found:                info.Client != (common.Address{}),
+		Client:               info.Client,
+		Provider:             info.Provider,
+		Activated:            info.Activated,
+		PieceCidHash:         info.PieceCidHash,
+		PaymentToken:         info.PaymentToken,
+		PieceSize:            info.PieceSize,
+		SectorNumber:         info.SectorNumber,
+		PricePerBytePerEpoch: info.PricePerBytePerEpoch,
+		RailId:               info.RailId,
+		Rail:                 rail,
+	}
+}
+
+// GetAllocationDetails fetches the state and rail of many allocations with batched calls
+func GetAllocationDetails(client *ddo.Client, allocationIds []uint64) ([]types.AllocationDetails, error) {
+	infos, err := client.GetAllocationInfos(allocationIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocation info: %w", err)
+	}
+	rails, err := client.GetAllocationRailInfos(allocationIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocation rail info: %w", err)
+	}
+
+	details := make([]types.AllocationDetails, len(allocationIds))
+	for i, id := range allocationIds {
+		details[i] = NewAllocationDetails(id, infos[i], rails[i].Rail)
+	}
+	return details, nil
+}
+
+// CreateAllocations sets up payments for the pieces, unless skipPaymentSetup is set,
+// creates their allocation requests in one transaction and waits for it to be mined.
+// It returns the new allocation IDs, in piece order, and the transaction hash, which
+// is also returned with errors once the transaction was sent. progress, if set, is
+// called before each step.
+func CreateAllocations(
+	ddoClient *ddo.Client,
+	paymentsClient *payments.Client,
+	pieceInfos []types.PieceInfo,
+	client common.Address,
+	contractAddress common.Address,
+	auth *bind.TransactOpts,
+	skipPaymentSetup bool,
+	progress func(string),
+) ([]uint64, string, error) {
+	if progress == nil {
+		progress = func(string) {}
+	}
+
+	if !skipPaymentSetup {
+		progress("setting up payments")
+		err := CheckAndSetupPayments(ddoClient, paymentsClient, pieceInfos, client, contractAddress, auth)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to setup payments: %w", err)
+		}
+	}
+
+	progress("creating allocation requests")
+	txHash, err := ddoClient.CreateAllocationRequests(pieceInfos)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create allocation requests: %w", err)
+	}
+
+	progress(fmt.Sprintf("waiting for transaction %s", txHash))
+	receipt, err := WaitForTransactionWithReceipt(ddoClient.TxManager(), txHash)
+	if receipt != nil {
+		txHash = receipt.TxHash.Hex()
+	}
+	if err != nil {
+		return nil, txHash, err
+	}
+	allocationIds, err := ddo.ParseAllocationCreatedEvents(receipt)
+	if err != nil {
+		return nil, txHash, fmt.Errorf("failed to parse allocation events: %w", err)
+	}
+	if len(allocationIds) != len(pieceInfos) {
+		return allocationIds, txHash, fmt.Errorf("got %d allocation(s) for %d piece(s)", len(allocationIds), len(pieceInfos))
+	}
+	return allocationIds, txHash, nil
+}
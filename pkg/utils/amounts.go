@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// LookupTokenMetadata returns the cached metadata of a token for display. If the token
+// cannot be queried it falls back to USD_DECIMALS and the token address as symbol.
+func LookupTokenMetadata(rpcEndpoint string, rpcCfg rpcclient.Config, tokenAddr common.Address) *types.TokenMetadata {
+	meta, err := token.GetTokenMetadata(rpcEndpoint, rpcCfg, tokenAddr)
+	if err != nil {
+		log.Warnw("failed to get token metadata, assuming defaults", "token", tokenAddr.Hex(), "decimals", USD_DECIMALS, "error", err)
+		return &types.TokenMetadata{
+			Address:  tokenAddr,
+			Name:     tokenAddr.Hex(),
+			Symbol:   tokenAddr.Hex(),
+			Decimals: USD_DECIMALS,
+		}
+	}
+	return meta
+}
+
+// ParseUnits converts a decimal string such as "12.5" into base units of a token
+// with the given decimals. It is exact and rejects more fractional digits than decimals.
+func ParseUnits(value string, decimals uint8) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("empty amount")
+	}
+	if strings.HasPrefix(value, "-") {
+		return nil, fmt.Errorf("negative amount: %s", value)
+	}
+
+	whole, frac, hasFrac := strings.Cut(value, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if hasFrac && frac == "" {
+		return nil, fmt.Errorf("invalid amount: %s", value)
+	}
+	if len(frac) > int(decimals) {
+		// Allow trailing zeros beyond the token's precision
+		if strings.TrimRight(frac[decimals:], "0") != "" {
+			return nil, fmt.Errorf("amount %s has more than %d decimal places", value, decimals)
+		}
+		frac = frac[:decimals]
+	}
+	frac += strings.Repeat("0", int(decimals)-len(frac))
+
+	result, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %s", value)
+	}
+	return result, nil
+}
+
+// FormatUnits renders base units as a decimal string with trailing zeros removed
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return "0"
+	}
+
+	sign := ""
+	abs := new(big.Int).Set(amount)
+	if abs.Sign() < 0 {
+		sign = "-"
+		abs.Neg(abs)
+	}
+
+	digits := abs.String()
+	if decimals == 0 {
+		return sign + digits
+	}
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	split := len(digits) - int(decimals)
+	whole, frac := digits[:split], strings.TrimRight(digits[split:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// BaseUnitSuffix marks an amount flag value given in base units, e.g. "1500000 wei"
+const BaseUnitSuffix = "wei"
+
+// ParseTokenAmount parses an amount flag for a token. Numbers are token units converted
+// with the token's decimals ("12", "12.5", "12.5 USDFC"); base units must be given
+// explicitly with BaseUnitSuffix ("12500000 wei").
+func ParseTokenAmount(value string, meta *types.TokenMetadata) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+
+	number, unit, hasUnit := strings.Cut(value, " ")
+	if !hasUnit {
+		return ParseUnits(value, meta.Decimals)
+	}
+
+	unit = strings.TrimSpace(unit)
+	switch {
+	case strings.EqualFold(unit, BaseUnitSuffix):
+		result, ok := new(big.Int).SetString(number, 10)
+		if !ok || result.Sign() < 0 {
+			return nil, fmt.Errorf("invalid amount in base units: %s", value)
+		}
+		return result, nil
+	case strings.EqualFold(unit, meta.Symbol):
+		return ParseUnits(number, meta.Decimals)
+	default:
+		return nil, fmt.Errorf("amount %q is in %s but the token is %s", value, unit, meta.Symbol)
+	}
+}
+
+// FormatTokenAmount renders base units in human units with the token symbol, e.g. "12.5 USDFC"
+func FormatTokenAmount(amount *big.Int, meta *types.TokenMetadata) string {
+	return FormatUnits(amount, meta.Decimals) + " " + meta.Symbol
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestParseUnits(t *testing.T) {
+	cases := []struct {
+		value    string
+		decimals uint8
+		want     string
+	}{
+		{"12.5", 6, "12500000"},
+		{"1", 18, "1000000000000000000"},
+		{".5", 2, "50"},
+		{"0.010", 2, "1"},
+		{"7", 0, "7"},
+	}
+	for _, c := range cases {
+		got, err := ParseUnits(c.value, c.decimals)
+		if err != nil {
+			t.Fatalf("ParseUnits(%q, %d): %v", c.value, c.decimals, err)
+		}
+		if got.String() != c.want {
+			t.Fatalf("ParseUnits(%q, %d) = %s, want %s", c.value, c.decimals, got, c.want)
+		}
+	}
+
+	for _, value := range []string{"", "1.", "-1", "abc", "0.001"} {
+		if _, err := ParseUnits(value, 2); err == nil {
+			t.Fatalf("ParseUnits(%q, 2): expected error", value)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	cases := []struct {
+		amount   *big.Int
+		decimals uint8
+		want     string
+	}{
+		{big.NewInt(12500000), 6, "12.5"},
+		{big.NewInt(1), 6, "0.000001"},
+		{big.NewInt(0), 18, "0"},
+		{big.NewInt(-150), 2, "-1.5"},
+		{big.NewInt(42), 0, "42"},
+	}
+	for _, c := range cases {
+		if got := FormatUnits(c.amount, c.decimals); got != c.want {
+			t.Fatalf("FormatUnits(%s, %d) = %s, want %s", c.amount, c.decimals, got, c.want)
+		}
+	}
+}
+
+func TestParseTokenAmount(t *testing.T) {
+	meta := &types.TokenMetadata{Symbol: "USDFC", Decimals: 6}
+
+	cases := map[string]string{
+		"1500000 wei": "1500000",
+		"1500000 WEI": "1500000",
+		"2":           "2000000",
+		"1.5":         "1500000",
+		"1.5 USDFC":   "1500000",
+		"3 usdfc":     "3000000",
+		" 2 USDFC   ": "2000000",
+	}
+	for value, want := range cases {
+		got, err := ParseTokenAmount(value, meta)
+		if err != nil {
+			t.Fatalf("ParseTokenAmount(%q): %v", value, err)
+		}
+		if got.String() != want {
+			t.Fatalf("ParseTokenAmount(%q) = %s, want %s", value, got, want)
+		}
+	}
+
+	for _, value := range []string{"1 FIL", "-5", "ten", "1.5 wei", "-5 wei"} {
+		if _, err := ParseTokenAmount(value, meta); err == nil {
+			t.Fatalf("ParseTokenAmount(%q): expected error", value)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ipfs/go-cid"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// ClaimPieceCid decodes the piece CID stored in a claim's data. The verified registry
+// stores it with a leading multibase identity byte, which is skipped when present.
+func ClaimPieceCid(data []byte) string {
+	if c, err := cid.Cast(data); err == nil {
+		return c.String()
+	}
+	if len(data) > 1 {
+		if c, err := cid.Cast(data[1:]); err == nil {
+			return c.String()
+		}
+	}
+	return ""
+}
+
+// NewProviderClaim combines an allocation and its claim. Claims ending within
+// expiringWithin epochs of currentEpoch are marked as expiring.
+func NewProviderClaim(
+	allocationId uint64,
+	info *types.AllocationInfo,
+	claim *types.Claim,
+	currentEpoch uint64,
+	currentTime time.Time,
+	expiringWithin int64,
+) types.ProviderClaim {
+	termEnd := claim.TermStart + claim.TermMax
+	remaining := termEnd - int64(currentEpoch)
+
+	pc := types.ProviderClaim{
+		AllocationId:    allocationId,
+		ClientActorId:   claim.Client,
+		PieceCid:        ClaimPieceCid(claim.Data),
+		Size:            claim.Size,
+		Sector:          claim.Sector,
+		TermMin:         claim.TermMin,
+		TermMax:         claim.TermMax,
+		TermStart:       claim.TermStart,
+		TermEnd:         termEnd,
+		EpochsRemaining: remaining,
+		EndsAt:          currentTime.Add(time.Duration(remaining*EPOCH_DURATION_SECONDS) * time.Second),
+		Expiring:        remaining <= expiringWithin,
+	}
+	if info != nil {
+		pc.Client = info.Client
+	}
+	return pc
+}
+
+// GetProviderClaims returns the claims of all of a provider's DDO allocations. Allocations
+// that have not been activated yet are skipped. Results are ordered by allocation ID.
+func GetProviderClaims(
+	ddoClient *ddo.Client,
+	providerId uint64,
+	currentEpoch uint64,
+	currentTime time.Time,
+	expiringWithin int64,
+) ([]types.ProviderClaim, error) {
+	allocationIds, err := ddoClient.GetAllocationIdsForProvider(providerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocation IDs for provider %d: %w", providerId, err)
+	}
+
+	infos, err := ddoClient.GetAllocationInfos(allocationIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocation info: %w", err)
+	}
+
+	claims := make([]types.ProviderClaim, 0, len(allocationIds))
+	for i, allocationId := range allocationIds {
+		info := infos[i]
+		// Allocations are only claimed once activated in a sector
+		if !info.Activated {
+			continue
+		}
+
+		claim, err := ddoClient.GetClaimInfo(providerId, allocationId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get claim %d: %w", allocationId, err)
+		}
+		if claim == nil {
+			log.Warnw("activated allocation has no claim", "allocationId", allocationId, "provider", providerId)
+			continue
+		}
+
+		claims = append(claims, NewProviderClaim(allocationId, info, claim, currentEpoch, currentTime, expiringWithin))
+	}
+
+	sort.Slice(claims, func(i, j int) bool { return claims[i].AllocationId < claims[j].AllocationId })
+	return claims, nil
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ipfs/go-cid"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestClaimPieceCid(t *testing.T) {
+	c, err := cid.Parse("baga6ea4seaqhpxa6yyafiw4irpaikk3o256l2smmiavkffkvykztotukpqheqfq")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ClaimPieceCid(c.Bytes()); got != c.String() {
+		t.Fatalf("plain CID: got %q, want %q", got, c.String())
+	}
+	if got := ClaimPieceCid(append([]byte{0x00}, c.Bytes()...)); got != c.String() {
+		t.Fatalf("prefixed CID: got %q, want %q", got, c.String())
+	}
+	if got := ClaimPieceCid([]byte{0x01}); got != "" {
+		t.Fatalf("invalid data: got %q, want empty", got)
+	}
+}
+
+func TestNewProviderClaim(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	info := &types.AllocationInfo{Client: common.HexToAddress("0x01")}
+	claim := &types.Claim{Client: 1234, TermStart: 1000, TermMin: 500, TermMax: 2000}
+
+	pc := NewProviderClaim(7, info, claim, 2000, now, 500)
+	if pc.TermEnd != 3000 || pc.EpochsRemaining != 1000 || pc.Expiring {
+		t.Fatalf("unexpected claim: %+v", pc)
+	}
+	if want := now.Add(1000 * EPOCH_DURATION_SECONDS * time.Second); !pc.EndsAt.Equal(want) {
+		t.Fatalf("EndsAt = %s, want %s", pc.EndsAt, want)
+	}
+	if pc.Client != info.Client || pc.ClientActorId != 1234 {
+		t.Fatalf("unexpected client: %+v", pc)
+	}
+
+	if pc := NewProviderClaim(7, info, claim, 2600, now, 500); !pc.Expiring {
+		t.Fatalf("claim ending in 400 epochs should be expiring")
+	}
+	if pc := NewProviderClaim(7, info, claim, 3500, now, 500); !pc.Expiring || pc.EpochsRemaining != -500 {
+		t.Fatalf("ended claim should be expiring: %+v", pc)
+	}
+}
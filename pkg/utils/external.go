@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"math/bits"
+	"os"
+	"strings"
+
+	commcid "github.com/filecoin-project/go-fil-commcid"
+	commp "github.com/filecoin-project/go-fil-commp-hashhash"
+	"github.com/ipfs/go-cid"
+	car "github.com/ipld/go-car"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// ReadCarRoots checks that path starts with a valid CAR header and returns its roots
+func ReadCarRoots(path string) ([]cid.Cid, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	header, err := car.ReadHeader(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid CAR file: %w", path, err)
+	}
+	if header.Version != 1 {
+		return nil, fmt.Errorf("%s is a version %d CAR file, only version 1 is supported", path, header.Version)
+	}
+	return header.Roots, nil
+}
+
+// ParsePieceCid decodes a v1 or v2 piece CID into its v1 form. A v2 piece CID also
+// carries the payload size, which is returned; it is zero for v1 piece CIDs.
+func ParsePieceCid(s string) (cid.Cid, uint64, error) {
+	c, err := cid.Decode(s)
+	if err != nil {
+		return cid.Undef, 0, fmt.Errorf("invalid piece CID %q: %w", s, err)
+	}
+	if _, err := commcid.CIDToDataCommitmentV1(c); err == nil {
+		return c, 0, nil
+	}
+	v1, payloadSize, err := commcid.PieceCidV1FromV2(c)
+	if err != nil {
+		return cid.Undef, 0, fmt.Errorf("%s is not a piece CID", s)
+	}
+	return v1, payloadSize, nil
+}
+
+// ExternalPiece validates a piece prepared outside this client, given as a piece CID,
+// its padded size and a download URL. pieceSize may be zero for a v2 piece CID, and
+// payloadSize (the CAR size) is taken from a v2 piece CID when not given. A v2 piece
+// CID given with a larger pieceSize is padded up to it.
+func ExternalPiece(pieceCid string, pieceSize, payloadSize uint64, downloadURL string) (*types.PreparedPiece, error) {
+	v1, cidPayload, err := ParsePieceCid(pieceCid)
+	if err != nil {
+		return nil, err
+	}
+	if cidPayload != 0 {
+		if payloadSize != 0 && payloadSize != cidPayload {
+			return nil, fmt.Errorf("payload size %d does not match the %d bytes in piece CID %s", payloadSize, cidPayload, pieceCid)
+		}
+		payloadSize = cidPayload
+		if pieceSize == 0 {
+			pieceSize = PaddedPieceSize(payloadSize)
+		}
+	}
+
+	if pieceSize == 0 {
+		return nil, fmt.Errorf("piece size is required for a v1 piece CID")
+	}
+	if pieceSize < 128 || bits.OnesCount64(pieceSize) != 1 {
+		return nil, fmt.Errorf("piece size %d is not a power of two of at least 128 bytes", pieceSize)
+	}
+	if payloadSize != 0 && PaddedPieceSize(payloadSize) > pieceSize {
+		return nil, fmt.Errorf("%d bytes of payload do not fit in a %d byte piece", payloadSize, pieceSize)
+	}
+	if !strings.HasPrefix(downloadURL, "http://") && !strings.HasPrefix(downloadURL, "https://") {
+		return nil, fmt.Errorf("download URL must be an http(s) URL, got %q", downloadURL)
+	}
+
+	// The v1 form of a v2 piece CID is for its natural size
+	resultCid := v1.String()
+	if natural := PaddedPieceSize(cidPayload); cidPayload != 0 && pieceSize > natural {
+		if resultCid, err = PadPieceCid(resultCid, natural, pieceSize); err != nil {
+			return nil, err
+		}
+	}
+
+	return &types.PreparedPiece{
+		PieceCid:    resultCid,
+		PieceSize:   pieceSize,
+		CarSize:     payloadSize,
+		DownloadURL: downloadURL,
+	}, nil
+}
+
+// PadPieceCid returns the piece CID of a piece padded with zeros from pieceSize up to
+// targetSize
+func PadPieceCid(pieceCid string, pieceSize, targetSize uint64) (string, error) {
+	c, err := cid.Decode(pieceCid)
+	if err != nil {
+		return "", fmt.Errorf("invalid piece CID %q: %w", pieceCid, err)
+	}
+	rawCommP, err := commcid.CIDToDataCommitmentV1(c)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a piece CID: %w", pieceCid, err)
+	}
+	padded, err := commp.PadCommP(rawCommP, pieceSize, targetSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to pad piece: %w", err)
+	}
+	paddedCid, err := commcid.DataCommitmentV1ToCID(padded)
+	if err != nil {
+		return "", fmt.Errorf("failed to build piece CID: %w", err)
+	}
+	return paddedCid.String(), nil
+}
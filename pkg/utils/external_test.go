@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	car "github.com/ipld/go-car"
+)
+
+func TestReadCarRoots(t *testing.T) {
+	dir := t.TempDir()
+	root, err := cid.Decode("bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := car.WriteHeader(&car.CarHeader{Roots: []cid.Cid{root}, Version: 1}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	carPath := filepath.Join(dir, "data.car")
+	if err := os.WriteFile(carPath, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	roots, err := ReadCarRoots(carPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 1 || !roots[0].Equals(root) {
+		t.Errorf("roots = %v, want [%s]", roots, root)
+	}
+
+	notCar := filepath.Join(dir, "data.txt")
+	if err := os.WriteFile(notCar, []byte("hello world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadCarRoots(notCar); err == nil {
+		t.Error("expected an error for a file that is not a CAR")
+	}
+}
+
+func TestExternalPiece(t *testing.T) {
+	data := make([]byte, 3000)
+	rand.New(rand.NewSource(3)).Read(data)
+	commitment, err := ComputePieceCommitment(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "https://example.com/data.car"
+
+	// A v2 piece CID carries the payload size, from which the piece size follows
+	p, err := ExternalPiece(commitment.PieceCidV2, 0, 0, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PieceCid != commitment.PieceCidV1 || p.PieceSize != 4096 || p.CarSize != 3000 {
+		t.Errorf("v2 piece = %s, %d, %d, want %s, 4096, 3000", p.PieceCid, p.PieceSize, p.CarSize, commitment.PieceCidV1)
+	}
+
+	// A v2 piece CID with a larger piece size is padded up to it
+	p, err = ExternalPiece(commitment.PieceCidV2, 16384, 0, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	padded, err := PadPieceCid(commitment.PieceCidV1, 4096, 16384)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PieceCid != padded || p.PieceSize != 16384 || p.CarSize != 3000 {
+		t.Errorf("padded v2 piece = %s, %d, %d, want %s, 16384, 3000", p.PieceCid, p.PieceSize, p.CarSize, padded)
+	}
+
+	p, err = ExternalPiece(commitment.PieceCidV1, 4096, 0, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PieceCid != commitment.PieceCidV1 || p.PieceSize != 4096 || p.CarSize != 0 {
+		t.Errorf("v1 piece = %s, %d, %d, want %s, 4096, 0", p.PieceCid, p.PieceSize, p.CarSize, commitment.PieceCidV1)
+	}
+
+	for name, tc := range map[string]struct {
+		pieceCid    string
+		pieceSize   uint64
+		payloadSize uint64
+		url         string
+	}{
+		"v1 without size":      {commitment.PieceCidV1, 0, 0, url},
+		"size not power of 2":  {commitment.PieceCidV1, 3000, 0, url},
+		"payload too large":    {commitment.PieceCidV1, 2048, 3000, url},
+		"payload mismatch":     {commitment.PieceCidV2, 0, 2999, url},
+		"not a piece CID":      {"bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi", 4096, 0, url},
+		"not an http(s) URL":   {commitment.PieceCidV1, 4096, 0, "/tmp/data.car"},
+		"piece size too small": {commitment.PieceCidV1, 64, 0, url},
+	} {
+		if _, err := ExternalPiece(tc.pieceCid, tc.pieceSize, tc.payloadSize, tc.url); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPadPieceCid(t *testing.T) {
+	data := make([]byte, 3000)
+	rand.New(rand.NewSource(4)).Read(data)
+	commitment, err := ComputePieceCommitment(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	padded, err := PadPieceCid(commitment.PieceCidV1, 4096, 8192)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Padding matches hashing the data followed by zeros filling the unpadded target size
+	zeroPadded := append(data, make([]byte, 8128-len(data))...)
+	want, err := ComputePieceCommitment(bytes.NewReader(zeroPadded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if padded != want.PieceCidV1 {
+		t.Errorf("padded = %s, want %s", padded, want.PieceCidV1)
+	}
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// AUCTION_HALVING_SECONDS is how long it takes the fee auction price to halve (3.5 days)
+const AUCTION_HALVING_SECONDS = 302400
+
+// EstimateAuctionPrice estimates the fee auction price at a unix timestamp. The price
+// decays exponentially from startPrice, halving every AUCTION_HALVING_SECONDS.
+// Because the price only decreases, paying the estimate for the latest block always
+// covers the price at the block the transaction is included in.
+func EstimateAuctionPrice(startPrice *big.Int, startTime uint64, now uint64) *big.Int {
+	if startPrice.Sign() == 0 || now <= startTime {
+		return new(big.Int).Set(startPrice)
+	}
+
+	elapsed := now - startTime
+	halvings := elapsed / AUCTION_HALVING_SECONDS
+	if halvings >= uint64(startPrice.BitLen()) {
+		return big.NewInt(0)
+	}
+	price := new(big.Int).Rsh(startPrice, uint(halvings))
+
+	// Decay within the current halving interval, rounded up so the estimate never undershoots
+	remainder := float64(elapsed%AUCTION_HALVING_SECONDS) / AUCTION_HALVING_SECONDS
+	factor := big.NewFloat(math.Pow(2, -remainder))
+	decayed, accuracy := new(big.Float).Mul(new(big.Float).SetInt(price), factor).Int(nil)
+	if accuracy == big.Below {
+		decayed.Add(decayed, big.NewInt(1))
+	}
+	if decayed.Cmp(price) > 0 {
+		return price
+	}
+	return decayed
+}
+
+// GetFeeAuctionStatus reads the accumulated network fees and auction state for a token
+// and estimates the auction price at currentTime
+func GetFeeAuctionStatus(paymentsClient *payments.Client, token common.Address, currentTime time.Time) (*types.FeeAuctionStatus, error) {
+	fees, err := paymentsClient.GetAccumulatedFees(token)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := paymentsClient.GetAuctionInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.FeeAuctionStatus{
+		Token:           token,
+		AccumulatedFees: fees,
+		StartPrice:      auction.StartPrice,
+		StartTime:       time.Unix(auction.StartTime.Int64(), 0).UTC(),
+		CurrentPrice:    EstimateAuctionPrice(auction.StartPrice, auction.StartTime.Uint64(), uint64(currentTime.Unix())),
+	}, nil
+}
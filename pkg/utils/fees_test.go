@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEstimateAuctionPrice(t *testing.T) {
+	start := big.NewInt(1_000_000)
+
+	if got := EstimateAuctionPrice(start, 100, 100); got.Cmp(start) != 0 {
+		t.Fatalf("at start: got %s, want %s", got, start)
+	}
+	if got := EstimateAuctionPrice(start, 100, 100+AUCTION_HALVING_SECONDS); got.Int64() != 500_000 {
+		t.Fatalf("after one halving: got %s, want 500000", got)
+	}
+
+	// Halfway through an interval the price is start / sqrt(2), rounded up
+	if got := EstimateAuctionPrice(start, 0, AUCTION_HALVING_SECONDS/2); got.Int64() != 707_107 {
+		t.Fatalf("after half an interval: got %s, want 707107", got)
+	}
+
+	if got := EstimateAuctionPrice(start, 0, 64*AUCTION_HALVING_SECONDS); got.Sign() != 0 {
+		t.Fatalf("after many halvings: got %s, want 0", got)
+	}
+}
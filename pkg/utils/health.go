@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+const (
+	// EPOCHS_PER_DAY represents the number of Filecoin epochs in a day
+	EPOCHS_PER_DAY = 2880
+	// EPOCH_DURATION_SECONDS is the length of a Filecoin epoch
+	EPOCH_DURATION_SECONDS = 30
+)
+
+// AccountReader is the part of the Payments contract client read to compute account health
+type AccountReader interface {
+	GetAccountInfoIfSettled(token, owner common.Address) (*types.AccountSettledInfo, error)
+	GetRailsForPayerAndToken(payer, token common.Address) ([]*types.RailInfo, error)
+	GetRail(railId *big.Int) (*types.RailView, error)
+}
+
+// GetAccountHealth reads the settled account state and the payer's rails for a token
+// and computes its burn rate and the projected epoch and time at which funds run out.
+// currentTime is the timestamp of currentEpoch and is used to turn epochs into dates.
+func GetAccountHealth(
+	paymentsClient AccountReader,
+	token common.Address,
+	owner common.Address,
+	currentEpoch uint64,
+	currentTime time.Time,
+) (*types.AccountHealth, error) {
+	info, err := paymentsClient.GetAccountInfoIfSettled(token, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	rails, err := paymentsClient.GetRailsForPayerAndToken(owner, token)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &types.AccountHealth{
+		Token:            token,
+		Owner:            owner,
+		CurrentEpoch:     currentEpoch,
+		CurrentFunds:     info.CurrentFunds,
+		AvailableFunds:   info.AvailableFunds,
+		LockupRate:       info.CurrentLockupRate,
+		BurnRatePerEpoch: big.NewInt(0),
+	}
+
+	epoch := new(big.Int).SetUint64(currentEpoch)
+	for _, r := range rails {
+		// Terminated rails keep streaming until their end epoch
+		if r.IsTerminated && r.EndEpoch.Cmp(epoch) <= 0 {
+			continue
+		}
+		rail, err := paymentsClient.GetRail(r.RailId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rail %s: %w", r.RailId.String(), err)
+		}
+		health.BurnRatePerEpoch.Add(health.BurnRatePerEpoch, rail.PaymentRate)
+		if r.IsTerminated {
+			health.TerminatedRails++
+		} else {
+			health.ActiveRails++
+		}
+	}
+	health.BurnRatePerDay = new(big.Int).Mul(health.BurnRatePerEpoch, big.NewInt(EPOCHS_PER_DAY))
+
+	// With no lockup rate the contract reports the account as funded forever
+	if info.CurrentLockupRate.Sign() == 0 {
+		health.Unlimited = true
+		return health, nil
+	}
+
+	health.FundedUntilEpoch = info.FundedUntilEpoch
+	runway := new(big.Int).Sub(info.FundedUntilEpoch, epoch)
+	if runway.Sign() < 0 {
+		runway.SetInt64(0)
+	}
+	health.RunwayEpochs = runway
+
+	runwayDays, _ := new(big.Float).Quo(new(big.Float).SetInt(runway), big.NewFloat(EPOCHS_PER_DAY)).Float64()
+	health.RunwayDays = runwayDays
+
+	runsOutAt := EpochToTime(info.FundedUntilEpoch.Uint64(), currentEpoch, currentTime)
+	health.RunsOutAt = &runsOutAt
+
+	return health, nil
+}
+
+// EpochToTime estimates the wall-clock time of an epoch from a reference epoch and its timestamp
+func EpochToTime(epoch, refEpoch uint64, refTime time.Time) time.Time {
+	delta := int64(epoch) - int64(refEpoch)
+	return refTime.Add(time.Duration(delta*EPOCH_DURATION_SECONDS) * time.Second)
+}
+
+// TimeToEpoch estimates the epoch at a wall-clock time from a reference epoch and its timestamp.
+// Times before genesis are clamped to epoch 0.
+func TimeToEpoch(t time.Time, refEpoch uint64, refTime time.Time) uint64 {
+	delta := int64(t.Sub(refTime) / time.Second / EPOCH_DURATION_SECONDS)
+	epoch := int64(refEpoch) + delta
+	if epoch < 0 {
+		return 0
+	}
+	return uint64(epoch)
+}
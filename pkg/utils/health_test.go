@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// fakeAccount is an AccountReader over one account and its rails, keyed by rail ID
+type fakeAccount struct {
+	info  types.AccountSettledInfo
+	rails []*types.RailInfo
+	rates map[int64]int64
+}
+
+func (f *fakeAccount) GetAccountInfoIfSettled(token, owner common.Address) (*types.AccountSettledInfo, error) {
+	return &f.info, nil
+}
+
+func (f *fakeAccount) GetRailsForPayerAndToken(payer, token common.Address) ([]*types.RailInfo, error) {
+	return f.rails, nil
+}
+
+func (f *fakeAccount) GetRail(railId *big.Int) (*types.RailView, error) {
+	rate, ok := f.rates[railId.Int64()]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return &types.RailView{PaymentRate: big.NewInt(rate)}, nil
+}
+
+func TestGetAccountHealth(t *testing.T) {
+	const current = 10000
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	account := func(fundedUntil, available, lockupRate int64) types.AccountSettledInfo {
+		return types.AccountSettledInfo{
+			FundedUntilEpoch:  big.NewInt(fundedUntil),
+			CurrentFunds:      big.NewInt(1000),
+			AvailableFunds:    big.NewInt(available),
+			CurrentLockupRate: big.NewInt(lockupRate),
+		}
+	}
+	active := func(id int64) *types.RailInfo {
+		return &types.RailInfo{RailId: big.NewInt(id), EndEpoch: big.NewInt(0)}
+	}
+	terminated := func(id, endEpoch int64) *types.RailInfo {
+		return &types.RailInfo{RailId: big.NewInt(id), IsTerminated: true, EndEpoch: big.NewInt(endEpoch)}
+	}
+
+	tests := []struct {
+		name           string
+		account        fakeAccount
+		wantUnlimited  bool
+		wantRunway     int64
+		wantRunsOutAt  time.Time
+		wantBurnRate   int64
+		wantActive     int
+		wantTerminated int
+	}{
+		{
+			name:          "zero lockup rate",
+			account:       fakeAccount{info: account(0, 1000, 0), rails: []*types.RailInfo{active(1)}, rates: map[int64]int64{1: 0}},
+			wantUnlimited: true,
+			wantActive:    1,
+		},
+		{
+			name:          "runway of a few days",
+			account:       fakeAccount{info: account(current+3*EPOCHS_PER_DAY, 600, 2), rails: []*types.RailInfo{active(1), active(2)}, rates: map[int64]int64{1: 1, 2: 1}},
+			wantRunway:    3 * EPOCHS_PER_DAY,
+			wantRunsOutAt: now.Add(3 * 24 * time.Hour),
+			wantBurnRate:  2,
+			wantActive:    2,
+		},
+		{
+			name:          "funds run out this epoch",
+			account:       fakeAccount{info: account(current, 0, 2), rails: []*types.RailInfo{active(1)}, rates: map[int64]int64{1: 2}},
+			wantRunway:    0,
+			wantRunsOutAt: now,
+			wantBurnRate:  2,
+			wantActive:    1,
+		},
+		{
+			name:          "funds run out next epoch",
+			account:       fakeAccount{info: account(current+1, 2, 2), rails: []*types.RailInfo{active(1)}, rates: map[int64]int64{1: 2}},
+			wantRunway:    1,
+			wantRunsOutAt: now.Add(EPOCH_DURATION_SECONDS * time.Second),
+			wantBurnRate:  2,
+			wantActive:    1,
+		},
+		{
+			name:          "negative available balance",
+			account:       fakeAccount{info: account(current-100, -50, 2), rails: []*types.RailInfo{active(1)}, rates: map[int64]int64{1: 2}},
+			wantRunway:    0,
+			wantRunsOutAt: now.Add(-100 * EPOCH_DURATION_SECONDS * time.Second),
+			wantBurnRate:  2,
+			wantActive:    1,
+		},
+		{
+			name: "terminated rails stream until their end epoch",
+			account: fakeAccount{
+				info:  account(current+EPOCHS_PER_DAY, 100, 7),
+				rails: []*types.RailInfo{active(1), terminated(2, current), terminated(3, current+1)},
+				rates: map[int64]int64{1: 3, 3: 4}, // rail 2 has ended and is not read
+			},
+			wantRunway:     EPOCHS_PER_DAY,
+			wantRunsOutAt:  now.Add(24 * time.Hour),
+			wantBurnRate:   7,
+			wantActive:     1,
+			wantTerminated: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health, err := GetAccountHealth(&tt.account, common.HexToAddress("0x70"), common.HexToAddress("0xc1"), current, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if health.AvailableFunds.Cmp(tt.account.info.AvailableFunds) != 0 {
+				t.Errorf("available funds = %s, want %s", health.AvailableFunds, tt.account.info.AvailableFunds)
+			}
+			if health.BurnRatePerEpoch.Int64() != tt.wantBurnRate || health.BurnRatePerDay.Int64() != tt.wantBurnRate*EPOCHS_PER_DAY {
+				t.Errorf("burn rate = %s per epoch, %s per day, want %d per epoch", health.BurnRatePerEpoch, health.BurnRatePerDay, tt.wantBurnRate)
+			}
+			if health.ActiveRails != tt.wantActive || health.TerminatedRails != tt.wantTerminated {
+				t.Errorf("rails = %d active, %d terminated, want %d and %d", health.ActiveRails, health.TerminatedRails, tt.wantActive, tt.wantTerminated)
+			}
+
+			if tt.wantUnlimited {
+				if !health.Unlimited || health.RunwayEpochs != nil || health.RunsOutAt != nil {
+					t.Errorf("health = %+v, want an unlimited runway", health)
+				}
+				return
+			}
+			if health.Unlimited {
+				t.Fatal("runway reported as unlimited")
+			}
+			if health.RunwayEpochs.Int64() != tt.wantRunway {
+				t.Errorf("runway = %s epochs, want %d", health.RunwayEpochs, tt.wantRunway)
+			}
+			if want := float64(tt.wantRunway) / EPOCHS_PER_DAY; health.RunwayDays != want {
+				t.Errorf("runway = %v days, want %v", health.RunwayDays, want)
+			}
+			if health.RunsOutAt == nil || !health.RunsOutAt.Equal(tt.wantRunsOutAt) {
+				t.Errorf("runs out at %v, want %s", health.RunsOutAt, tt.wantRunsOutAt)
+			}
+		})
+	}
+}
+
+func TestGetAccountHealthRailError(t *testing.T) {
+	account := &fakeAccount{
+		info:  types.AccountSettledInfo{FundedUntilEpoch: big.NewInt(200), CurrentFunds: big.NewInt(10), AvailableFunds: big.NewInt(10), CurrentLockupRate: big.NewInt(1)},
+		rails: []*types.RailInfo{{RailId: big.NewInt(9), EndEpoch: big.NewInt(0)}},
+	}
+	if _, err := GetAccountHealth(account, common.Address{}, common.Address{}, 100, time.Now()); err == nil {
+		t.Fatal("expected the rail read error")
+	}
+}
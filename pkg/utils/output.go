@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// Output formats accepted by WriteResult and the CLI's --output flag
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// ParseOutputFormat validates an output format name
+func ParseOutputFormat(s string) (string, error) {
+	switch s {
+	case OutputTable, OutputJSON, OutputYAML:
+		return s, nil
+	case "yml":
+		return OutputYAML, nil
+	}
+	return "", fmt.Errorf("invalid output format %q (expected table, json or yaml)", s)
+}
+
+// WriteResult encodes a command result as JSON or YAML. YAML is produced from the JSON
+// encoding, so both formats share the json field names and value representations.
+func WriteResult(w io.Writer, format string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	switch format {
+	case OutputJSON:
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case OutputYAML:
+		// Decoding into a node keeps the field order of the JSON encoding
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return fmt.Errorf("failed to convert result to yaml: %w", err)
+		}
+		clearYAMLStyle(&node)
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return fmt.Errorf("failed to encode result as yaml: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("failed to encode result as yaml: %w", err)
+		}
+		_, err = w.Write(buf.Bytes())
+		return err
+	}
+	return fmt.Errorf("cannot encode a result as %q", format)
+}
+
+// clearYAMLStyle drops the flow and quoting styles a node inherits from its JSON source
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// NewTxResult describes a sent transaction from its receipt, which may be that of a
+// replacement. A nil receipt with waitErr set means the transaction was sent but could
+// not be confirmed; a *txmgr.CanceledError means a cancellation was mined instead.
+func NewTxResult(txHash string, receipt *ethtypes.Receipt, waitErr error) types.TxResult {
+	result := types.TxResult{TxHash: txHash, Status: types.TxStatusPending}
+	if receipt == nil {
+		if waitErr != nil {
+			result.Error = waitErr.Error()
+		}
+		return result
+	}
+
+	if mined := receipt.TxHash; mined != (common.Hash{}) && mined != common.HexToHash(txHash) {
+		// A replacement was mined instead of the transaction sent
+		result.TxHash = mined.Hex()
+		result.Replaces = txHash
+	}
+	result.Status = types.TxStatusSuccess
+	var canceled *txmgr.CanceledError
+	if errors.As(waitErr, &canceled) {
+		result.Status = types.TxStatusCanceled
+		result.Error = waitErr.Error()
+	} else if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		result.Status = types.TxStatusReverted
+	}
+	if receipt.BlockNumber != nil {
+		result.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	result.GasUsed = receipt.GasUsed
+	return result
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	for in, want := range map[string]string{"table": "table", "json": "json", "yaml": "yaml", "yml": "yaml"} {
+		got, err := ParseOutputFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseOutputFormat(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseOutputFormat("csv"); err == nil {
+		t.Error("expected an error for csv")
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	result := struct {
+		Token  common.Address `json:"token"`
+		Amount *big.Int       `json:"amount"`
+		Ids    []uint64       `json:"ids"`
+		Note   string         `json:"note,omitempty"`
+	}{
+		Token:  common.HexToAddress("0x80B98d3aa09ffff255c3ba4A241111Ff1262F045"),
+		Amount: new(big.Int).Lsh(big.NewInt(1), 70),
+		Ids:    []uint64{7, 9},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteResult(&buf, OutputJSON, result); err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `{
+  "token": "0x80b98d3aa09ffff255c3ba4a241111ff1262f045",
+  "amount": 1180591620717411303424,
+  "ids": [
+    7,
+    9
+  ]
+}
+`
+	if buf.String() != wantJSON {
+		t.Errorf("json output:\n%s\nwant:\n%s", buf.String(), wantJSON)
+	}
+
+	buf.Reset()
+	if err := WriteResult(&buf, OutputYAML, result); err != nil {
+		t.Fatal(err)
+	}
+	wantYAML := `token: 0x80b98d3aa09ffff255c3ba4a241111ff1262f045
+amount: 1180591620717411303424
+ids:
+  - 7
+  - 9
+`
+	if buf.String() != wantYAML {
+		t.Errorf("yaml output:\n%s\nwant:\n%s", buf.String(), wantYAML)
+	}
+
+	if err := WriteResult(&buf, OutputTable, result); err == nil {
+		t.Error("expected an error for the table format")
+	}
+}
+
+func TestNewTxResult(t *testing.T) {
+	receipt := &ethtypes.Receipt{Status: ethtypes.ReceiptStatusSuccessful, BlockNumber: big.NewInt(42), GasUsed: 21000}
+	got := NewTxResult("0xabc", receipt, nil)
+	want := types.TxResult{TxHash: "0xabc", Status: types.TxStatusSuccess, BlockNumber: 42, GasUsed: 21000}
+	if got != want {
+		t.Errorf("NewTxResult = %+v, want %+v", got, want)
+	}
+
+	receipt.Status = ethtypes.ReceiptStatusFailed
+	if got := NewTxResult("0xabc", receipt, nil); got.Status != types.TxStatusReverted {
+		t.Errorf("status = %q, want %q", got.Status, types.TxStatusReverted)
+	}
+
+	replacement := common.HexToHash("0xdef")
+	receipt = &ethtypes.Receipt{Status: ethtypes.ReceiptStatusSuccessful, TxHash: replacement, BlockNumber: big.NewInt(43)}
+	got = NewTxResult(common.HexToHash("0xabc").Hex(), receipt, nil)
+	if got.TxHash != replacement.Hex() || got.Replaces != common.HexToHash("0xabc").Hex() {
+		t.Errorf("NewTxResult for a replacement = %+v", got)
+	}
+
+	canceled := &txmgr.CanceledError{TxHash: common.HexToHash("0xabc"), Cancellation: replacement}
+	got = NewTxResult(common.HexToHash("0xabc").Hex(), receipt, fmt.Errorf("transaction failed: %w", canceled))
+	if got.Status != types.TxStatusCanceled || got.TxHash != replacement.Hex() || got.Error == "" {
+		t.Errorf("NewTxResult for a cancellation = %+v", got)
+	}
+
+	got = NewTxResult("0xabc", nil, errors.New("timed out"))
+	if got.Status != types.TxStatusPending || got.Error != "timed out" {
+		t.Errorf("NewTxResult without receipt = %+v", got)
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
 	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
 	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/rpcclient"
+	"github.com/Eastore-project/ddo-client/pkg/txmgr"
 	"github.com/Eastore-project/ddo-client/pkg/types"
 )
 
@@ -25,7 +27,7 @@ const (
 	EPOCHS_PER_MONTH = 86400
 	// BYTES_PER_TB represents the number of bytes in a terabyte
 	BYTES_PER_TB = 1024 * 1024 * 1024 * 1024
-	// USD_DECIMALS represents the number of decimals for USD calculations (6 for USDC)
+	// USD_DECIMALS is the token precision assumed when a token's decimals are unknown
 	USD_DECIMALS = 18
 )
 
@@ -37,9 +39,51 @@ type StorageCostResult struct {
 	TotalEpochs          int64    `json:"totalEpochs"`
 }
 
-// CheckAndSetupPayments handles the complete payment setup process.
+// PaymentRequirements are the payments contract deposit and DDO operator allowances
+// needed to create a batch of allocations
+type PaymentRequirements struct {
+	MonthlyCost     *big.Int `json:"monthlyCost"`
+	RequiredDeposit *big.Int `json:"requiredDeposit"`
+	RateAllowance   *big.Int `json:"rateAllowance"`
+	LockupAllowance *big.Int `json:"lockupAllowance"`
+}
+
+// CalculatePaymentRequirements derives the deposit and operator allowances that
+// CheckAndSetupPayments ensures before allocations are created.
+func CalculatePaymentRequirements(costResult *StorageCostResult, allocationLockupAmount *big.Int, numPieces int) *PaymentRequirements {
+	// One month of payments: total_bytes * price_per_byte_per_epoch * epochs_per_month
+	ratePerEpoch := new(big.Int).Mul(costResult.PricePerBytePerEpoch, new(big.Int).SetUint64(costResult.TotalBytes))
+	oneMonthCost := new(big.Int).Mul(ratePerEpoch, big.NewInt(EPOCHS_PER_MONTH))
+
+	// Required deposit must cover both the storage cost and the fixed lockup per rail.
+	// The contract locks allocationLockupAmount per rail at creation time.
+	//
+	// We apply a 2x buffer to the required deposit to ensure the user has enough headroom
+	// for rate-based lockups and payment settlement timing. This is a conservative estimate —
+	// TODO: improve to calculate the exact required amount based on rail lockup mechanics.
+	totalFixedLockup := new(big.Int).Mul(allocationLockupAmount, big.NewInt(int64(numPieces)))
+	requiredDeposit := new(big.Int).Mul(costResult.TotalCost, big.NewInt(2))
+	if totalFixedLockup.Cmp(requiredDeposit) > 0 {
+		requiredDeposit.Mul(totalFixedLockup, big.NewInt(2))
+	}
+
+	// Lockup allowance must cover the contract's allocationLockupAmount (fixed lockup per rail)
+	// plus the ongoing rate-based lockup. The peak usage is allocationLockupAmount * numPieces
+	// (applied at rail creation), which later decreases when the rail is activated.
+	// We apply a 2x buffer here as well for safety — TODO: calculate exact requirement.
+	lockupAllowance := new(big.Int).Mul(totalFixedLockup, big.NewInt(2))
+
+	return &PaymentRequirements{
+		MonthlyCost:     oneMonthCost,
+		RequiredDeposit: requiredDeposit,
+		RateAllowance:   ratePerEpoch,
+		LockupAllowance: lockupAllowance,
+	}
+}
+
+// CheckAndSetupPayments handles the complete payment setup process. The token
+// approval is sent over the payments client's connection and transaction manager.
 func CheckAndSetupPayments(
-	ethClient *ethclient.Client,
 	ddoClient *ddo.Client,
 	paymentsClient *payments.Client,
 	pieceInfos []types.PieceInfo,
@@ -60,31 +104,15 @@ func CheckAndSetupPayments(
 	}
 	tokenAddress := pieceInfos[0].PaymentTokenAddress
 
-	// Calculate one month allowance (for operator approval lockup allowance)
-	// This is: total_bytes * price_per_byte_per_epoch * epochs_per_month
-	oneMonthCost := new(big.Int).Mul(
-		new(big.Int).SetUint64(costResult.TotalBytes),
-		costResult.PricePerBytePerEpoch,
-	)
-	oneMonthCost.Mul(oneMonthCost, big.NewInt(EPOCHS_PER_MONTH))
-
 	// Query the contract's allocationLockupAmount (fixed lockup applied per rail at creation)
 	allocationLockupAmount, err := ddoClient.GetAllocationLockupAmount()
 	if err != nil {
 		return fmt.Errorf("failed to get allocation lockup amount: %w", err)
 	}
 
-	// Required deposit must cover both the storage cost and the fixed lockup per rail.
-	// The contract locks allocationLockupAmount per rail at creation time.
-	//
-	// We apply a 2x buffer to the required deposit to ensure the user has enough headroom
-	// for rate-based lockups and payment settlement timing. This is a conservative estimate —
-	// TODO: improve to calculate the exact required amount based on rail lockup mechanics.
-	totalFixedLockupForDeposit := new(big.Int).Mul(allocationLockupAmount, big.NewInt(int64(len(pieceInfos))))
-	requiredDeposit := new(big.Int).Mul(costResult.TotalCost, big.NewInt(2))
-	if totalFixedLockupForDeposit.Cmp(requiredDeposit) > 0 {
-		requiredDeposit.Mul(totalFixedLockupForDeposit, big.NewInt(2))
-	}
+	req := CalculatePaymentRequirements(costResult, allocationLockupAmount, len(pieceInfos))
+	oneMonthCost := req.MonthlyCost
+	requiredDeposit := req.RequiredDeposit
 
 	log.Infow("payment setup summary",
 		"token", tokenAddress.Hex(),
@@ -114,7 +142,7 @@ func CheckAndSetupPayments(
 			log.Info("checking ERC20 token allowance")
 
 			// Create ERC20 client using the caller-supplied transactor
-			erc20Client, err := token.NewERC20ClientWithTransactor(ethClient, tokenAddress.Hex(), auth)
+			erc20Client, err := token.NewERC20ClientWithTransactor(paymentsClient.GetEthClient(), tokenAddress.Hex(), auth, paymentsClient.TxManager())
 			if err != nil {
 				return fmt.Errorf("failed to create ERC20 client: %w", err)
 			}
@@ -137,8 +165,8 @@ func CheckAndSetupPayments(
 
 			if approved {
 				log.Infow("token allowance approved", "txHash", allowanceTx)
-				if err := WaitForTransaction(ethClient, allowanceTx); err != nil {
-					log.Warnw("allowance transaction may not have been mined", "error", err)
+				if err := WaitForTransaction(paymentsClient.TxManager(), allowanceTx); err != nil {
+					return fmt.Errorf("token allowance transaction failed: %w", err)
 				}
 			} else {
 				log.Info("token allowance already sufficient")
@@ -151,8 +179,8 @@ func CheckAndSetupPayments(
 			return fmt.Errorf("failed to deposit tokens: %w", err)
 		}
 		log.Infow("deposit transaction sent", "txHash", txHash)
-		if err := WaitForTransaction(ethClient, txHash); err != nil {
-			log.Warnw("deposit transaction may not have been mined", "error", err)
+		if err := WaitForTransaction(paymentsClient.TxManager(), txHash); err != nil {
+			return fmt.Errorf("deposit transaction failed: %w", err)
 		}
 	}
 
@@ -169,15 +197,8 @@ func CheckAndSetupPayments(
 		"rateUsage", operatorApproval.RateUsage,
 		"lockupUsage", operatorApproval.LockupUsage)
 
-	// Set rate allowance to a large number for flexibility
-	rateAllowance := new(big.Int).Mul(costResult.PricePerBytePerEpoch, new(big.Int).SetUint64(costResult.TotalBytes))
-	// Lockup allowance must cover the contract's allocationLockupAmount (fixed lockup per rail)
-	// plus the ongoing rate-based lockup. The peak usage is allocationLockupAmount * numPieces
-	// (applied at rail creation), which later decreases when the rail is activated.
-	// We apply a 2x buffer here as well for safety — TODO: calculate exact requirement.
-	perPieceLockup := new(big.Int).Set(allocationLockupAmount)
-	totalFixedLockup := new(big.Int).Mul(perPieceLockup, big.NewInt(int64(len(pieceInfos))))
-	lockupAllowance := new(big.Int).Mul(totalFixedLockup, big.NewInt(2))
+	rateAllowance := req.RateAllowance
+	lockupAllowance := req.LockupAllowance
 	var txHash string
 	if !operatorApproval.IsApproved || (new(big.Int).Sub(operatorApproval.RateAllowance, operatorApproval.RateUsage).Cmp(rateAllowance) < 0 && new(big.Int).Sub(operatorApproval.LockupAllowance, operatorApproval.LockupUsage).Cmp(lockupAllowance) < 0) {
 		log.Info("setting operator approval")
@@ -218,10 +239,10 @@ func CheckAndSetupPayments(
 	}
 
 	if txHash != "" {
-		if err := WaitForTransaction(ethClient, txHash); err != nil {
-			log.Warnw("operator approval transaction may not have been mined", "error", err)
-		}
 		log.Infow("operator approval transaction sent", "txHash", txHash)
+		if err := WaitForTransaction(paymentsClient.TxManager(), txHash); err != nil {
+			return fmt.Errorf("operator approval transaction failed: %w", err)
+		}
 	}
 
 	return nil
@@ -295,47 +316,39 @@ func CalculateStorageCosts(ddoClient *ddo.Client, pieceInfos []types.PieceInfo)
 	}, nil
 }
 
-// WaitForTransaction waits for a transaction to be mined
-func WaitForTransaction(client *ethclient.Client, txHash string) error {
+// WaitForTransaction waits for a transaction sent through txm to be mined. A reverted
+// transaction is returned as a *txmgr.RevertedError.
+func WaitForTransaction(txm *txmgr.Manager, txHash string) error {
 	if txHash == "" {
 		return nil
 	}
 
-	hash := common.HexToHash(txHash)
 	log.Infow("waiting for transaction", "txHash", txHash)
-
-	tx, _, err := client.TransactionByHash(context.Background(), hash)
+	receipt, err := WaitForTransactionWithReceipt(txm, txHash)
 	if err != nil {
-		return fmt.Errorf("failed to get transaction: %w", err)
+		return err
 	}
 
-	_, err = bind.WaitMined(context.Background(), client, tx)
-	if err != nil {
-		return fmt.Errorf("transaction failed or timed out: %w", err)
-	}
-
-	log.Infow("transaction mined", "txHash", txHash)
+	log.Infow("transaction mined", "txHash", receipt.TxHash.Hex(), "block", receipt.BlockNumber)
 	return nil
 }
 
-// WaitForTransactionWithReceipt waits for a transaction to be mined and returns the receipt
-func WaitForTransactionWithReceipt(client *ethclient.Client, txHash string) (*ethtypes.Receipt, error) {
+// WaitForTransactionWithReceipt waits for a transaction, or a replacement sent for it,
+// to be mined with txm's confirmation depth and timeout, and returns the receipt. A
+// reverted transaction returns its receipt and a *txmgr.RevertedError, a canceled one
+// a *txmgr.CanceledError.
+func WaitForTransactionWithReceipt(txm *txmgr.Manager, txHash string) (*ethtypes.Receipt, error) {
 	if txHash == "" {
 		return nil, fmt.Errorf("empty transaction hash")
 	}
 
-	hash := common.HexToHash(txHash)
-
-	tx, _, err := client.TransactionByHash(context.Background(), hash)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	if txm == nil {
+		return nil, fmt.Errorf("no transaction manager to wait with")
 	}
-
-	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	receipt, err := txm.Wait(context.Background(), common.HexToHash(txHash))
 	if err != nil {
-		return nil, fmt.Errorf("transaction failed or timed out: %w", err)
+		return receipt, fmt.Errorf("transaction %s failed: %w", txHash, err)
 	}
-
 	return receipt, nil
 }
 
@@ -353,6 +366,7 @@ func CalculateTotalDataCap(pieceInfos []types.PieceInfo) *big.Int {
 // CheckTokenAllowanceAndBalance checks token balance and allowance for a user
 func CheckTokenAllowanceAndBalance(
 	rpcEndpoint string,
+	rpcCfg rpcclient.Config,
 	tokenAddress string,
 	userAddress, spenderAddress common.Address,
 	requiredAmount *big.Int,
@@ -363,7 +377,7 @@ func CheckTokenAllowanceAndBalance(
 	}
 
 	// Create ERC20 client for read-only operations
-	erc20Client, err := token.NewERC20ReadOnlyClient(rpcEndpoint, tokenAddress)
+	erc20Client, err := token.NewERC20ReadOnlyClient(rpcEndpoint, tokenAddress, rpcCfg)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create ERC20 client: %w", err)
 	}
@@ -387,6 +401,7 @@ func CheckTokenAllowanceAndBalance(
 // ApproveTokenIfNeeded approves tokens for spending if the current allowance is insufficient
 func ApproveTokenIfNeeded(
 	ethClient *ethclient.Client,
+	txm *txmgr.Manager,
 	auth *bind.TransactOpts,
 	tokenAddress string,
 	userAddress, spenderAddress common.Address,
@@ -398,7 +413,7 @@ func ApproveTokenIfNeeded(
 	}
 
 	// Create ERC20 client
-	erc20Client, err := token.NewERC20ClientWithTransactor(ethClient, tokenAddress, auth)
+	erc20Client, err := token.NewERC20ClientWithTransactor(ethClient, tokenAddress, auth, txm)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to create ERC20 client: %w", err)
 	}
@@ -443,6 +458,10 @@ func FormatBytes(bytes *big.Int) string {
 
 // Price Conversion Functions
 
+// maxPriceRoundingBps is the largest share of a price, in basis points, that may be
+// lost when it is rounded down to whole base units per byte per epoch
+const maxPriceRoundingBps = 500
+
 // ConvertTBPerMonthToBytesPerEpoch converts price per TB per month to price per byte per epoch
 func ConvertTBPerMonthToBytesPerEpoch(pricePerTBPerMonth *big.Int) *big.Int {
 	// price per byte per epoch = price per TB per month / (BYTES_PER_TB * EPOCHS_PER_MONTH)
@@ -463,43 +482,52 @@ func ConvertBytesPerEpochToTBPerMonth(pricePerBytePerEpoch *big.Int) *big.Int {
 	return result
 }
 
-// ConvertUSDPerTBPerMonthToTokenUnits converts USD per TB per month to token units (USDC with 6 decimals)
-func ConvertUSDPerTBPerMonthToTokenUnits(usdPriceStr string) (*big.Int, error) {
-	// Parse the USD price as a float
-	usdPrice := new(big.Float)
-	_, ok := usdPrice.SetString(usdPriceStr)
-	if !ok {
+// ConvertUSDPerTBPerMonthToTokenUnits converts a USD per TB per month price into base units
+// of a USD-pegged token with the given decimals
+func ConvertUSDPerTBPerMonthToTokenUnits(usdPriceStr string, decimals uint8) (*big.Int, error) {
+	result, err := ParseUnits(usdPriceStr, decimals)
+	if err != nil {
 		return nil, fmt.Errorf("invalid USD price format: %s", usdPriceStr)
 	}
-
-	// Convert to token units (multiply by 10^USD_DECIMALS for USDC)
-	decimalsMultiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(USD_DECIMALS), nil))
-	tokenUnits := new(big.Float).Mul(usdPrice, decimalsMultiplier)
-
-	// Convert to big.Int
-	result, _ := tokenUnits.Int(nil)
 	return result, nil
 }
 
 // ConvertUSDPerTBPerMonthToBytesPerEpoch converts USD per TB per month to bytes per epoch in token units
-func ConvertUSDPerTBPerMonthToBytesPerEpoch(usdPriceStr string) (*big.Int, error) {
+func ConvertUSDPerTBPerMonthToBytesPerEpoch(usdPriceStr string, decimals uint8) (*big.Int, error) {
 	// First convert USD to token units (TB per month)
-	pricePerTBPerMonth, err := ConvertUSDPerTBPerMonthToTokenUnits(usdPriceStr)
+	pricePerTBPerMonth, err := ConvertUSDPerTBPerMonthToTokenUnits(usdPriceStr, decimals)
 	if err != nil {
 		return nil, err
 	}
 
-	// Then convert TB per month to bytes per epoch
-	return ConvertTBPerMonthToBytesPerEpoch(pricePerTBPerMonth), nil
+	// Then convert TB per month to bytes per epoch. The contract stores whole base
+	// units per byte per epoch, so low-decimal tokens may not represent the price.
+	pricePerBytePerEpoch := ConvertTBPerMonthToBytesPerEpoch(pricePerTBPerMonth)
+	if pricePerBytePerEpoch.Sign() == 0 {
+		if pricePerTBPerMonth.Sign() == 0 {
+			return nil, fmt.Errorf("price must be greater than zero")
+		}
+		return nil, fmt.Errorf("price %s USD/TB/month is below the smallest price a %d-decimal token can store (%s USD/TB/month)",
+			usdPriceStr, decimals, ConvertTokenUnitsToUSD(ConvertBytesPerEpochToTBPerMonth(big.NewInt(1)), decimals))
+	}
+
+	stored := ConvertBytesPerEpochToTBPerMonth(pricePerBytePerEpoch)
+	lost := new(big.Int).Sub(pricePerTBPerMonth, stored)
+	if new(big.Int).Mul(lost, big.NewInt(10000)).Cmp(new(big.Int).Mul(pricePerTBPerMonth, big.NewInt(maxPriceRoundingBps))) > 0 {
+		return nil, fmt.Errorf("price %s USD/TB/month cannot be stored precisely with a %d-decimal token: it would be rounded down to %s USD/TB/month",
+			usdPriceStr, decimals, ConvertTokenUnitsToUSD(stored, decimals))
+	}
+	return pricePerBytePerEpoch, nil
 }
 
-// ConvertTokenUnitsToUSD converts token units to USD (assuming USDC with 6 decimals)
-func ConvertTokenUnitsToUSD(tokenUnits *big.Int) string {
+// ConvertTokenUnitsToUSD converts base units of a USD-pegged token with the given decimals
+// to a USD amount with 2 decimal places
+func ConvertTokenUnitsToUSD(tokenUnits *big.Int, decimals uint8) string {
 	// Convert token units to float
 	tokenFloat := new(big.Float).SetInt(tokenUnits)
 
-	// Divide by 10^USD_DECIMALS
-	decimalsMultiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(USD_DECIMALS), nil))
+	// Divide by 10^decimals
+	decimalsMultiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
 	usdFloat := new(big.Float).Quo(tokenFloat, decimalsMultiplier)
 
 	// Format to 2 decimal places
@@ -507,21 +535,20 @@ func ConvertTokenUnitsToUSD(tokenUnits *big.Int) string {
 }
 
 // FormatPriceBothFormats formats a price in both TB per month and bytes per epoch formats
-func FormatPriceBothFormats(pricePerBytePerEpoch *big.Int) string {
+func FormatPriceBothFormats(pricePerBytePerEpoch *big.Int, meta *types.TokenMetadata) string {
 	pricePerTBPerMonth := ConvertBytesPerEpochToTBPerMonth(pricePerBytePerEpoch)
-	usdPricePerTB := ConvertTokenUnitsToUSD(pricePerTBPerMonth)
-	return fmt.Sprintf("$%s USD per TB per month (%s token units per byte per epoch)",
-		usdPricePerTB,
+	return fmt.Sprintf("%s %s per TB per month (%s base units per byte per epoch)",
+		ConvertTokenUnitsToUSD(pricePerTBPerMonth, meta.Decimals),
+		meta.Symbol,
 		pricePerBytePerEpoch.String())
 }
 
 // FormatPriceWithUnit formats price with appropriate unit for display
-func FormatPriceWithUnit(pricePerBytePerEpoch *big.Int, showBothFormats bool) string {
+func FormatPriceWithUnit(pricePerBytePerEpoch *big.Int, meta *types.TokenMetadata, showBothFormats bool) string {
 	if showBothFormats {
-		return FormatPriceBothFormats(pricePerBytePerEpoch)
+		return FormatPriceBothFormats(pricePerBytePerEpoch, meta)
 	}
 
 	pricePerTBPerMonth := ConvertBytesPerEpochToTBPerMonth(pricePerBytePerEpoch)
-	usdPricePerTB := ConvertTokenUnitsToUSD(pricePerTBPerMonth)
-	return fmt.Sprintf("$%s USD per TB per month", usdPricePerTB)
+	return fmt.Sprintf("%s %s per TB per month", ConvertTokenUnitsToUSD(pricePerTBPerMonth, meta.Decimals), meta.Symbol)
 }
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertUSDPerTBPerMonthToBytesPerEpoch(t *testing.T) {
+	cases := []struct {
+		name        string
+		price       string
+		decimals    uint8
+		want        string
+		errContains string
+	}{
+		{"18 decimals", "10", 18, "105", ""},
+		{"18 decimals round price", "2.5", 18, "26", ""},
+		{"18 decimals smallest precise price", "10.40", 18, "109", ""},
+		{"18 decimals too much rounding", "0.15", 18, "", "rounded down to 0.09"},
+		{"18 decimals within rounding tolerance", "3", 18, "31", ""},
+		{"6 decimals realistic price", "10", 6, "", "below the smallest price"},
+		{"6 decimals huge price", "100000000000000", 6, "1052", ""},
+		{"zero price", "0", 18, "", "greater than zero"},
+		{"malformed price", "ten", 18, "", "invalid USD price format"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ConvertUSDPerTBPerMonthToBytesPerEpoch(c.price, c.decimals)
+			if c.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), c.errContains) {
+					t.Fatalf("error = %v, want it to contain %q", err, c.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != c.want {
+				t.Fatalf("price = %s, want %s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	commcid "github.com/filecoin-project/go-fil-commcid"
+	commp "github.com/filecoin-project/go-fil-commp-hashhash"
+	"github.com/ipfs/go-cid"
+
+	"github.com/Eastore-project/ddo-client/pkg/curio/cidconv"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// commpBufferSize is the read buffer used while hashing; memory use does not grow with the data
+const commpBufferSize = 4 << 20
+
+// minPieceCidV2Payload is the smallest payload a v2 piece CID can encode
+const minPieceCidV2Payload = 127
+
+// ComputePieceCommitment streams r through the commP hasher and returns its v1 and v2
+// piece CIDs. Only a fixed-size buffer is held in memory, so inputs of any size work.
+func ComputePieceCommitment(r io.Reader) (*types.PieceCommitment, error) {
+	cp := new(commp.Calc)
+	n, err := io.CopyBuffer(cp, r, make([]byte, commpBufferSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data: %w", err)
+	}
+	rawCommP, pieceSize, err := cp.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute commP: %w", err)
+	}
+
+	v1, err := commcid.DataCommitmentV1ToCID(rawCommP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build piece CID: %w", err)
+	}
+	commitment := &types.PieceCommitment{
+		PieceCidV1:  v1.String(),
+		PieceSize:   pieceSize,
+		PayloadSize: uint64(n),
+	}
+
+	// v2 piece CIDs can't describe payloads below one Fr32 chunk
+	if n >= minPieceCidV2Payload {
+		v2, err := cidconv.PieceCidV2FromV1(v1, uint64(n))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build piece CID v2: %w", err)
+		}
+		commitment.PieceCidV2 = v2.String()
+	}
+	return commitment, nil
+}
+
+// OpenPieceSource opens a local file, or an http(s) URL for streaming
+func OpenPieceSource(ctx context.Context, source string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", source, err)
+		}
+		return f, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", source, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: HTTP %d", source, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// PieceCidHash returns the hash the DDO contract stores for a piece CID: the keccak256
+// of its binary form
+func PieceCidHash(pieceCid cid.Cid) common.Hash {
+	return crypto.Keccak256Hash(pieceCid.Bytes())
+}
+
+// VerifyPiece compares a locally computed commitment with an allocation and, when the
+// allocation has been claimed, with its claim. A piece smaller than the allocation is
+// first padded to the allocation's size, as pieces are when they are allocated.
+func VerifyPiece(allocationId uint64, commitment *types.PieceCommitment, info *types.AllocationInfo, claim *types.Claim) (*types.PieceVerification, error) {
+	pieceCidStr, pieceSize := commitment.PieceCidV1, commitment.PieceSize
+	var paddedPieceCid string
+	if info.PieceSize > pieceSize {
+		// Sizes that are not a power of two cannot be padded to and are reported as a mismatch
+		if padded, err := PadPieceCid(pieceCidStr, pieceSize, info.PieceSize); err == nil {
+			pieceCidStr, pieceSize, paddedPieceCid = padded, info.PieceSize, padded
+		}
+	}
+
+	pieceCid, err := cid.Decode(pieceCidStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid piece CID: %w", err)
+	}
+
+	hash := PieceCidHash(pieceCid)
+	expected := common.Hash(info.PieceCidHash)
+	v := &types.PieceVerification{
+		AllocationId:         allocationId,
+		Commitment:           *commitment,
+		PaddedPieceCid:       paddedPieceCid,
+		PieceCidHash:         hash.Hex(),
+		PieceSize:            pieceSize,
+		ExpectedPieceCidHash: expected.Hex(),
+		ExpectedPieceSize:    info.PieceSize,
+		HashMatches:          hash == expected,
+		SizeMatches:          pieceSize == info.PieceSize,
+	}
+	v.Match = v.HashMatches && v.SizeMatches
+
+	if claim != nil {
+		v.ClaimChecked = true
+		v.ClaimPieceCid = ClaimPieceCid(claim.Data)
+		v.ClaimSize = claim.Size
+		v.ClaimMatches = v.ClaimPieceCid == pieceCidStr && claim.Size == pieceSize
+		v.Match = v.Match && v.ClaimMatches
+	}
+	return v, nil
+}
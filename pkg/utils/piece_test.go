@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestComputePieceCommitment(t *testing.T) {
+	data := make([]byte, 300)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	c, err := ComputePieceCommitment(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.PieceSize != 512 || c.PayloadSize != 300 {
+		t.Errorf("piece size %d, payload %d, want 512 and 300", c.PieceSize, c.PayloadSize)
+	}
+	if c.PieceCidV2 == "" {
+		t.Error("expected a v2 piece CID")
+	}
+
+	small, err := ComputePieceCommitment(bytes.NewReader(data[:100]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.PieceCidV2 != "" {
+		t.Errorf("PieceCidV2 = %s, want empty below 127 bytes", small.PieceCidV2)
+	}
+}
+
+func TestVerifyPiece(t *testing.T) {
+	data := make([]byte, 300)
+	rand.New(rand.NewSource(2)).Read(data)
+	c, err := ComputePieceCommitment(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieceCid, err := cid.Decode(c.PieceCidV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.AllocationInfo{PieceCidHash: PieceCidHash(pieceCid), PieceSize: 512}
+
+	v, err := VerifyPiece(1, c, info, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Match || v.ClaimChecked {
+		t.Errorf("match = %v, claimChecked = %v, want true and false", v.Match, v.ClaimChecked)
+	}
+
+	claim := &types.Claim{Data: pieceCid.Bytes(), Size: 512}
+	v, err = VerifyPiece(1, c, info, claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Match || !v.ClaimMatches {
+		t.Errorf("claim: match = %v, claimMatches = %v, want both true", v.Match, v.ClaimMatches)
+	}
+
+	info.PieceSize = 256
+	v, err = VerifyPiece(1, c, info, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Match || v.SizeMatches || !v.HashMatches {
+		t.Errorf("size mismatch not detected: %+v", v)
+	}
+
+	// A larger allocation only matches the unpadded hash after padding
+	info.PieceSize = 1024
+	v, err = VerifyPiece(1, c, info, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Match || v.HashMatches || !v.SizeMatches {
+		t.Errorf("unpadded hash of a padded allocation matched: %+v", v)
+	}
+
+	info.PieceSize = 512
+	info.PieceCidHash = [32]byte{1}
+	v, err = VerifyPiece(1, c, info, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Match || v.HashMatches {
+		t.Errorf("hash mismatch not detected: %+v", v)
+	}
+}
+
+func TestVerifyPaddedPiece(t *testing.T) {
+	data := make([]byte, 300)
+	rand.New(rand.NewSource(3)).Read(data)
+	c, err := ComputePieceCommitment(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pieces are padded to the allocation size, as with split, aggregate and --car pieces
+	padded, err := PadPieceCid(c.PieceCidV1, c.PieceSize, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paddedCid, err := cid.Decode(padded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.AllocationInfo{PieceCidHash: PieceCidHash(paddedCid), PieceSize: 4096}
+	claim := &types.Claim{Data: paddedCid.Bytes(), Size: 4096}
+
+	v, err := VerifyPiece(1, c, info, claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Match || !v.HashMatches || !v.SizeMatches || !v.ClaimMatches {
+		t.Errorf("padded piece did not match: %+v", v)
+	}
+	if v.PaddedPieceCid != padded || v.PieceSize != 4096 {
+		t.Errorf("padded piece CID %s (%d bytes), want %s (4096 bytes)", v.PaddedPieceCid, v.PieceSize, padded)
+	}
+
+	// Other data padded to the same size does not match
+	other := make([]byte, 300)
+	other[0] = 1
+	oc, err := ComputePieceCommitment(bytes.NewReader(other))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = VerifyPiece(1, oc, info, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Match || v.HashMatches {
+		t.Errorf("different data matched: %+v", v)
+	}
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"math/bits"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	commcid "github.com/filecoin-project/go-fil-commcid"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// PaddedPieceSize returns the piece size a payload of the given size occupies on-chain:
+// the payload with Fr32 padding (128 bytes for every 127) rounded up to a power of two.
+func PaddedPieceSize(payloadSize uint64) uint64 {
+	if payloadSize <= 127 {
+		return 128
+	}
+	padded := (payloadSize + 126) / 127 * 128
+	return 1 << bits.Len64(padded-1)
+}
+
+// DataSize returns the size in bytes of a file, or of all regular files below a directory.
+// CAR encoding adds a small overhead that is not included.
+func DataSize(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return uint64(info.Size()), nil
+	}
+
+	var total uint64
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += uint64(fi.Size())
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to walk %s: %w", path, err)
+	}
+	return total, nil
+}
+
+// ErrProviderRejects is returned by QuoteAllocation when the provider does not accept
+// the piece size or term
+var ErrProviderRejects = errors.New("provider does not accept the allocation")
+
+// QuoteAllocation prices an allocation of dataSize bytes with a provider over termLength
+// epochs. Gas is estimated for from with termMax as the maximum term; a failed estimate
+// is reported in GasError rather than failing the quote.
+func QuoteAllocation(ddoClient *ddo.Client, provider uint64, token common.Address, dataSize uint64, termLength, termMax int64, from common.Address) (*types.AllocationQuote, error) {
+	if termLength <= 0 {
+		return nil, fmt.Errorf("term length must be positive")
+	}
+
+	quote := &types.AllocationQuote{
+		Provider:   provider,
+		Token:      token,
+		DataSize:   dataSize,
+		PieceSize:  PaddedPieceSize(dataSize),
+		TermLength: termLength,
+	}
+
+	spConfig, err := ddoClient.GetSPConfig(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SP config: %w", err)
+	}
+	if spConfig == nil {
+		return nil, fmt.Errorf("%w: storage provider %d is not registered", ErrProviderRejects, provider)
+	}
+	if quote.PieceSize < spConfig.MinPieceSize || quote.PieceSize > spConfig.MaxPieceSize {
+		return nil, fmt.Errorf("%w: piece size %d is outside provider %d's accepted range %d - %d",
+			ErrProviderRejects, quote.PieceSize, provider, spConfig.MinPieceSize, spConfig.MaxPieceSize)
+	}
+	if termLength < spConfig.MinTermLength || termLength > spConfig.MaxTermLength {
+		return nil, fmt.Errorf("%w: term %d is outside provider %d's accepted range %d - %d epochs",
+			ErrProviderRejects, termLength, provider, spConfig.MinTermLength, spConfig.MaxTermLength)
+	}
+
+	quote.PricePerBytePerEpoch, err = ddoClient.GetAndValidateSPPrice(provider, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SP price: %w", err)
+	}
+	quote.StorageCost, err = ddoClient.CalculateStorageCost(provider, token, quote.PieceSize, termLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate storage cost: %w", err)
+	}
+	quote.AllocationLockupAmount, err = ddoClient.GetAllocationLockupAmount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocation lockup amount: %w", err)
+	}
+
+	req := CalculatePaymentRequirements(&StorageCostResult{
+		TotalCost:            quote.StorageCost,
+		PricePerBytePerEpoch: quote.PricePerBytePerEpoch,
+		TotalBytes:           quote.PieceSize,
+		TotalEpochs:          termLength,
+	}, quote.AllocationLockupAmount, 1)
+	quote.MonthlyCost = req.MonthlyCost
+	quote.RequiredDeposit = req.RequiredDeposit
+	quote.RateAllowance = req.RateAllowance
+	quote.LockupAllowance = req.LockupAllowance
+
+	estimateQuoteGas(ddoClient, quote, from, termMax)
+	return quote, nil
+}
+
+// estimateQuoteGas fills in the gas fields of quote. The allocation request uses a
+// placeholder piece CID since the data has not been prepared.
+func estimateQuoteGas(ddoClient *ddo.Client, quote *types.AllocationQuote, from common.Address, termMax int64) {
+	placeholder, err := commcid.DataCommitmentV1ToCID(make([]byte, 32))
+	if err != nil {
+		quote.GasError = err.Error()
+		return
+	}
+
+	pieceInfo := types.PieceInfo{
+		PieceCid:            placeholder.Bytes(),
+		Size:                quote.PieceSize,
+		Provider:            quote.Provider,
+		TermMin:             quote.TermLength,
+		TermMax:             termMax,
+		ExpirationOffset:    172800, // create-from-file default
+		PaymentTokenAddress: quote.Token,
+	}
+
+	gasLimit, err := ddoClient.EstimateCreateAllocationRequestsGas(from, []types.PieceInfo{pieceInfo})
+	if err != nil {
+		quote.GasError = err.Error()
+		return
+	}
+	gasPrice, err := ddoClient.GetEthClient().SuggestGasPrice(context.Background())
+	if err != nil {
+		quote.GasError = fmt.Sprintf("failed to get gas price: %v", err)
+		return
+	}
+
+	quote.GasLimit = gasLimit
+	quote.GasPrice = gasPrice
+	quote.GasCost = new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPrice)
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPaddedPieceSize(t *testing.T) {
+	tests := []struct {
+		payload uint64
+		want    uint64
+	}{
+		{0, 128},
+		{1, 128},
+		{127, 128},
+		{128, 256},
+		{254, 256},
+		{255, 512},
+		{1 << 20, 2 << 20},
+		{127 << 20, 128 << 20},
+		{(127 << 20) + 1, 256 << 20},
+	}
+	for _, tt := range tests {
+		if got := PaddedPieceSize(tt.payload); got != tt.want {
+			t.Errorf("PaddedPieceSize(%d) = %d, want %d", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestDataSize(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 50), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := DataSize(filepath.Join(dir, "a")); err != nil || got != 100 {
+		t.Errorf("DataSize(file) = %d, %v, want 100", got, err)
+	}
+	if got, err := DataSize(dir); err != nil || got != 150 {
+		t.Errorf("DataSize(dir) = %d, %v, want 150", got, err)
+	}
+	if _, err := DataSize(filepath.Join(dir, "missing")); err == nil {
+		t.Error("DataSize(missing) should fail")
+	}
+}
+
+func TestCalculatePaymentRequirements(t *testing.T) {
+	cost := &StorageCostResult{
+		TotalCost:            big.NewInt(1000),
+		PricePerBytePerEpoch: big.NewInt(2),
+		TotalBytes:           10,
+		TotalEpochs:          50,
+	}
+
+	req := CalculatePaymentRequirements(cost, big.NewInt(100), 1)
+	if req.RateAllowance.Cmp(big.NewInt(20)) != 0 {
+		t.Errorf("RateAllowance = %s, want 20", req.RateAllowance)
+	}
+	if req.MonthlyCost.Cmp(big.NewInt(20*EPOCHS_PER_MONTH)) != 0 {
+		t.Errorf("MonthlyCost = %s, want %d", req.MonthlyCost, 20*EPOCHS_PER_MONTH)
+	}
+	if req.RequiredDeposit.Cmp(big.NewInt(2000)) != 0 {
+		t.Errorf("RequiredDeposit = %s, want 2000", req.RequiredDeposit)
+	}
+	if req.LockupAllowance.Cmp(big.NewInt(200)) != 0 {
+		t.Errorf("LockupAllowance = %s, want 200", req.LockupAllowance)
+	}
+
+	// The fixed per-rail lockup dominates the deposit when it exceeds the storage cost
+	req = CalculatePaymentRequirements(cost, big.NewInt(1200), 2)
+	if req.RequiredDeposit.Cmp(big.NewInt(4800)) != 0 {
+		t.Errorf("RequiredDeposit = %s, want 4800", req.RequiredDeposit)
+	}
+	if req.LockupAllowance.Cmp(big.NewInt(4800)) != 0 {
+		t.Errorf("LockupAllowance = %s, want 4800", req.LockupAllowance)
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"math/big"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// COMMISSION_MAX_BPS is the basis point denominator used by the Payments contract
+const COMMISSION_MAX_BPS = 10000
+
+// SplitSettlementAmount splits a gross settlement amount into network fee, operator
+// commission and net payee amount using the Payments contract's fee rules.
+// The network fee is taken first and the commission applies to the remainder.
+func SplitSettlementAmount(gross, feeNumerator, feeDenominator, commissionRateBps *big.Int) (networkFee, commission, net *big.Int) {
+	networkFee = big.NewInt(0)
+	if feeDenominator != nil && feeDenominator.Sign() > 0 && feeNumerator != nil {
+		networkFee = new(big.Int).Mul(gross, feeNumerator)
+		networkFee.Div(networkFee, feeDenominator)
+	}
+
+	afterFee := new(big.Int).Sub(gross, networkFee)
+
+	commission = big.NewInt(0)
+	if commissionRateBps != nil && commissionRateBps.Sign() > 0 {
+		commission = new(big.Int).Mul(afterFee, commissionRateBps)
+		commission.Div(commission, big.NewInt(COMMISSION_MAX_BPS))
+	}
+
+	net = new(big.Int).Sub(afterFee, commission)
+	return networkFee, commission, net
+}
+
+// ProjectRailSettlement estimates the payout of settling a rail up to untilEpoch and
+// the locked funds that are returned to the payer once the rail is finalized.
+// For terminated rails untilEpoch is capped at the rail's end epoch.
+func ProjectRailSettlement(railId *big.Int, rail *types.RailView, untilEpoch, feeNumerator, feeDenominator *big.Int) *types.RailProjection {
+	until := new(big.Int).Set(untilEpoch)
+	if rail.EndEpoch != nil && rail.EndEpoch.Sign() > 0 && until.Cmp(rail.EndEpoch) > 0 {
+		until.Set(rail.EndEpoch)
+	}
+
+	epochs := new(big.Int).Sub(until, rail.SettledUpTo)
+	if epochs.Sign() < 0 {
+		epochs.SetInt64(0)
+	}
+
+	gross := new(big.Int).Mul(rail.PaymentRate, epochs)
+	networkFee, commission, net := SplitSettlementAmount(gross, feeNumerator, feeDenominator, rail.CommissionRateBps)
+
+	// The payer's lockup for a rail is lockupFixed plus rate * lockupPeriod. Only the
+	// streaming part that is actually paid out is consumed; the rest comes back.
+	consumedEpochs := new(big.Int).Set(epochs)
+	if consumedEpochs.Cmp(rail.LockupPeriod) > 0 {
+		consumedEpochs.Set(rail.LockupPeriod)
+	}
+	unusedEpochs := new(big.Int).Sub(rail.LockupPeriod, consumedEpochs)
+	refund := new(big.Int).Mul(rail.PaymentRate, unusedEpochs)
+	refund.Add(refund, rail.LockupFixed)
+
+	return &types.RailProjection{
+		RailId:             railId,
+		FromEpoch:          new(big.Int).Set(rail.SettledUpTo),
+		UntilEpoch:         until,
+		GrossPayout:        gross,
+		NetworkFee:         networkFee,
+		OperatorCommission: commission,
+		NetPayout:          net,
+		LockupRefund:       refund,
+	}
+}
+
+// IsRailTerminated reports whether a rail has been terminated
+func IsRailTerminated(rail *types.RailView) bool {
+	return rail.EndEpoch != nil && rail.EndEpoch.Sign() > 0
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestSplitSettlementAmount(t *testing.T) {
+	fee, commission, net := SplitSettlementAmount(big.NewInt(10000), big.NewInt(1), big.NewInt(200), big.NewInt(100))
+
+	// 0.5% network fee, then 1% commission on the remainder
+	if fee.Int64() != 50 {
+		t.Fatalf("network fee: got %s, want 50", fee)
+	}
+	if commission.Int64() != 99 {
+		t.Fatalf("commission: got %s, want 99", commission)
+	}
+	if net.Int64() != 9851 {
+		t.Fatalf("net: got %s, want 9851", net)
+	}
+}
+
+func TestProjectRailSettlementCapsAtEndEpoch(t *testing.T) {
+	rail := &types.RailView{
+		PaymentRate:       big.NewInt(10),
+		LockupPeriod:      big.NewInt(100),
+		LockupFixed:       big.NewInt(7),
+		SettledUpTo:       big.NewInt(1000),
+		EndEpoch:          big.NewInt(1050),
+		CommissionRateBps: big.NewInt(0),
+	}
+
+	p := ProjectRailSettlement(big.NewInt(1), rail, big.NewInt(2000), big.NewInt(0), big.NewInt(1))
+
+	if p.UntilEpoch.Int64() != 1050 {
+		t.Fatalf("until epoch: got %s, want 1050", p.UntilEpoch)
+	}
+	if p.GrossPayout.Int64() != 500 {
+		t.Fatalf("gross payout: got %s, want 500", p.GrossPayout)
+	}
+	// 50 of the 100 lockup epochs are unused, plus the fixed lockup
+	if p.LockupRefund.Int64() != 507 {
+		t.Fatalf("lockup refund: got %s, want 507", p.LockupRefund)
+	}
+}
+
+func TestProjectRailSettlementAlreadySettled(t *testing.T) {
+	rail := &types.RailView{
+		PaymentRate:       big.NewInt(10),
+		LockupPeriod:      big.NewInt(100),
+		LockupFixed:       big.NewInt(0),
+		SettledUpTo:       big.NewInt(500),
+		EndEpoch:          big.NewInt(0),
+		CommissionRateBps: big.NewInt(0),
+	}
+
+	p := ProjectRailSettlement(big.NewInt(1), rail, big.NewInt(400), big.NewInt(0), big.NewInt(1))
+	if p.GrossPayout.Sign() != 0 {
+		t.Fatalf("gross payout: got %s, want 0", p.GrossPayout)
+	}
+}
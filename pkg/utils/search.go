@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// SPSearchFilter selects storage provider offers in `sp search`. Zero values match anything.
+type SPSearchFilter struct {
+	Tokens          []common.Address
+	PieceSize       uint64
+	TermLength      int64
+	IncludeInactive bool
+}
+
+// MatchSPOffers returns the offers of one storage provider that satisfy the filter, one per
+// supported token. monthlyPrices may be nil; it is matched to the config's tokens by address.
+// decimals returns the decimals of a token, reported with its offers; its error is returned.
+func MatchSPOffers(
+	providerId uint64,
+	cfg *types.SPConfig,
+	monthlyPrices []types.SPTokenPrice,
+	filter SPSearchFilter,
+	decimals func(token common.Address) (uint8, error),
+) ([]types.SPOffer, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if !cfg.IsActive && !filter.IncludeInactive {
+		return nil, nil
+	}
+	if filter.PieceSize != 0 && (filter.PieceSize < cfg.MinPieceSize || filter.PieceSize > cfg.MaxPieceSize) {
+		return nil, nil
+	}
+	if filter.TermLength != 0 && (filter.TermLength < cfg.MinTermLength || filter.TermLength > cfg.MaxTermLength) {
+		return nil, nil
+	}
+
+	monthly := make(map[common.Address]*big.Int, len(monthlyPrices))
+	for _, p := range monthlyPrices {
+		monthly[p.Token] = p.PricePerTBPerMonth
+	}
+
+	var offers []types.SPOffer
+	for _, tc := range cfg.SupportedTokens {
+		if len(filter.Tokens) > 0 && !containsAddress(filter.Tokens, tc.Token) {
+			continue
+		}
+		if !tc.IsActive && !filter.IncludeInactive {
+			continue
+		}
+		if tc.PricePerBytePerEpoch == nil {
+			continue
+		}
+
+		tokenDecimals, err := decimals(tc.Token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get decimals of token %s: %w", tc.Token.Hex(), err)
+		}
+
+		perMonth, ok := monthly[tc.Token]
+		if !ok {
+			perMonth = ConvertBytesPerEpochToTBPerMonth(tc.PricePerBytePerEpoch)
+		}
+
+		offer := types.SPOffer{
+			ProviderId:           providerId,
+			PaymentAddress:       cfg.PaymentAddress,
+			Active:               cfg.IsActive && tc.IsActive,
+			MinPieceSize:         cfg.MinPieceSize,
+			MaxPieceSize:         cfg.MaxPieceSize,
+			MinTermLength:        cfg.MinTermLength,
+			MaxTermLength:        cfg.MaxTermLength,
+			Token:                tc.Token,
+			Decimals:             tokenDecimals,
+			PricePerBytePerEpoch: tc.PricePerBytePerEpoch,
+			PricePerTBPerMonth:   perMonth,
+		}
+		if filter.PieceSize != 0 && filter.TermLength != 0 {
+			// Same formula as the contract's calculateStorageCost
+			offer.TotalCost = new(big.Int).Mul(tc.PricePerBytePerEpoch, new(big.Int).SetUint64(filter.PieceSize))
+			offer.TotalCost.Mul(offer.TotalCost, big.NewInt(filter.TermLength))
+		}
+		offers = append(offers, offer)
+	}
+	return offers, nil
+}
+
+// SearchSPOffers scans the SP registry and returns the matching offers of every registered
+// provider, ranked with RankSPOffers, along with the number of registered providers.
+// Providers whose config cannot be read are skipped; a token whose decimals cannot be read
+// fails the search rather than being reported with made-up decimals.
+func SearchSPOffers(ddoClient *ddo.Client, filter SPSearchFilter, decimals func(token common.Address) (uint8, error)) ([]types.SPOffer, int, error) {
+	spIds, err := ddoClient.GetAllSPIds()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get SP IDs: %w", err)
+	}
+
+	configs, errs := ddoClient.GetSPConfigs(spIds)
+	// Monthly prices are derived from the per-epoch prices where these calls fail
+	prices, _ := ddoClient.GetSPsAllTokenPricesPerMonth(spIds)
+
+	var offers []types.SPOffer
+	for i, id := range spIds {
+		if errs[i] != nil {
+			log.Warnw("skipping provider", "provider", id, "error", errs[i])
+			continue
+		}
+		matched, err := MatchSPOffers(id, configs[i], prices[i], filter, decimals)
+		if err != nil {
+			return nil, 0, fmt.Errorf("provider %d: %w", id, err)
+		}
+		offers = append(offers, matched...)
+	}
+
+	RankSPOffers(offers)
+	return offers, len(spIds), nil
+}
+
+// TopProviders returns the IDs of the first n distinct providers of ranked offers, which
+// should all be in one token. A provider is skipped when it shares no piece size with the
+// providers already picked, so that one prepared piece suits them all. A zero maximum piece
+// size is not a limit.
+func TopProviders(offers []types.SPOffer, n int) []uint64 {
+	var ids []uint64
+	var minSize, maxSize uint64
+	seen := make(map[uint64]bool)
+	for _, o := range offers {
+		if len(ids) == n {
+			break
+		}
+		if seen[o.ProviderId] {
+			continue
+		}
+		newMin, newMax := minSize, maxSize
+		if o.MinPieceSize > newMin {
+			newMin = o.MinPieceSize
+		}
+		if o.MaxPieceSize != 0 && (newMax == 0 || o.MaxPieceSize < newMax) {
+			newMax = o.MaxPieceSize
+		}
+		if newMax != 0 && newMin > newMax {
+			continue
+		}
+		seen[o.ProviderId] = true
+		minSize, maxSize = newMin, newMax
+		ids = append(ids, o.ProviderId)
+	}
+	return ids
+}
+
+// RankSPOffers groups offers by token and sorts each group from cheapest to most expensive.
+// Amounts in different tokens are not comparable, so offers are only ranked against offers
+// in the same token.
+func RankSPOffers(offers []types.SPOffer) {
+	key := func(o types.SPOffer) *big.Int {
+		if o.TotalCost != nil {
+			return o.TotalCost
+		}
+		return o.PricePerBytePerEpoch
+	}
+
+	sort.SliceStable(offers, func(i, j int) bool {
+		if cmp := bytes.Compare(offers[i].Token.Bytes(), offers[j].Token.Bytes()); cmp != 0 {
+			return cmp < 0
+		}
+		if cmp := key(offers[i]).Cmp(key(offers[j])); cmp != 0 {
+			return cmp < 0
+		}
+		return offers[i].ProviderId < offers[j].ProviderId
+	})
+}
+
+// LimitSPOffers keeps the first n offers of each token of ranked offers
+func LimitSPOffers(offers []types.SPOffer, n int) []types.SPOffer {
+	var limited []types.SPOffer
+	perToken := make(map[common.Address]int)
+	for _, o := range offers {
+		if perToken[o.Token] < n {
+			perToken[o.Token]++
+			limited = append(limited, o)
+		}
+	}
+	return limited
+}
+
+func containsAddress(list []common.Address, addr common.Address) bool {
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestMatchSPOffers(t *testing.T) {
+	usd6 := common.HexToAddress("0x06")
+	usd18 := common.HexToAddress("0x18")
+	unknown := common.HexToAddress("0x99")
+	decimals := func(token common.Address) (uint8, error) {
+		switch token {
+		case usd6:
+			return 6, nil
+		case unknown:
+			return 0, errors.New("execution reverted")
+		}
+		return 18, nil
+	}
+
+	cfg := func(active bool, tokens ...types.TokenConfig) *types.SPConfig {
+		return &types.SPConfig{
+			MinPieceSize:    1024,
+			MaxPieceSize:    1 << 30,
+			MinTermLength:   100,
+			MaxTermLength:   1000,
+			SupportedTokens: tokens,
+			IsActive:        active,
+		}
+	}
+
+	match := func(providerId uint64, cfg *types.SPConfig, monthly []types.SPTokenPrice, filter SPSearchFilter) []types.SPOffer {
+		t.Helper()
+		offers, err := MatchSPOffers(providerId, cfg, monthly, filter, decimals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return offers
+	}
+
+	filter := SPSearchFilter{PieceSize: 2048, TermLength: 500}
+
+	offersA := match(1, cfg(true, types.TokenConfig{Token: usd18, PricePerBytePerEpoch: big.NewInt(3_000_000_000_000), IsActive: true}), nil, filter)
+	offersB := match(2, cfg(true, types.TokenConfig{Token: usd6, PricePerBytePerEpoch: big.NewInt(4), IsActive: true}), nil, filter)
+	if len(offersA) != 1 || len(offersB) != 1 {
+		t.Fatalf("expected one offer each, got %d and %d", len(offersA), len(offersB))
+	}
+	if got := offersB[0].TotalCost.Int64(); got != 4*2048*500 {
+		t.Fatalf("total cost = %d, want %d", got, 4*2048*500)
+	}
+	if offersB[0].Decimals != 6 {
+		t.Fatalf("decimals = %d, want 6", offersB[0].Decimals)
+	}
+
+	inactiveToken := cfg(true, types.TokenConfig{Token: usd6, PricePerBytePerEpoch: big.NewInt(1), IsActive: false})
+	if got := match(3, inactiveToken, nil, filter); len(got) != 0 {
+		t.Fatalf("inactive token should be excluded, got %+v", got)
+	}
+	if got := match(3, inactiveToken, nil, SPSearchFilter{IncludeInactive: true}); len(got) != 1 || got[0].Active {
+		t.Fatalf("inactive token should be included when requested, got %+v", got)
+	}
+	if got := match(4, cfg(false, types.TokenConfig{Token: usd6, PricePerBytePerEpoch: big.NewInt(1), IsActive: true}), nil, filter); len(got) != 0 {
+		t.Fatalf("inactive SP should be excluded, got %+v", got)
+	}
+	if got := match(1, cfg(true, types.TokenConfig{Token: usd6, PricePerBytePerEpoch: big.NewInt(1), IsActive: true}), nil, SPSearchFilter{PieceSize: 512}); len(got) != 0 {
+		t.Fatalf("piece size below minimum should be excluded, got %+v", got)
+	}
+	if got := match(1, cfg(true, types.TokenConfig{Token: usd6, PricePerBytePerEpoch: big.NewInt(1), IsActive: true}), nil, SPSearchFilter{Tokens: []common.Address{usd18}}); len(got) != 0 {
+		t.Fatalf("token filter should exclude other tokens, got %+v", got)
+	}
+
+	monthly := []types.SPTokenPrice{{Token: usd6, PricePerTBPerMonth: big.NewInt(10_000_000)}}
+	got := match(1, cfg(true, types.TokenConfig{Token: usd6, PricePerBytePerEpoch: big.NewInt(1), IsActive: true}), monthly, SPSearchFilter{})
+	if len(got) != 1 || got[0].PricePerTBPerMonth.Int64() != 10_000_000 || got[0].TotalCost != nil {
+		t.Fatalf("unexpected offer without size and term: %+v", got)
+	}
+
+	if _, err := MatchSPOffers(5, cfg(true, types.TokenConfig{Token: unknown, PricePerBytePerEpoch: big.NewInt(1), IsActive: true}), nil, SPSearchFilter{}, decimals); err == nil {
+		t.Fatal("expected the decimals error for a token without metadata")
+	}
+}
+
+func TestRankSPOffers(t *testing.T) {
+	usd6 := common.HexToAddress("0x06")
+	usd18 := common.HexToAddress("0x18")
+	offer := func(provider uint64, token common.Address, cost int64) types.SPOffer {
+		return types.SPOffer{ProviderId: provider, Token: token, PricePerBytePerEpoch: big.NewInt(1), TotalCost: big.NewInt(cost)}
+	}
+
+	// Amounts in different tokens are never compared, whatever their decimals
+	offers := []types.SPOffer{
+		offer(1, usd18, 3_000_000_000_000),
+		offer(2, usd6, 4),
+		offer(3, usd18, 1),
+		offer(4, usd6, 2),
+		offer(5, usd6, 2),
+	}
+	RankSPOffers(offers)
+
+	want := []uint64{4, 5, 2, 3, 1}
+	for i, id := range want {
+		if offers[i].ProviderId != id {
+			t.Fatalf("ranking = %v, want %v", offers, want)
+		}
+	}
+
+	limited := LimitSPOffers(offers, 2)
+	if len(limited) != 4 || limited[1].ProviderId != 5 || limited[2].ProviderId != 3 || limited[3].ProviderId != 1 {
+		t.Errorf("LimitSPOffers(2) = %+v, want two offers per token", limited)
+	}
+}
+
+func TestTopProviders(t *testing.T) {
+	offers := []types.SPOffer{
+		{ProviderId: 3}, {ProviderId: 1}, {ProviderId: 3}, {ProviderId: 2}, {ProviderId: 4},
+	}
+
+	got := TopProviders(offers, 3)
+	want := []uint64{3, 1, 2}
+	if len(got) != len(want) {
+		t.Fatalf("TopProviders = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("TopProviders = %v, want %v", got, want)
+		}
+	}
+
+	if got := TopProviders(offers, 10); len(got) != 4 {
+		t.Errorf("TopProviders(10) = %v, want all 4 providers", got)
+	}
+
+	// Provider 2 accepts no piece size provider 1 does
+	sized := []types.SPOffer{
+		{ProviderId: 1, MinPieceSize: 1 << 10, MaxPieceSize: 1 << 20},
+		{ProviderId: 2, MinPieceSize: 1 << 21, MaxPieceSize: 1 << 30},
+		{ProviderId: 3, MinPieceSize: 1 << 12, MaxPieceSize: 1 << 30},
+	}
+	if got := TopProviders(sized, 2); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("TopProviders = %v, want [1 3]", got)
+	}
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// settleBatchSize is the number of allocations settled per transaction when a
+// provider cannot be settled in one
+const settleBatchSize uint64 = 50
+
+// SettleSPPayments settles the payments of one allocation, or of every allocation of
+// providerId when allocationId is 0, up to untilEpoch, waiting for each transaction.
+// A provider is settled in a single transaction first and in batches if that fails.
+// onTx, if set, is called with each mined transaction. All transactions sent are
+// returned, including the one that failed.
+func SettleSPPayments(ddoClient *ddo.Client, providerId, allocationId, untilEpoch uint64, onTx func(types.TxResult)) ([]types.TxResult, error) {
+	var txs []types.TxResult
+	settleEpoch := new(big.Int).SetUint64(untilEpoch)
+
+	wait := func(txHash string) error {
+		receipt, err := WaitForTransactionWithReceipt(ddoClient.TxManager(), txHash)
+		tx := NewTxResult(txHash, receipt, err)
+		txs = append(txs, tx)
+		if onTx != nil {
+			onTx(tx)
+		}
+		return settlementTxError(tx)
+	}
+
+	if allocationId > 0 {
+		txHash, err := ddoClient.SettleSpPayment(allocationId, settleEpoch)
+		if err != nil {
+			return txs, fmt.Errorf("failed to settle SP payment for allocation %d: %w", allocationId, err)
+		}
+		if err := wait(txHash); err != nil {
+			return txs, fmt.Errorf("settlement transaction failed: %w", err)
+		}
+		return txs, nil
+	}
+
+	// Try settling all at once first
+	txHash, err := ddoClient.SettleSpTotalPayment(providerId, settleEpoch, big.NewInt(0), big.NewInt(0))
+	if err == nil {
+		if err = wait(txHash); err == nil {
+			return txs, nil
+		}
+	}
+
+	allocationIds, err := ddoClient.GetAllocationIdsForProvider(providerId)
+	if err != nil {
+		return txs, fmt.Errorf("failed to get allocation IDs for provider: %w", err)
+	}
+
+	total := uint64(len(allocationIds))
+	for startIndex := uint64(0); startIndex < total; startIndex += settleBatchSize {
+		batchTxHash, err := ddoClient.SettleSpTotalPayment(
+			providerId, settleEpoch,
+			new(big.Int).SetUint64(startIndex),
+			new(big.Int).SetUint64(settleBatchSize),
+		)
+		if err != nil {
+			return txs, fmt.Errorf("failed to settle batch starting at index %d: %w", startIndex, err)
+		}
+		if err := wait(batchTxHash); err != nil {
+			return txs, fmt.Errorf("batch transaction failed: %w", err)
+		}
+	}
+	return txs, nil
+}
+
+// settlementTxError returns an error unless a settlement transaction succeeded
+func settlementTxError(tx types.TxResult) error {
+	switch tx.Status {
+	case types.TxStatusPending:
+		return fmt.Errorf("transaction %s may not have been mined: %s", tx.TxHash, tx.Error)
+	case types.TxStatusReverted:
+		return fmt.Errorf("transaction %s reverted in block %d", tx.TxHash, tx.BlockNumber)
+	case types.TxStatusSuccess:
+		return nil
+	}
+	return fmt.Errorf("transaction %s %s", tx.TxHash, tx.Status)
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// LoadSPSpec reads a storage provider spec from a YAML or JSON file and validates it
+func LoadSPSpec(path string) (*types.SPSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file: %w", err)
+	}
+
+	var spec types.SPSpec
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &spec)
+	} else {
+		err = yaml.Unmarshal(data, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec file: %w", err)
+	}
+
+	if err := ValidateSPSpec(&spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// ValidateSPSpec checks a spec with the same rules as `sp register`
+func ValidateSPSpec(spec *types.SPSpec) error {
+	if spec.ActorId == 0 {
+		return fmt.Errorf("spec is missing actorId")
+	}
+	if !common.IsHexAddress(spec.PaymentAddress) {
+		return fmt.Errorf("invalid payment address: %q", spec.PaymentAddress)
+	}
+	if spec.MinPieceSize == 0 || spec.MaxPieceSize < spec.MinPieceSize {
+		return fmt.Errorf("invalid piece size range: min=%d, max=%d", spec.MinPieceSize, spec.MaxPieceSize)
+	}
+	if spec.MinTermLength <= 0 || spec.MaxTermLength < spec.MinTermLength {
+		return fmt.Errorf("invalid term range: min=%d, max=%d", spec.MinTermLength, spec.MaxTermLength)
+	}
+	if len(spec.Tokens) == 0 {
+		return fmt.Errorf("spec must list at least one token")
+	}
+
+	seen := make(map[common.Address]bool)
+	for _, t := range spec.Tokens {
+		if !common.IsHexAddress(t.Token) {
+			return fmt.Errorf("invalid token address: %q", t.Token)
+		}
+		addr := common.HexToAddress(t.Token)
+		if seen[addr] {
+			return fmt.Errorf("token %s is listed more than once", addr.Hex())
+		}
+		seen[addr] = true
+		if t.PriceUSDPerTBPerMonth == "" {
+			return fmt.Errorf("token %s is missing priceUSDPerTBPerMonth", addr.Hex())
+		}
+	}
+	return nil
+}
+
+// PlanSPChanges returns the contract calls that move the current on-chain state
+// (nil if the SP is not registered) to the desired state, in execution order.
+// Tokens on-chain but not in the desired state are removed. formatPrice renders
+// prices in the plan; if nil they are shown in base units.
+func PlanSPChanges(desired types.SPRegistrationParams, current *types.SPConfig, formatPrice func(token common.Address, price *big.Int) string) []types.SPPlanStep {
+	if formatPrice == nil {
+		formatPrice = func(_ common.Address, price *big.Int) string { return price.String() }
+	}
+
+	if current == nil {
+		step := types.SPPlanStep{
+			Action: types.SPActionRegister,
+			Changes: []types.SPFieldChange{
+				{Field: "paymentAddress", To: desired.PaymentAddress.Hex()},
+				{Field: "minPieceSize", To: formatSize(desired.MinPieceSize)},
+				{Field: "maxPieceSize", To: formatSize(desired.MaxPieceSize)},
+				{Field: "minTermLength", To: strconv.FormatInt(desired.MinTermLength, 10)},
+				{Field: "maxTermLength", To: strconv.FormatInt(desired.MaxTermLength, 10)},
+			},
+		}
+		for _, tc := range desired.TokenConfigs {
+			step.Changes = append(step.Changes, types.SPFieldChange{
+				Field: "token " + tc.Token.Hex(),
+				To:    fmt.Sprintf("%s (active: %t)", formatPrice(tc.Token, tc.PricePerBytePerEpoch), tc.IsActive),
+			})
+		}
+		return []types.SPPlanStep{step}
+	}
+
+	var steps []types.SPPlanStep
+
+	var changes []types.SPFieldChange
+	if current.PaymentAddress != desired.PaymentAddress {
+		changes = append(changes, types.SPFieldChange{Field: "paymentAddress", From: current.PaymentAddress.Hex(), To: desired.PaymentAddress.Hex()})
+	}
+	if current.MinPieceSize != desired.MinPieceSize {
+		changes = append(changes, types.SPFieldChange{Field: "minPieceSize", From: formatSize(current.MinPieceSize), To: formatSize(desired.MinPieceSize)})
+	}
+	if current.MaxPieceSize != desired.MaxPieceSize {
+		changes = append(changes, types.SPFieldChange{Field: "maxPieceSize", From: formatSize(current.MaxPieceSize), To: formatSize(desired.MaxPieceSize)})
+	}
+	if current.MinTermLength != desired.MinTermLength {
+		changes = append(changes, types.SPFieldChange{Field: "minTermLength", From: strconv.FormatInt(current.MinTermLength, 10), To: strconv.FormatInt(desired.MinTermLength, 10)})
+	}
+	if current.MaxTermLength != desired.MaxTermLength {
+		changes = append(changes, types.SPFieldChange{Field: "maxTermLength", From: strconv.FormatInt(current.MaxTermLength, 10), To: strconv.FormatInt(desired.MaxTermLength, 10)})
+	}
+	if len(changes) > 0 {
+		steps = append(steps, types.SPPlanStep{Action: types.SPActionUpdateConfig, Changes: changes})
+	}
+
+	existing := make(map[common.Address]types.TokenConfig, len(current.SupportedTokens))
+	for _, tc := range current.SupportedTokens {
+		existing[tc.Token] = tc
+	}
+	wanted := make(map[common.Address]bool, len(desired.TokenConfigs))
+
+	for _, tc := range desired.TokenConfigs {
+		wanted[tc.Token] = true
+
+		cur, ok := existing[tc.Token]
+		if !ok {
+			// addSPToken always adds an active token, so deactivate it afterwards if needed
+			steps = append(steps, types.SPPlanStep{
+				Action:               types.SPActionAddToken,
+				Token:                tc.Token,
+				PricePerBytePerEpoch: tc.PricePerBytePerEpoch,
+				IsActive:             true,
+				Changes:              []types.SPFieldChange{{Field: "price", To: formatPrice(tc.Token, tc.PricePerBytePerEpoch)}},
+			})
+			if !tc.IsActive {
+				steps = append(steps, types.SPPlanStep{
+					Action:               types.SPActionUpdateToken,
+					Token:                tc.Token,
+					PricePerBytePerEpoch: tc.PricePerBytePerEpoch,
+					IsActive:             false,
+					Changes:              []types.SPFieldChange{{Field: "isActive", From: "true", To: "false"}},
+				})
+			}
+			continue
+		}
+
+		var tokenChanges []types.SPFieldChange
+		if cur.PricePerBytePerEpoch == nil || cur.PricePerBytePerEpoch.Cmp(tc.PricePerBytePerEpoch) != 0 {
+			from := "none"
+			if cur.PricePerBytePerEpoch != nil {
+				from = formatPrice(tc.Token, cur.PricePerBytePerEpoch)
+			}
+			tokenChanges = append(tokenChanges, types.SPFieldChange{Field: "price", From: from, To: formatPrice(tc.Token, tc.PricePerBytePerEpoch)})
+		}
+		if cur.IsActive != tc.IsActive {
+			tokenChanges = append(tokenChanges, types.SPFieldChange{Field: "isActive", From: strconv.FormatBool(cur.IsActive), To: strconv.FormatBool(tc.IsActive)})
+		}
+		if len(tokenChanges) > 0 {
+			steps = append(steps, types.SPPlanStep{
+				Action:               types.SPActionUpdateToken,
+				Token:                tc.Token,
+				PricePerBytePerEpoch: tc.PricePerBytePerEpoch,
+				IsActive:             tc.IsActive,
+				Changes:              tokenChanges,
+			})
+		}
+	}
+
+	for _, tc := range current.SupportedTokens {
+		if !wanted[tc.Token] {
+			steps = append(steps, types.SPPlanStep{Action: types.SPActionRemoveToken, Token: tc.Token})
+		}
+	}
+
+	return steps
+}
+
+func formatSize(size uint64) string {
+	return FormatBytes(new(big.Int).SetUint64(size))
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestLoadSPSpec(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sp.yaml")
+	spec := `actorId: 1000
+paymentAddress: "0x0000000000000000000000000000000000000001"
+minPieceSize: 128
+maxPieceSize: 34359738368
+minTermLength: 518400
+maxTermLength: 5256000
+tokens:
+  - token: "0x00000000000000000000000000000000000000aa"
+    priceUSDPerTBPerMonth: "10.00"
+  - token: "0x00000000000000000000000000000000000000bb"
+    priceUSDPerTBPerMonth: "12.50"
+    isActive: false
+`
+	if err := os.WriteFile(path, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadSPSpec(path)
+	if err != nil {
+		t.Fatalf("LoadSPSpec: %v", err)
+	}
+	if got.ActorId != 1000 || got.MaxPieceSize != 34359738368 || len(got.Tokens) != 2 {
+		t.Fatalf("unexpected spec: %+v", got)
+	}
+	if got.Tokens[0].IsActive != nil || got.Tokens[1].IsActive == nil || *got.Tokens[1].IsActive {
+		t.Fatalf("unexpected isActive values: %+v", got.Tokens)
+	}
+
+	got.Tokens = append(got.Tokens, got.Tokens[0])
+	if err := ValidateSPSpec(got); err == nil {
+		t.Fatalf("expected duplicate token error")
+	}
+}
+
+func TestPlanSPChanges(t *testing.T) {
+	tokenA := common.HexToAddress("0xaa")
+	tokenB := common.HexToAddress("0xbb")
+	tokenC := common.HexToAddress("0xcc")
+
+	desired := types.SPRegistrationParams{
+		ActorId:        1000,
+		PaymentAddress: common.HexToAddress("0x01"),
+		MinPieceSize:   128,
+		MaxPieceSize:   1024,
+		MinTermLength:  100,
+		MaxTermLength:  200,
+		TokenConfigs: []types.TokenConfig{
+			{Token: tokenA, PricePerBytePerEpoch: big.NewInt(5), IsActive: true},
+			{Token: tokenB, PricePerBytePerEpoch: big.NewInt(7), IsActive: false},
+		},
+	}
+
+	steps := PlanSPChanges(desired, nil, nil)
+	if len(steps) != 1 || steps[0].Action != types.SPActionRegister {
+		t.Fatalf("unregistered SP: got %+v", steps)
+	}
+
+	current := &types.SPConfig{
+		PaymentAddress: desired.PaymentAddress,
+		MinPieceSize:   128,
+		MaxPieceSize:   1024,
+		MinTermLength:  100,
+		MaxTermLength:  200,
+		SupportedTokens: []types.TokenConfig{
+			{Token: tokenA, PricePerBytePerEpoch: big.NewInt(5), IsActive: true},
+			{Token: tokenB, PricePerBytePerEpoch: big.NewInt(7), IsActive: false},
+		},
+		IsActive: true,
+	}
+	if steps := PlanSPChanges(desired, current, nil); len(steps) != 0 {
+		t.Fatalf("matching state: expected no steps, got %+v", steps)
+	}
+
+	current.MaxTermLength = 300
+	current.SupportedTokens = []types.TokenConfig{
+		{Token: tokenA, PricePerBytePerEpoch: big.NewInt(4), IsActive: true},
+		{Token: tokenC, PricePerBytePerEpoch: big.NewInt(1), IsActive: true},
+	}
+	steps = PlanSPChanges(desired, current, nil)
+
+	want := []types.SPPlanAction{
+		types.SPActionUpdateConfig,
+		types.SPActionUpdateToken,
+		types.SPActionAddToken,
+		types.SPActionUpdateToken,
+		types.SPActionRemoveToken,
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d: %+v", len(steps), len(want), steps)
+	}
+	for i, action := range want {
+		if steps[i].Action != action {
+			t.Fatalf("step %d: got %s, want %s", i, steps[i].Action, action)
+		}
+	}
+	if c := steps[0].Changes; len(c) != 1 || c[0].Field != "maxTermLength" || c[0].From != "300" || c[0].To != "200" {
+		t.Fatalf("unexpected config changes: %+v", c)
+	}
+	if steps[1].Token != tokenA || steps[1].PricePerBytePerEpoch.Int64() != 5 {
+		t.Fatalf("unexpected price update: %+v", steps[1])
+	}
+	if steps[2].Token != tokenB || steps[3].Token != tokenB || steps[3].IsActive {
+		t.Fatalf("new inactive token should be added then deactivated: %+v", steps[2:4])
+	}
+	if steps[4].Token != tokenC {
+		t.Fatalf("unexpected removal: %+v", steps[4])
+	}
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// MaxChunkPayload returns how many input bytes can go into one CAR so that its piece
+// does not exceed maxPieceSize. 1/64 of the unpadded piece is reserved for the CAR
+// and UnixFS overhead.
+func MaxChunkPayload(maxPieceSize uint64) int64 {
+	unpadded := maxPieceSize / 128 * 127
+	return int64(unpadded - unpadded/64)
+}
+
+// PieceSizeLimits returns the piece size range accepted by all of the given providers
+func PieceSizeLimits(configs map[uint64]*types.SPConfig) (uint64, uint64, error) {
+	var minSize, maxSize uint64
+	for id, cfg := range configs {
+		if cfg == nil {
+			return 0, 0, fmt.Errorf("storage provider %d is not registered", id)
+		}
+		if cfg.MinPieceSize > minSize {
+			minSize = cfg.MinPieceSize
+		}
+		if maxSize == 0 || cfg.MaxPieceSize < maxSize {
+			maxSize = cfg.MaxPieceSize
+		}
+	}
+	if maxSize != 0 && minSize > maxSize {
+		return 0, 0, fmt.Errorf("providers have no common piece size: minimum %d exceeds maximum %d", minSize, maxSize)
+	}
+	return minSize, maxSize, nil
+}
+
+// ListFileRanges lists the regular files of a file or directory as whole-file ranges, in
+// lexical order, along with their total size
+func ListFileRanges(root string) ([]types.FileRange, int64, error) {
+	var files []types.FileRange
+	var total int64
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, types.FileRange{Path: p, Size: info.Size(), Start: 0, End: info.Size()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list %s: %w", root, err)
+	}
+	return files, total, nil
+}
+
+// SplitFileRanges divides files into the fewest chunks of at most maxPayload bytes. Chunks
+// are balanced to about the same size; files that cross a chunk boundary are split into
+// byte ranges.
+func SplitFileRanges(files []types.FileRange, maxPayload int64) [][]types.FileRange {
+	var total int64
+	for _, f := range files {
+		total += f.End - f.Start
+	}
+	if total == 0 || maxPayload <= 0 {
+		return [][]types.FileRange{files}
+	}
+
+	numChunks := (total + maxPayload - 1) / maxPayload
+	target := (total + numChunks - 1) / numChunks
+
+	var chunks [][]types.FileRange
+	var current []types.FileRange
+	var used int64
+	for _, f := range files {
+		start := f.Start
+		for {
+			take := f.End - start
+			if take > target-used {
+				take = target - used
+			}
+			current = append(current, types.FileRange{Path: f.Path, Size: f.Size, Start: start, End: start + take})
+			start += take
+			used += take
+
+			if used == target {
+				chunks = append(chunks, current)
+				current, used = nil, 0
+			}
+			if start == f.End {
+				break
+			}
+		}
+	}
+	if len(current) > 0 {
+		if used == 0 && len(chunks) > 0 {
+			// Only empty files remain; keep them with the last chunk
+			chunks[len(chunks)-1] = append(chunks[len(chunks)-1], current...)
+		} else {
+			chunks = append(chunks, current)
+		}
+	}
+	return chunks
+}
+
+// WritePieceManifest writes a piece manifest as indented JSON
+func WritePieceManifest(path string, manifest *types.PieceManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", path, err)
+	}
+	return nil
+}
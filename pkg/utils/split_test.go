@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func TestMaxChunkPayload(t *testing.T) {
+	// The padded piece of a full chunk, even with the reserved overhead, must fit
+	for _, size := range []uint64{1 << 20, 32 << 30} {
+		payload := MaxChunkPayload(size)
+		if PaddedPieceSize(uint64(payload+payload/64)) > size {
+			t.Errorf("MaxChunkPayload(%d) = %d does not leave room for overhead", size, payload)
+		}
+		if payload < int64(size/2) {
+			t.Errorf("MaxChunkPayload(%d) = %d is too small", size, payload)
+		}
+	}
+}
+
+func TestPieceSizeLimits(t *testing.T) {
+	minSize, maxSize, err := PieceSizeLimits(map[uint64]*types.SPConfig{
+		1: {MinPieceSize: 128, MaxPieceSize: 1 << 30},
+		2: {MinPieceSize: 1024, MaxPieceSize: 1 << 20},
+	})
+	if err != nil || minSize != 1024 || maxSize != 1<<20 {
+		t.Errorf("PieceSizeLimits = %d, %d, %v, want 1024, %d", minSize, maxSize, err, 1<<20)
+	}
+
+	if _, _, err := PieceSizeLimits(map[uint64]*types.SPConfig{
+		1: {MinPieceSize: 4096, MaxPieceSize: 1 << 30},
+		2: {MinPieceSize: 128, MaxPieceSize: 2048},
+	}); err == nil {
+		t.Error("expected an error for disjoint ranges")
+	}
+
+	if _, _, err := PieceSizeLimits(map[uint64]*types.SPConfig{1: nil}); err == nil {
+		t.Error("expected an error for an unregistered provider")
+	}
+}
+
+func TestListFileRanges(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "b"), make([]byte, 20), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a"), make([]byte, 10), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, total, err := ListFileRanges(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 30 || len(files) != 2 {
+		t.Fatalf("ListFileRanges = %v, %d, want 2 files, 30 bytes", files, total)
+	}
+	if files[0].Path != filepath.Join(dir, "a") || files[0].End != 10 {
+		t.Errorf("first file = %+v, want a [0, 10)", files[0])
+	}
+}
+
+func TestSplitFileRanges(t *testing.T) {
+	files := []types.FileRange{
+		{Path: "a", Size: 50, End: 50},
+		{Path: "b", Size: 120, End: 120},
+		{Path: "empty", Size: 0, End: 0},
+		{Path: "c", Size: 30, End: 30},
+	}
+
+	// 200 bytes with at most 80 per chunk: 3 chunks of about 67 bytes
+	chunks := SplitFileRanges(files, 80)
+	if len(chunks) != 3 {
+		t.Fatalf("got %d chunks, want 3: %v", len(chunks), chunks)
+	}
+
+	var total int64
+	covered := make(map[string]int64)
+	for _, chunk := range chunks {
+		var size int64
+		for _, f := range chunk {
+			size += f.End - f.Start
+			covered[f.Path] += f.End - f.Start
+		}
+		if size > 80 {
+			t.Errorf("chunk of %d bytes exceeds the maximum: %v", size, chunk)
+		}
+		total += size
+	}
+	if total != 200 {
+		t.Errorf("chunks cover %d bytes, want 200", total)
+	}
+	for _, f := range files {
+		if covered[f.Path] != f.Size {
+			t.Errorf("file %s covered %d bytes, want %d", f.Path, covered[f.Path], f.Size)
+		}
+	}
+
+	// b is split across the first two chunks
+	first, second := chunks[0], chunks[1]
+	if last := first[len(first)-1]; last.Path != "b" || last.Start != 0 || last.End != 17 {
+		t.Errorf("first chunk ends with %+v, want b [0, 17)", last)
+	}
+	if second[0].Path != "b" || second[0].Start != 17 {
+		t.Errorf("second chunk starts with %+v, want b from 17", second[0])
+	}
+
+	if chunks := SplitFileRanges(files, 1000); len(chunks) != 1 {
+		t.Errorf("got %d chunks for data below the maximum, want 1", len(chunks))
+	}
+}
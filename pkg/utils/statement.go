@@ -0,0 +1,290 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// Statement periods accepted by PeriodKey
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// GetAllocationRails resolves the payment rail of each allocation. Allocations without a rail are skipped.
+func GetAllocationRails(ddoClient *ddo.Client, allocationIds []uint64) ([]types.AllocationRail, error) {
+	infos, err := ddoClient.GetAllocationRailInfos(allocationIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rail info: %w", err)
+	}
+
+	rails := make([]types.AllocationRail, 0, len(infos))
+	for _, info := range infos {
+		if info.RailId == 0 {
+			continue
+		}
+
+		rails = append(rails, types.AllocationRail{
+			AllocationId: info.AllocationId,
+			ProviderId:   info.ProviderId,
+			RailId:       new(big.Int).SetUint64(info.RailId),
+			Client:       info.Rail.From,
+			Payee:        info.Rail.To,
+			Token:        info.Rail.Token,
+		})
+	}
+	return rails, nil
+}
+
+// CollectPaymentRecords reads settlement and one-time payment events for the given rails between
+// fromBlock and toBlock and attributes them to their allocations. Event times are estimated from
+// refEpoch/refTime, which should be a recent block and its timestamp.
+func CollectPaymentRecords(
+	paymentsClient *payments.Client,
+	rails []types.AllocationRail,
+	fromBlock, toBlock uint64,
+	refEpoch uint64,
+	refTime time.Time,
+) ([]types.PaymentRecord, error) {
+	if len(rails) == 0 {
+		return []types.PaymentRecord{}, nil
+	}
+
+	byRail := make(map[string]types.AllocationRail, len(rails))
+	railIds := make([]*big.Int, 0, len(rails))
+	for _, r := range rails {
+		byRail[r.RailId.String()] = r
+		railIds = append(railIds, r.RailId)
+	}
+
+	settled, err := paymentsClient.GetRailSettledEvents(railIds, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	oneTime, err := paymentsClient.GetRailOneTimePaymentEvents(railIds, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]types.PaymentRecord, 0, len(settled)+len(oneTime))
+	for _, e := range settled {
+		rail := byRail[e.RailId.String()]
+		records = append(records, types.PaymentRecord{
+			Kind:               "settlement",
+			AllocationId:       rail.AllocationId,
+			ProviderId:         rail.ProviderId,
+			RailId:             e.RailId,
+			Client:             rail.Client,
+			Token:              rail.Token,
+			Gross:              e.TotalSettledAmount,
+			NetworkFee:         e.NetworkFee,
+			OperatorCommission: e.OperatorCommission,
+			Net:                e.TotalNetPayeeAmount,
+			SettledUpTo:        e.SettledUpTo,
+			BlockNumber:        e.BlockNumber,
+			Time:               EpochToTime(e.BlockNumber, refEpoch, refTime),
+			TxHash:             e.TxHash,
+		})
+	}
+	for _, e := range oneTime {
+		rail := byRail[e.RailId.String()]
+		gross := new(big.Int).Add(e.NetPayeeAmount, e.OperatorCommission)
+		gross.Add(gross, e.NetworkFee)
+		records = append(records, types.PaymentRecord{
+			Kind:               "one-time",
+			AllocationId:       rail.AllocationId,
+			ProviderId:         rail.ProviderId,
+			RailId:             e.RailId,
+			Client:             rail.Client,
+			Token:              rail.Token,
+			Gross:              gross,
+			NetworkFee:         e.NetworkFee,
+			OperatorCommission: e.OperatorCommission,
+			Net:                e.NetPayeeAmount,
+			BlockNumber:        e.BlockNumber,
+			Time:               EpochToTime(e.BlockNumber, refEpoch, refTime),
+			TxHash:             e.TxHash,
+		})
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].BlockNumber < records[j].BlockNumber
+	})
+
+	return records, nil
+}
+
+// PeriodKey returns the statement period a time falls into: YYYY-MM-DD for days,
+// ISO YYYY-Www for weeks and YYYY-MM for months
+func PeriodKey(t time.Time, period string) (string, error) {
+	t = t.UTC()
+	switch period {
+	case PeriodDay:
+		return t.Format("2006-01-02"), nil
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week), nil
+	case PeriodMonth:
+		return t.Format("2006-01"), nil
+	default:
+		return "", fmt.Errorf("invalid period %q (expected %s, %s or %s)", period, PeriodDay, PeriodWeek, PeriodMonth)
+	}
+}
+
+// ParseEpochOrDate parses an epoch number or a UTC date (YYYY-MM-DD or RFC3339) into an epoch,
+// estimating dates from refEpoch/refTime
+func ParseEpochOrDate(value string, refEpoch uint64, refTime time.Time) (uint64, error) {
+	if epoch, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return epoch, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return TimeToEpoch(t, refEpoch, refTime), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return TimeToEpoch(t, refEpoch, refTime), nil
+	}
+	return 0, fmt.Errorf("invalid epoch or date %q (expected an epoch number, YYYY-MM-DD or RFC3339)", value)
+}
+
+// ParseEndEpochOrDate parses the inclusive end of a range like ParseEpochOrDate, except that a
+// date (YYYY-MM-DD) covers the whole day: the next midnight is the exclusive bound, so the
+// result is the last epoch before it
+func ParseEndEpochOrDate(value string, refEpoch uint64, refTime time.Time) (uint64, error) {
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return ParseEpochOrDate(value, refEpoch, refTime)
+	}
+	next := day.AddDate(0, 0, 1)
+	end := TimeToEpoch(next, refEpoch, refTime)
+	if EpochToTime(end, refEpoch, refTime).Before(next) {
+		end++
+	}
+	if end == 0 {
+		return 0, nil
+	}
+	return end - 1, nil
+}
+
+// SummarizeByAllocation aggregates payment records per allocation
+func SummarizeByAllocation(records []types.PaymentRecord) []types.StatementLine {
+	return summarize(records, func(r types.PaymentRecord) types.StatementLine {
+		return types.StatementLine{AllocationId: r.AllocationId, ProviderId: r.ProviderId, RailId: r.RailId, Token: r.Token}
+	})
+}
+
+// SummarizeByProvider aggregates payment records per provider and token
+func SummarizeByProvider(records []types.PaymentRecord) []types.StatementLine {
+	return summarize(records, func(r types.PaymentRecord) types.StatementLine {
+		return types.StatementLine{ProviderId: r.ProviderId, Token: r.Token}
+	})
+}
+
+// SummarizeByToken aggregates payment records per token
+func SummarizeByToken(records []types.PaymentRecord) []types.StatementLine {
+	return summarize(records, func(r types.PaymentRecord) types.StatementLine {
+		return types.StatementLine{Token: r.Token}
+	})
+}
+
+// SummarizeByPeriod aggregates payment records per period and token
+func SummarizeByPeriod(records []types.PaymentRecord, period string) ([]types.StatementLine, error) {
+	if _, err := PeriodKey(time.Time{}, period); err != nil {
+		return nil, err
+	}
+	return summarize(records, func(r types.PaymentRecord) types.StatementLine {
+		key, _ := PeriodKey(r.Time, period)
+		return types.StatementLine{Period: key, Token: r.Token}
+	}), nil
+}
+
+// summarize groups records by the line returned from keyOf and sums their amounts,
+// preserving the order in which each group first appears
+func summarize(records []types.PaymentRecord, keyOf func(types.PaymentRecord) types.StatementLine) []types.StatementLine {
+	var lines []types.StatementLine
+	index := make(map[string]int)
+
+	for _, r := range records {
+		line := keyOf(r)
+		railKey := ""
+		if line.RailId != nil {
+			railKey = line.RailId.String()
+		}
+		key := fmt.Sprintf("%s|%d|%d|%s|%s", line.Period, line.AllocationId, line.ProviderId, railKey, line.Token.Hex())
+
+		i, ok := index[key]
+		if !ok {
+			line.Gross = big.NewInt(0)
+			line.NetworkFee = big.NewInt(0)
+			line.OperatorCommission = big.NewInt(0)
+			line.Net = big.NewInt(0)
+			lines = append(lines, line)
+			i = len(lines) - 1
+			index[key] = i
+		}
+
+		lines[i].Payments++
+		lines[i].Gross.Add(lines[i].Gross, r.Gross)
+		lines[i].NetworkFee.Add(lines[i].NetworkFee, r.NetworkFee)
+		lines[i].OperatorCommission.Add(lines[i].OperatorCommission, r.OperatorCommission)
+		lines[i].Net.Add(lines[i].Net, r.Net)
+	}
+
+	return lines
+}
+
+// WriteStatementCSV writes statement lines as CSV. formatAmount renders an amount of a
+// line's token; pass nil to write raw base units.
+func WriteStatementCSV(w io.Writer, lines []types.StatementLine, formatAmount func(token common.Address, amount *big.Int) string) error {
+	if formatAmount == nil {
+		formatAmount = func(_ common.Address, v *big.Int) string { return v.String() }
+	}
+
+	cw := csv.NewWriter(w)
+	header := []string{"period", "allocation_id", "provider_id", "rail_id", "token", "payments", "gross", "network_fee", "operator_commission", "net"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, l := range lines {
+		row := []string{
+			l.Period,
+			optionalUint(l.AllocationId),
+			optionalUint(l.ProviderId),
+			"",
+			l.Token.Hex(),
+			strconv.Itoa(l.Payments),
+			formatAmount(l.Token, l.Gross),
+			formatAmount(l.Token, l.NetworkFee),
+			formatAmount(l.Token, l.OperatorCommission),
+			formatAmount(l.Token, l.Net),
+		}
+		if l.RailId != nil {
+			row[3] = l.RailId.String()
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func optionalUint(v uint64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatUint(v, 10)
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+func testRecord(allocationId uint64, at time.Time, gross, fee, commission int64) types.PaymentRecord {
+	return types.PaymentRecord{
+		AllocationId:       allocationId,
+		ProviderId:         1000,
+		RailId:             big.NewInt(int64(allocationId) + 100),
+		Token:              common.HexToAddress("0x01"),
+		Gross:              big.NewInt(gross),
+		NetworkFee:         big.NewInt(fee),
+		OperatorCommission: big.NewInt(commission),
+		Net:                big.NewInt(gross - fee - commission),
+		Time:               at,
+	}
+}
+
+func TestPeriodKey(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]string{
+		PeriodDay:   "2025-01-01",
+		PeriodWeek:  "2025-W01",
+		PeriodMonth: "2025-01",
+	}
+	for period, want := range cases {
+		got, err := PeriodKey(at, period)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", period, err)
+		}
+		if got != want {
+			t.Fatalf("%s: got %s, want %s", period, got, want)
+		}
+	}
+
+	if _, err := PeriodKey(at, "year"); err == nil {
+		t.Fatalf("expected error for unknown period")
+	}
+}
+
+func TestTimeToEpoch(t *testing.T) {
+	ref := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		at   time.Time
+		want uint64
+	}{
+		{"reference", ref, 10000},
+		{"one epoch later", ref.Add(30 * time.Second), 10001},
+		{"within an epoch", ref.Add(59 * time.Second), 10001},
+		{"one day earlier", ref.AddDate(0, 0, -1), 10000 - EPOCHS_PER_DAY},
+		{"before genesis", ref.Add(-10001 * 30 * time.Second), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TimeToEpoch(tt.at, 10000, ref); got != tt.want {
+				t.Errorf("TimeToEpoch = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := EpochToTime(TimeToEpoch(ref.Add(time.Hour), 10000, ref), 10000, ref); !got.Equal(ref.Add(time.Hour)) {
+		t.Errorf("round trip = %s, want %s", got, ref.Add(time.Hour))
+	}
+}
+
+func TestParseEpochOrDate(t *testing.T) {
+	// Epoch 10000 is at 2025-01-01 12:00 UTC
+	ref := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		value     string
+		wantStart uint64
+		wantEnd   uint64
+		wantErr   bool
+	}{
+		{name: "epoch", value: "1234", wantStart: 1234, wantEnd: 1234},
+		{name: "date", value: "2025-01-01", wantStart: 10000 - EPOCHS_PER_DAY/2, wantEnd: 10000 + EPOCHS_PER_DAY/2 - 1},
+		{name: "later date", value: "2025-01-02", wantStart: 10000 + EPOCHS_PER_DAY/2, wantEnd: 10000 + 3*EPOCHS_PER_DAY/2 - 1},
+		{name: "RFC3339", value: "2025-01-01T12:30:00Z", wantStart: 10060, wantEnd: 10060},
+		{name: "date before genesis", value: "2024-01-01", wantStart: 0, wantEnd: 0},
+		{name: "invalid", value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := ParseEpochOrDate(tt.value, 10000, ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if _, err := ParseEndEpochOrDate(tt.value, 10000, ref); err == nil {
+					t.Fatal("expected an error for the end")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			end, err := ParseEndEpochOrDate(tt.value, 10000, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("start %d, end %d, want %d and %d", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+
+	// Consecutive dates cover consecutive epochs without overlap
+	end, _ := ParseEndEpochOrDate("2025-01-01", 10000, ref)
+	next, _ := ParseEpochOrDate("2025-01-02", 10000, ref)
+	if next != end+1 {
+		t.Errorf("2025-01-01 ends at %d, 2025-01-02 starts at %d", end, next)
+	}
+}
+
+func TestSummarizeStatement(t *testing.T) {
+	jan := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	records := []types.PaymentRecord{
+		testRecord(1, jan, 1000, 5, 10),
+		testRecord(2, jan, 500, 2, 5),
+		testRecord(1, feb, 1000, 5, 10),
+	}
+
+	byAllocation := SummarizeByAllocation(records)
+	if len(byAllocation) != 2 {
+		t.Fatalf("allocation lines: got %d, want 2", len(byAllocation))
+	}
+	if byAllocation[0].Payments != 2 || byAllocation[0].Gross.Int64() != 2000 || byAllocation[0].Net.Int64() != 1970 {
+		t.Fatalf("allocation 1: got %d payments, gross %s, net %s", byAllocation[0].Payments, byAllocation[0].Gross, byAllocation[0].Net)
+	}
+
+	byPeriod, err := SummarizeByPeriod(records, PeriodMonth)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(byPeriod) != 2 || byPeriod[0].Period != "2025-01" || byPeriod[0].Gross.Int64() != 1500 {
+		t.Fatalf("unexpected period lines: %+v", byPeriod)
+	}
+
+	// Summing must not alias the amounts of the source records
+	if records[0].Gross.Int64() != 1000 {
+		t.Fatalf("source record modified: gross %s", records[0].Gross)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteStatementCSV(&buf, byAllocation, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(rows) != 3 {
+		t.Fatalf("csv rows: got %d, want 3", len(rows))
+	}
+	if !strings.HasPrefix(rows[1], ",1,1000,101,") {
+		t.Fatalf("unexpected csv row: %s", rows[1])
+	}
+}
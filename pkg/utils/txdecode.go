@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+	"github.com/Eastore-project/ddo-client/pkg/types"
+)
+
+// Names of the contracts a TxDecoder knows
+const (
+	ContractDDO      = "DDO"
+	ContractPayments = "Payments"
+	ContractERC20    = "ERC20"
+)
+
+type knownContract struct {
+	name    string
+	address common.Address // zero for contracts deployed at many addresses, like tokens
+	abi     abi.ABI
+}
+
+// TxDecoder decodes calls to and events of the DDO, Payments and ERC20 contracts
+type TxDecoder struct {
+	contracts []knownContract
+}
+
+// NewTxDecoder returns a decoder for the DDO and Payments contracts at the given
+// addresses. Transactions to other addresses are matched by method selector or event
+// topic, which also covers ERC20 tokens.
+func NewTxDecoder(ddoAddress, paymentsAddress common.Address) (*TxDecoder, error) {
+	d := &TxDecoder{}
+	for _, c := range []struct {
+		name    string
+		address common.Address
+		json    string
+	}{
+		{ContractDDO, ddoAddress, ddo.DDOClientABI},
+		{ContractPayments, paymentsAddress, payments.PaymentsABI},
+		{ContractERC20, common.Address{}, token.ERC20ABI},
+	} {
+		parsed, err := abi.JSON(strings.NewReader(c.json))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s ABI: %w", c.name, err)
+		}
+		d.contracts = append(d.contracts, knownContract{name: c.name, address: c.address, abi: parsed})
+	}
+	return d, nil
+}
+
+// DecodeCall decodes the call data of a transaction to the address to. It returns nil
+// when the call matches no known method.
+func (d *TxDecoder) DecodeCall(to *common.Address, data []byte) *types.DecodedCall {
+	if len(data) < 4 {
+		return nil
+	}
+	for _, c := range d.candidates(to) {
+		method, err := c.abi.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		args := make(map[string]interface{})
+		if err := method.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+			log.Debugw("failed to unpack call", "contract", c.name, "method", method.Name, "error", err)
+			continue
+		}
+		return &types.DecodedCall{Contract: c.name, Method: method.Name, Args: formatArgs(args)}
+	}
+	return nil
+}
+
+// DecodeLogs decodes the logs of a receipt, skipping those that match no known event
+func (d *TxDecoder) DecodeLogs(logs []*ethtypes.Log) []types.DecodedEvent {
+	events := make([]types.DecodedEvent, 0, len(logs))
+	for _, l := range logs {
+		if event, ok := d.decodeLog(l); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (d *TxDecoder) decodeLog(l *ethtypes.Log) (types.DecodedEvent, bool) {
+	if len(l.Topics) == 0 {
+		return types.DecodedEvent{}, false
+	}
+	address := l.Address
+	for _, c := range d.candidates(&address) {
+		event, err := c.abi.EventByID(l.Topics[0])
+		if err != nil {
+			continue
+		}
+		args := make(map[string]interface{})
+		if err := event.Inputs.UnpackIntoMap(args, l.Data); err != nil {
+			continue
+		}
+		var indexed abi.Arguments
+		for _, arg := range event.Inputs {
+			if arg.Indexed {
+				indexed = append(indexed, arg)
+			}
+		}
+		if err := abi.ParseTopicsIntoMap(args, indexed, l.Topics[1:]); err != nil {
+			continue
+		}
+		return types.DecodedEvent{Contract: c.name, Address: l.Address, Name: event.Name, Args: formatArgs(args)}, true
+	}
+	return types.DecodedEvent{}, false
+}
+
+// candidates returns the contracts to try for the address to: the contract deployed
+// there if known, otherwise every contract
+func (d *TxDecoder) candidates(to *common.Address) []knownContract {
+	if to != nil {
+		for _, c := range d.contracts {
+			if c.address != (common.Address{}) && c.address == *to {
+				return []knownContract{c}
+			}
+		}
+	}
+	return d.contracts
+}
+
+// formatArgs renders byte values as hex so decoded arguments read like the chain's
+func formatArgs(args map[string]interface{}) map[string]interface{} {
+	for name, v := range args {
+		args[name] = formatValue(v)
+	}
+	return args
+}
+
+func formatValue(v interface{}) interface{} {
+	switch b := v.(type) {
+	case []byte:
+		return hexutil.Encode(b)
+	case [32]byte:
+		return common.Hash(b).Hex()
+	}
+	// Fixed-size bytesN values; named types like common.Address format themselves
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Name() == "" && rv.Type().Elem().Kind() == reflect.Uint8 {
+		buf := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(buf), rv)
+		return hexutil.Encode(buf)
+	}
+	return v
+}
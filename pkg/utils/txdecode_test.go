@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Eastore-project/ddo-client/pkg/contract/ddo"
+	"github.com/Eastore-project/ddo-client/pkg/contract/payments"
+	"github.com/Eastore-project/ddo-client/pkg/contract/token"
+)
+
+var (
+	testDDOAddress      = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	testPaymentsAddress = common.HexToAddress("0x2000000000000000000000000000000000000002")
+)
+
+func mustParseABI(t *testing.T, json string) abi.ABI {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(json))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestDecodeCall(t *testing.T) {
+	d, err := NewTxDecoder(testDDOAddress, testPaymentsAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenAddr := common.HexToAddress("0x3000000000000000000000000000000000000003")
+	owner := common.HexToAddress("0x4000000000000000000000000000000000000004")
+	data, err := mustParseABI(t, payments.PaymentsABI).Pack("deposit", tokenAddr, owner, big.NewInt(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := d.DecodeCall(&testPaymentsAddress, data)
+	if call == nil || call.Contract != ContractPayments || call.Method != "deposit" {
+		t.Fatalf("call = %+v, want Payments.deposit", call)
+	}
+	if call.Args["token"] != tokenAddr || call.Args["to"] != owner || call.Args["amount"].(*big.Int).Int64() != 500 {
+		t.Errorf("args = %v", call.Args)
+	}
+
+	// Token contracts are not known by address but by selector
+	data, err = mustParseABI(t, token.ERC20ABI).Pack("approve", owner, big.NewInt(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call := d.DecodeCall(&tokenAddr, data); call == nil || call.Contract != ContractERC20 || call.Method != "approve" {
+		t.Errorf("call = %+v, want ERC20.approve", call)
+	}
+
+	if call := d.DecodeCall(&tokenAddr, []byte{0xde, 0xad, 0xbe, 0xef}); call != nil {
+		t.Errorf("call = %+v, want nil for an unknown selector", call)
+	}
+	if call := d.DecodeCall(&owner, nil); call != nil {
+		t.Errorf("call = %+v, want nil for a transfer", call)
+	}
+}
+
+func TestDecodeLogs(t *testing.T) {
+	d, err := NewTxDecoder(testDDOAddress, testPaymentsAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := mustParseABI(t, ddo.DDOClientABI).Events["AllocationActivated"]
+	data, err := event.Inputs.NonIndexed().Pack(uint64(9), big.NewInt(3), big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs := []*ethtypes.Log{
+		{
+			Address: testDDOAddress,
+			Topics: []common.Hash{
+				event.ID,
+				common.BigToHash(big.NewInt(42)),
+				common.BigToHash(big.NewInt(1000)),
+			},
+			Data: data,
+		},
+		// Not from a known contract
+		{Address: testDDOAddress, Topics: []common.Hash{common.HexToHash("0x1234")}},
+	}
+
+	events := d.DecodeLogs(logs)
+	if len(events) != 1 {
+		t.Fatalf("decoded %d events, want 1", len(events))
+	}
+	got := events[0]
+	if got.Contract != ContractDDO || got.Name != "AllocationActivated" || got.Address != testDDOAddress {
+		t.Errorf("event = %+v", got)
+	}
+	if got.Args["allocationId"] != uint64(42) || got.Args["provider"] != uint64(1000) || got.Args["sector"] != uint64(9) {
+		t.Errorf("args = %v", got.Args)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	if got := formatValue([]byte{0xab, 0xcd}); got != "0xabcd" {
+		t.Errorf("formatValue(bytes) = %v, want 0xabcd", got)
+	}
+	if got := formatValue([4]byte{1, 2, 3, 4}); got != "0x01020304" {
+		t.Errorf("formatValue(bytes4) = %v, want 0x01020304", got)
+	}
+	if got := formatValue(uint64(5)); got != uint64(5) {
+		t.Errorf("formatValue(uint64) = %v, want 5", got)
+	}
+}